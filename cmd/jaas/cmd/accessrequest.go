@@ -0,0 +1,366 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	requestAccessCommandDoc = `
+request-access asks the administrators of a model, cloud or application offer
+to grant you access to it.
+
+The resource is specified as a tag, for example "model-<owner>/<name>",
+"cloud-<name>" or "applicationoffer-<offer-url>". The access levels are the
+same as those used by "juju grant". Administrators of the resource can
+review the request using the approve-access-request and deny-access-request
+commands.
+`
+	requestAccessCommandExamples = `
+    juju request-access model-alice@canonical.com/prod write --reason "investigating incident 42"
+    juju request-access cloud-aws add-model
+`
+
+	listAccessRequestsCommandDoc = `
+list-access-requests lists the access requests for resources you administer.
+
+Use --mine to list the requests you have made instead.
+`
+	listAccessRequestsCommandExamples = `
+    juju list-access-requests
+    juju list-access-requests --status approved --format yaml
+    juju list-access-requests --mine
+`
+
+	approveAccessRequestCommandDoc = `
+approve-access-request approves an access request for a resource you
+administer, granting the requested access.
+
+If --expires-in is specified the access is revoked automatically once
+the duration has passed.
+`
+	approveAccessRequestCommandExamples = `
+    juju approve-access-request 42
+    juju approve-access-request 42 --expires-in 4h --comment "for the incident only"
+`
+
+	denyAccessRequestCommandDoc = `
+deny-access-request denies an access request for a resource you administer.
+`
+	denyAccessRequestCommandExamples = `
+    juju deny-access-request 42 --comment "please ask the team lead"
+`
+)
+
+// NewRequestAccessCommand returns a command to request access to a
+// resource.
+func NewRequestAccessCommand() cmd.Command {
+	cmd := &requestAccessCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// requestAccessCommand requests access to a resource.
+type requestAccessCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	resource string
+	access   string
+	reason   string
+}
+
+// Info implements Command.Info.
+func (c *requestAccessCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "request-access",
+		Args:     "<resource> <access>",
+		Purpose:  "Request access to a model, cloud or application offer",
+		Examples: requestAccessCommandExamples,
+		Doc:      requestAccessCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *requestAccessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.reason, "reason", "", "reason access is required")
+}
+
+// Init implements the cmd.Command interface.
+func (c *requestAccessCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("resource not specified")
+	}
+	c.resource = args[0]
+	if len(args) < 2 {
+		return errors.E("access not specified")
+	}
+	c.access = args[1]
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *requestAccessCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return errors.E(err, "failed to dial the controller")
+	}
+
+	client := api.NewClient(apiCaller)
+	ar, err := client.RequestAccess(&apiparams.RequestAccessRequest{
+		Resource: c.resource,
+		Access:   c.access,
+		Reason:   c.reason,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return c.out.Write(ctxt, ar)
+}
+
+// NewListAccessRequestsCommand returns a command to list access
+// requests.
+func NewListAccessRequestsCommand() cmd.Command {
+	cmd := &listAccessRequestsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAccessRequestsCommand lists access requests.
+type listAccessRequestsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	status string
+	mine   bool
+}
+
+// Info implements Command.Info.
+func (c *listAccessRequestsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "list-access-requests",
+		Purpose:  "List access requests",
+		Examples: listAccessRequestsCommandExamples,
+		Doc:      listAccessRequestsCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAccessRequestsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAccessRequestsTabular,
+	})
+	f.StringVar(&c.status, "status", "pending", `only list requests with the given status, "pending", "approved" or "denied", or "" for all`)
+	f.BoolVar(&c.mine, "mine", false, "list the requests you have made")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAccessRequestsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAccessRequestsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return errors.E(err, "failed to dial the controller")
+	}
+
+	client := api.NewClient(apiCaller)
+	requests, err := client.ListAccessRequests(&apiparams.ListAccessRequestsRequest{
+		Status: c.status,
+		Mine:   c.mine,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return c.out.Write(ctxt, requests)
+}
+
+// formatAccessRequestsTabular writes a tabular summary of access
+// requests.
+func formatAccessRequestsTabular(writer io.Writer, value interface{}) error {
+	requests, ok := value.([]apiparams.AccessRequest)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", requests, value))
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+	w.Println("ID", "Requester", "Resource", "Relation", "Status", "Reason")
+	for _, ar := range requests {
+		w.Println(ar.ID, ar.Requester, ar.Resource, ar.Relation, ar.Status, ar.Reason)
+	}
+	return tw.Flush()
+}
+
+// NewApproveAccessRequestCommand returns a command to approve an access
+// request.
+func NewApproveAccessRequestCommand() cmd.Command {
+	cmd := &reviewAccessRequestCommand{
+		store:   jujuclient.NewFileClientStore(),
+		approve: true,
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// NewDenyAccessRequestCommand returns a command to deny an access
+// request.
+func NewDenyAccessRequestCommand() cmd.Command {
+	cmd := &reviewAccessRequestCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// reviewAccessRequestCommand approves or denies an access request.
+type reviewAccessRequestCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	approve   bool
+	id        uint
+	comment   string
+	expiresIn time.Duration
+}
+
+// Info implements Command.Info.
+func (c *reviewAccessRequestCommand) Info() *cmd.Info {
+	if c.approve {
+		return jujucmd.Info(&cmd.Info{
+			Name:     "approve-access-request",
+			Args:     "<id>",
+			Purpose:  "Approve an access request",
+			Examples: approveAccessRequestCommandExamples,
+			Doc:      approveAccessRequestCommandDoc,
+		})
+	}
+	return jujucmd.Info(&cmd.Info{
+		Name:     "deny-access-request",
+		Args:     "<id>",
+		Purpose:  "Deny an access request",
+		Examples: denyAccessRequestCommandExamples,
+		Doc:      denyAccessRequestCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *reviewAccessRequestCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.comment, "comment", "", "comment for the requester")
+	if c.approve {
+		f.DurationVar(&c.expiresIn, "expires-in", 0, "revoke the granted access after the given duration")
+	}
+}
+
+// Init implements the cmd.Command interface.
+func (c *reviewAccessRequestCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("access request ID not specified")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return errors.E(err, "invalid access request ID")
+	}
+	c.id = uint(id)
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if c.expiresIn < 0 {
+		return errors.E("--expires-in must be positive")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *reviewAccessRequestCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return errors.E(err, "failed to dial the controller")
+	}
+
+	req := apiparams.ReviewAccessRequestRequest{
+		ID:      c.id,
+		Comment: c.comment,
+	}
+	if c.expiresIn > 0 {
+		expiresAt := time.Now().Add(c.expiresIn)
+		req.ExpiresAt = &expiresAt
+	}
+
+	client := api.NewClient(apiCaller)
+	var ar apiparams.AccessRequest
+	if c.approve {
+		ar, err = client.ApproveAccessRequest(&req)
+	} else {
+		ar, err = client.DenyAccessRequest(&req)
+	}
+	if err != nil {
+		return errors.E(err)
+	}
+	return c.out.Write(ctxt, ar)
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jaas/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type accessRequestSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&accessRequestSuite{})
+
+func (s *accessRequestSuite) TestRequestAndApprove(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	bobClient := jimmtest.NewUserSessionLogin(c, "bob")
+	charlieClient := jimmtest.NewUserSessionLogin(c, "charlie")
+
+	context, err := cmdtesting.RunCommand(c, cmd.NewRequestAccessCommandForTesting(s.ClientStore(), bobClient), "model-charlie@canonical.com/model-2", "read", "--reason", "debugging")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s)id: 1
+requester: bob@canonical.com
+resource: model-charlie@canonical.com/model-2
+relation: reader
+reason: debugging
+status: pending
+.*`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewListAccessRequestsCommandForTesting(s.ClientStore(), charlieClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `ID  Requester          Resource                            Relation  Status   Reason
+1   bob@canonical.com  model-charlie@canonical.com/model-2  reader    pending  debugging
+`)
+
+	s.RefreshControllerAddress(c)
+	_, err = cmdtesting.RunCommand(c, cmd.NewApproveAccessRequestCommandForTesting(s.ClientStore(), bobClient), "1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewApproveAccessRequestCommandForTesting(s.ClientStore(), charlieClient), "1", "--comment", "ok", "--expires-in", "1h")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s).*status: approved
+reviewed-by: charlie@canonical.com
+comment: ok
+expires-at: .*`)
+
+	ok, err := s.JIMM.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.ReaderRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(ok, gc.Equals, true)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewListAccessRequestsCommandForTesting(s.ClientStore(), bobClient), "--mine", "--status", dbmodel.AccessRequestApproved, "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, fmt.Sprintf(`\[\{"id":1,"requester":"bob@canonical.com","resource":%q.*"status":"approved".*\}\]\n`, "model-charlie@canonical.com/model-2"))
+}
+
+func (s *accessRequestSuite) TestDeny(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	bobClient := jimmtest.NewUserSessionLogin(c, "bob")
+	// alice is superuser
+	aliceClient := jimmtest.NewUserSessionLogin(c, "alice")
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewRequestAccessCommandForTesting(s.ClientStore(), bobClient), "model-charlie@canonical.com/model-2", "write")
+	c.Assert(err, gc.IsNil)
+
+	s.RefreshControllerAddress(c)
+	context, err := cmdtesting.RunCommand(c, cmd.NewDenyAccessRequestCommandForTesting(s.ClientStore(), aliceClient), "1", "--comment", "no")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s).*status: denied
+reviewed-by: alice@canonical.com
+comment: no
+.*`)
+}
+
+func (s *accessRequestSuite) TestMissingArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	clientStore := s.ClientStore()
+
+	_, err := cmdtesting.RunCommand(c, cmd.NewRequestAccessCommandForTesting(clientStore, bClient))
+	c.Check(err, gc.ErrorMatches, "resource not specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewRequestAccessCommandForTesting(clientStore, bClient), "cloud-aws")
+	c.Check(err, gc.ErrorMatches, "access not specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewApproveAccessRequestCommandForTesting(clientStore, bClient))
+	c.Check(err, gc.ErrorMatches, "access request ID not specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewDenyAccessRequestCommandForTesting(clientStore, bClient), "one")
+	c.Check(err, gc.ErrorMatches, "invalid access request ID.*")
+	_, err = cmdtesting.RunCommand(c, cmd.NewDenyAccessRequestCommandForTesting(clientStore, bClient), "1", "--expires-in", "1h")
+	c.Check(err, gc.ErrorMatches, ".*flag provided but not defined: --expires-in")
+}
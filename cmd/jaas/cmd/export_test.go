@@ -46,3 +46,40 @@ func NewGrantCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginPro
 
 	return modelcmd.WrapBase(cmd)
 }
+
+func NewRequestAccessCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &requestAccessCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewListAccessRequestsCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &listAccessRequestsCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewApproveAccessRequestCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &reviewAccessRequestCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+		approve:  true,
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewDenyAccessRequestCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &reviewAccessRequestCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
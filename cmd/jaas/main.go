@@ -30,6 +30,10 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	serviceAccountCmd.Register(cmd.NewListServiceAccountCredentialsCommand())
 	serviceAccountCmd.Register(cmd.NewUpdateCredentialCommand())
 	serviceAccountCmd.Register(cmd.NewGrantCommand())
+	serviceAccountCmd.Register(cmd.NewRequestAccessCommand())
+	serviceAccountCmd.Register(cmd.NewListAccessRequestsCommand())
+	serviceAccountCmd.Register(cmd.NewApproveAccessRequestCommand())
+	serviceAccountCmd.Register(cmd.NewDenyAccessRequestCommand())
 	return serviceAccountCmd
 }
 
@@ -0,0 +1,138 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const applyAccessPolicyDoc = `
+apply command brings the groups and relations in JIMM in line with an
+access policy file.
+
+The policy file declares groups, the members of groups and relations,
+using the same tag syntax as "relation add":
+
+	groups:
+	- ops
+	memberships:
+	  ops:
+	  - user-alice@canonical.com
+	  - group-sre#member
+	relations:
+	- object: group-ops#member
+	  relation: administrator
+	  target_object: model-alice@canonical.com/prod
+
+The policy manages the members of the groups it declares and the relations
+to every resource named as a target in it. Any other member or relation of
+those groups and resources is removed, other groups and resources are left
+unchanged. Missing groups are created.
+
+With --plan, the default, the changes required are shown without being
+made. With --apply the changes are made and recorded in the audit log. If
+the relations cannot all be changed those already changed are restored,
+any groups created are kept and applying the policy again completes the
+changes.
+
+Example:
+	jimmctl auth apply policy.yaml --plan
+	jimmctl auth apply policy.yaml --apply
+`
+
+// newApplyAccessPolicyCommand returns a command to apply an access
+// policy.
+func newApplyAccessPolicyCommand() cmd.Command {
+	cmd := &applyAccessPolicyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// applyAccessPolicyCommand plans, or applies, the changes required to
+// bring JIMM in line with an access policy.
+type applyAccessPolicyCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	file     cmd.FileVar
+
+	plan  bool
+	apply bool
+}
+
+// Info implements the cmd.Command interface.
+func (c *applyAccessPolicyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "apply",
+		Args:    "<policy file>",
+		Purpose: "Apply an access policy.",
+		Doc:     applyAccessPolicyDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *applyAccessPolicyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.BoolVar(&c.plan, "plan", false, "show the changes required without making them")
+	f.BoolVar(&c.apply, "apply", false, "make the changes required")
+	c.file.StdinMarkers = stdinMarkers
+}
+
+// Init implements the cmd.Command interface.
+func (c *applyAccessPolicyCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("policy file not specified")
+	}
+	c.file.Path = args[0]
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if c.plan && c.apply {
+		return errors.E("only one of --plan and --apply may be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *applyAccessPolicyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	req := apiparams.ApplyAccessPolicyRequest{
+		Apply: c.apply,
+	}
+	if err := unmarshalYAMLFile(ctxt, &req.Policy, c.file); err != nil {
+		return errors.E(err)
+	}
+
+	client := api.NewClient(apiCaller)
+	plan, err := client.ApplyAccessPolicy(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+	return c.out.Write(ctxt, plan)
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type accessPolicySuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&accessPolicySuite{})
+
+const testAccessPolicy = `
+groups:
+- ops
+memberships:
+  ops:
+  - user-bob@canonical.com
+relations:
+- object: user-charlie@canonical.com
+  relation: administrator
+  target_object: model-charlie@canonical.com/model-2
+- object: group-ops#member
+  relation: reader
+  target_object: model-charlie@canonical.com/model-2
+`
+
+func (s *accessPolicySuite) TestApplyAccessPolicy(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	policyFile := filepath.Join(c.MkDir(), "policy.yaml")
+	err := os.WriteFile(policyFile, []byte(testAccessPolicy), 0600)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient), policyFile, "--plan")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `create-groups:
+- ops
+add:
+- object: user-bob@canonical.com
+  relation: member
+  target_object: group-ops
+- object: group-ops#member
+  relation: reader
+  target_object: model-charlie@canonical.com/model-2
+applied: false
+`)
+	err = s.JIMM.Database.GetGroup(ctx, &dbmodel.GroupEntry{Name: "ops"})
+	c.Check(err, gc.ErrorMatches, `.*record not found`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient), policyFile, "--apply")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s)create-groups:
+- ops
+.*applied: true
+`)
+	allowed, err := s.JIMM.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.ReaderRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}, false)
+	c.Assert(err, gc.IsNil)
+	c.Check(allowed, gc.Equals, true)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient), policyFile)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Equals, "applied: false\n")
+}
+
+func (s *accessPolicySuite) TestApplyAccessPolicyRejectsUnauthorisedUsers(c *gc.C) {
+	policyFile := filepath.Join(c.MkDir(), "policy.yaml")
+	err := os.WriteFile(policyFile, []byte(testAccessPolicy), 0600)
+	c.Assert(err, gc.IsNil)
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err = cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient), policyFile, "--apply")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *accessPolicySuite) TestApplyAccessPolicyInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `policy file not specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewApplyAccessPolicyCommandForTesting(s.ClientStore(), bClient), "policy.yaml", "--plan", "--apply")
+	c.Assert(err, gc.ErrorMatches, `only one of --plan and --apply may be specified`)
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const accessReportDoc = `
+access-report command reports the effective access to a resource, or of
+an identity.
+
+Unlike "relation list", which shows the stored relations, the report
+expands group membership and the administrator relations inherited from
+controllers and models. Each entry shows whether the access is granted
+directly, through a group, or inherited, and the groups and parent
+resources it is granted through.
+
+Exactly one of --resource and --identity must be specified. Resources may
+be controllers, clouds, models, application offers or service accounts.
+
+Example:
+	jimmctl auth access-report --resource model-alice@canonical.com/prod
+	jimmctl auth access-report --identity user-bob@canonical.com --format csv
+`
+
+// newAccessReportCommand returns a command to report effective access.
+func newAccessReportCommand() cmd.Command {
+	cmd := &accessReportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// accessReportCommand reports the effective access to a resource or of
+// an identity.
+type accessReportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.AccessReportRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *accessReportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "access-report",
+		Purpose: "Report effective access.",
+		Doc:     accessReportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *accessReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+		"csv":  formatAccessReportCSV,
+	})
+	f.StringVar(&c.req.Resource, "resource", "", "report the access to the given resource")
+	f.StringVar(&c.req.Identity, "identity", "", "report the access of the given identity")
+}
+
+// Init implements the cmd.Command interface.
+func (c *accessReportCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if (c.req.Resource == "") == (c.req.Identity == "") {
+		return errors.E("either --resource or --identity must be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *accessReportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AccessReport(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+	return c.out.Write(ctxt, resp)
+}
+
+// formatAccessReportCSV writes an access report as CSV with a header
+// row. The groups and parent resources access is granted through are
+// joined with " > ".
+func formatAccessReportCSV(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.AccessReportResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	w := csv.NewWriter(writer)
+	if err := w.Write([]string{"identity", "resource", "relation", "source", "via"}); err != nil {
+		return errors.E(err)
+	}
+	for _, e := range resp.Entries {
+		if err := w.Write([]string{e.Identity, e.Resource, e.Relation, e.Source, strings.Join(e.Via, " > ")}); err != nil {
+			return errors.E(err)
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
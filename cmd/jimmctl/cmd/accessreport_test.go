@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type accessReportSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&accessReportSuite{})
+
+func (s *accessReportSuite) TestAccessReport(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	group, err := s.JIMM.Database.AddGroup(ctx, "reviewers")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.ReaderRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewAccessReportCommandForTesting(s.ClientStore(), bClient), "--resource", "model-charlie@canonical.com/model-2", "--format", "csv")
+	c.Assert(err, gc.IsNil)
+	stdout := cmdtesting.Stdout(context)
+	c.Check(stdout, gc.Matches, `identity,resource,relation,source,via\n(?s).*`)
+	c.Check(stdout, gc.Matches, `(?s).*user-alice@canonical.com,model-charlie@canonical.com/model-2,administrator,inherited,controller-jimm > controller-controller-1\n.*`)
+	c.Check(stdout, gc.Matches, `(?s).*user-bob@canonical.com,model-charlie@canonical.com/model-2,reader,group,group-reviewers\n.*`)
+	c.Check(stdout, gc.Matches, `(?s).*user-charlie@canonical.com,model-charlie@canonical.com/model-2,administrator,direct,\n.*`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewAccessReportCommandForTesting(s.ClientStore(), bClient), "--identity", "user-bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s)entries:
+.*- identity: user-bob@canonical.com
+  resource: model-charlie@canonical.com/model-2
+  relation: reader
+  source: group
+  via:
+  - group-reviewers
+.*`)
+}
+
+func (s *accessReportSuite) TestAccessReportRejectsUnauthorisedUsers(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAccessReportCommandForTesting(s.ClientStore(), bClient), "--identity", "user-bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *accessReportSuite) TestAccessReportInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAccessReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `either --resource or --identity must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewAccessReportCommandForTesting(s.ClientStore(), bClient), "--resource", "cloud-test", "--identity", "user-bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `either --resource or --identity must be specified`)
+}
@@ -0,0 +1,475 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	auditDoc = `
+audit enables users to manage the audit log held by JIMM.
+`
+
+	verifyAuditLogDoc = `
+verify command checks that the audit log has not been tampered with.
+
+When JIMM is configured to hash chain the audit log (JIMM_AUDIT_LOG_HASH_CHAIN)
+every audit log entry holds a hash of its contents and of the entry added
+before it. Entries added before the chain was enabled are reported as
+unhashed. The command walks the audit log checking every hash and reports
+the first entry at which the chain is broken. Entries removed by purging
+old audit logs do not break the chain. If the chain is broken the command
+exits with an error.
+
+Example:
+	jimmctl audit verify
+	jimmctl audit verify --format json
+`
+
+	exportAuditLogDoc = `
+export command streams audit events from JIMM in NDJSON or CSV format.
+
+The events are written as they are read from the audit log, so large
+exports do not need to fit in memory. Events can be filtered using the
+same filters as the audit-events command. Unlike audit-events, all
+matching events are exported unless a limit is specified.
+
+Example:
+	jimmctl audit export > audit.ndjson
+	jimmctl audit export --format csv --after 2024-01-01T00:00:00Z --output audit.csv
+	jimmctl audit export --facade ModelManager --errors
+`
+
+	listAuditLogArchivesDoc = `
+archives command lists the segments of the audit log that have been
+archived.
+
+When archiving is configured, audit log entries are written to the archive
+store in compressed, checksummed segments before they are purged. The
+command shows the key, entry range and checksum of each segment and
+whether it has been restored.
+
+Example:
+	jimmctl audit archives
+	jimmctl audit archives --format json
+`
+
+	restoreAuditLogArchiveDoc = `
+restore command restores an archived segment of the audit log.
+
+The segment is read from the archive store and its checksum and entry
+hashes are verified before its entries are restored. Restored entries are
+held separately from the live audit log and can be queried using the
+--restored flag of the audit-events and audit export commands.
+
+Example:
+	jimmctl audit restore <archive id>
+`
+)
+
+// NewAuditCommand returns a command for audit log management.
+func NewAuditCommand() *cmd.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:    "audit",
+		Doc:     auditDoc,
+		Purpose: "Audit log management.",
+	})
+	cmd.Register(newVerifyAuditLogCommand())
+	cmd.Register(newExportAuditLogCommand())
+	cmd.Register(newListAuditLogArchivesCommand())
+	cmd.Register(newRestoreAuditLogArchiveCommand())
+
+	return cmd
+}
+
+// newVerifyAuditLogCommand returns a command to verify the audit log.
+func newVerifyAuditLogCommand() cmd.Command {
+	cmd := &verifyAuditLogCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// verifyAuditLogCommand verifies the audit log hash chain.
+type verifyAuditLogCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *verifyAuditLogCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "verify",
+		Purpose: "Verify the audit log has not been tampered with",
+		Doc:     verifyAuditLogDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *verifyAuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *verifyAuditLogCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyAuditLogCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.VerifyAuditLog()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	if !resp.Valid {
+		return errors.E("audit log hash chain is broken")
+	}
+	return nil
+}
+
+// newExportAuditLogCommand returns a command to export the audit log.
+func newExportAuditLogCommand() cmd.Command {
+	cmd := &exportAuditLogCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// exportAuditLogCommand streams audit events from JIMM.
+type exportAuditLogCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	args     apiparams.FindAuditEventsRequest
+	format   string
+	output   string
+}
+
+// Info implements the cmd.Command interface.
+func (c *exportAuditLogCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export",
+		Purpose: "Export audit events",
+		Doc:     exportAuditLogDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportAuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.format, "format", api.AuditExportNDJSON, "export format, either ndjson or csv")
+	f.StringVar(&c.output, "output", "", "write the events to the given file instead of stdout")
+	f.StringVar(&c.args.After, "after", "", "export events that happened after specified time")
+	f.StringVar(&c.args.Before, "before", "", "export events that happened before specified time")
+	f.StringVar(&c.args.UserTag, "user-tag", "", "export events performed by authenticated user")
+	f.StringVar(&c.args.Method, "method", "", "export events for a specific method call")
+	f.StringVar(&c.args.Model, "model", "", "export events for a specific model (model name is controller/model)")
+	f.StringVar(&c.args.FacadeName, "facade", "", "export events for a specific facade")
+	f.StringVar(&c.args.ConversationId, "conversation-id", "", "export events from a specific conversation")
+	f.StringVar(&c.args.ObjectId, "object-id", "", "export events for a specific object id")
+	f.BoolVar(&c.args.HasErrors, "errors", false, "export only responses containing errors")
+	f.StringVar(&c.args.ParamsContain, "params", "", "export events whose parameters contain the given JSON object")
+	f.IntVar(&c.args.Limit, "limit", 0, "limit the maximum number of exported audit events")
+	f.BoolVar(&c.args.SortTime, "reverse", false, "reverse the order of logs, exporting the most recent first")
+	f.BoolVar(&c.args.Restored, "restored", false, "export events restored from the audit log archive")
+}
+
+// Init implements the cmd.Command interface.
+func (c *exportAuditLogCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.format != api.AuditExportNDJSON && c.format != api.AuditExportCSV {
+		return errors.E(fmt.Sprintf("invalid format %q, expected %q or %q", c.format, api.AuditExportNDJSON, api.AuditExportCSV))
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *exportAuditLogCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	// Log in to the controller first so that an expired session token
+	// is refreshed before it is used for the export.
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+	apiCaller.Close()
+
+	controller, err := c.store.ControllerByName(currentController)
+	if err != nil {
+		return errors.E(err)
+	}
+	account, err := c.store.AccountDetails(currentController)
+	if err != nil {
+		return errors.E(err)
+	}
+	if account.SessionToken == "" {
+		return errors.E(fmt.Sprintf("no session token found for controller %q, please log in again", currentController))
+	}
+	client, err := auditExportHTTPClient(controller)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	events, err := api.ExportAuditEvents(ctxt, client, auditExportBaseURL(controller), account.SessionToken, &c.args, c.format)
+	if err != nil {
+		return errors.E(err)
+	}
+	defer events.Close()
+
+	if c.output == "" {
+		if _, err := io.Copy(ctxt.Stdout, events); err != nil {
+			return errors.E(err, "audit log export failed")
+		}
+		return nil
+	}
+	f, err := os.Create(ctxt.AbsPath(c.output))
+	if err != nil {
+		return errors.E(err)
+	}
+	if _, err := io.Copy(f, events); err != nil {
+		f.Close()
+		return errors.E(err, "audit log export failed")
+	}
+	if err := f.Close(); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// auditExportBaseURL returns the base URL of the HTTP endpoints of the
+// given controller.
+func auditExportBaseURL(controller *jujuclient.ControllerDetails) string {
+	addr := controller.PublicDNSName
+	if addr == "" && len(controller.APIEndpoints) > 0 {
+		addr = controller.APIEndpoints[0]
+	}
+	if strings.Contains(addr, "://") {
+		return addr
+	}
+	return "https://" + addr
+}
+
+// auditExportHTTPClient returns an HTTP client that trusts the CA
+// certificate of the given controller, if it has one.
+func auditExportHTTPClient(controller *jujuclient.ControllerDetails) (*http.Client, error) {
+	if controller.CACert == "" {
+		return http.DefaultClient, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(controller.CACert)) {
+		return nil, errors.E("invalid controller CA certificate")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// newListAuditLogArchivesCommand returns a command to list the audit log
+// archives.
+func newListAuditLogArchivesCommand() cmd.Command {
+	cmd := &listAuditLogArchivesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listAuditLogArchivesCommand lists the audit log archives.
+type listAuditLogArchivesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listAuditLogArchivesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "archives",
+		Purpose: "List archived segments of the audit log",
+		Doc:     listAuditLogArchivesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAuditLogArchivesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listAuditLogArchivesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAuditLogArchivesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListAuditLogArchives()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newRestoreAuditLogArchiveCommand returns a command to restore an audit
+// log archive.
+func newRestoreAuditLogArchiveCommand() cmd.Command {
+	cmd := &restoreAuditLogArchiveCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// restoreAuditLogArchiveCommand restores an audit log archive.
+type restoreAuditLogArchiveCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	req      apiparams.RestoreAuditLogArchiveRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *restoreAuditLogArchiveCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "restore",
+		Args:    "<archive id>",
+		Purpose: "Restore an archived segment of the audit log",
+		Doc:     restoreAuditLogArchiveDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreAuditLogArchiveCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *restoreAuditLogArchiveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("archive id not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil || id == 0 {
+		return errors.E(fmt.Sprintf("invalid archive id %q", args[0]))
+	}
+	c.req.ID = uint(id)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreAuditLogArchiveCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RestoreAuditLogArchive(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
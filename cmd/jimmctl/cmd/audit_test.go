@@ -0,0 +1,182 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/juju/jujuclient"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/auditarchive"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type auditSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) TestVerifyAuditLog(c *gc.C) {
+	ctx := context.Background()
+	s.JIMM.Database.AuditLogHashChain = true
+	for i := 0; i < 2; i++ {
+		err := s.JIMM.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time:         time.Now(),
+			FacadeName:   "JIMM",
+			FacadeMethod: "ListControllers",
+		})
+		c.Assert(err, gc.IsNil)
+	}
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctxt, err := cmdtesting.RunCommand(c, cmd.NewVerifyAuditLogCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctxt), gc.Matches, `(?s)valid: true\nverified: [0-9]+\nunhashed: 0\n`)
+}
+
+func (s *auditSuite) TestVerifyAuditLogBroken(c *gc.C) {
+	ctx := context.Background()
+	s.JIMM.Database.AuditLogHashChain = true
+	var entries []dbmodel.AuditLogEntry
+	for i := 0; i < 2; i++ {
+		ale := dbmodel.AuditLogEntry{
+			Time:         time.Now(),
+			FacadeName:   "JIMM",
+			FacadeMethod: "ListControllers",
+		}
+		err := s.JIMM.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, gc.IsNil)
+		entries = append(entries, ale)
+	}
+	err := s.JIMM.Database.DB.Model(&entries[0]).Update("facade_method", "AddController").Error
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctxt, err := cmdtesting.RunCommand(c, cmd.NewVerifyAuditLogCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `audit log hash chain is broken`)
+	c.Check(cmdtesting.Stdout(ctxt), gc.Matches, `(?s)valid: false\n.*broken-entry-id: [0-9]+\nreason: hash does not match the entry contents.*`)
+}
+
+func (s *auditSuite) TestVerifyAuditLogUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewVerifyAuditLogCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+// storeWithSessionToken returns a client store holding a session token
+// for the given user.
+func (s *auditSuite) storeWithSessionToken(c *gc.C, username string) *jujuclient.MemStore {
+	store := s.ClientStore()
+	store.Accounts["JIMM"] = jujuclient.AccountDetails{
+		User:         username + "@canonical.com",
+		SessionToken: jimmtest.NewUserSessionToken(c, username),
+	}
+	return store
+}
+
+func (s *auditSuite) TestExportAuditLog(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctxt, err := cmdtesting.RunCommand(c, cmd.NewExportAuditLogCommandForTesting(s.storeWithSessionToken(c, "alice"), bClient), "--facade", "Admin", "--limit", "2")
+	c.Assert(err, gc.IsNil)
+	lines := strings.Split(strings.TrimSpace(cmdtesting.Stdout(ctxt)), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	c.Check(lines[0], gc.Matches, `\{"time":".*","conversation-id":".*","message-id":1,"facade-name":"Admin","facade-method":"LoginWithSessionToken",.*"is-response":false,"params":\{"params":"redacted"\}\}`)
+	c.Check(lines[1], gc.Matches, `\{"time":".*","conversation-id":".*","message-id":1,"facade-name":"Admin","facade-method":"LoginWithSessionToken",.*"user-tag":"user-alice@canonical.com","is-response":true,.*\}`)
+}
+
+func (s *auditSuite) TestExportAuditLogCSV(c *gc.C) {
+	output := filepath.Join(c.MkDir(), "audit.csv")
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportAuditLogCommandForTesting(s.storeWithSessionToken(c, "alice"), bClient), "--format", "csv", "--method", "LoginWithSessionToken", "--output", output)
+	c.Assert(err, gc.IsNil)
+	buf, err := os.ReadFile(output)
+	c.Assert(err, gc.IsNil)
+	c.Check(string(buf), gc.Matches, `time,conversation-id,message-id,facade-name,facade-method,facade-version,object-id,user-tag,model,is-response,params,errors
+.*,1,Admin,LoginWithSessionToken,\d,,user-,,false,"\{""params"":""redacted""\}",
+[\s\S]*`)
+}
+
+func (s *auditSuite) TestExportAuditLogInvalidFormat(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportAuditLogCommandForTesting(s.storeWithSessionToken(c, "alice"), bClient), "--format", "yaml")
+	c.Assert(err, gc.ErrorMatches, `invalid format "yaml", expected "ndjson" or "csv"`)
+}
+
+func (s *auditSuite) TestExportAuditLogNoSessionToken(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportAuditLogCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `no session token found for controller "JIMM", please log in again`)
+}
+
+func (s *auditSuite) TestExportAuditLogUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportAuditLogCommandForTesting(s.storeWithSessionToken(c, "bob"), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *auditSuite) TestListAndRestoreAuditLogArchives(c *gc.C) {
+	ctx := context.Background()
+	store, err := auditarchive.NewFileStore(c.MkDir())
+	c.Assert(err, gc.IsNil)
+	s.JIMM.AuditArchiver = &jimm.AuditLogArchiver{
+		Database: s.JIMM.Database,
+		Store:    store,
+	}
+	for i := 0; i < 2; i++ {
+		err := s.JIMM.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time:         time.Now().Add(-time.Hour),
+			FacadeName:   "JIMM",
+			FacadeMethod: "ListControllers",
+		})
+		c.Assert(err, gc.IsNil)
+	}
+	archives, err := s.JIMM.AuditArchiver.Archive(ctx, time.Now().Add(-time.Minute))
+	c.Assert(err, gc.IsNil)
+	c.Assert(archives, gc.HasLen, 1)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctxt, err := cmdtesting.RunCommand(c, cmd.NewListAuditLogArchivesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctxt), gc.Matches, `(?s)archives:\n- id: [0-9]+\n.*key: `+archives[0].Key+`\n.*entry-count: 2\n.*`)
+
+	ctxt, err = cmdtesting.RunCommand(c, cmd.NewRestoreAuditLogArchiveCommandForTesting(s.ClientStore(), bClient), strconv.Itoa(int(archives[0].ID)))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctxt), gc.Equals, "restored: 2\n")
+
+	ctxt, err = cmdtesting.RunCommand(c, cmd.NewListAuditEventsCommandForTesting(s.ClientStore(), bClient), "--restored", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Check(strings.Count(cmdtesting.Stdout(ctxt), `"facade-method":"ListControllers"`), gc.Equals, 2)
+}
+
+func (s *auditSuite) TestRestoreAuditLogArchiveInvalidID(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRestoreAuditLogArchiveCommandForTesting(s.ClientStore(), bClient), "one")
+	c.Assert(err, gc.ErrorMatches, `invalid archive id "one"`)
+}
+
+func (s *auditSuite) TestAuditLogArchivesUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListAuditLogArchivesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreAuditLogArchiveCommandForTesting(s.ClientStore(), bClient), "1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
@@ -18,6 +18,8 @@ func NewAuthCommand() *jujucmd.SuperCommand {
 	})
 	cmd.Register(NewGroupCommand())
 	cmd.Register(NewRelationCommand())
+	cmd.Register(newAccessReportCommand())
+	cmd.Register(newApplyAccessPolicyCommand())
 
 	return cmd
 }
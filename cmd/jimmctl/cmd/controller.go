@@ -0,0 +1,410 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	controllerDoc = `
+controller command enables management of the controllers known to jimm.
+`
+
+	setRegionPriorityDoc = `
+set-region-priority command sets the priority with which a controller is
+chosen when placing new models in a cloud region. Controllers with a higher
+priority are preferred. Setting the priority to 0 prefers every other
+controller serving the region.
+
+Example:
+	jimmctl controller set-region-priority controller-1 aws/eu-west-1 10
+`
+
+	listRegionPrioritiesDoc = `
+list-region-priorities command lists the priorities with which controllers
+are chosen when placing new models in cloud regions. If a controller is
+specified only the priorities of that controller are listed.
+
+Example:
+	jimmctl controller list-region-priorities
+	jimmctl controller list-region-priorities controller-1 --format json
+`
+
+	drainDoc = `
+drain command migrates all models off a controller so that it can be
+retired. The controller is deprecated, so that no new models are placed on
+it, and its models are migrated in batches to other controllers serving the
+same cloud regions. The drain runs in the background, use drain-status to
+follow its progress. Running drain again on a controller resumes the drain,
+retrying any models that failed to migrate.
+
+Example:
+	jimmctl controller drain controller-1
+	jimmctl controller drain controller-1 --batch-size 10
+`
+
+	drainStatusDoc = `
+drain-status command displays the progress of a controller drain.
+
+Example:
+	jimmctl controller drain-status controller-1
+	jimmctl controller drain-status controller-1 --format json
+`
+)
+
+// NewControllerCommand returns a command for controller management.
+func NewControllerCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "controller",
+		Doc:     controllerDoc,
+		Purpose: "Controller management.",
+	})
+	cmd.Register(newSetRegionPriorityCommand())
+	cmd.Register(newListRegionPrioritiesCommand())
+	cmd.Register(newDrainCommand())
+	cmd.Register(newDrainStatusCommand())
+
+	return cmd
+}
+
+// newSetRegionPriorityCommand returns a command to set the priority of a
+// controller for a cloud region.
+func newSetRegionPriorityCommand() cmd.Command {
+	cmd := &setRegionPriorityCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setRegionPriorityCommand sets the priority of a controller for a cloud
+// region.
+type setRegionPriorityCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+	cloud          string
+	region         string
+	priority       uint
+}
+
+// Info implements the cmd.Command interface.
+func (c *setRegionPriorityCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-region-priority",
+		Args:    "<controller> <cloud>/<region> <priority>",
+		Purpose: "Set the priority of a controller for a cloud region.",
+		Doc:     setRegionPriorityDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setRegionPriorityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+}
+
+// Init implements the cmd.Command interface.
+func (c *setRegionPriorityCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.E("controller, cloud region and priority not specified")
+	}
+	if len(args) > 3 {
+		return errors.E("too many args")
+	}
+	c.controllerName = args[0]
+	var ok bool
+	c.cloud, c.region, ok = strings.Cut(args[1], "/")
+	if !ok || c.cloud == "" || c.region == "" {
+		return errors.E("cloud region must be specified as <cloud>/<region>")
+	}
+	priority, err := strconv.ParseUint(args[2], 10, 0)
+	if err != nil {
+		return errors.E("invalid priority")
+	}
+	c.priority = uint(priority)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setRegionPriorityCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.SetControllerRegionPriority(&apiparams.SetControllerRegionPriorityRequest{
+		Name:     c.controllerName,
+		Cloud:    c.cloud,
+		Region:   c.region,
+		Priority: c.priority,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListRegionPrioritiesCommand returns a command to list the cloud
+// region priorities of controllers.
+func newListRegionPrioritiesCommand() cmd.Command {
+	cmd := &listRegionPrioritiesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listRegionPrioritiesCommand lists the cloud region priorities of
+// controllers.
+type listRegionPrioritiesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+}
+
+// Info implements the cmd.Command interface.
+func (c *listRegionPrioritiesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "list-region-priorities",
+		Args:    "[<controller>]",
+		Purpose: "List the cloud region priorities of controllers.",
+		Doc:     listRegionPrioritiesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listRegionPrioritiesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listRegionPrioritiesCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		c.controllerName = args[0]
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listRegionPrioritiesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	priorities, err := client.ListControllerRegionPriorities(&apiparams.ListControllerRegionPrioritiesRequest{
+		Name: c.controllerName,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, priorities)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newDrainCommand returns a command to drain a controller.
+func newDrainCommand() cmd.Command {
+	cmd := &drainCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// drainCommand migrates all models off a controller.
+type drainCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+	batchSize      int
+}
+
+// Info implements the cmd.Command interface.
+func (c *drainCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "drain",
+		Args:    "<controller>",
+		Purpose: "Migrate all models off a controller.",
+		Doc:     drainDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *drainCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.IntVar(&c.batchSize, "batch-size", 0, "maximum number of models migrated concurrently")
+}
+
+// Init implements the cmd.Command interface.
+func (c *drainCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("controller not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.controllerName = args[0]
+	if c.batchSize < 0 {
+		return errors.E("invalid batch size")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *drainCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	status, err := client.DrainController(&apiparams.DrainControllerRequest{
+		Name:      c.controllerName,
+		BatchSize: c.batchSize,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, status)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newDrainStatusCommand returns a command to display the progress of a
+// controller drain.
+func newDrainStatusCommand() cmd.Command {
+	cmd := &drainStatusCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// drainStatusCommand displays the progress of a controller drain.
+type drainStatusCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+}
+
+// Info implements the cmd.Command interface.
+func (c *drainStatusCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "drain-status",
+		Args:    "<controller>",
+		Purpose: "Display the progress of a controller drain.",
+		Doc:     drainStatusDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *drainStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *drainStatusCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("controller not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.controllerName = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *drainStatusCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	status, err := client.ControllerDrainStatus(&apiparams.ControllerDrainStatusRequest{
+		Name: c.controllerName,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, status)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
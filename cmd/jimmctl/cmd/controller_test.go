@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type controllerSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&controllerSuite{})
+
+func (s *controllerSuite) TestSetListRegionPrioritiesSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetRegionPriorityCommandForTesting(s.ClientStore(), bClient), "controller-1", jimmtest.TestCloudName+"/"+jimmtest.TestCloudRegionName, "5")
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewListRegionPrioritiesCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `- controller: controller-1
+  cloud: `+jimmtest.TestCloudName+`
+  region: `+jimmtest.TestCloudRegionName+`
+  priority: 5
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetRegionPriorityCommandForTesting(s.ClientStore(), bClient), "controller-1", jimmtest.TestCloudName+"/no-such-region", "5")
+	c.Assert(err, gc.ErrorMatches, `controller "controller-1" does not serve cloud region .*/no-such-region`)
+}
+
+func (s *controllerSuite) TestSetRegionPriority(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetRegionPriorityCommandForTesting(s.ClientStore(), bClient), "controller-1", jimmtest.TestCloudName+"/"+jimmtest.TestCloudRegionName, "5")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewListRegionPrioritiesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *controllerSuite) TestSetRegionPriorityInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetRegionPriorityCommandForTesting(s.ClientStore(), bClient), "controller-1", jimmtest.TestCloudName, "5")
+	c.Assert(err, gc.ErrorMatches, `cloud region must be specified as <cloud>/<region>`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetRegionPriorityCommandForTesting(s.ClientStore(), bClient), "controller-1", jimmtest.TestCloudName+"/"+jimmtest.TestCloudRegionName, "-1")
+	c.Assert(err, gc.ErrorMatches, `invalid priority`)
+}
+
+func (s *controllerSuite) TestDrainSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewDrainCommandForTesting(s.ClientStore(), bClient), "controller-1", "--batch-size", "2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `(?s)controller: controller-1
+status: running
+batch-size: 2
+started: .*
+updated: .*
+models: .*`)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewDrainStatusCommandForTesting(s.ClientStore(), bClient), "controller-1", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Matches, `\{"controller":"controller-1","status":"running","batch-size":2,.*\}\n`)
+}
+
+func (s *controllerSuite) TestDrain(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewDrainCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewDrainStatusCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
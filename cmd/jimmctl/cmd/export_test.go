@@ -118,6 +118,15 @@ func NewControllerInfoCommandForTesting(store jujuclient.ClientStore) cmd.Comman
 	return modelcmd.WrapBase(cmd)
 }
 
+func NewSetControllerCapacityCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &setControllerCapacityCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
 func NewSetControllerDeprecatedCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
 	cmd := &setControllerDeprecatedCommand{
 		store:    store,
@@ -252,3 +261,192 @@ func NewMigrateModelCommandForTesting(store jujuclient.ClientStore, lp jujuapi.L
 
 	return modelcmd.WrapBase(cmd)
 }
+
+func NewSetQuotaCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &setQuotaCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewRemoveQuotaCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &removeQuotaCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewListQuotasCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &listQuotasCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewSetRegionPriorityCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &setRegionPriorityCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewListRegionPrioritiesCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &listRegionPrioritiesCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewDrainCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &drainCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewDrainStatusCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &drainStatusCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewMigrationsCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &migrationsCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewImportModelsCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &importModelsCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewReconcileCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &reconcileCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewVerifyAuditLogCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &verifyAuditLogCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewExportAuditLogCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &exportAuditLogCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewListAuditLogArchivesCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &listAuditLogArchivesCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewRestoreAuditLogArchiveCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &restoreAuditLogArchiveCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewAccessReportCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &accessReportCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewApplyAccessPolicyCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &applyAccessPolicyCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewListIdentitiesCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &listIdentitiesCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewShowIdentityCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &showIdentityCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewOffboardIdentityCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &offboardIdentityCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewTransferModelCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &transferModelCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+func NewStaleReportCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &staleReportCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
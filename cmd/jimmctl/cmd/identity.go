@@ -0,0 +1,363 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	identityDoc = `
+identity command enables management of the identities known to jimm.
+`
+
+	listIdentitiesDoc = `
+list command lists the identities known to jimm, ordered by name.
+
+Example:
+	jimmctl identity list
+	jimmctl identity list --match alice --format tabular
+	jimmctl identity list --disabled
+`
+
+	showIdentityDoc = `
+show command displays the details of an identity, including the cloud
+credentials and models it owns and the groups it is a member of.
+
+Example:
+	jimmctl identity show alice@canonical.com
+`
+
+	offboardIdentityDoc = `
+offboard command removes a departing identity from jimm. The models and
+cloud credentials owned by the identity are transferred to the new owner,
+every relation the identity has is removed and the identity is disabled.
+
+Usage:
+-y	Offboard the identity without prompting for confirmation
+
+Example:
+	jimmctl identity offboard alice@canonical.com --new-owner bob@canonical.com
+`
+)
+
+// NewIdentityCommand returns a command for identity management.
+func NewIdentityCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "identity",
+		Doc:     identityDoc,
+		Purpose: "Identity management.",
+	})
+	cmd.Register(newListIdentitiesCommand())
+	cmd.Register(newShowIdentityCommand())
+	cmd.Register(newOffboardIdentityCommand())
+
+	return cmd
+}
+
+// newListIdentitiesCommand returns a command to list identities.
+func newListIdentitiesCommand() cmd.Command {
+	cmd := &listIdentitiesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listIdentitiesCommand lists identities.
+type listIdentitiesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	args apiparams.ListIdentitiesRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *listIdentitiesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "list",
+		Purpose: "List identities.",
+		Doc:     listIdentitiesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listIdentitiesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatIdentitiesTabular,
+	})
+	f.StringVar(&c.args.Match, "match", "", "only list identities whose name or display name contains the specified string")
+	f.BoolVar(&c.args.Disabled, "disabled", false, "only list disabled identities")
+	f.IntVar(&c.args.Offset, "offset", 0, "offset the set of returned identities")
+	f.IntVar(&c.args.Limit, "limit", 0, "limit the maximum number of returned identities")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listIdentitiesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listIdentitiesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListIdentities(&c.args)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, *resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatIdentitiesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(apiparams.ListIdentitiesResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Name", "Display name", "Disabled", "Created", "Last login")
+	for _, i := range resp.Identities {
+		var lastLogin string
+		if i.LastLogin != nil {
+			lastLogin = i.LastLogin.Format(time.RFC3339)
+		}
+		table.AddRow(i.Name, i.DisplayName, i.Disabled, i.Created.Format(time.RFC3339), lastLogin)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
+
+// newShowIdentityCommand returns a command to display the details of an
+// identity.
+func newShowIdentityCommand() cmd.Command {
+	cmd := &showIdentityCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showIdentityCommand displays the details of an identity.
+type showIdentityCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	identity string
+}
+
+// Info implements the cmd.Command interface.
+func (c *showIdentityCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "show",
+		Args:    "<identity>",
+		Purpose: "Show the details of an identity.",
+		Doc:     showIdentityDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showIdentityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showIdentityCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("identity not specified")
+	}
+	c.identity, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidUser(c.identity) {
+		return errors.E(fmt.Sprintf("invalid identity %q", c.identity))
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showIdentityCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	details, err := client.GetIdentity(&apiparams.GetIdentityRequest{
+		Tag: names.NewUserTag(c.identity).String(),
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, *details)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newOffboardIdentityCommand returns a command to offboard an identity.
+func newOffboardIdentityCommand() cmd.Command {
+	cmd := &offboardIdentityCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// offboardIdentityCommand offboards an identity.
+type offboardIdentityCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	identity string
+	newOwner string
+	force    bool
+}
+
+// Info implements the cmd.Command interface.
+func (c *offboardIdentityCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "offboard",
+		Args:    "<identity>",
+		Purpose: "Offboard an identity.",
+		Doc:     offboardIdentityDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *offboardIdentityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.newOwner, "new-owner", "", "identity to transfer the models and cloud credentials to")
+	f.BoolVar(&c.force, "y", false, "offboard the identity without prompt")
+}
+
+// Init implements the cmd.Command interface.
+func (c *offboardIdentityCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("identity not specified")
+	}
+	c.identity, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidUser(c.identity) {
+		return errors.E(fmt.Sprintf("invalid identity %q", c.identity))
+	}
+	if c.newOwner == "" {
+		return errors.E("new owner not specified")
+	}
+	if !names.IsValidUser(c.newOwner) {
+		return errors.E(fmt.Sprintf("invalid identity %q", c.newOwner))
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *offboardIdentityCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	if !c.force {
+		reader := bufio.NewReader(ctxt.Stdin)
+		// Using Fprintf over c.out.write to avoid printing a new line.
+		_, err := fmt.Fprintf(ctxt.Stdout, "This will transfer all models and cloud credentials owned by %q to %q, remove all of its relations and disable it.\nConfirm you would like to offboard %q (y/N): ", c.identity, c.newOwner, c.identity)
+		if err != nil {
+			return err
+		}
+		text, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.E(err, "Failed to read from input.")
+		}
+		text = strings.TrimSpace(text)
+		if !(text == "y" || text == "Y") {
+			return nil
+		}
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	summary, err := client.OffboardIdentity(&apiparams.OffboardIdentityRequest{
+		Tag:         names.NewUserTag(c.identity).String(),
+		NewOwnerTag: names.NewUserTag(c.newOwner).String(),
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, *summary)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type identitySuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&identitySuite{})
+
+func (s *identitySuite) TestListIdentities(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	charlie, err := dbmodel.NewIdentity("charlie@canonical.com")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.GetIdentity(ctx, charlie)
+	c.Assert(err, gc.IsNil)
+	charlie.Disabled = true
+	err = s.JIMM.Database.UpdateIdentity(ctx, charlie)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewListIdentitiesCommandForTesting(s.ClientStore(), bClient), "--match", "CHARLIE")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `identities:
+- name: charlie@canonical.com
+  display-name: charlie
+  disabled: true
+  created: .*
+`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewListIdentitiesCommandForTesting(s.ClientStore(), bClient), "--disabled", "--format", "tabular")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `Name\s+Display name\s+Disabled\s+Created\s+Last login\s*
+charlie@canonical.com\s+charlie\s+true\s+\S+\s*
+`)
+}
+
+func (s *identitySuite) TestListIdentitiesUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListIdentitiesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *identitySuite) TestShowIdentity(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	group, err := s.JIMM.Database.AddGroup(ctx, "reviewers")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `name: charlie@canonical.com
+display-name: charlie
+disabled: false
+created: .*
+cloud-credentials:
+- `+jimmtest.TestCloudName+`/charlie@canonical.com/cred
+models:
+- name: model-2
+  uuid: .*
+  controller: controller-1
+  cloud: `+jimmtest.TestCloudName+`
+  region: `+jimmtest.TestCloudRegionName+`
+groups:
+- reviewers
+`)
+
+	// bob may see his own details, but not charlie's.
+	s.RefreshControllerAddress(c)
+	bClient = jimmtest.NewUserSessionLogin(c, "bob")
+	context, err = cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient), "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `name: bob@canonical.com
+(?s).*`)
+
+	s.RefreshControllerAddress(c)
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *identitySuite) TestShowIdentityInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `identity not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient), "alice@canonical.com", "bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowIdentityCommandForTesting(s.ClientStore(), bClient), "not/valid")
+	c.Assert(err, gc.ErrorMatches, `invalid identity "not/valid"`)
+}
+
+func (s *identitySuite) TestOffboardIdentity(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	group, err := s.JIMM.Database.AddGroup(ctx, "reviewers")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.GetIdentity(ctx, bob)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "--new-owner", "bob@canonical.com", "-y")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `identity: charlie@canonical.com
+new-owner: bob@canonical.com
+models:
+- name: model-2
+  uuid: .*
+  controller: controller-1
+  cloud: `+jimmtest.TestCloudName+`
+  region: `+jimmtest.TestCloudRegionName+`
+cloud-credentials:
+- `+jimmtest.TestCloudName+`/bob@canonical.com/cred
+removed-relations:
+(?s).*- object: user-charlie@canonical.com
+  relation: member
+  target_object: group-reviewers
+.*disabled: true
+`)
+
+	charlie := dbmodel.Identity{Name: "charlie@canonical.com"}
+	err = s.JIMM.Database.FetchIdentity(ctx, &charlie)
+	c.Assert(err, gc.IsNil)
+	c.Check(charlie.Disabled, gc.Equals, true)
+
+	models, err := s.JIMM.Database.GetModelsByOwner(ctx, "bob@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Assert(models, gc.HasLen, 1)
+	c.Check(models[0].Name, gc.Equals, "model-2")
+	c.Check(models[0].CloudCredential.Path(), gc.Equals, jimmtest.TestCloudName+"/bob@canonical.com/cred")
+}
+
+func (s *identitySuite) TestOffboardIdentityWithoutFlag(c *gc.C) {
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "--new-owner", "bob@canonical.com")
+	c.Assert(err.Error(), gc.Matches, "Failed to read from input.")
+}
+
+func (s *identitySuite) TestOffboardIdentityUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "--new-owner", "bob@canonical.com", "-y")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *identitySuite) TestOffboardIdentityInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "--new-owner", "bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `identity not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `new owner not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewOffboardIdentityCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "--new-owner", "not/valid")
+	c.Assert(err, gc.ErrorMatches, `invalid identity "not/valid"`)
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var importModelsCommandDoc = `
+	import-models imports the models running on a controller to jimm.
+	Models that are already known to jimm and the controller model are
+	skipped. The result of importing each model is displayed.
+
+	Either --all must be specified, or the models to import must be
+	selected using the --owner and --name glob patterns.
+
+	The --owner-map option specifies a YAML file mapping the owners of
+	models on the controller to the owners of the imported models. This
+	is necessary when importing models created by local users. E.g.
+
+		admin: my-user@canonical.com
+		bob: bob@canonical.com
+
+	Example:
+		jimmctl import-models <controller name> --all
+		jimmctl import-models <controller name> --all --owner-map owners.yaml
+		jimmctl import-models <controller name> --owner 'bob*' --name 'prod-*'
+`
+
+// NewImportModelsCommand returns a command to import all models on a
+// controller.
+func NewImportModelsCommand() cmd.Command {
+	cmd := &importModelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// importModelsCommand imports all models on a controller.
+type importModelsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	all          bool
+	ownerMapFile cmd.FileVar
+	req          apiparams.ImportModelsRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *importModelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "import-models",
+		Args:    "<controller name>",
+		Purpose: "Import all models on a controller to jimm",
+		Doc:     importModelsCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *importModelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.BoolVar(&c.all, "all", false, "import all models on the controller")
+	f.StringVar(&c.req.OwnerPattern, "owner", "", "only import models whose owner matches the glob pattern")
+	f.StringVar(&c.req.NamePattern, "name", "", "only import models whose name matches the glob pattern")
+	f.Var(&c.ownerMapFile, "owner-map", "YAML file mapping model owners to the owners of the imported models")
+}
+
+// Init implements the cmd.Command interface.
+func (c *importModelsCommand) Init(args []string) error {
+	switch len(args) {
+	default:
+		return errors.E("too many args")
+	case 0:
+		return errors.E("controller not specified")
+	case 1:
+	}
+	c.req.Controller = args[0]
+	if !c.all && c.req.OwnerPattern == "" && c.req.NamePattern == "" {
+		return errors.E("either --all, --owner or --name must be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *importModelsCommand) Run(ctxt *cmd.Context) error {
+	if c.ownerMapFile.Path != "" {
+		if err := unmarshalYAMLFile(ctxt, &c.req.OwnerMap, c.ownerMapFile); err != nil {
+			return errors.E(err, "cannot read owner map")
+		}
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ImportModels(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jjcloud "github.com/juju/juju/cloud"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type importModelsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&importModelsSuite{})
+
+func (s *importModelsSuite) TestImportModelsSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+
+	err := s.BackingState.UpdateCloudCredential(cct, jjcloud.NewCredential(jjcloud.EmptyAuthType, map[string]string{"key": "value"}))
+	c.Assert(err, gc.Equals, nil)
+
+	m := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name:            "model-2",
+		Owner:           names.NewUserTag("charlie@canonical.com"),
+		CloudName:       jimmtest.TestCloudName,
+		CloudRegion:     jimmtest.TestCloudRegionName,
+		CloudCredential: cct,
+	})
+	defer m.Close()
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewImportModelsCommandForTesting(s.ClientStore(), bClient), "controller-1", "--owner", "charlie@canonical.com")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)results:\n- model-tag: model-`+m.ModelUUID()+`\n  name: model-2\n  owner: charlie@canonical.com\n`)
+
+	var model dbmodel.Model
+	model.SetTag(names.NewModelTag(m.ModelUUID()))
+	err = s.JIMM.Database.GetModel(context.Background(), &model)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(model.OwnerIdentityName, gc.Equals, "charlie@canonical.com")
+}
+
+func (s *importModelsSuite) TestImportModelsUnauthorized(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelsCommandForTesting(s.ClientStore(), bClient), "controller-1", "--all")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *importModelsSuite) TestImportModelsNoController(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelsCommandForTesting(s.ClientStore(), bClient), "--all")
+	c.Assert(err, gc.ErrorMatches, `controller not specified`)
+}
+
+func (s *importModelsSuite) TestImportModelsNoSelection(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelsCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `either --all, --owner or --name must be specified`)
+}
+
+func (s *importModelsSuite) TestImportModelsTooManyArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelsCommandForTesting(s.ClientStore(), bClient), "controller-1", "spare-argument", "--all")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
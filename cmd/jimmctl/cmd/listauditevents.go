@@ -23,9 +23,25 @@ import (
 var listAuditEventsCommandDoc = `
 	list-audit-events command displays matching audit events.
 
+	Events can be filtered by facade, conversation, object id, whether the
+	response contains errors and the request parameters. The --params
+	filter takes a JSON object and matches events whose parameters
+	contain it.
+
+	If more events match than are displayed a continuation token is
+	included in the output. Passing it with --continuation-token, along
+	with the same filters, displays the following events. To export
+	large numbers of events use "jimmctl audit export".
+
+	If --conversations is specified the events are grouped by
+	conversation, with each request paired with its response.
+
 	Example:
 		jimmctl list-audit-events --after <time> --before <time> --user-tag <user-tag> --limit <limit>
 		jimmctl audit-events --after <time> --format yaml
+		jimmctl audit-events --facade ModelManager --errors
+		jimmctl audit-events --params '{"name": "model-1"}'
+		jimmctl audit-events --conversation-id <conversation id> --conversations
 `
 
 // NewListAuditEventsCommand returns a command to list audit events matching
@@ -47,6 +63,8 @@ type listAuditEventsCommand struct {
 	store    jujuclient.ClientStore
 	dialOpts *jujuapi.DialOpts
 	args     apiparams.FindAuditEventsRequest
+
+	conversations bool
 }
 
 func (c *listAuditEventsCommand) Info() *cmd.Info {
@@ -74,6 +92,14 @@ func (c *listAuditEventsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.args.Offset, "offset", 0, "offset the set of returned audit events")
 	f.IntVar(&c.args.Limit, "limit", 0, "limit the maximum number of returned audit events")
 	f.BoolVar(&c.args.SortTime, "reverse", false, "reverse the order of logs, showing the most recent first")
+	f.StringVar(&c.args.FacadeName, "facade", "", "display events for a specific facade")
+	f.StringVar(&c.args.ConversationId, "conversation-id", "", "display events from a specific conversation")
+	f.StringVar(&c.args.ObjectId, "object-id", "", "display events for a specific object id")
+	f.BoolVar(&c.args.HasErrors, "errors", false, "display only responses containing errors")
+	f.StringVar(&c.args.ParamsContain, "params", "", "display events whose parameters contain the given JSON object")
+	f.BoolVar(&c.conversations, "conversations", false, "group events by conversation, pairing requests with responses")
+	f.StringVar(&c.args.ContinuationToken, "continuation-token", "", "display the events following those of a previous call that returned the token")
+	f.BoolVar(&c.args.Restored, "restored", false, "display events restored from the audit log archive")
 
 }
 
@@ -103,14 +129,78 @@ func (c *listAuditEventsCommand) Run(ctxt *cmd.Context) error {
 		return errors.E(err)
 	}
 
-	err = c.out.Write(ctxt, events)
+	if c.conversations {
+		convs := groupAuditConversations(events.Events)
+		convs.ContinuationToken = events.ContinuationToken
+		err = c.out.Write(ctxt, convs)
+	} else {
+		err = c.out.Write(ctxt, events)
+	}
 	if err != nil {
 		return errors.E(err)
 	}
 	return nil
 }
 
+// auditConversations holds audit events grouped by conversation.
+type auditConversations struct {
+	Conversations     []auditConversation `json:"conversations" yaml:"conversations"`
+	ContinuationToken string              `json:"continuation-token,omitempty" yaml:"continuation-token,omitempty"`
+}
+
+// An auditConversation holds the audit events of a single conversation.
+type auditConversation struct {
+	ConversationId string      `json:"conversation-id" yaml:"conversation-id"`
+	Calls          []auditCall `json:"calls" yaml:"calls"`
+}
+
+// An auditCall holds a request and its response, joined by message ID.
+// Either may be missing if it is outside of the events found.
+type auditCall struct {
+	MessageId uint64                `json:"message-id" yaml:"message-id"`
+	Request   *apiparams.AuditEvent `json:"request,omitempty" yaml:"request,omitempty"`
+	Response  *apiparams.AuditEvent `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// groupAuditConversations groups the given events by conversation,
+// pairing each request with its response. Conversations and calls are
+// ordered by their first event.
+func groupAuditConversations(events []apiparams.AuditEvent) auditConversations {
+	var convs auditConversations
+	convIndex := make(map[string]int)
+	callIndex := make(map[string]map[uint64]int)
+	for i := range events {
+		ev := &events[i]
+		ci, ok := convIndex[ev.ConversationId]
+		if !ok {
+			ci = len(convs.Conversations)
+			convIndex[ev.ConversationId] = ci
+			callIndex[ev.ConversationId] = make(map[uint64]int)
+			convs.Conversations = append(convs.Conversations, auditConversation{ConversationId: ev.ConversationId})
+		}
+		conv := &convs.Conversations[ci]
+		calls := callIndex[ev.ConversationId]
+		// A repeated request or response for the same message starts
+		// a new call.
+		j, ok := calls[ev.MessageId]
+		if !ok || (ev.IsResponse && conv.Calls[j].Response != nil) || (!ev.IsResponse && conv.Calls[j].Request != nil) {
+			j = len(conv.Calls)
+			calls[ev.MessageId] = j
+			conv.Calls = append(conv.Calls, auditCall{MessageId: ev.MessageId})
+		}
+		if ev.IsResponse {
+			conv.Calls[j].Response = ev
+		} else {
+			conv.Calls[j].Request = ev
+		}
+	}
+	return convs
+}
+
 func formatTabular(writer io.Writer, value interface{}) error {
+	if convs, ok := value.(auditConversations); ok {
+		return formatConversationsTabular(writer, convs)
+	}
 	e, ok := value.(apiparams.AuditEvents)
 	if !ok {
 		return errors.E(fmt.Sprintf("expected value of type %T, got %T", e, value))
@@ -135,3 +225,36 @@ func formatTabular(writer io.Writer, value interface{}) error {
 	fmt.Fprint(writer, table)
 	return nil
 }
+
+func formatConversationsTabular(writer io.Writer, convs auditConversations) error {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("ConversationId", "MessageId", "Time", "User", "Model", "Facade", "Method", "Params", "Errors")
+	for _, conv := range convs.Conversations {
+		for _, call := range conv.Calls {
+			var ev apiparams.AuditEvent
+			if call.Request != nil {
+				ev = *call.Request
+			} else if call.Response != nil {
+				ev = *call.Response
+			}
+			var paramsJSON, errorJSON []byte
+			var err error
+			if call.Request != nil {
+				if paramsJSON, err = json.Marshal(call.Request.Params); err != nil {
+					return errors.E(err)
+				}
+			}
+			if call.Response != nil {
+				if errorJSON, err = json.Marshal(call.Response.Errors); err != nil {
+					return errors.E(err)
+				}
+			}
+			table.AddRow(conv.ConversationId, call.MessageId, ev.Time, ev.UserTag, ev.Model, ev.FacadeName, ev.FacadeMethod, string(paramsJSON), string(errorJSON))
+		}
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
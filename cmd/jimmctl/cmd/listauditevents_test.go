@@ -70,3 +70,40 @@ func (s *listAuditEventsSuite) TestListAuditEventsStatus(c *gc.C) {
 	_, err := cmdtesting.RunCommand(c, cmd.NewListAuditEventsCommandForTesting(s.ClientStore(), bClient))
 	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
 }
+
+func (s *listAuditEventsSuite) TestListAuditEventsConversations(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewListAuditEventsCommandForTesting(s.ClientStore(), bClient), "--facade", "Admin", "--conversations")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Matches,
+		`conversations:
+- conversation-id: .*
+  calls:
+  - message-id: 1
+    request:
+      time: .*
+      conversation-id: .*
+      message-id: 1
+      facade-name: Admin
+      facade-method: LoginWithSessionToken
+      facade-version: \d
+      user-tag: user-
+      is-response: false
+      params:
+        params: redacted
+    response:
+      time: .*
+      conversation-id: .*
+      message-id: 1
+      facade-name: Admin
+      facade-method: LoginWithSessionToken
+      facade-version: \d
+      user-tag: user-alice@canonical.com
+      is-response: true
+      errors:
+        results:
+        - error:
+            code: ""
+            message: ""
+[\s\S]*`)
+}
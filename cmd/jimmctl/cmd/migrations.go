@@ -0,0 +1,155 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var migrationsCommandDoc = `
+	migrations command displays the history of model migrations between
+	controllers, most recent first. If a migration ID is specified the
+	status of that migration is displayed.
+
+	Example:
+		jimmctl migrations
+		jimmctl migrations --active
+		jimmctl migrations --model <model-uuid> --format tabular
+		jimmctl migrations <migration-id>
+`
+
+// NewMigrationsCommand returns a command to display model migrations.
+func NewMigrationsCommand() cmd.Command {
+	cmd := &migrationsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// migrationsCommand displays model migrations.
+type migrationsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	migrationID string
+	model       string
+	args        apiparams.ListMigrationsRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *migrationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "migrations",
+		Args:    "[<migration-id>]",
+		Purpose: "Displays model migrations",
+		Doc:     migrationsCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *migrationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMigrationsTabular,
+	})
+	f.StringVar(&c.model, "model", "", "display migrations of the model with the specified uuid")
+	f.BoolVar(&c.args.Active, "active", false, "only display migrations that are in progress")
+	f.IntVar(&c.args.Offset, "offset", 0, "offset the set of returned migrations")
+	f.IntVar(&c.args.Limit, "limit", 0, "limit the maximum number of returned migrations")
+}
+
+// Init implements the cmd.Command interface.
+func (c *migrationsCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		c.migrationID = args[0]
+	}
+	if c.model != "" {
+		if !names.IsValidModel(c.model) {
+			return errors.E("invalid model uuid")
+		}
+		c.args.ModelTag = names.NewModelTag(c.model).String()
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *migrationsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	var resp apiparams.ListMigrationsResponse
+	if c.migrationID != "" {
+		migration, err := client.MigrationStatus(&apiparams.MigrationStatusRequest{
+			MigrationID: c.migrationID,
+		})
+		if err != nil {
+			return errors.E(err)
+		}
+		resp.Migrations = []apiparams.Migration{migration}
+	} else {
+		resp, err = client.ListMigrations(&c.args)
+		if err != nil {
+			return errors.E(err)
+		}
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatMigrationsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(apiparams.ListMigrationsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Migration", "Model", "Source", "Target", "Requester", "Phase", "Started", "Ended", "Message")
+	for _, m := range resp.Migrations {
+		var ended string
+		if m.EndedAt != nil {
+			ended = m.EndedAt.Format(time.RFC3339)
+		}
+		table.AddRow(m.MigrationID, m.ModelName, m.SourceController, m.TargetController, m.Requester, m.Phase, m.StartedAt.Format(time.RFC3339), ended, m.Message)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
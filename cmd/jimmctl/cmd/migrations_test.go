@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type migrationsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&migrationsSuite{})
+
+func (s *migrationsSuite) TestMigrationsSuperuser(c *gc.C) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := s.JIMM.Database.AddMigration(context.Background(), &dbmodel.Migration{
+		MigrationID:      "00000002-0000-0000-0000-000000000001:0",
+		ModelUUID:        "00000002-0000-0000-0000-000000000001",
+		ModelName:        "model-1",
+		SourceController: "controller-1",
+		TargetController: "controller-2",
+		Requester:        "alice@canonical.com",
+		Phase:            dbmodel.MigrationPhaseRunning,
+		Message:          "importing",
+		StartedAt:        started,
+	})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	expected := `migrations:
+- migration-id: 00000002-0000-0000-0000-000000000001:0
+  model-uuid: 00000002-0000-0000-0000-000000000001
+  model-name: model-1
+  source-controller: controller-1
+  target-controller: controller-2
+  requester: alice@canonical.com
+  phase: running
+  message: importing
+  started-at: 2024-01-02T03:04:05Z
+`
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient), "--active")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, expected)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient), "00000002-0000-0000-0000-000000000001:0")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, expected)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient), "--model", "00000002-0000-0000-0000-000000000002")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "migrations: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient), "no-such-migration")
+	c.Assert(err, gc.ErrorMatches, `migration not found`)
+}
+
+func (s *migrationsSuite) TestMigrations(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *migrationsSuite) TestMigrationsInvalidModel(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewMigrationsCommandForTesting(s.ClientStore(), bClient), "--model", "not-a-uuid")
+	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+}
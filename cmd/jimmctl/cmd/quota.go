@@ -0,0 +1,316 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	quotaDoc = `
+quota command enables management of model quotas in jimm.
+`
+
+	setQuotaDoc = `
+set command sets the limits of a quota for a user or group on a cloud.
+Only the limits specified are enforced, any existing limits are replaced.
+
+Quotas are only checked when a model is created. Creating a model is
+refused once the usage reported by the watcher has reached the machine,
+core or unit limits, but machines and units added to existing models are
+not limited, so usage may grow beyond these limits. The check is not
+atomic with model creation, so models created concurrently may also
+exceed the model limit.
+
+Example:
+	jimmctl quota set user-alice@canonical.com aws --max-models 5
+	jimmctl quota set group-team-a aws --region us-east-1 --max-machines 20 --max-cores 80
+`
+
+	removeQuotaDoc = `
+remove command removes a quota for a user or group on a cloud.
+
+Example:
+	jimmctl quota remove user-alice@canonical.com aws
+	jimmctl quota remove group-team-a aws --region us-east-1
+`
+
+	listQuotasDoc = `
+list command lists all quotas in jimm along with their current usage.
+
+Example:
+	jimmctl quota list
+`
+)
+
+// NewQuotaCommand returns a command for quota management.
+func NewQuotaCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "quota",
+		Doc:     quotaDoc,
+		Purpose: "Quota management.",
+	})
+	cmd.Register(newSetQuotaCommand())
+	cmd.Register(newRemoveQuotaCommand())
+	cmd.Register(newListQuotasCommand())
+
+	return cmd
+}
+
+// newSetQuotaCommand returns a command to set a quota.
+func newSetQuotaCommand() cmd.Command {
+	cmd := &setQuotaCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setQuotaCommand sets a quota.
+type setQuotaCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	subject     string
+	cloud       string
+	region      string
+	maxModels   int64
+	maxMachines int64
+	maxCores    int64
+	maxUnits    int64
+}
+
+// Info implements the cmd.Command interface.
+func (c *setQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set",
+		Args:    "<user|group> <cloud>",
+		Purpose: "Set a quota.",
+		Doc:     setQuotaDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setQuotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.region, "region", "", "cloud region the quota applies to, defaults to all regions")
+	f.Int64Var(&c.maxModels, "max-models", -1, "maximum number of models")
+	f.Int64Var(&c.maxMachines, "max-machines", -1, "maximum number of machines across all models")
+	f.Int64Var(&c.maxCores, "max-cores", -1, "maximum number of cores across all models")
+	f.Int64Var(&c.maxUnits, "max-units", -1, "maximum number of units across all models")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setQuotaCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("subject and cloud not specified")
+	}
+	c.subject, c.cloud, args = args[0], args[1], args[2:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidCloud(c.cloud) {
+		return errors.E("invalid cloud name")
+	}
+	if c.maxModels < 0 && c.maxMachines < 0 && c.maxCores < 0 && c.maxUnits < 0 {
+		return errors.E("no limits specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setQuotaCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.SetQuota(&apiparams.SetQuotaRequest{
+		Subject:  c.subject,
+		CloudTag: names.NewCloudTag(c.cloud).String(),
+		Region:   c.region,
+		Limits: apiparams.QuotaLimits{
+			MaxModels:   quotaLimit(c.maxModels),
+			MaxMachines: quotaLimit(c.maxMachines),
+			MaxCores:    quotaLimit(c.maxCores),
+			MaxUnits:    quotaLimit(c.maxUnits),
+		},
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// quotaLimit converts a limit flag value into a quota limit, negative
+// values mean the limit was not specified.
+func quotaLimit(v int64) *int64 {
+	if v < 0 {
+		return nil
+	}
+	return &v
+}
+
+// newRemoveQuotaCommand returns a command to remove a quota.
+func newRemoveQuotaCommand() cmd.Command {
+	cmd := &removeQuotaCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeQuotaCommand removes a quota.
+type removeQuotaCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	subject string
+	cloud   string
+	region  string
+}
+
+// Info implements the cmd.Command interface.
+func (c *removeQuotaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove",
+		Args:    "<user|group> <cloud>",
+		Purpose: "Remove a quota.",
+		Doc:     removeQuotaDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *removeQuotaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.region, "region", "", "cloud region the quota applies to")
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeQuotaCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("subject and cloud not specified")
+	}
+	c.subject, c.cloud, args = args[0], args[1], args[2:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if !names.IsValidCloud(c.cloud) {
+		return errors.E("invalid cloud name")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *removeQuotaCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.RemoveQuota(&apiparams.RemoveQuotaRequest{
+		Subject:  c.subject,
+		CloudTag: names.NewCloudTag(c.cloud).String(),
+		Region:   c.region,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// newListQuotasCommand returns a command to list all quotas.
+func newListQuotasCommand() cmd.Command {
+	cmd := &listQuotasCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listQuotasCommand lists all quotas.
+type listQuotasCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements the cmd.Command interface.
+func (c *listQuotasCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "list",
+		Purpose: "List all quotas.",
+		Doc:     listQuotasDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listQuotasCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listQuotasCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listQuotasCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	quotas, err := client.ListQuotas()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, quotas)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
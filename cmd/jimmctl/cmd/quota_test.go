@@ -0,0 +1,68 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type quotaSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&quotaSuite{})
+
+func (s *quotaSuite) TestSetListRemoveQuotaSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetQuotaCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", jimmtest.TestCloudName, "--max-models", "3", "--max-units", "10")
+	c.Assert(err, gc.IsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewListQuotasCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `- subject: user-bob@canonical.com
+  cloud: `+jimmtest.TestCloudName+`
+  limits:
+    max-models: 3
+    max-units: 10
+  usage:
+    models: 0
+    machines: 0
+    cores: 0
+    units: 0
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveQuotaCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", jimmtest.TestCloudName)
+	c.Assert(err, gc.IsNil)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewListQuotasCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "[]\n")
+}
+
+func (s *quotaSuite) TestSetQuota(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetQuotaCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", jimmtest.TestCloudName, "--max-models", "3")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *quotaSuite) TestSetQuotaNoLimits(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetQuotaCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", jimmtest.TestCloudName)
+	c.Assert(err, gc.ErrorMatches, `no limits specified`)
+}
+
+func (s *quotaSuite) TestListQuotas(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListQuotasCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
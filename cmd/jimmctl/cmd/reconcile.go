@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var reconcileCommandDoc = `
+	reconcile command compares the models, application offers and clouds
+	held by jimm, and their relations in OpenFGA, with those reported by
+	the controllers and displays any discrepancies found. If a controller
+	name is specified only that controller is reconciled.
+
+	If --fix is specified jimm attempts to fix the discrepancies found by
+	bringing its own state in line with the controllers.
+
+	Example:
+		jimmctl reconcile
+		jimmctl reconcile <controller name> --format tabular
+		jimmctl reconcile <controller name> --fix
+`
+
+// NewReconcileCommand returns a command to reconcile jimm with the
+// controllers.
+func NewReconcileCommand() cmd.Command {
+	cmd := &reconcileCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// reconcileCommand reconciles jimm with the controllers.
+type reconcileCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.ReconcileRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *reconcileCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "reconcile",
+		Args:    "[<controller name>]",
+		Purpose: "Reconcile jimm with the controllers",
+		Doc:     reconcileCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *reconcileCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatReconcileTabular,
+	})
+	f.BoolVar(&c.req.Fix, "fix", false, "fix the discrepancies found")
+}
+
+// Init implements the cmd.Command interface.
+func (c *reconcileCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		c.req.Controller = args[0]
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *reconcileCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.Reconcile(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatReconcileTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(apiparams.ReconcileResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Kind", "Tag", "Fixed", "Description")
+	for _, report := range resp.Reports {
+		if report.Error != "" {
+			table.AddRow(report.Controller, "error", "", "", report.Error)
+		}
+		for _, d := range report.Discrepancies {
+			description := d.Description
+			if d.FixError != "" {
+				description = fmt.Sprintf("%s (fix failed: %s)", description, d.FixError)
+			}
+			table.AddRow(report.Controller, d.Kind, d.Tag, d.Fixed, description)
+		}
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
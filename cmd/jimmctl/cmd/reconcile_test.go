@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jjcloud "github.com/juju/juju/cloud"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type reconcileSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&reconcileSuite{})
+
+func (s *reconcileSuite) TestReconcileSuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+
+	err := s.BackingState.UpdateCloudCredential(cct, jjcloud.NewCredential(jjcloud.EmptyAuthType, nil))
+	c.Assert(err, gc.Equals, nil)
+
+	// Create a model directly on the controller.
+	m := s.Factory.MakeModel(c, &factory.ModelParams{
+		Name:            "model-2",
+		Owner:           names.NewUserTag("charlie@canonical.com"),
+		CloudName:       jimmtest.TestCloudName,
+		CloudRegion:     jimmtest.TestCloudRegionName,
+		CloudCredential: cct,
+	})
+	defer m.Close()
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewReconcileCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)reports:\n- controller: controller-1\n  discrepancies:\n.*- kind: model-not-in-jimm\n    tag: model-`+m.ModelUUID()+`\n    description: model "model-2" is not known to JIMM\n    fixed: false\n.*`)
+
+	var model dbmodel.Model
+	model.SetTag(names.NewModelTag(m.ModelUUID()))
+	err = s.JIMM.Database.GetModel(context.Background(), &model)
+	c.Assert(err, gc.ErrorMatches, `model not found`)
+
+	ctx, err = cmdtesting.RunCommand(c, cmd.NewReconcileCommandForTesting(s.ClientStore(), bClient), "controller-1", "--fix")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s).*- kind: model-not-in-jimm\n    tag: model-`+m.ModelUUID()+`\n    description: model "model-2" is not known to JIMM\n    fixed: true\n.*`)
+
+	err = s.JIMM.Database.GetModel(context.Background(), &model)
+	c.Assert(err, gc.IsNil)
+	c.Check(model.OwnerIdentityName, gc.Equals, "charlie@canonical.com")
+}
+
+func (s *reconcileSuite) TestReconcileUnauthorized(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewReconcileCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *reconcileSuite) TestReconcileTooManyArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewReconcileCommandForTesting(s.ClientStore(), bClient), "controller-1", "spare-argument")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/gosuri/uitable"
 	"github.com/juju/cmd/v3"
@@ -17,6 +18,7 @@ import (
 	"github.com/juju/juju/jujuclient"
 
 	"github.com/canonical/jimm/v3/internal/errors"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/pkg/api"
 	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
@@ -95,11 +97,16 @@ add command adds relation to jimm.
 Example:
 	jimmctl auth relation add <object> <relation> <target_object>
 	jimmctl auth relation add -f <filename>
+
+The --expires-in or --expires-at flags may be used to add temporary
+relations that are removed automatically once they expire.
 ` + genericConstraintsDoc +
 		`
 Examples:
 jimmctl auth relation add user-Alice member group-MyGroup
 jimmctl auth relation add group-MyTeam#member loginer controller-MyController
+jimmctl auth relation add user-Bob administrator model-MyModel --expires-in 4h
+jimmctl auth relation add user-Bob reader model-MyModel --expires-at 2024-06-01T18:00:00Z
 `
 
 	removeRelationDoc = `
@@ -117,8 +124,14 @@ jimmctl auth relation remove group-MyTeam#member loginer controller-MyController
 	checkRelationDoc = `
 Verifies the access between resources.
 
+The --explain flag additionally shows, as a tree, every chain of
+relations through which the access is granted, expanding group
+membership and the administrator relations inherited from controllers
+and models.
+
 Example:
 jimmctl auth relation check user-alice@canonical.com administrator controller-aws-controller-1
+jimmctl auth relation check --explain user-alice@canonical.com writer model-alice@canonical.com/prod
 
 Example:
 	jimmctl auth relation check <object> <relation> <target_object>
@@ -179,7 +192,11 @@ type addRelationCommand struct {
 	relation     string
 	targetObject string
 
-	filename string // optional
+	filename  string        // optional
+	expiresIn time.Duration // optional
+	expiresAt string        // optional
+
+	expiry time.Time
 }
 
 // Info implements the cmd.Command interface.
@@ -193,6 +210,19 @@ func (c *addRelationCommand) Info() *cmd.Info {
 
 // Init implements the cmd.Command interface.
 func (c *addRelationCommand) Init(args []string) error {
+	if c.expiresAt != "" {
+		if c.expiresIn != 0 {
+			return errors.E("cannot specify both --expires-in and --expires-at")
+		}
+		t, err := time.Parse(time.RFC3339, c.expiresAt)
+		if err != nil {
+			return errors.E(err, "invalid --expires-at value, expected RFC3339 time")
+		}
+		c.expiry = t
+	}
+	if c.expiresIn < 0 {
+		return errors.E("--expires-in must be positive")
+	}
 	if c.filename != "" {
 		return nil
 	}
@@ -212,6 +242,8 @@ func (c *addRelationCommand) SetFlags(f *gnuflag.FlagSet) {
 		"json": cmd.FormatJson,
 	})
 	f.StringVar(&c.filename, "f", "", "file location of JSON encoded tuples")
+	f.DurationVar(&c.expiresIn, "expires-in", 0, "remove the relations after the given duration")
+	f.StringVar(&c.expiresAt, "expires-at", "", "remove the relations at the given RFC3339 time")
 }
 
 // Run implements Command.Run.
@@ -239,6 +271,13 @@ func (c *addRelationCommand) Run(ctxt *cmd.Context) error {
 			return err
 		}
 	}
+	switch {
+	case c.expiresIn > 0:
+		expiresAt := time.Now().Add(c.expiresIn)
+		params.ExpiresAt = &expiresAt
+	case !c.expiry.IsZero():
+		params.ExpiresAt = &c.expiry
+	}
 
 	client := api.NewClient(apiCaller)
 	err = client.AddRelation(&params)
@@ -347,7 +386,8 @@ type checkRelationCommand struct {
 	store    jujuclient.ClientStore
 	dialOpts *jujuapi.DialOpts
 
-	tuple apiparams.RelationshipTuple
+	tuple   apiparams.RelationshipTuple
+	explain bool
 }
 
 // accessResult holds the accessCheck result to be passed to a formatter
@@ -355,6 +395,7 @@ type accessResult struct {
 	Msg     string                      `yaml:"result" json:"result"`
 	Tuple   apiparams.RelationshipTuple `yaml:"tuple" json:"tuple"`
 	Allowed bool                        `yaml:"allowed" json:"allowed"`
+	Paths   []apiparams.RelationPath    `yaml:"paths,omitempty" json:"paths,omitempty"`
 }
 
 func (ar *accessResult) setMessage() *accessResult {
@@ -394,6 +435,7 @@ func (c *checkRelationCommand) SetFlags(f *gnuflag.FlagSet) {
 		"json":  cmd.FormatJson,
 		"yaml":  cmd.FormatYaml,
 	})
+	f.BoolVar(&c.explain, "explain", false, "show the chains of relations through which access is granted")
 }
 
 // Init implements the cmd.Command interface.
@@ -419,9 +461,68 @@ func formatCheckRelationString(writer io.Writer, value interface{}) error {
 	if err != nil {
 		return errors.E("failed to write access result", err)
 	}
+	if len(accessResult.Paths) == 0 {
+		return nil
+	}
+	fmt.Fprintf(writer, "\n%s\n", accessResult.Tuple.Object)
+	newRelationTree(accessResult.Tuple, accessResult.Paths).write(writer, "")
 	return nil
 }
 
+// relationTreeNode is a node in the tree of relations through which a
+// relation check is allowed.
+type relationTreeNode struct {
+	label    string
+	children []*relationTreeNode
+}
+
+// newRelationTree returns a tree, rooted at the object of the given
+// tuple, holding the given paths. Paths sharing the same initial steps
+// share the same branch.
+func newRelationTree(tuple apiparams.RelationshipTuple, paths []apiparams.RelationPath) *relationTreeNode {
+	root := new(relationTreeNode)
+	for _, p := range paths {
+		n := root
+		if p.Identity == "user-"+ofganames.EveryoneUser {
+			n = n.child("as " + p.Identity)
+		}
+		for i, step := range p.Steps {
+			label := step.Relation + " of " + step.Target
+			if i == len(p.Steps)-1 && step.Relation != tuple.Relation {
+				label += " (implies " + tuple.Relation + ")"
+			}
+			n = n.child(label)
+		}
+	}
+	return root
+}
+
+// child returns the child of the node with the given label, adding it if
+// necessary.
+func (n *relationTreeNode) child(label string) *relationTreeNode {
+	for _, c := range n.children {
+		if c.label == label {
+			return c
+		}
+	}
+	c := &relationTreeNode{label: label}
+	n.children = append(n.children, c)
+	return c
+}
+
+// write writes the descendants of the node, one per line, with each line
+// starting with the given prefix.
+func (n *relationTreeNode) write(w io.Writer, prefix string) {
+	for i, c := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, c.label)
+		c.write(w, prefix+indent)
+	}
+}
+
 // Run implements Command.Run.
 func (c *checkRelationCommand) Run(ctxt *cmd.Context) error {
 	currentController, err := c.store.CurrentController()
@@ -435,6 +536,20 @@ func (c *checkRelationCommand) Run(ctxt *cmd.Context) error {
 	}
 	client := api.NewClient(apiCaller)
 
+	if c.explain {
+		resp, err := client.ExplainRelation(&apiparams.ExplainRelationRequest{
+			Tuple: c.tuple,
+		})
+		if err != nil {
+			return err
+		}
+		return c.out.Write(ctxt, *(&accessResult{
+			Tuple:   c.tuple,
+			Allowed: resp.Allowed,
+			Paths:   resp.Paths,
+		}).setMessage())
+	}
+
 	resp, err := client.CheckRelation(&apiparams.CheckRelationRequest{
 		Tuple: c.tuple,
 	})
@@ -156,6 +156,38 @@ func (s *relationSuite) TestAddRelationRejectsUnauthorisedUsers(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
 }
 
+func (s *relationSuite) TestAddTemporaryRelation(c *gc.C) {
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx := context.Background()
+
+	_, err := s.JimmCmdSuite.JIMM.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, gc.IsNil)
+
+	before := time.Now()
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-test-group", "--expires-in", "4h")
+	c.Assert(err, gc.IsNil)
+
+	var expiries []dbmodel.RelationExpiry
+	err = s.JimmCmdSuite.JIMM.Database.ForEachRelationExpiry(ctx, time.Time{}, func(re *dbmodel.RelationExpiry) error {
+		expiries = append(expiries, *re)
+		return nil
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(expiries, gc.HasLen, 1)
+	c.Check(expiries[0].Object, gc.Equals, "user:bob@canonical.com")
+	c.Check(expiries[0].Relation, gc.Equals, "member")
+	c.Check(expiries[0].GrantedBy, gc.Equals, "alice@canonical.com")
+	c.Check(expiries[0].ExpiresAt.Before(before.Add(4*time.Hour)), gc.Equals, false)
+	c.Check(expiries[0].ExpiresAt.After(time.Now().Add(4*time.Hour)), gc.Equals, false)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-test-group", "--expires-in", "4h", "--expires-at", "2030-01-01T00:00:00Z")
+	c.Assert(err, gc.ErrorMatches, `cannot specify both --expires-in and --expires-at`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddRelationCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "member", "group-test-group", "--expires-at", "tomorrow")
+	c.Assert(err, gc.ErrorMatches, `invalid --expires-at value, expected RFC3339 time`)
+}
+
 func (s *relationSuite) TestRemoveRelationSuperuser(c *gc.C) {
 	// alice is superuser
 	bClient := jimmtest.NewUserSessionLogin(c, "alice")
@@ -672,3 +704,43 @@ func (s *relationSuite) TestCheckRelation(c *gc.C) {
 	)
 	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
 }
+
+func (s *relationSuite) TestCheckRelationExplain(c *gc.C) {
+	ctx := context.Background()
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+
+	group, err := s.JIMM.Database.AddGroup(ctx, "viewers")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.AuditLogViewerRelation,
+		Target:   ofganames.ConvertTag(s.JIMM.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewCheckRelationCommandForTesting(s.ClientStore(), bClient), "--explain", "user-bob@canonical.com", "audit_log_viewer", "controller-jimm")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Equals, `access check for user-bob@canonical.com on resource controller-jimm with role audit_log_viewer is allowed
+user-bob@canonical.com
+└── member of group-viewers
+    └── audit_log_viewer of controller-jimm
+`)
+
+	s.RefreshControllerAddress(c)
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewCheckRelationCommandForTesting(s.ClientStore(), bClient), "--explain", "user-alice@canonical.com", "audit_log_viewer", "controller-jimm")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Equals, `access check for user-alice@canonical.com on resource controller-jimm with role audit_log_viewer is allowed
+user-alice@canonical.com
+└── administrator of controller-jimm (implies audit_log_viewer)
+`)
+
+	s.RefreshControllerAddress(c)
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewCheckRelationCommandForTesting(s.ClientStore(), bClient), "--explain", "user-bob@canonical.com", "administrator", "controller-jimm")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Equals, `access check for user-bob@canonical.com on resource controller-jimm with role administrator is not allowed`)
+}
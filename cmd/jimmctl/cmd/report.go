@@ -0,0 +1,165 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	jujucmdv3 "github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	reportDoc = `
+report command enables reporting on the resources managed by jimm.
+`
+
+	staleReportDoc = `
+stale command reports the identities that have not logged in, and the
+models that have had no activity, for the given number of days, along with
+the machines, cores and units they use.
+
+Identities that have never logged in, and models in which no activity has
+been seen, are reported once they are older than the given number of days.
+
+Example:
+	jimmctl report stale
+	jimmctl report stale --days 90 --format tabular
+`
+)
+
+// NewReportCommand returns a command for reports.
+func NewReportCommand() *jujucmdv3.SuperCommand {
+	cmd := jujucmd.NewSuperCommand(jujucmdv3.SuperCommandParams{
+		Name:    "report",
+		Doc:     reportDoc,
+		Purpose: "Reports.",
+	})
+	cmd.Register(newStaleReportCommand())
+
+	return cmd
+}
+
+// newStaleReportCommand returns a command to report inactive identities
+// and stale models.
+func newStaleReportCommand() cmd.Command {
+	cmd := &staleReportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// staleReportCommand reports inactive identities and stale models.
+type staleReportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.StaleReportRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *staleReportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "stale",
+		Purpose: "Report inactive identities and stale models.",
+		Doc:     staleReportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *staleReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStaleReportTabular,
+	})
+	f.IntVar(&c.req.Days, "days", 30, "number of days without a login or model activity")
+}
+
+// Init implements the cmd.Command interface.
+func (c *staleReportCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.req.Days <= 0 {
+		return errors.E("days must be positive")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *staleReportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.StaleReport(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, *resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatStaleReportTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(apiparams.StaleReportResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	identities := uitable.New()
+	identities.MaxColWidth = 50
+	identities.Wrap = true
+
+	identities.AddRow("Identity", "Last login", "Disabled", "Models", "Machines", "Cores", "Units")
+	for _, i := range resp.Identities {
+		var lastLogin string
+		if i.LastLogin != nil {
+			lastLogin = i.LastLogin.Format(time.RFC3339)
+		}
+		identities.AddRow(i.Name, lastLogin, i.Disabled, i.Footprint.Models, i.Footprint.Machines, i.Footprint.Cores, i.Footprint.Units)
+	}
+
+	models := uitable.New()
+	models.MaxColWidth = 50
+	models.Wrap = true
+
+	models.AddRow("Model", "UUID", "Owner", "Controller", "Last activity", "Machines", "Cores", "Units")
+	for _, m := range resp.Models {
+		var lastActivity string
+		if m.LastActivity != nil {
+			lastActivity = m.LastActivity.Format(time.RFC3339)
+		}
+		models.AddRow(m.Name, m.UUID, m.Owner, m.Controller, lastActivity, m.Footprint.Machines, m.Footprint.Cores, m.Footprint.Units)
+	}
+	fmt.Fprintf(writer, "%s\n\n%s", identities, models)
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type reportSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&reportSuite{})
+
+func (s *reportSuite) TestStaleReport(c *gc.C) {
+	ctx := context.Background()
+
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	charlie := dbmodel.Identity{Name: "charlie@canonical.com"}
+	err := s.JIMM.Database.FetchIdentity(ctx, &charlie)
+	c.Assert(err, gc.IsNil)
+	charlie.LastLogin = sql.NullTime{Time: time.Now().AddDate(0, 0, -60), Valid: true}
+	err = s.JIMM.Database.UpdateIdentity(ctx, &charlie)
+	c.Assert(err, gc.IsNil)
+
+	var model dbmodel.Model
+	model.SetTag(mt)
+	err = s.JIMM.Database.GetModel(ctx, &model)
+	c.Assert(err, gc.IsNil)
+	model.LastActivity = sql.NullTime{Time: time.Now().AddDate(0, 0, -60), Valid: true}
+	model.Units = 2
+	err = s.JIMM.Database.UpdateModel(ctx, &model)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewStaleReportCommandForTesting(s.ClientStore(), bClient), "--days", "30")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `before: .*
+identities:
+- name: charlie@canonical.com
+  display-name: charlie
+  disabled: false
+  created: .*
+  last-login: .*
+  footprint:
+    models: 1
+    machines: 0
+    cores: 0
+    units: 2
+models:
+- name: model-2
+  uuid: `+mt.Id()+`
+  controller: controller-1
+  cloud: `+jimmtest.TestCloudName+`
+  region: `+jimmtest.TestCloudRegionName+`
+  owner: charlie@canonical.com
+  last-activity: .*
+  footprint:
+    models: 1
+    machines: 0
+    cores: 0
+    units: 2
+`)
+
+	s.RefreshControllerAddress(c)
+	context, err = cmdtesting.RunCommand(c, cmd.NewStaleReportCommandForTesting(s.ClientStore(), bClient), "--days", "90", "--format", "tabular")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `Identity\s+Last login\s+Disabled\s+Models\s+Machines\s+Cores\s+Units
+
+Model\s+UUID\s+Owner\s+Controller\s+Last activity\s+Machines\s+Cores\s+Units\s*
+`)
+}
+
+func (s *reportSuite) TestStaleReportUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewStaleReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *reportSuite) TestStaleReportInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewStaleReportCommandForTesting(s.ClientStore(), bClient), "--days", "0")
+	c.Check(err, gc.ErrorMatches, `days must be positive`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewStaleReportCommandForTesting(s.ClientStore(), bClient), "extra")
+	c.Check(err, gc.ErrorMatches, `too many args`)
+}
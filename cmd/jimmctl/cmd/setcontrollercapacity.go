@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var setControllerCapacityDoc = `
+	set-controller-capacity sets the capacity caps of a controller. JIMM
+	will not place new models on a controller hosting max-models models,
+	running max-units units or running max-machines machines. Any cap that
+	is not specified is removed.
+
+	Example:
+		jimmctl set-controller-capacity <name> --max-models 100 --max-units 1000 --max-machines 300
+`
+
+// NewSetControllerCapacityCommand returns a command used to set the
+// capacity caps of a controller.
+func NewSetControllerCapacityCommand() cmd.Command {
+	cmd := &setControllerCapacityCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setControllerCapacityCommand sets the capacity caps of a controller.
+type setControllerCapacityCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controllerName string
+	maxModels      int64
+	maxUnits       int64
+	maxMachines    int64
+}
+
+func (c *setControllerCapacityCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-controller-capacity",
+		Args:    "<name>",
+		Purpose: "Sets controller capacity caps.",
+		Doc:     setControllerCapacityDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setControllerCapacityCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.Int64Var(&c.maxModels, "max-models", -1, "maximum number of models placed on the controller")
+	f.Int64Var(&c.maxUnits, "max-units", -1, "maximum number of units running on the controller")
+	f.Int64Var(&c.maxMachines, "max-machines", -1, "maximum number of machines running on the controller")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setControllerCapacityCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("missing controller name")
+	}
+	c.controllerName, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setControllerCapacityCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+
+	req := apiparams.SetControllerCapacityRequest{
+		Name: c.controllerName,
+	}
+	if c.maxModels >= 0 {
+		req.MaxModels = &c.maxModels
+	}
+	if c.maxUnits >= 0 {
+		req.MaxUnits = &c.maxUnits
+	}
+	if c.maxMachines >= 0 {
+		req.MaxMachines = &c.maxMachines
+	}
+	info, err := client.SetControllerCapacity(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, info)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
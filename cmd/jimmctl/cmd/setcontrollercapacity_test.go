@@ -0,0 +1,59 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type setControllerCapacitySuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&setControllerCapacitySuite{})
+
+func (s *setControllerCapacitySuite) TestSetControllerCapacitySuperuser(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewSetControllerCapacityCommandForTesting(s.ClientStore(), bClient), "controller-1", "--max-models", "10", "--max-units", "100", "--max-machines", "30")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)name: controller-1\n.*`)
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = s.JIMM.Database.GetController(context.Background(), &ctl)
+	c.Assert(err, gc.IsNil)
+	c.Check(ctl.MaxModels, gc.Equals, sql.NullInt64{Int64: 10, Valid: true})
+	c.Check(ctl.MaxUnits, gc.Equals, sql.NullInt64{Int64: 100, Valid: true})
+	c.Check(ctl.MaxMachines, gc.Equals, sql.NullInt64{Int64: 30, Valid: true})
+
+	// Caps that are not specified are removed.
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetControllerCapacityCommandForTesting(s.ClientStore(), bClient), "controller-1", "--max-units", "50")
+	c.Assert(err, gc.IsNil)
+
+	ctl = dbmodel.Controller{Name: "controller-1"}
+	err = s.JIMM.Database.GetController(context.Background(), &ctl)
+	c.Assert(err, gc.IsNil)
+	c.Check(ctl.MaxModels, gc.Equals, sql.NullInt64{})
+	c.Check(ctl.MaxUnits, gc.Equals, sql.NullInt64{Int64: 50, Valid: true})
+	c.Check(ctl.MaxMachines, gc.Equals, sql.NullInt64{})
+}
+
+func (s *setControllerCapacitySuite) TestSetControllerCapacity(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetControllerCapacityCommandForTesting(s.ClientStore(), bClient), "controller-1", "--max-models", "10")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
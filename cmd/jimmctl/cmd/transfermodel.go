@@ -0,0 +1,131 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const transferModelCommandDoc = `
+	transfer-model transfers the ownership of a model to another identity.
+
+	The model is switched to use a cloud credential owned by the new owner.
+	The --credential flag selects the credential to use, otherwise the first,
+	by name, of the new owner's credentials for the model's cloud is used.
+
+	The transfer fails if the new owner already owns a model with the same
+	name. Only JIMM administrators, or model administrators taking over the
+	model themselves, may transfer a model.
+
+	Example:
+		jimmctl transfer-model <model-uuid> <new owner>
+		jimmctl transfer-model <model-uuid> <new owner> --credential <cloud>/<new owner>/<name>
+`
+
+// NewTransferModelCommand returns a command to transfer the ownership of
+// a model.
+func NewTransferModelCommand() cmd.Command {
+	cmd := &transferModelCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// transferModelCommand transfers the ownership of a model.
+type transferModelCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	credential string
+	req        apiparams.TransferModelOwnershipRequest
+}
+
+// Info implements the cmd.Command interface.
+func (c *transferModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "transfer-model",
+		Args:    "<model uuid> <new owner>",
+		Purpose: "Transfer the ownership of a model",
+		Doc:     transferModelCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *transferModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.credential, "credential", "", "cloud credential of the new owner the model will use")
+}
+
+// Init implements the cmd.Command interface.
+func (c *transferModelCommand) Init(args []string) error {
+	switch len(args) {
+	default:
+		return errors.E("too many args")
+	case 0:
+		return errors.E("model uuid not specified")
+	case 1:
+		return errors.E("new owner not specified")
+	case 2:
+	}
+
+	if !names.IsValidModel(args[0]) {
+		return errors.E("invalid model uuid")
+	}
+	c.req.ModelTag = names.NewModelTag(args[0]).String()
+	if !names.IsValidUser(args[1]) {
+		return errors.E(fmt.Sprintf("invalid identity %q", args[1]))
+	}
+	c.req.NewOwnerTag = names.NewUserTag(args[1]).String()
+	if c.credential != "" {
+		if !names.IsValidCloudCredential(c.credential) {
+			return errors.E(fmt.Sprintf("invalid cloud credential %q", c.credential))
+		}
+		c.req.CloudCredentialTag = names.NewCloudCredentialTag(c.credential).String()
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *transferModelCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	transfer, err := client.TransferModelOwnership(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, *transfer)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
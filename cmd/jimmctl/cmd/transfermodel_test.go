@@ -0,0 +1,94 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type transferModelSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&transferModelSuite{})
+
+func (s *transferModelSuite) TestTransferModel(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	bobCred := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/bob@canonical.com/cred")
+	s.UpdateCloudCredential(c, bobCred, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewTransferModelCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com", "--credential", bobCred.Id())
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `model:
+  name: model-2
+  uuid: `+mt.Id()+`
+  controller: controller-1
+  cloud: `+jimmtest.TestCloudName+`
+  region: `+jimmtest.TestCloudRegionName+`
+previous-owner: charlie@canonical.com
+new-owner: bob@canonical.com
+previous-cloud-credential: `+cct.Id()+`
+cloud-credential: `+bobCred.Id()+`
+`)
+
+	var model dbmodel.Model
+	model.SetTag(mt)
+	err = s.JIMM.Database.GetModel(context.Background(), &model)
+	c.Assert(err, gc.IsNil)
+	c.Check(model.OwnerIdentityName, gc.Equals, "bob@canonical.com")
+	c.Check(model.CloudCredential.Path(), gc.Equals, bobCred.Id())
+}
+
+func (s *transferModelSuite) TestTransferModelUnauthorized(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.UpdateCloudCredential(c, names.NewCloudCredentialTag(jimmtest.TestCloudName+"/bob@canonical.com/cred"), jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// bob is not superuser
+	bClient := jimmtest.NewUserSessionLogin(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewTransferModelCommandForTesting(s.ClientStore(), bClient), mt.Id(), "bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *transferModelSuite) TestTransferModelInvalidArgs(c *gc.C) {
+	bClient := jimmtest.NewUserSessionLogin(c, "alice")
+	tests := []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "model uuid not specified",
+	}, {
+		args:        []string{"00000002-0000-0000-0000-000000000001"},
+		expectError: "new owner not specified",
+	}, {
+		args:        []string{"00000002-0000-0000-0000-000000000001", "bob@canonical.com", "extra"},
+		expectError: "too many args",
+	}, {
+		args:        []string{"not-a-uuid", "bob@canonical.com"},
+		expectError: "invalid model uuid",
+	}, {
+		args:        []string{"00000002-0000-0000-0000-000000000001", "bob@canonical.com", "--credential", "cred"},
+		expectError: `invalid cloud credential "cred"`,
+	}}
+	for _, test := range tests {
+		_, err := cmdtesting.RunCommand(c, cmd.NewTransferModelCommandForTesting(s.ClientStore(), bClient), test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
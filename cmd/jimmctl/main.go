@@ -21,23 +21,33 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 		Doc:  jimmctlDoc,
 	})
 	jimmcmd.Register(cmd.NewAddControllerCommand())
+	jimmcmd.Register(cmd.NewControllerCommand())
 	jimmcmd.Register(cmd.NewControllerInfoCommand())
 	jimmcmd.Register(cmd.NewGrantAuditLogAccessCommand())
 	jimmcmd.Register(cmd.NewImportCloudCredentialsCommand())
 	jimmcmd.Register(cmd.NewImportModelCommand())
+	jimmcmd.Register(cmd.NewImportModelsCommand())
 	jimmcmd.Register(cmd.NewListAuditEventsCommand())
 	jimmcmd.Register(cmd.NewListControllersCommand())
 	jimmcmd.Register(cmd.NewModelStatusCommand())
 	jimmcmd.Register(cmd.NewRemoveControllerCommand())
 	jimmcmd.Register(cmd.NewRevokeAuditLogAccessCommand())
+	jimmcmd.Register(cmd.NewSetControllerCapacityCommand())
 	jimmcmd.Register(cmd.NewSetControllerDeprecatedCommand())
 	jimmcmd.Register(cmd.NewUpdateMigratedModelCommand())
 	jimmcmd.Register(cmd.NewAddCloudToControllerCommand())
 	jimmcmd.Register(cmd.NewRemoveCloudFromControllerCommand())
+	jimmcmd.Register(cmd.NewAuditCommand())
 	jimmcmd.Register(cmd.NewAuthCommand())
 	jimmcmd.Register(cmd.NewCrossModelQueryCommand())
 	jimmcmd.Register(cmd.NewPurgeLogsCommand())
 	jimmcmd.Register(cmd.NewMigrateModelCommand())
+	jimmcmd.Register(cmd.NewMigrationsCommand())
+	jimmcmd.Register(cmd.NewReconcileCommand())
+	jimmcmd.Register(cmd.NewQuotaCommand())
+	jimmcmd.Register(cmd.NewIdentityCommand())
+	jimmcmd.Register(cmd.NewTransferModelCommand())
+	jimmcmd.Register(cmd.NewReportCommand())
 	return jimmcmd
 }
 
@@ -128,6 +128,16 @@ func start(ctx context.Context, s *service.Service) error {
 		secureSessionCookies = true
 	}
 
+	auditAuthorizationDecisions := false
+	if _, ok := os.LookupEnv("JIMM_AUDIT_AUTHORIZATION_DECISIONS"); ok {
+		auditAuthorizationDecisions = true
+	}
+
+	auditLogHashChain := false
+	if _, ok := os.LookupEnv("JIMM_AUDIT_LOG_HASH_CHAIN"); ok {
+		auditLogHashChain = true
+	}
+
 	sessionCookieMaxAge := os.Getenv("JIMM_SESSION_COOKIE_MAX_AGE")
 	sessionCookieMaxAgeInt, err := strconv.Atoi(sessionCookieMaxAge)
 	if err != nil {
@@ -178,6 +188,28 @@ func start(ctx context.Context, s *service.Service) error {
 		DashboardFinalRedirectURL: os.Getenv("JIMM_DASHBOARD_FINAL_REDIRECT_URL"),
 		SecureSessionCookies:      secureSessionCookies,
 		CookieSessionKey:          []byte(sessionSecretKey),
+		PlacementStrategy:         os.Getenv("JIMM_PLACEMENT_STRATEGY"),
+		AuditSinkParams: jimmsvc.AuditSinkParams{
+			FilePath:             os.Getenv("JIMM_AUDIT_FILE"),
+			SyslogURL:            os.Getenv("JIMM_AUDIT_SYSLOG_URL"),
+			WebhookURL:           os.Getenv("JIMM_AUDIT_WEBHOOK_URL"),
+			WebhookToken:         os.Getenv("JIMM_AUDIT_WEBHOOK_TOKEN"),
+			WebhookBatchSize:     os.Getenv("JIMM_AUDIT_WEBHOOK_BATCH_SIZE"),
+			WebhookFlushInterval: os.Getenv("JIMM_AUDIT_WEBHOOK_FLUSH_INTERVAL"),
+			WebhookMaxRetries:    os.Getenv("JIMM_AUDIT_WEBHOOK_MAX_RETRIES"),
+		},
+		AuditRedactionRules:         os.Getenv("JIMM_AUDIT_REDACTION_RULES"),
+		AuditAuthorizationDecisions: auditAuthorizationDecisions,
+		AuditLogHashChain:           auditLogHashChain,
+		AuditArchiveParams: jimmsvc.AuditArchiveParams{
+			Dir:               os.Getenv("JIMM_AUDIT_ARCHIVE_DIR"),
+			S3Endpoint:        os.Getenv("JIMM_AUDIT_ARCHIVE_S3_ENDPOINT"),
+			S3Region:          os.Getenv("JIMM_AUDIT_ARCHIVE_S3_REGION"),
+			S3Bucket:          os.Getenv("JIMM_AUDIT_ARCHIVE_S3_BUCKET"),
+			S3AccessKeyID:     os.Getenv("JIMM_AUDIT_ARCHIVE_S3_ACCESS_KEY_ID"),
+			S3SecretAccessKey: os.Getenv("JIMM_AUDIT_ARCHIVE_S3_SECRET_ACCESS_KEY"),
+			SegmentSize:       os.Getenv("JIMM_AUDIT_ARCHIVE_SEGMENT_SIZE"),
+		},
 	})
 	if err != nil {
 		return err
@@ -186,8 +218,11 @@ func start(ctx context.Context, s *service.Service) error {
 	isLeader := os.Getenv("JIMM_IS_LEADER") != ""
 	if isLeader {
 		s.Go(func() error { return jimmsvc.WatchControllers(ctx) }) // Deletes dead/dying models, updates model config.
+		s.Go(func() error { return jimmsvc.RunControllerDrains(ctx) })
+		s.Go(func() error { return jimmsvc.RunRelationExpiry(ctx) })
 	}
 	s.Go(func() error { return jimmsvc.WatchModelSummaries(ctx) })
+	s.Go(func() error { return jimmsvc.RunSessionCheck(ctx) })
 
 	if isLeader {
 		zapctx.Info(ctx, "attempting to start JWKS rotator and generate OAuth secret key")
@@ -24,6 +24,9 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/canonical/jimm/v3/internal/auditarchive"
+	"github.com/canonical/jimm/v3/internal/auditredact"
+	"github.com/canonical/jimm/v3/internal/auditsink"
 	"github.com/canonical/jimm/v3/internal/auth"
 	"github.com/canonical/jimm/v3/internal/dashboard"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
@@ -42,6 +45,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/pubsub"
 	"github.com/canonical/jimm/v3/internal/vault"
 	"github.com/canonical/jimm/v3/internal/wellknownapi"
+	"github.com/canonical/jimm/v3/pkg/api"
 )
 
 const (
@@ -58,6 +62,67 @@ type OpenFGAParams struct {
 	Port      string
 }
 
+// AuditSinkParams holds parameters used to configure the sinks that
+// audit log entries are exported to in addition to the database. Each
+// sink is enabled by specifying its location.
+type AuditSinkParams struct {
+	// FilePath is the path of a file audit log entries are appended
+	// to as JSON lines.
+	FilePath string
+
+	// SyslogURL is the URL of a syslog server audit log entries are
+	// sent to, e.g. udp://localhost:514, tcp://localhost:601 or
+	// unix:///dev/log.
+	SyslogURL string
+
+	// WebhookURL is the URL batches of audit log entries are POSTed to.
+	WebhookURL string
+
+	// WebhookToken, if set, is sent as a bearer token in requests to
+	// the webhook.
+	WebhookToken string
+
+	// WebhookBatchSize is the maximum number of audit log entries sent
+	// to the webhook in one request.
+	WebhookBatchSize string
+
+	// WebhookFlushInterval is the maximum duration audit log entries
+	// are held before being sent to the webhook.
+	WebhookFlushInterval string
+
+	// WebhookMaxRetries is the number of times a failed request to the
+	// webhook is retried.
+	WebhookMaxRetries string
+}
+
+// AuditArchiveParams holds parameters used to configure the archiving
+// of audit log entries before they are purged. Archiving is enabled by
+// specifying either a directory or an S3 endpoint.
+type AuditArchiveParams struct {
+	// Dir is the directory archive segments are written to.
+	Dir string
+
+	// S3Endpoint is the URL of an S3-compatible object store archive
+	// segments are written to.
+	S3Endpoint string
+
+	// S3Region is the region used when signing requests to the object
+	// store.
+	S3Region string
+
+	// S3Bucket is the bucket archive segments are written to.
+	S3Bucket string
+
+	// S3AccessKeyID and S3SecretAccessKey are the credentials used to
+	// access the object store.
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	// SegmentSize is the maximum number of audit log entries in an
+	// archive segment.
+	SegmentSize string
+}
+
 // OAuthAuthenticatorParams holds parameters needed to configure an OAuthAuthenticator
 // implementation.
 type OAuthAuthenticatorParams struct {
@@ -183,6 +248,36 @@ type Params struct {
 	// cookie data. The recommended length is 32/64 characters from the Gorilla securecookie lib.
 	// https://github.com/gorilla/securecookie/blob/main/securecookie.go#L124
 	CookieSessionKey []byte
+
+	// PlacementStrategy is the name of the strategy used to select the
+	// controller on which new models are placed. Valid strategies are
+	// "priority", "least-models", "least-units", "least-machines" and
+	// "round-robin". If this is empty the "priority" strategy is used.
+	PlacementStrategy string
+
+	// AuditSinkParams holds parameters used to configure the sinks audit
+	// log entries are exported to.
+	AuditSinkParams AuditSinkParams
+
+	// AuditRedactionRules is the path of a YAML file holding rules used
+	// to redact request parameters before they are written to the audit
+	// log. The default rules, which redact requests carrying
+	// credentials, are always applied in addition to these rules.
+	AuditRedactionRules string
+
+	// AuditArchiveParams holds parameters used to configure the
+	// archiving of audit log entries before they are purged.
+	AuditArchiveParams AuditArchiveParams
+
+	// AuditAuthorizationDecisions, if true, records the authorization
+	// decisions made when handling requests in the audit log.
+	AuditAuthorizationDecisions bool
+
+	// AuditLogHashChain, if true, adds audit log entries to a
+	// tamper-evident hash chain. As extending the chain serializes all
+	// additions to the audit log this should only be enabled when the
+	// tamper evidence is required.
+	AuditLogHashChain bool
 }
 
 // A Service is the implementation of a JIMM server.
@@ -207,8 +302,10 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 // given context is canceled, or there is a fatal error watching models.
 func (s *Service) WatchControllers(ctx context.Context) error {
 	w := jimm.Watcher{
-		Database: s.jimm.Database,
-		Dialer:   s.jimm.Dialer,
+		Database:      s.jimm.Database,
+		Dialer:        s.jimm.Dialer,
+		AuditLog:      &s.jimm,
+		AuditRedactor: s.jimm.AuditRedactor,
 	}
 	return w.Watch(ctx, 10*time.Minute)
 }
@@ -226,6 +323,28 @@ func (s *Service) WatchModelSummaries(ctx context.Context) error {
 	return w.WatchAllModelSummaries(ctx, 10*time.Minute)
 }
 
+// RunControllerDrains progresses all running controller drains.
+// RunControllerDrains finishes when the given context is canceled, or
+// there is a fatal error querying the database.
+func (s *Service) RunControllerDrains(ctx context.Context) error {
+	return s.jimm.RunControllerDrains(ctx, time.Minute)
+}
+
+// RunRelationExpiry revokes temporary relations once they expire.
+// RunRelationExpiry finishes when the given context is canceled, or
+// there is a fatal error querying the database.
+func (s *Service) RunRelationExpiry(ctx context.Context) error {
+	return s.jimm.RunRelationExpiry(ctx, time.Minute)
+}
+
+// RunSessionCheck terminates the sessions open on this server of
+// identities that have been disabled, possibly through another server.
+// RunSessionCheck finishes when the given context is canceled, or there
+// is a fatal error querying the database.
+func (s *Service) RunSessionCheck(ctx context.Context) error {
+	return s.jimm.RunSessionCheck(ctx, time.Minute)
+}
+
 // StartJWKSRotator see internal/jimmjwx/jwks.go for details.
 func (s *Service) StartJWKSRotator(ctx context.Context, checkRotateRequired <-chan time.Time, initialRotateRequiredTime time.Time) error {
 	if s.jimm.JWKService == nil {
@@ -284,19 +403,31 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	s.jimm.UUID = p.ControllerUUID
 	s.jimm.Pubsub = &pubsub.Hub{MaxConcurrency: 50}
 
+	placementStrategy, err := jimm.NewPlacementStrategy(p.PlacementStrategy)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	s.jimm.PlacementStrategy = placementStrategy
+
 	if p.DSN == "" {
 		return nil, errors.E(op, "missing DSN")
 	}
 
-	var err error
 	s.jimm.Database.DB, err = openDB(ctx, p.DSN)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
+	s.jimm.Database.AuditLogHashChain = p.AuditLogHashChain
 	if err := s.jimm.Database.Migrate(ctx, false); err != nil {
 		return nil, errors.E(op, err)
 	}
 
+	s.jimm.AuditAuthorizationDecisions = p.AuditAuthorizationDecisions
+
+	if err := s.setupAuditArchiver(p.AuditArchiveParams); err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	if p.AuditLogRetentionPeriodInDays != "" {
 		period, err := strconv.Atoi(p.AuditLogRetentionPeriodInDays)
 		if err != nil {
@@ -306,7 +437,18 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 			return nil, errors.E(op, "retention period cannot be less than 0")
 		}
 		if period != 0 {
-			jimm.NewAuditLogCleanupService(s.jimm.Database, period).Start(ctx)
+			jimm.NewAuditLogCleanupService(s.jimm.Database, s.jimm.AuditArchiver, period).Start(ctx)
+		}
+	}
+
+	if err := s.setupAuditSinks(ctx, p.AuditSinkParams); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	if p.AuditRedactionRules != "" {
+		s.jimm.AuditRedactor, err = auditredact.Load(p.AuditRedactionRules)
+		if err != nil {
+			return nil, errors.E(op, err, "failed to load audit redaction rules")
 		}
 	}
 
@@ -431,6 +573,7 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 
 	s.mux.Handle("/api", jujuapi.APIHandler(ctx, &s.jimm, params))
 	s.mux.Handle("/model/*", jujuapi.ModelHandler(ctx, &s.jimm, params))
+	s.mux.Handle(api.AuditExportPath, jujuapi.AuditExportHandler(ctx, &s.jimm))
 	// If the request is not for a known path assume it is part of the dashboard.
 	// If dashboard location env var is not defined, do not handle a dashboard.
 	if p.DashboardLocation != "" {
@@ -528,6 +671,101 @@ func (s *Service) setupCredentialStore(ctx context.Context, p Params) error {
 	return errors.E(op, "jimm cannot start without a credential store")
 }
 
+func (s *Service) setupAuditSinks(ctx context.Context, p AuditSinkParams) error {
+	const op = errors.Op("setupAuditSinks")
+
+	addSink := func(sink jimm.AuditSink) {
+		s.jimm.AuditSinks = append(s.jimm.AuditSinks, sink)
+		s.AddCleanup(sink.Close)
+	}
+
+	if p.FilePath != "" {
+		sink, err := auditsink.NewFileSink(p.FilePath)
+		if err != nil {
+			return errors.E(op, err, "failed to open audit log file")
+		}
+		addSink(sink)
+	}
+	if p.SyslogURL != "" {
+		sink, err := auditsink.NewSyslogSink(ctx, p.SyslogURL, "jimm")
+		if err != nil {
+			return errors.E(op, err, "failed to configure audit syslog sink")
+		}
+		addSink(sink)
+	}
+	if p.WebhookURL != "" {
+		wp := auditsink.WebhookParams{
+			URL:   p.WebhookURL,
+			Token: p.WebhookToken,
+		}
+		var err error
+		if p.WebhookBatchSize != "" {
+			if wp.BatchSize, err = strconv.Atoi(p.WebhookBatchSize); err != nil {
+				return errors.E(op, "failed to parse audit webhook batch size")
+			}
+		}
+		if p.WebhookFlushInterval != "" {
+			if wp.FlushInterval, err = time.ParseDuration(p.WebhookFlushInterval); err != nil {
+				return errors.E(op, "failed to parse audit webhook flush interval")
+			}
+		}
+		if p.WebhookMaxRetries != "" {
+			if wp.MaxRetries, err = strconv.Atoi(p.WebhookMaxRetries); err != nil {
+				return errors.E(op, "failed to parse audit webhook max retries")
+			}
+		}
+		sink, err := auditsink.NewWebhookSink(ctx, wp)
+		if err != nil {
+			return errors.E(op, err, "failed to configure audit webhook sink")
+		}
+		addSink(sink)
+	}
+	return nil
+}
+
+func (s *Service) setupAuditArchiver(p AuditArchiveParams) error {
+	const op = errors.Op("setupAuditArchiver")
+
+	var store jimm.AuditArchiveStore
+	switch {
+	case p.Dir != "" && p.S3Endpoint != "":
+		return errors.E(op, "only one of audit archive directory and S3 endpoint may be specified")
+	case p.Dir != "":
+		fs, err := auditarchive.NewFileStore(p.Dir)
+		if err != nil {
+			return errors.E(op, err, "failed to configure audit archive directory")
+		}
+		store = fs
+	case p.S3Endpoint != "":
+		s3, err := auditarchive.NewS3Store(auditarchive.S3Params{
+			Endpoint:        p.S3Endpoint,
+			Region:          p.S3Region,
+			Bucket:          p.S3Bucket,
+			AccessKeyID:     p.S3AccessKeyID,
+			SecretAccessKey: p.S3SecretAccessKey,
+		})
+		if err != nil {
+			return errors.E(op, err, "failed to configure audit archive S3 store")
+		}
+		store = s3
+	default:
+		return nil
+	}
+
+	archiver := &jimm.AuditLogArchiver{
+		Database: s.jimm.Database,
+		Store:    store,
+	}
+	if p.SegmentSize != "" {
+		var err error
+		if archiver.SegmentSize, err = strconv.Atoi(p.SegmentSize); err != nil || archiver.SegmentSize < 1 {
+			return errors.E(op, "failed to parse audit archive segment size")
+		}
+	}
+	s.jimm.AuditArchiver = archiver
+	return nil
+}
+
 func newVaultStore(ctx context.Context, p Params) (jimmcreds.CredentialStore, error) {
 	if p.VaultRoleID == "" || p.VaultRoleSecretID == "" {
 		return nil, nil
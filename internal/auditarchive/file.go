@@ -0,0 +1,95 @@
+// Copyright 2024 Canonical.
+
+// Package auditarchive contains implementations of jimm.AuditArchiveStore
+// that hold audit log archive segments.
+package auditarchive
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A FileStore is an archive store that holds each segment in a file
+// below a directory on the local filesystem.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore that stores segments below the given
+// directory, creating the directory if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	const op = errors.Op("auditarchive.NewFileStore")
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put implements jimm.AuditArchiveStore. The data is written to a
+// temporary file which is then renamed so that a partially written
+// segment is never stored.
+func (s *FileStore) Put(_ context.Context, key string, data []byte) error {
+	const op = errors.Op("auditarchive.FileStore.Put")
+
+	p, err := s.path(key)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return errors.E(op, err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.E(op, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.E(op, err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.E(op, err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// Get implements jimm.AuditArchiveStore.
+func (s *FileStore) Get(_ context.Context, key string) ([]byte, error) {
+	const op = errors.Op("auditarchive.FileStore.Get")
+
+	p, err := s.path(key)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	data, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, errors.E(op, errors.CodeNotFound, "archive not found")
+	}
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return data, nil
+}
+
+// path returns the path of the file holding the segment with the given
+// key. Keys are slash separated relative paths which may not refer
+// outside of the store's directory.
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", errors.E(errors.CodeBadRequest, "invalid archive key")
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package auditarchive_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditarchive"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestFileStore(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	dir := filepath.Join(c.TempDir(), "archive")
+	s, err := auditarchive.NewFileStore(dir)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Put(ctx, "audit-log/run-1/1-2.ndjson.gz", []byte("segment 1"))
+	c.Assert(err, qt.IsNil)
+	buf, err := os.ReadFile(filepath.Join(dir, "audit-log", "run-1", "1-2.ndjson.gz"))
+	c.Assert(err, qt.IsNil)
+	c.Check(string(buf), qt.Equals, "segment 1")
+
+	data, err := s.Get(ctx, "audit-log/run-1/1-2.ndjson.gz")
+	c.Assert(err, qt.IsNil)
+	c.Check(string(data), qt.Equals, "segment 1")
+
+	// Put replaces existing data.
+	err = s.Put(ctx, "audit-log/run-1/1-2.ndjson.gz", []byte("segment 2"))
+	c.Assert(err, qt.IsNil)
+	data, err = s.Get(ctx, "audit-log/run-1/1-2.ndjson.gz")
+	c.Assert(err, qt.IsNil)
+	c.Check(string(data), qt.Equals, "segment 2")
+
+	// No temporary files are left behind.
+	files, err := os.ReadDir(filepath.Join(dir, "audit-log", "run-1"))
+	c.Assert(err, qt.IsNil)
+	c.Check(files, qt.HasLen, 1)
+
+	_, err = s.Get(ctx, "audit-log/run-2/1-2.ndjson.gz")
+	c.Check(err, qt.ErrorMatches, `archive not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestFileStoreInvalidKey(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	s, err := auditarchive.NewFileStore(c.TempDir())
+	c.Assert(err, qt.IsNil)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "audit-log/../../outside", "audit-log//segment"} {
+		c.Run(key, func(c *qt.C) {
+			err := s.Put(ctx, key, []byte("data"))
+			c.Check(err, qt.ErrorMatches, `invalid archive key`)
+			_, err = s.Get(ctx, key)
+			c.Check(err, qt.ErrorMatches, `invalid archive key`)
+		})
+	}
+}
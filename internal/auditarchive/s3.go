@@ -0,0 +1,157 @@
+// Copyright 2024 Canonical.
+
+package auditarchive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	defaultS3Region  = "us-east-1"
+	defaultS3Timeout = 5 * time.Minute
+)
+
+// S3Params holds the parameters used to configure an S3Store.
+type S3Params struct {
+	// Endpoint is the URL of the S3-compatible service, for example
+	// https://s3.us-east-1.amazonaws.com.
+	Endpoint string
+
+	// Region is the region used to sign requests. If this is empty a
+	// default of us-east-1 is used.
+	Region string
+
+	// Bucket is the bucket the segments are stored in.
+	Bucket string
+
+	// AccessKeyID and SecretAccessKey are the credentials used to sign
+	// requests.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// Client is the HTTP client used to send requests. If this is nil a
+	// client with a 5 minute timeout is used.
+	Client *http.Client
+}
+
+// An S3Store is an archive store that holds each segment as an object in
+// a bucket of an S3-compatible object store. Objects are addressed using
+// path-style URLs so that services other than AWS can be used.
+type S3Store struct {
+	p      S3Params
+	signer *v4.Signer
+}
+
+// NewS3Store returns an S3Store configured with the given parameters.
+func NewS3Store(p S3Params) (*S3Store, error) {
+	const op = errors.Op("auditarchive.NewS3Store")
+
+	if p.Endpoint == "" {
+		return nil, errors.E(op, "missing S3 endpoint")
+	}
+	if _, err := url.Parse(p.Endpoint); err != nil {
+		return nil, errors.E(op, err, "invalid S3 endpoint")
+	}
+	if p.Bucket == "" {
+		return nil, errors.E(op, "missing S3 bucket")
+	}
+	if p.Region == "" {
+		p.Region = defaultS3Region
+	}
+	if p.Client == nil {
+		p.Client = &http.Client{Timeout: defaultS3Timeout}
+	}
+	signer := v4.NewSigner(func(o *v4.SignerOptions) {
+		// S3 requires that the path is escaped only once.
+		o.DisableURIPathEscaping = true
+	})
+	return &S3Store{p: p, signer: signer}, nil
+}
+
+// Put implements jimm.AuditArchiveStore.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	const op = errors.Op("auditarchive.S3Store.Put")
+
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.E(op, s3Error(resp))
+	}
+	return nil
+}
+
+// Get implements jimm.AuditArchiveStore.
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	const op = errors.Op("auditarchive.S3Store.Get")
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.E(op, errors.CodeNotFound, "archive not found")
+	default:
+		return nil, errors.E(op, s3Error(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return data, nil
+}
+
+// do sends a signed request for the object with the given key.
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	u := strings.TrimSuffix(s.p.Endpoint, "/") + "/" + url.PathEscape(s.p.Bucket) + "/" + escapeKey(key)
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := aws.Credentials{
+		AccessKeyID:     s.p.AccessKeyID,
+		SecretAccessKey: s.p.SecretAccessKey,
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.p.Region, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.p.Client.Do(req)
+}
+
+// escapeKey escapes each element of the given slash separated key.
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// s3Error returns an error describing the given unsuccessful response.
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected response from S3: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical.
+
+package auditarchive_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditarchive"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// s3Server is a minimal S3-compatible object store that checks that
+// requests are signed.
+type s3Server struct {
+	c *qt.C
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/") || !strings.Contains(auth, "/eu-west-1/s3/aws4_request") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch req.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(req.Body)
+		s.c.Assert(err, qt.IsNil)
+		sum := sha256.Sum256(body)
+		s.c.Check(req.Header.Get("X-Amz-Content-Sha256"), qt.Equals, hex.EncodeToString(sum[:]))
+		s.objects[req.URL.Path] = body
+	case http.MethodGet:
+		body, ok := s.objects[req.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srv := &s3Server{c: c, objects: make(map[string][]byte)}
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+
+	s, err := auditarchive.NewS3Store(auditarchive.S3Params{
+		Endpoint:        hs.URL,
+		Region:          "eu-west-1",
+		Bucket:          "audit",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Put(ctx, "audit-log/run-1/1-2.ndjson.gz", []byte("segment 1"))
+	c.Assert(err, qt.IsNil)
+	c.Check(string(srv.objects["/audit/audit-log/run-1/1-2.ndjson.gz"]), qt.Equals, "segment 1")
+
+	data, err := s.Get(ctx, "audit-log/run-1/1-2.ndjson.gz")
+	c.Assert(err, qt.IsNil)
+	c.Check(string(data), qt.Equals, "segment 1")
+
+	_, err = s.Get(ctx, "audit-log/run-2/1-2.ndjson.gz")
+	c.Check(err, qt.ErrorMatches, `archive not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestS3StoreError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srv := &s3Server{c: c, objects: make(map[string][]byte)}
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+
+	s, err := auditarchive.NewS3Store(auditarchive.S3Params{
+		Endpoint:        hs.URL,
+		Bucket:          "audit",
+		AccessKeyID:     "other-key",
+		SecretAccessKey: "test-secret",
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Put(ctx, "audit-log/run-1/1-2.ndjson.gz", []byte("segment 1"))
+	c.Check(err, qt.ErrorMatches, `unexpected response from S3: 403 Forbidden: AccessDenied`)
+}
+
+func TestNewS3StoreValidation(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := auditarchive.NewS3Store(auditarchive.S3Params{Bucket: "audit"})
+	c.Check(err, qt.ErrorMatches, `missing S3 endpoint`)
+
+	_, err = auditarchive.NewS3Store(auditarchive.S3Params{Endpoint: "http://localhost:9000"})
+	c.Check(err, qt.ErrorMatches, `missing S3 bucket`)
+}
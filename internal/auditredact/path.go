@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical.
+
+package auditredact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A selector is a single element of a JSON path. A selector matches
+// either the object field with the given name, the array element with
+// the given index, or, if wildcard is set, every field or element.
+type selector struct {
+	name     string
+	index    int
+	wildcard bool
+}
+
+// parsePath parses a JSON path selector of the form described in
+// Rule.Paths.
+func parsePath(p string) ([]selector, error) {
+	if !strings.HasPrefix(p, "$") {
+		return nil, fmt.Errorf("invalid path %q: must start with $", p)
+	}
+	var sels []selector
+	rest := p[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			n := strings.IndexAny(rest, ".[")
+			if n < 0 {
+				n = len(rest)
+			}
+			name := rest[:n]
+			rest = rest[n:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid path %q: empty field name", p)
+			case "*":
+				sels = append(sels, selector{index: -1, wildcard: true})
+			default:
+				sels = append(sels, selector{name: name, index: -1})
+			}
+		case '[':
+			n := strings.IndexByte(rest, ']')
+			if n < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", p)
+			}
+			elem := rest[1:n]
+			rest = rest[n+1:]
+			switch {
+			case elem == "*":
+				sels = append(sels, selector{index: -1, wildcard: true})
+			case len(elem) >= 2 && (elem[0] == '\'' || elem[0] == '"') && elem[len(elem)-1] == elem[0]:
+				sels = append(sels, selector{name: elem[1 : len(elem)-1], index: -1})
+			default:
+				i, err := strconv.Atoi(elem)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("invalid path %q: invalid index %q", p, elem)
+				}
+				sels = append(sels, selector{index: i})
+			}
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", p, rest[0])
+		}
+	}
+	if len(sels) == 0 {
+		return nil, fmt.Errorf("invalid path %q: no fields selected", p)
+	}
+	return sels, nil
+}
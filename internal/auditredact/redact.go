@@ -0,0 +1,223 @@
+// Copyright 2024 Canonical.
+
+// Package auditredact redacts sensitive request parameters from audit log
+// entries according to a configurable set of rules.
+package auditredact
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// RedactedValue is the value that replaces redacted fields.
+const RedactedValue = "redacted"
+
+// redactedParams replaces the parameters of entries that are redacted
+// entirely.
+var redactedParams = dbmodel.JSON(`{"params":"redacted"}`)
+
+// A Rule selects the parameters to redact from requests to a facade
+// method.
+type Rule struct {
+	// Facade is the name of the facade the rule applies to. If this is
+	// empty the rule applies to all facades.
+	Facade string `json:"facade,omitempty"`
+
+	// Method is the name of the facade method the rule applies to. If
+	// this is empty the rule applies to all methods.
+	Method string `json:"method,omitempty"`
+
+	// Version is the facade version the rule applies to. If this is 0
+	// the rule applies to all versions.
+	Version int `json:"version,omitempty"`
+
+	// Paths holds JSON path selectors for the fields to redact, for
+	// example "$.credentials[*].credential.attrs". A selector starts
+	// with "$" followed by any number of ".name", "['name']", "[n]",
+	// ".*" or "[*]" elements. If no paths are specified all of the
+	// parameters are redacted.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Rules is the format of a redaction rules file.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultRules are always applied, in addition to any configured rules.
+// They redact all parameters of requests that carry credentials.
+var DefaultRules = []Rule{
+	{Method: "Login"},
+	{Method: "LoginDevice"},
+	{Method: "GetDeviceSessionToken"},
+	{Method: "LoginWithSessionToken"},
+	{Method: "AddCredentials"},
+	{Method: "UpdateCredentials"},
+}
+
+var defaultRedactor = mustNew(DefaultRules)
+
+// A Redactor redacts audit log entries according to a set of rules.
+type Redactor struct {
+	rules []rule
+}
+
+// rule is a Rule with the paths parsed.
+type rule struct {
+	Rule
+	selectors [][]selector
+}
+
+// New returns a Redactor that applies the given rules. An error with a
+// code of errors.CodeBadRequest is returned if any of the paths are
+// invalid.
+func New(rules []Rule) (*Redactor, error) {
+	const op = errors.Op("auditredact.New")
+
+	r := Redactor{rules: make([]rule, len(rules))}
+	for i, rl := range rules {
+		r.rules[i].Rule = rl
+		for _, p := range rl.Paths {
+			sel, err := parsePath(p)
+			if err != nil {
+				return nil, errors.E(op, errors.CodeBadRequest, err)
+			}
+			r.rules[i].selectors = append(r.rules[i].selectors, sel)
+		}
+	}
+	return &r, nil
+}
+
+func mustNew(rules []Rule) *Redactor {
+	r, err := New(rules)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Load returns a Redactor that applies the DefaultRules and the rules in
+// the YAML (or JSON) file at the given path. The rules in the file cannot
+// remove any of the DefaultRules.
+func Load(path string) (*Redactor, error) {
+	const op = errors.Op("auditredact.Load")
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	var rules Rules
+	if err := yaml.UnmarshalStrict(buf, &rules); err != nil {
+		return nil, errors.E(op, errors.CodeBadRequest, err)
+	}
+	r, err := New(append(append([]Rule(nil), DefaultRules...), rules.Rules...))
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return r, nil
+}
+
+// Redact redacts the parameters of the given entry according to the
+// rules that match it. Calling Redact on a nil Redactor applies the
+// DefaultRules.
+func (r *Redactor) Redact(ale *dbmodel.AuditLogEntry) {
+	if r == nil {
+		r = defaultRedactor
+	}
+	if len(ale.Params) == 0 {
+		return
+	}
+	var selectors [][]selector
+	for _, rl := range r.rules {
+		if !rl.matches(ale) {
+			continue
+		}
+		if len(rl.selectors) == 0 {
+			ale.Params = append(dbmodel.JSON(nil), redactedParams...)
+			return
+		}
+		selectors = append(selectors, rl.selectors...)
+	}
+	if len(selectors) == 0 {
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(ale.Params))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		// The parameters cannot be inspected, so err on the side of
+		// caution.
+		ale.Params = append(dbmodel.JSON(nil), redactedParams...)
+		return
+	}
+	var changed bool
+	for _, sel := range selectors {
+		v = redact(v, sel, &changed)
+	}
+	if !changed {
+		return
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		ale.Params = append(dbmodel.JSON(nil), redactedParams...)
+		return
+	}
+	ale.Params = buf
+}
+
+// matches returns whether the rule applies to the given entry.
+func (r rule) matches(ale *dbmodel.AuditLogEntry) bool {
+	if r.Facade != "" && !strings.EqualFold(r.Facade, ale.FacadeName) {
+		return false
+	}
+	if r.Method != "" && !strings.EqualFold(r.Method, ale.FacadeMethod) {
+		return false
+	}
+	if r.Version != 0 && r.Version != ale.FacadeVersion {
+		return false
+	}
+	return true
+}
+
+// redact replaces the values in v selected by sel with RedactedValue,
+// setting changed if any value is replaced. The updated value is
+// returned.
+func redact(v any, sel []selector, changed *bool) any {
+	if len(sel) == 0 {
+		*changed = true
+		return RedactedValue
+	}
+	s, rest := sel[0], sel[1:]
+	switch v := v.(type) {
+	case map[string]any:
+		if s.index >= 0 {
+			return v
+		}
+		for k, e := range v {
+			if s.wildcard || k == s.name {
+				v[k] = redact(e, rest, changed)
+			}
+		}
+		return v
+	case []any:
+		if s.name != "" {
+			return v
+		}
+		for i, e := range v {
+			if s.wildcard || i == s.index {
+				v[i] = redact(e, rest, changed)
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
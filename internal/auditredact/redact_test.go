@@ -0,0 +1,185 @@
+// Copyright 2024 Canonical.
+
+package auditredact_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditredact"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+var redactTests = []struct {
+	name         string
+	rules        []auditredact.Rule
+	entry        dbmodel.AuditLogEntry
+	expectParams string
+}{{
+	name:  "NoMatchingRule",
+	rules: []auditredact.Rule{{Method: "AddCredentials"}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "ModelManager",
+		FacadeMethod: "CreateModel",
+		Params:       dbmodel.JSON(`{"name": "model-1"}`),
+	},
+	expectParams: `{"name": "model-1"}`,
+}, {
+	name:  "WholeParams",
+	rules: []auditredact.Rule{{Method: "addcredentials"}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "Cloud",
+		FacadeMethod: "AddCredentials",
+		Params:       dbmodel.JSON(`{"credentials": []}`),
+	},
+	expectParams: `{"params":"redacted"}`,
+}, {
+	name: "Paths",
+	rules: []auditredact.Rule{{
+		Facade: "Cloud",
+		Method: "UpdateCredentialsCheckModels",
+		Paths:  []string{"$.credentials[*].credential.attrs"},
+	}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "Cloud",
+		FacadeMethod: "UpdateCredentialsCheckModels",
+		Params:       dbmodel.JSON(`{"credentials":[{"tag":"cloudcred-a","credential":{"auth-type":"userpass","attrs":{"password":"secret"}}},{"tag":"cloudcred-b","credential":{"auth-type":"empty"}}],"force":false}`),
+	},
+	expectParams: `{"credentials":[{"credential":{"attrs":"redacted","auth-type":"userpass"},"tag":"cloudcred-a"},{"credential":{"auth-type":"empty"},"tag":"cloudcred-b"}],"force":false}`,
+}, {
+	name: "WildcardsAndIndexes",
+	rules: []auditredact.Rule{{
+		Paths: []string{"$.config.*", "$['secrets'][1]"},
+	}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "ModelConfig",
+		FacadeMethod: "ModelSet",
+		Params:       dbmodel.JSON(`{"config":{"a":1,"b":{"c":2}},"secrets":["x","y"],"n":12345678901234567890}`),
+	},
+	expectParams: `{"config":{"a":"redacted","b":"redacted"},"n":12345678901234567890,"secrets":["x","redacted"]}`,
+}, {
+	name: "VersionMismatch",
+	rules: []auditredact.Rule{{
+		Facade:  "ModelConfig",
+		Version: 2,
+		Paths:   []string{"$.config"},
+	}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:    "ModelConfig",
+		FacadeMethod:  "ModelSet",
+		FacadeVersion: 3,
+		Params:        dbmodel.JSON(`{"config":{"a":1}}`),
+	},
+	expectParams: `{"config":{"a":1}}`,
+}, {
+	name: "PathNotPresent",
+	rules: []auditredact.Rule{{
+		Paths: []string{"$.config.secret"},
+	}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "ModelConfig",
+		FacadeMethod: "ModelSet",
+		Params:       dbmodel.JSON(`{"config": [1, 2]}`),
+	},
+	expectParams: `{"config": [1, 2]}`,
+}, {
+	name: "InvalidJSON",
+	rules: []auditredact.Rule{{
+		Paths: []string{"$.config"},
+	}},
+	entry: dbmodel.AuditLogEntry{
+		FacadeName:   "ModelConfig",
+		FacadeMethod: "ModelSet",
+		Params:       dbmodel.JSON(`{"config":`),
+	},
+	expectParams: `{"params":"redacted"}`,
+}}
+
+func TestRedact(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range redactTests {
+		c.Run(test.name, func(c *qt.C) {
+			r, err := auditredact.New(test.rules)
+			c.Assert(err, qt.IsNil)
+			ale := test.entry
+			r.Redact(&ale)
+			c.Check(string(ale.Params), qt.Equals, test.expectParams)
+		})
+	}
+}
+
+func TestRedactDefaultRules(t *testing.T) {
+	c := qt.New(t)
+
+	var r *auditredact.Redactor
+	ale := dbmodel.AuditLogEntry{
+		FacadeName:   "Admin",
+		FacadeMethod: "LoginWithSessionToken",
+		Params:       dbmodel.JSON(`{"token":"secret"}`),
+	}
+	r.Redact(&ale)
+	c.Check(string(ale.Params), qt.Equals, `{"params":"redacted"}`)
+
+	ale = dbmodel.AuditLogEntry{
+		FacadeName:   "ModelManager",
+		FacadeMethod: "ListModels",
+		Params:       dbmodel.JSON(`{"tag":"user-alice"}`),
+	}
+	r.Redact(&ale)
+	c.Check(string(ale.Params), qt.Equals, `{"tag":"user-alice"}`)
+}
+
+func TestNewInvalidPath(t *testing.T) {
+	c := qt.New(t)
+
+	for _, p := range []string{"config", "$", "$.", "$.a[", "$.a[x]", "$a"} {
+		_, err := auditredact.New([]auditredact.Rule{{Paths: []string{p}}})
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest, qt.Commentf("%s", p))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c := qt.New(t)
+
+	path := filepath.Join(c.TempDir(), "rules.yaml")
+	err := os.WriteFile(path, []byte(`
+rules:
+- facade: ModelManager
+  method: CreateModel
+  paths:
+  - $.config.secret
+`), 0600)
+	c.Assert(err, qt.IsNil)
+
+	r, err := auditredact.Load(path)
+	c.Assert(err, qt.IsNil)
+
+	ale := dbmodel.AuditLogEntry{
+		FacadeName:   "ModelManager",
+		FacadeMethod: "CreateModel",
+		Params:       dbmodel.JSON(`{"name":"model-1","config":{"secret":"s3cr3t"}}`),
+	}
+	r.Redact(&ale)
+	c.Check(string(ale.Params), qt.Equals, `{"config":{"secret":"redacted"},"name":"model-1"}`)
+
+	// The default rules are still applied.
+	for _, method := range []string{"Login", "AddCredentials"} {
+		ale = dbmodel.AuditLogEntry{
+			FacadeName:   "Admin",
+			FacadeMethod: method,
+			Params:       dbmodel.JSON(`{"password":"secret"}`),
+		}
+		r.Redact(&ale)
+		c.Check(string(ale.Params), qt.Equals, `{"params":"redacted"}`, qt.Commentf("%s", method))
+	}
+
+	err = os.WriteFile(path, []byte("rules:\n- method: Login\n  unknown: true\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	_, err = auditredact.Load(path)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
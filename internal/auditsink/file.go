@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+// Package auditsink contains implementations of jimm.AuditSink that
+// export audit log entries to external systems.
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A FileSink is an audit sink that appends audit log entries to a file,
+// one JSON encoded entry per line.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileSink returns a FileSink that appends to the file at the given
+// path, creating the file if it does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	const op = errors.Op("auditsink.NewFileSink")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Write implements jimm.AuditSink, it writes the entry as a single line
+// of JSON.
+func (s *FileSink) Write(_ context.Context, ale *dbmodel.AuditLogEntry) error {
+	const op = errors.Op("auditsink.FileSink.Write")
+
+	buf, err := json.Marshal(ale.ToAPIAuditEvent())
+	if err != nil {
+		return errors.E(op, err)
+	}
+	buf = append(buf, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(buf); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// Close implements jimm.AuditSink, it closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Canonical.
+
+package auditsink_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditsink"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func testAuditLogEntry(method string) *dbmodel.AuditLogEntry {
+	return &dbmodel.AuditLogEntry{
+		Time:           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		ConversationId: "conversation-1",
+		MessageId:      1,
+		FacadeName:     "JIMM",
+		FacadeMethod:   method,
+		FacadeVersion:  4,
+		IdentityTag:    "user-alice@canonical.com",
+		Params:         dbmodel.JSON(`{"key":"value"}`),
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	path := filepath.Join(c.TempDir(), "audit.jsonl")
+	s, err := auditsink.NewFileSink(path)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Write(ctx, testAuditLogEntry("RemoveController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	// Entries are appended to an existing file.
+	s, err = auditsink.NewFileSink(path)
+	c.Assert(err, qt.IsNil)
+	err = s.Write(ctx, testAuditLogEntry("ListControllers"))
+	c.Assert(err, qt.IsNil)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	buf, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	lines := strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	c.Assert(lines, qt.HasLen, 3)
+	var methods []string
+	for _, line := range lines {
+		var ev apiparams.AuditEvent
+		err := json.Unmarshal([]byte(line), &ev)
+		c.Assert(err, qt.IsNil)
+		methods = append(methods, ev.FacadeMethod)
+	}
+	c.Check(methods, qt.DeepEquals, []string{"AddController", "RemoveController", "ListControllers"})
+	c.Check(lines[0], qt.JSONEquals, testAuditLogEntry("AddController").ToAPIAuditEvent())
+}
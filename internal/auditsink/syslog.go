@@ -0,0 +1,213 @@
+// Copyright 2024 Canonical.
+
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	// syslogFacilityAudit is the "log audit" syslog facility.
+	syslogFacilityAudit = 13
+
+	// syslogSeverityInfo is the "informational" syslog severity.
+	syslogSeverityInfo = 6
+
+	// syslogMsgID is the MSGID sent with every audit message.
+	syslogMsgID = "audit"
+
+	// syslogDialTimeout is the time allowed to connect to the syslog
+	// server.
+	syslogDialTimeout = 10 * time.Second
+
+	// syslogWriteTimeout is the time allowed to send a message to the
+	// syslog server.
+	syslogWriteTimeout = 10 * time.Second
+
+	// syslogQueueSize is the number of messages that may be waiting to
+	// be sent to the syslog server.
+	syslogQueueSize = 1000
+)
+
+// A SyslogSink is an audit sink that sends audit log entries to a syslog
+// server in the RFC 5424 format. The message of each entry is the JSON
+// encoded entry. Entries are queued and sent asynchronously, so a slow or
+// unavailable syslog server does not delay the caller.
+type SyslogSink struct {
+	network  string
+	addr     string
+	hostname string
+	appName  string
+	procID   string
+
+	// conn is only used by the goroutine sending messages.
+	conn net.Conn
+
+	msgs      chan []byte
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSyslogSink returns a SyslogSink that sends entries to the syslog
+// server at the given URL and starts sending entries. The URL scheme
+// determines the transport and may be "udp", "tcp" or "unix", for example
+// "udp://localhost:514" or "unix:///dev/log". Messages are sent with the
+// given application name.
+func NewSyslogSink(ctx context.Context, rawURL, appName string) (*SyslogSink, error) {
+	const op = errors.Op("auditsink.NewSyslogSink")
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	s := SyslogSink{
+		network: u.Scheme,
+		addr:    u.Host,
+		appName: appName,
+		procID:  fmt.Sprint(os.Getpid()),
+		msgs:    make(chan []byte, syslogQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+	case "unix":
+		// Local syslog daemons listen on datagram sockets.
+		s.network = "unixgram"
+		s.addr = u.Path
+	default:
+		return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unsupported syslog scheme %q", u.Scheme))
+	}
+	if s.addr == "" {
+		return nil, errors.E(op, errors.CodeBadRequest, "syslog address not specified")
+	}
+	if s.appName == "" {
+		s.appName = "-"
+	}
+	s.hostname, err = os.Hostname()
+	if err != nil || s.hostname == "" {
+		s.hostname = "-"
+	}
+	// Queued entries are still sent when the sink is closed during
+	// shutdown, so the sink must outlive the given context.
+	go s.run(context.WithoutCancel(ctx))
+	return &s, nil
+}
+
+// Write implements jimm.AuditSink, it queues the entry to be sent to the
+// syslog server. If the queue is full the entry is dropped and an error
+// returned.
+func (s *SyslogSink) Write(_ context.Context, ale *dbmodel.AuditLogEntry) error {
+	const op = errors.Op("auditsink.SyslogSink.Write")
+
+	buf, err := json.Marshal(ale.ToAPIAuditEvent())
+	if err != nil {
+		return errors.E(op, err)
+	}
+	select {
+	case <-s.stop:
+		return errors.E(op, "audit syslog sink closed")
+	default:
+	}
+	select {
+	case s.msgs <- s.format(ale.Time, buf):
+		return nil
+	default:
+		return errors.E(op, "audit syslog queue full")
+	}
+}
+
+// run sends queued messages until the sink is closed.
+func (s *SyslogSink) run(ctx context.Context) {
+	defer close(s.done)
+	defer func() {
+		if s.conn != nil {
+			s.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-s.msgs:
+			s.send(ctx, msg)
+		case <-s.stop:
+			// Only run receives from msgs so this cannot block.
+			for len(s.msgs) > 0 {
+				if !s.send(ctx, <-s.msgs) {
+					zapctx.Error(ctx, "dropping audit log entries queued for syslog", zap.Int("count", len(s.msgs)))
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+// send sends the given message to the syslog server. If sending fails the
+// connection is re-established and the message sent once more, if that
+// also fails the message is dropped and false is returned.
+func (s *SyslogSink) send(ctx context.Context, msg []byte) bool {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			s.conn, err = net.DialTimeout(s.network, s.addr, syslogDialTimeout)
+			if err != nil {
+				continue
+			}
+		}
+		if err = s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err == nil {
+			if _, err = s.conn.Write(msg); err == nil {
+				return true
+			}
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	zapctx.Error(ctx, "cannot send audit log entry to syslog", zap.Error(err))
+	return false
+}
+
+// format formats the given message as an RFC 5424 syslog message. Over
+// TCP messages are framed using octet counting as described in RFC 6587.
+func (s *SyslogSink) format(t time.Time, msg []byte) []byte {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	m := fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		syslogFacilityAudit*8+syslogSeverityInfo,
+		t.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		s.appName,
+		s.procID,
+		syslogMsgID,
+		msg,
+	)
+	if s.network == "tcp" {
+		m = fmt.Sprintf("%d %s", len(m), m)
+	}
+	return []byte(m)
+}
+
+// Close implements jimm.AuditSink, it sends any queued entries and closes
+// the connection to the syslog server.
+func (s *SyslogSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
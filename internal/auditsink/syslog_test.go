@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical.
+
+package auditsink_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditsink"
+)
+
+func TestSyslogSinkUDP(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	defer conn.Close()
+
+	s, err := auditsink.NewSyslogSink(ctx, "udp://"+conn.LocalAddr().String(), "jimm")
+	c.Assert(err, qt.IsNil)
+	defer s.Close()
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	c.Assert(err, qt.IsNil)
+
+	hostname, err := os.Hostname()
+	c.Assert(err, qt.IsNil)
+	prefix := fmt.Sprintf("<110>1 2024-01-02T03:04:05Z %s jimm %d audit - ", hostname, os.Getpid())
+	msg := string(buf[:n])
+	c.Assert(strings.HasPrefix(msg, prefix), qt.IsTrue, qt.Commentf("%s", msg))
+	c.Check(strings.TrimPrefix(msg, prefix), qt.JSONEquals, testAuditLogEntry("AddController").ToAPIAuditEvent())
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	defer l.Close()
+
+	msgs := make(chan string)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(msgs)
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			// Messages are framed using octet counting.
+			length, err := r.ReadString(' ')
+			if err != nil {
+				close(msgs)
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+			if err != nil {
+				close(msgs)
+				return
+			}
+			buf := make([]byte, n)
+			if _, err := r.Read(buf); err != nil {
+				close(msgs)
+				return
+			}
+			msgs <- string(buf)
+		}
+	}()
+
+	s, err := auditsink.NewSyslogSink(ctx, "tcp://"+l.Addr().String(), "")
+	c.Assert(err, qt.IsNil)
+	defer s.Close()
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Write(ctx, testAuditLogEntry("RemoveController"))
+	c.Assert(err, qt.IsNil)
+
+	re := regexp.MustCompile(`^<110>1 2024-01-02T03:04:05Z \S+ - \d+ audit - \{.*"facade-method":"(\w+)".*\}$`)
+	for _, method := range []string{"AddController", "RemoveController"} {
+		msg := <-msgs
+		m := re.FindStringSubmatch(msg)
+		c.Assert(m, qt.HasLen, 2, qt.Commentf("%s", msg))
+		c.Check(m[1], qt.Equals, method)
+	}
+}
+
+func TestSyslogSinkUnavailable(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	// Find an address that nothing is listening on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	addr := l.Addr().String()
+	l.Close()
+
+	s, err := auditsink.NewSyslogSink(ctx, "tcp://"+addr, "jimm")
+	c.Assert(err, qt.IsNil)
+
+	// Entries are queued without waiting for the server.
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	err = s.Write(ctx, testAuditLogEntry("RemoveController"))
+	c.Check(err, qt.ErrorMatches, `audit syslog sink closed`)
+}
+
+func TestNewSyslogSinkInvalidURL(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	_, err := auditsink.NewSyslogSink(ctx, "http://localhost:514", "jimm")
+	c.Check(err, qt.ErrorMatches, `unsupported syslog scheme "http"`)
+
+	_, err = auditsink.NewSyslogSink(ctx, "udp://", "jimm")
+	c.Check(err, qt.ErrorMatches, `syslog address not specified`)
+}
@@ -0,0 +1,287 @@
+// Copyright 2024 Canonical.
+
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = 5 * time.Second
+	defaultWebhookMaxRetries    = 3
+	defaultWebhookMaxPending    = 100
+	defaultWebhookRetryDelay    = time.Second
+	defaultWebhookTimeout       = 30 * time.Second
+)
+
+// WebhookParams holds the parameters used to configure a WebhookSink.
+type WebhookParams struct {
+	// URL is the URL batches of audit log entries are POSTed to.
+	URL string
+
+	// Token, if set, is sent as a bearer token in the Authorization
+	// header of every request.
+	Token string
+
+	// BatchSize is the maximum number of entries sent in a single
+	// request. If this is 0 a default of 100 is used.
+	BatchSize int
+
+	// FlushInterval is the maximum time an entry is held before being
+	// sent. If this is 0 a default of 5 seconds is used.
+	FlushInterval time.Duration
+
+	// MaxRetries is the number of times sending a batch is retried
+	// before the batch is dropped. If this is 0 a default of 3 is used,
+	// a negative value disables retries.
+	MaxRetries int
+
+	// RetryDelay is the delay before the first retry, the delay doubles
+	// for each subsequent retry. If this is 0 a default of 1 second is
+	// used.
+	RetryDelay time.Duration
+
+	// MaxPending is the maximum number of batches held while waiting to
+	// retry a failed request. If more batches are collected the oldest
+	// is dropped. If this is 0 a default of 100 is used.
+	MaxPending int
+
+	// Client is the HTTP client used to send requests. If this is nil a
+	// client with a 30 second timeout is used.
+	Client *http.Client
+}
+
+// A WebhookSink is an audit sink that POSTs batches of audit log entries
+// to an HTTP endpoint. Each request body is a JSON object with a single
+// "events" field holding the entries. Entries are sent asynchronously,
+// once a batch is full or the flush interval expires. Requests that
+// fail, or receive a 429 or 5xx response, are retried. Entries continue
+// to be collected into batches while waiting to retry, these are sent, in
+// order, once the failed batch has been sent or dropped.
+type WebhookSink struct {
+	p WebhookParams
+
+	entries   chan apiparams.AuditEvent
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebhookSink returns a WebhookSink configured with the given
+// parameters and starts sending entries.
+func NewWebhookSink(ctx context.Context, p WebhookParams) (*WebhookSink, error) {
+	const op = errors.Op("auditsink.NewWebhookSink")
+
+	if p.URL == "" {
+		return nil, errors.E(op, errors.CodeBadRequest, "webhook url not specified")
+	}
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaultWebhookBatchSize
+	}
+	if p.FlushInterval <= 0 {
+		p.FlushInterval = defaultWebhookFlushInterval
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = defaultWebhookMaxRetries
+	}
+	if p.RetryDelay <= 0 {
+		p.RetryDelay = defaultWebhookRetryDelay
+	}
+	if p.MaxPending <= 0 {
+		p.MaxPending = defaultWebhookMaxPending
+	}
+	if p.Client == nil {
+		p.Client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	s := &WebhookSink{
+		p:       p,
+		entries: make(chan apiparams.AuditEvent, 10*p.BatchSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	// Queued entries are still sent when the sink is closed during
+	// shutdown, so the sink must outlive the given context.
+	go s.run(context.WithoutCancel(ctx))
+	return s, nil
+}
+
+// Write implements jimm.AuditSink, it queues the entry to be sent. If the
+// queue is full the entry is dropped and an error returned.
+func (s *WebhookSink) Write(_ context.Context, ale *dbmodel.AuditLogEntry) error {
+	const op = errors.Op("auditsink.WebhookSink.Write")
+
+	select {
+	case <-s.stop:
+		return errors.E(op, "audit webhook sink closed")
+	default:
+	}
+	select {
+	case s.entries <- ale.ToAPIAuditEvent():
+		return nil
+	default:
+		return errors.E(op, "audit webhook queue full")
+	}
+}
+
+// Close implements jimm.AuditSink, it sends any queued entries and stops
+// the sink.
+func (s *WebhookSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
+
+// A webhookBatch is a batch of entries waiting to be sent.
+type webhookBatch struct {
+	body     []byte
+	count    int
+	attempts int
+}
+
+// run collects queued entries into batches and sends them until the sink
+// is closed.
+func (s *WebhookSink) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.p.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []apiparams.AuditEvent
+	var pending []*webhookBatch
+	// retry is set while waiting to retry the first pending batch.
+	var retry *time.Timer
+	var retryC <-chan time.Time
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		body, err := json.Marshal(apiparams.AuditEvents{Events: batch})
+		if err != nil {
+			zapctx.Error(ctx, "cannot marshal audit events", zap.Error(err))
+			batch = nil
+			return
+		}
+		if len(pending) >= s.p.MaxPending {
+			zapctx.Error(ctx, "too many audit events waiting to be sent to webhook, dropping", zap.Int("count", pending[0].count))
+			pending = pending[1:]
+		}
+		pending = append(pending, &webhookBatch{body: body, count: len(batch)})
+		batch = nil
+	}
+	sendPending := func() {
+		for retryC == nil && len(pending) > 0 {
+			if delay, ok := s.send(ctx, pending[0]); !ok {
+				retry = time.NewTimer(delay)
+				retryC = retry.C
+				return
+			}
+			pending = pending[1:]
+		}
+	}
+	for {
+		select {
+		case ev := <-s.entries:
+			batch = append(batch, ev)
+			if len(batch) >= s.p.BatchSize {
+				flush()
+				sendPending()
+			}
+		case <-ticker.C:
+			flush()
+			sendPending()
+		case <-retryC:
+			retryC = nil
+			sendPending()
+		case <-s.stop:
+			if retry != nil {
+				retry.Stop()
+			}
+			// Only run receives from entries so this cannot block.
+			for len(s.entries) > 0 {
+				batch = append(batch, <-s.entries)
+				if len(batch) >= s.p.BatchSize {
+					flush()
+				}
+			}
+			flush()
+			// Send the remaining batches, waiting for any retries.
+			for len(pending) > 0 {
+				delay, ok := s.send(ctx, pending[0])
+				if ok {
+					pending = pending[1:]
+					continue
+				}
+				t := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return
+				case <-t.C:
+				}
+			}
+			return
+		}
+	}
+}
+
+// send attempts to send the given batch. If the batch has been sent, or
+// has failed and cannot be retried, true is returned. Otherwise the delay
+// before the batch should be retried is returned.
+func (s *WebhookSink) send(ctx context.Context, b *webhookBatch) (time.Duration, bool) {
+	retry, err := s.post(ctx, b.body)
+	if err == nil {
+		return 0, true
+	}
+	if !retry || b.attempts >= s.p.MaxRetries {
+		zapctx.Error(ctx, "cannot send audit events to webhook", zap.Int("count", b.count), zap.Error(err))
+		return 0, true
+	}
+	b.attempts++
+	zapctx.Warn(ctx, "failed to send audit events to webhook, retrying", zap.Int("attempt", b.attempts), zap.Error(err))
+	return s.p.RetryDelay << (b.attempts - 1), false
+}
+
+// post makes a single request to the webhook. If the request fails the
+// returned boolean indicates whether it is worth retrying.
+func (s *WebhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.p.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.p.Token)
+	}
+	resp, err := s.p.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, errors.E(fmt.Sprintf("unexpected status %q", resp.Status))
+	default:
+		return false, errors.E(fmt.Sprintf("unexpected status %q", resp.Status))
+	}
+}
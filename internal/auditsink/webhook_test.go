@@ -0,0 +1,226 @@
+// Copyright 2024 Canonical.
+
+package auditsink_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auditsink"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// webhookServer records the batches of audit events it receives. The
+// first few requests, as determined by failures, and any requests made
+// while the server is down receive a 503 response.
+type webhookServer struct {
+	mu       sync.Mutex
+	failures int
+	down     bool
+	requests int
+	batches  [][]apiparams.AuditEvent
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if req.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var events apiparams.AuditEvents
+	if err := json.NewDecoder(req.Body).Decode(&events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.batches = append(s.batches, events.Events)
+}
+
+func (s *webhookServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *webhookServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *webhookServer) methods() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var methods [][]string
+	for _, batch := range s.batches {
+		var m []string
+		for _, ev := range batch {
+			m = append(m, ev.FacadeMethod)
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+func TestWebhookSinkBatching(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ws := &webhookServer{failures: 1}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	s, err := auditsink.NewWebhookSink(ctx, auditsink.WebhookParams{
+		URL:           srv.URL,
+		Token:         "secret",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		RetryDelay:    time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	for _, method := range []string{"AddController", "RemoveController", "ListControllers"} {
+		err := s.Write(ctx, testAuditLogEntry(method))
+		c.Assert(err, qt.IsNil)
+	}
+	// Closing the sink sends the partial batch.
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	c.Check(ws.methods(), qt.DeepEquals, [][]string{
+		{"AddController", "RemoveController"},
+		{"ListControllers"},
+	})
+	c.Check(ws.requestCount(), qt.Equals, 3)
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Check(err, qt.ErrorMatches, `audit webhook sink closed`)
+}
+
+func TestWebhookSinkFlushInterval(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ws := &webhookServer{}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	s, err := auditsink.NewWebhookSink(ctx, auditsink.WebhookParams{
+		URL:           srv.URL,
+		Token:         "secret",
+		FlushInterval: 10 * time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+	defer s.Close()
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+
+	for i := 0; i < 500 && len(ws.methods()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Check(ws.methods(), qt.DeepEquals, [][]string{{"AddController"}})
+}
+
+func TestWebhookSinkRetriesExhausted(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ws := &webhookServer{failures: 10}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	s, err := auditsink.NewWebhookSink(ctx, auditsink.WebhookParams{
+		URL:        srv.URL,
+		Token:      "secret",
+		MaxRetries: 2,
+		RetryDelay: time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	c.Check(ws.methods(), qt.HasLen, 0)
+	c.Check(ws.requestCount(), qt.Equals, 3)
+}
+
+func TestWebhookSinkNoRetryOnClientError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ws := &webhookServer{}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	s, err := auditsink.NewWebhookSink(ctx, auditsink.WebhookParams{
+		URL:        srv.URL,
+		Token:      "wrong",
+		RetryDelay: time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Write(ctx, testAuditLogEntry("AddController"))
+	c.Assert(err, qt.IsNil)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+
+	c.Check(ws.requestCount(), qt.Equals, 1)
+}
+
+func TestWebhookSinkOutage(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ws := &webhookServer{down: true}
+	srv := httptest.NewServer(ws)
+	defer srv.Close()
+
+	s, err := auditsink.NewWebhookSink(ctx, auditsink.WebhookParams{
+		URL:           srv.URL,
+		Token:         "secret",
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		MaxRetries:    20,
+		RetryDelay:    10 * time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Write many more entries than can be queued while the sink is
+	// waiting to retry.
+	var expect [][]string
+	for i := 0; i < 50; i++ {
+		method := fmt.Sprintf("Method%d", i)
+		err := s.Write(ctx, testAuditLogEntry(method))
+		c.Assert(err, qt.IsNil)
+		expect = append(expect, []string{method})
+		time.Sleep(time.Millisecond)
+	}
+	c.Check(ws.methods(), qt.HasLen, 0)
+
+	// Once the webhook recovers all the entries are sent in order.
+	ws.setDown(false)
+	err = s.Close()
+	c.Assert(err, qt.IsNil)
+	c.Check(ws.methods(), qt.DeepEquals, expect)
+}
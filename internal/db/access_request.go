@@ -0,0 +1,169 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AccessRequestFilter can be used to find access requests.
+type AccessRequestFilter struct {
+	// Status, if set, limits the requests to those with the given
+	// status.
+	Status string
+
+	// Requester, if set, limits the requests to those made by the
+	// identity with the given name.
+	Requester string
+}
+
+// AddAccessRequest stores the given access request in the database. If
+// a pending request already exists from the same requester for the same
+// relation an error with a code of CodeAlreadyExists is returned.
+func (d *Database) AddAccessRequest(ctx context.Context, ar *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.AddAccessRequest")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(ar).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetAccessRequest fills in the given access request, which is looked up
+// by ID. If the request is not found an error with a code of CodeNotFound
+// is returned.
+func (d *Database) GetAccessRequest(ctx context.Context, ar *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.GetAccessRequest")
+
+	if ar.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "access request not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).First(ar, ar.ID).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "access request not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateAccessRequest updates the given access request.
+func (d *Database) UpdateAccessRequest(ctx context.Context, ar *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.UpdateAccessRequest")
+
+	if ar.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "access request not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Save(ar).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ReviewAccessRequest stores the status, reviewer and comment of the
+// given access request. The review is only stored if the request is
+// still pending, so that concurrent reviews of the same request cannot
+// both succeed. If the request is no longer pending an error with a code
+// of CodeBadRequest is returned.
+func (d *Database) ReviewAccessRequest(ctx context.Context, ar *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.ReviewAccessRequest")
+
+	if ar.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "access request not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(&dbmodel.AccessRequest{ID: ar.ID}).Where("status = ?", dbmodel.AccessRequestPending).Updates(map[string]interface{}{
+		"status":      ar.Status,
+		"reviewed_by": ar.ReviewedBy,
+		"comment":     ar.Comment,
+	})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeBadRequest, "access request is no longer pending")
+	}
+	return nil
+}
+
+// ForEachAccessRequest iterates through every access request matching
+// the given filter, oldest first, calling the given function for each
+// one. If the given function returns an error the iteration will stop
+// immediately and the error will be returned unmodified.
+func (d *Database) ForEachAccessRequest(ctx context.Context, filter AccessRequestFilter, f func(*dbmodel.AccessRequest) error) (err error) {
+	const op = errors.Op("db.ForEachAccessRequest")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.Requester != "" {
+		db = db.Where("requester = ?", filter.Requester)
+	}
+	rows, err := db.Model(&dbmodel.AccessRequest{}).Order("id").Rows()
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ar dbmodel.AccessRequest
+		if err := db.ScanRows(rows, &ar); err != nil {
+			return errors.E(op, dbError(err))
+		}
+		if err := f(&ar); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
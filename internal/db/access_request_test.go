@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddAccessRequestUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddAccessRequest(context.Background(), &dbmodel.AccessRequest{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAccessRequests(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	ar1 := dbmodel.AccessRequest{
+		Requester: "bob@canonical.com",
+		Resource:  "model-alice@canonical.com/model-1",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		Relation:  "writer",
+		Reason:    "on call",
+		Status:    dbmodel.AccessRequestPending,
+	}
+	err = s.Database.AddAccessRequest(ctx, &ar1)
+	c.Assert(err, qt.IsNil)
+
+	// Only one pending request may exist for the same access.
+	err = s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{
+		Requester: "bob@canonical.com",
+		Resource:  "model-00000002-0000-0000-0000-000000000001",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		Relation:  "writer",
+		Status:    dbmodel.AccessRequestPending,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	ar2 := dbmodel.AccessRequest{
+		Requester: "charlie@canonical.com",
+		Resource:  "cloud-test-cloud",
+		Target:    "cloud:test-cloud",
+		Relation:  "can_addmodel",
+		Status:    dbmodel.AccessRequestPending,
+	}
+	err = s.Database.AddAccessRequest(ctx, &ar2)
+	c.Assert(err, qt.IsNil)
+
+	ar := dbmodel.AccessRequest{ID: ar1.ID}
+	err = s.Database.GetAccessRequest(ctx, &ar)
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Requester, qt.Equals, "bob@canonical.com")
+	c.Check(ar.Reason, qt.Equals, "on call")
+
+	ar.Status = dbmodel.AccessRequestDenied
+	ar.ReviewedBy = "alice@canonical.com"
+	ar.Comment = "no"
+	err = s.Database.UpdateAccessRequest(ctx, &ar)
+	c.Assert(err, qt.IsNil)
+
+	// Once the request is no longer pending a new one may be made.
+	err = s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{
+		Requester: "bob@canonical.com",
+		Resource:  "model-00000002-0000-0000-0000-000000000001",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		Relation:  "writer",
+		Status:    dbmodel.AccessRequestPending,
+	})
+	c.Assert(err, qt.IsNil)
+
+	var requests []dbmodel.AccessRequest
+	collect := func(ar *dbmodel.AccessRequest) error {
+		requests = append(requests, *ar)
+		return nil
+	}
+	err = s.Database.ForEachAccessRequest(ctx, db.AccessRequestFilter{Status: dbmodel.AccessRequestPending}, collect)
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 2)
+	c.Check(requests[0].Requester, qt.Equals, "charlie@canonical.com")
+	c.Check(requests[1].Requester, qt.Equals, "bob@canonical.com")
+
+	requests = nil
+	err = s.Database.ForEachAccessRequest(ctx, db.AccessRequestFilter{Requester: "bob@canonical.com"}, collect)
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 2)
+	c.Check(requests[0].Status, qt.Equals, dbmodel.AccessRequestDenied)
+	c.Check(requests[0].ReviewedBy, qt.Equals, "alice@canonical.com")
+	c.Check(requests[1].Status, qt.Equals, dbmodel.AccessRequestPending)
+
+	// Only one review of a pending request succeeds.
+	review := dbmodel.AccessRequest{ID: ar2.ID, Status: dbmodel.AccessRequestApproved, ReviewedBy: "alice@canonical.com"}
+	err = s.Database.ReviewAccessRequest(ctx, &review)
+	c.Assert(err, qt.IsNil)
+	review = dbmodel.AccessRequest{ID: ar2.ID, Status: dbmodel.AccessRequestDenied, ReviewedBy: "bob@canonical.com"}
+	err = s.Database.ReviewAccessRequest(ctx, &review)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Check(err, qt.ErrorMatches, `access request is no longer pending`)
+	ar = dbmodel.AccessRequest{ID: ar2.ID}
+	err = s.Database.GetAccessRequest(ctx, &ar)
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestApproved)
+	c.Check(ar.ReviewedBy, qt.Equals, "alice@canonical.com")
+
+	err = s.Database.GetAccessRequest(ctx, &dbmodel.AccessRequest{ID: 1000})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
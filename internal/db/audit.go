@@ -6,12 +6,41 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
-// AddAuditLogEntry adds a new entry to the audit log.
+// auditLogLockID is the key of the postgres advisory lock taken whilst
+// modifying the audit log. The lock ensures that the hash chain is
+// extended by one entry at a time, even with multiple JIMM servers.
+//
+// As there is a single chain, when AuditLogHashChain is set every audited
+// request, from every JIMM server, waits for this lock. The hash of an entry is calculated by JIMM
+// so the lock is held while the previous hash is read, the new entry is
+// inserted and the transaction is committed, each a round trip to the
+// database. The rate at which entries can be added is therefore bounded
+// by the database latency rather than the number of JIMM servers. Time
+// spent waiting for the lock is included in the db.AddAuditLogEntry
+// query duration metric. DeleteAuditLogsBefore also holds the lock, so
+// audited requests wait for any purge of the audit log to complete.
+const auditLogLockID = 0x6a696d6d61756474
+
+// lockAuditLog takes the audit log lock for the duration of the given
+// transaction. As the lock serializes all audited requests nothing that
+// does not need the lock should be done in the transaction after the lock
+// is taken.
+func lockAuditLog(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", auditLogLockID).Error
+}
+
+// AddAuditLogEntry adds a new entry to the audit log. If AuditLogHashChain
+// is set the entry is chained to the previously added entry by setting its
+// PreviousHash and Hash fields. The entry time is truncated to the
+// precision stored in the database so that the hash can be recalculated
+// from the stored entry.
 func (d *Database) AddAuditLogEntry(ctx context.Context, ale *dbmodel.AuditLogEntry) (err error) {
 	const op = errors.Op("db.AddAuditLogEntry")
 
@@ -23,7 +52,28 @@ func (d *Database) AddAuditLogEntry(ctx context.Context, ale *dbmodel.AuditLogEn
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
-	if err := d.DB.WithContext(ctx).Create(ale).Error; err != nil {
+	ale.Time = ale.Time.Truncate(time.Microsecond)
+	if !d.AuditLogHashChain {
+		if err := d.DB.WithContext(ctx).Create(ale).Error; err != nil {
+			return errors.E(op, dbError(err))
+		}
+		return nil
+	}
+	// The lock is taken first so that the previous entry cannot change
+	// before the new entry is inserted, see auditLogLockID.
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAuditLog(tx); err != nil {
+			return err
+		}
+		var prev dbmodel.AuditLogEntry
+		if err := tx.Select("hash").Order("id DESC").Limit(1).Find(&prev).Error; err != nil {
+			return err
+		}
+		ale.PreviousHash = prev.Hash
+		ale.Hash = ale.ComputeHash()
+		return tx.Create(ale).Error
+	})
+	if err != nil {
 		return errors.E(op, dbError(err))
 	}
 	return nil
@@ -53,6 +103,27 @@ type AuditLogFilter struct {
 	// called a specific facade method.
 	Method string `json:"method,omitempty"`
 
+	// FacadeName is used to filter the event log to only contain events
+	// that called a specific facade.
+	FacadeName string `json:"facadeName,omitempty"`
+
+	// ConversationId is used to filter the event log to only contain
+	// events from a single conversation.
+	ConversationId string `json:"conversationId,omitempty"`
+
+	// ObjectId is used to filter the event log to only contain events
+	// that acted on a specific object.
+	ObjectId string `json:"objectId,omitempty"`
+
+	// HasErrors is used to filter the event log to only contain
+	// responses that contain at least one error.
+	HasErrors bool `json:"hasErrors,omitempty"`
+
+	// ParamsContain is used to filter the event log to only contain
+	// events whose parameters contain the given JSON document, as
+	// defined by the postgres JSONB containment operator.
+	ParamsContain string `json:"paramsContain,omitempty"`
+
 	// Offset is an offset that will be added when retrieving audit logs.
 	// An empty offset is equivalent to zero.
 	Offset int `json:"offset,omitempty"`
@@ -64,8 +135,37 @@ type AuditLogFilter struct {
 	// SortTime will sort by most recent first (time descending) when true.
 	// When false no explicit ordering will be applied.
 	SortTime bool `json:"sortTime,omitempty"`
+
+	// SortID will sort by ID ascending, the order in which the entries
+	// were added, when true. SortTime takes precedence if both are set.
+	SortID bool `json:"sortID,omitempty"`
+
+	// SortKeyset will sort by time and then ID when true, descending if
+	// SortTime is set and ascending otherwise. This gives a stable order
+	// that can be paginated using After.
+	SortKeyset bool `json:"sortKeyset,omitempty"`
+
+	// After, if set, restricts the results to entries that come after
+	// the given position in the order defined by SortKeyset. After
+	// implies SortKeyset.
+	After *AuditLogPosition `json:"after,omitempty"`
+
+	// Restored will find entries that have been restored from audit log
+	// archives, rather than entries in the current audit log, when true.
+	Restored bool `json:"restored,omitempty"`
 }
 
+// An AuditLogPosition is the position of an entry in the audit log when
+// ordered by time and then ID.
+type AuditLogPosition struct {
+	Time time.Time `json:"time"`
+	ID   uint      `json:"id"`
+}
+
+// auditLogErrorsPath is a JSON path that matches the errors of an audit
+// log response that contains at least one error.
+const auditLogErrorsPath = `$.results[*].error ? (@.message != "" || @.code != "")`
+
 // ForEachAuditLogEntry iterates through all audit log entries that match
 // the given filter calling f for each entry. If f returns an error
 // iteration stops immediately and the error is retuned unmodified.
@@ -80,6 +180,9 @@ func (d *Database) ForEachAuditLogEntry(ctx context.Context, filter AuditLogFilt
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
 	db := d.DB.WithContext(ctx).Model(&dbmodel.AuditLogEntry{})
+	if filter.Restored {
+		db = db.Table(restoredAuditLogTable)
+	}
 	if !filter.Start.IsZero() {
 		db = db.Where("time >= ?", filter.Start)
 	}
@@ -95,8 +198,37 @@ func (d *Database) ForEachAuditLogEntry(ctx context.Context, filter AuditLogFilt
 	if filter.Method != "" {
 		db = db.Where("facade_method = ?", filter.Method)
 	}
-	if filter.SortTime {
+	if filter.FacadeName != "" {
+		db = db.Where("facade_name = ?", filter.FacadeName)
+	}
+	if filter.ConversationId != "" {
+		db = db.Where("conversation_id = ?", filter.ConversationId)
+	}
+	if filter.ObjectId != "" {
+		db = db.Where("object_id = ?", filter.ObjectId)
+	}
+	if filter.HasErrors {
+		db = db.Where("is_response AND jsonb_path_exists(errors::jsonb, ?::jsonpath)", auditLogErrorsPath)
+	}
+	if filter.ParamsContain != "" {
+		db = db.Where("params::jsonb @> ?::jsonb", filter.ParamsContain)
+	}
+	if filter.SortKeyset || filter.After != nil {
+		if filter.SortTime {
+			if filter.After != nil {
+				db = db.Where("(time, id) < (?, ?)", filter.After.Time, filter.After.ID)
+			}
+			db = db.Order("time DESC, id DESC")
+		} else {
+			if filter.After != nil {
+				db = db.Where("(time, id) > (?, ?)", filter.After.Time, filter.After.ID)
+			}
+			db = db.Order("time, id")
+		}
+	} else if filter.SortTime {
 		db = db.Order("time DESC")
+	} else if filter.SortID {
+		db = db.Order("id")
 	}
 	db = db.Limit(filter.Limit)
 	db = db.Offset(filter.Offset)
@@ -121,8 +253,13 @@ func (d *Database) ForEachAuditLogEntry(ctx context.Context, filter AuditLogFilt
 	return nil
 }
 
-// CleanupAuditLogs cleans up audit logs after the auditLogRetentionPeriodInDays,
-// HARD deleting them from the database.
+// DeleteAuditLogsBefore HARD deletes all audit log entries from before the
+// given time. For every remaining entry whose previous entry is deleted a
+// checkpoint is recorded holding the deleted entry's hash and the hash of
+// the nearest remaining entry before it, so that the hash chain can still
+// be verified. Only the entries being deleted, and the entry following
+// each of them, are read to find these links. Checkpoints for deleted
+// entries are removed.
 func (d *Database) DeleteAuditLogsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
 	const op = errors.Op("db.DeleteAuditLogsBefore")
 
@@ -134,13 +271,78 @@ func (d *Database) DeleteAuditLogsBefore(ctx context.Context, before time.Time)
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
-	tx := d.DB.
-		WithContext(ctx).
-		Unscoped().
-		Where("time < ?", before).
-		Delete(&dbmodel.AuditLogEntry{})
-	if tx.Error != nil {
-		return 0, errors.E(op, dbError(tx.Error))
+	var deleted int64
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAuditLog(tx); err != nil {
+			return err
+		}
+		now := time.Now()
+		err := tx.Exec(`INSERT INTO audit_log_checkpoints (created_at, updated_at, entry_id, previous_hash, preceding_hash, purged_before)
+			SELECT ?, ?, e.id, e.previous_hash, COALESCE((
+				SELECT p.hash FROM audit_log AS p
+				WHERE p.id < e.id AND (p.time IS NULL OR p.time >= ?)
+				ORDER BY p.id DESC LIMIT 1
+			), ''), ? FROM audit_log AS d
+			JOIN LATERAL (
+				SELECT n.id, n.time, n.previous_hash FROM audit_log AS n
+				WHERE n.id > d.id
+				ORDER BY n.id LIMIT 1
+			) AS e ON true
+			WHERE d.time < ? AND (e.time IS NULL OR e.time >= ?)`,
+			now, now, before, before, before, before,
+		).Error
+		if err != nil {
+			return err
+		}
+		err = tx.
+			Where("entry_id IN (?)", tx.Model(&dbmodel.AuditLogEntry{}).Select("id").Where("time < ?", before)).
+			Delete(&dbmodel.AuditLogCheckpoint{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("time < ?", before).Delete(&dbmodel.AuditLogEntry{})
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return deleted, nil
+}
+
+// ForEachAuditLogCheckpoint iterates through all audit log checkpoints in
+// ID order calling f for each checkpoint. If f returns an error iteration
+// stops immediately and the error is returned unmodified.
+func (d *Database) ForEachAuditLogCheckpoint(ctx context.Context, f func(*dbmodel.AuditLogCheckpoint) error) (err error) {
+	const op = errors.Op("db.ForEachAuditLogCheckpoint")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(&dbmodel.AuditLogCheckpoint{}).Order("id")
+	rows, err := db.Rows()
+	if err != nil {
+		return errors.E(op, dbError(err))
 	}
-	return tx.RowsAffected, nil
+	defer rows.Close()
+	for rows.Next() {
+		var cp dbmodel.AuditLogCheckpoint
+		if err := db.ScanRows(rows, &cp); err != nil {
+			return errors.E(op, dbError(err))
+		}
+		if err := f(&cp); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
 }
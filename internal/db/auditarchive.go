@@ -0,0 +1,154 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// restoredAuditLogTable is the name of the table holding audit log
+// entries restored from archives. It has the same columns as the
+// audit_log table.
+const restoredAuditLogTable = "restored_audit_log"
+
+// restoreBatchSize is the maximum number of entries inserted in a single
+// statement when restoring audit log entries.
+const restoreBatchSize = 1000
+
+// AddAuditLogArchive records a new audit log archive segment.
+func (d *Database) AddAuditLogArchive(ctx context.Context, a *dbmodel.AuditLogArchive) (err error) {
+	const op = errors.Op("db.AddAuditLogArchive")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(a).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetAuditLogArchive fills in the given audit log archive based on its
+// ID. An error with a code of errors.CodeNotFound is returned if there is
+// no such archive.
+func (d *Database) GetAuditLogArchive(ctx context.Context, a *dbmodel.AuditLogArchive) (err error) {
+	const op = errors.Op("db.GetAuditLogArchive")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if a.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "audit log archive not found")
+	}
+	if err := d.DB.WithContext(ctx).First(a, a.ID).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "audit log archive not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateAuditLogArchive updates the given audit log archive.
+func (d *Database) UpdateAuditLogArchive(ctx context.Context, a *dbmodel.AuditLogArchive) (err error) {
+	const op = errors.Op("db.UpdateAuditLogArchive")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Save(a).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ForEachAuditLogArchive iterates through all audit log archives in ID
+// order calling f for each archive. If f returns an error iteration stops
+// immediately and the error is returned unmodified.
+func (d *Database) ForEachAuditLogArchive(ctx context.Context, f func(*dbmodel.AuditLogArchive) error) (err error) {
+	const op = errors.Op("db.ForEachAuditLogArchive")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(&dbmodel.AuditLogArchive{}).Order("id")
+	rows, err := db.Rows()
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a dbmodel.AuditLogArchive
+		if err := db.ScanRows(rows, &a); err != nil {
+			return errors.E(op, dbError(err))
+		}
+		if err := f(&a); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddRestoredAuditLogEntries adds the given entries, restored from an
+// archive, to the restored audit log. Entries are stored with their
+// original IDs, entries that have already been restored are skipped. The
+// number of entries added is returned.
+func (d *Database) AddRestoredAuditLogEntries(ctx context.Context, entries []dbmodel.AuditLogEntry) (_ int64, err error) {
+	const op = errors.Op("db.AddRestoredAuditLogEntries")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var added int64
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for len(entries) > 0 {
+			n := len(entries)
+			if n > restoreBatchSize {
+				n = restoreBatchSize
+			}
+			batch := entries[:n]
+			entries = entries[n:]
+			res := tx.Table(restoredAuditLogTable).Clauses(clause.OnConflict{DoNothing: true}).Create(&batch)
+			if res.Error != nil {
+				return res.Error
+			}
+			added += res.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return added, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddAuditLogArchiveUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddAuditLogArchive(context.Background(), &dbmodel.AuditLogArchive{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAuditLogArchive(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	a1 := dbmodel.AuditLogArchive{
+		Key:          "audit-log/run-1/1-2.ndjson.gz",
+		FirstEntryID: 1,
+		LastEntryID:  2,
+		StartTime:    now.Add(-time.Hour),
+		EndTime:      now,
+		EntryCount:   2,
+		Size:         100,
+		SHA256:       "0123",
+	}
+	err := s.Database.AddAuditLogArchive(ctx, &a1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddAuditLogArchive(ctx, &a1)
+	c.Assert(err, qt.IsNil)
+	a2 := a1
+	a2.ID = 0
+	err = s.Database.AddAuditLogArchive(ctx, &a2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+	a2.Key = "audit-log/run-1/3-3.ndjson.gz"
+	a2.FirstEntryID = 3
+	a2.LastEntryID = 3
+	err = s.Database.AddAuditLogArchive(ctx, &a2)
+	c.Assert(err, qt.IsNil)
+
+	a := dbmodel.AuditLogArchive{ID: a1.ID}
+	err = s.Database.GetAuditLogArchive(ctx, &a)
+	c.Assert(err, qt.IsNil)
+	c.Check(a.Key, qt.Equals, a1.Key)
+	c.Check(a.RestoredAt.Valid, qt.IsFalse)
+
+	a.RestoredAt = sql.NullTime{Time: now, Valid: true}
+	err = s.Database.UpdateAuditLogArchive(ctx, &a)
+	c.Assert(err, qt.IsNil)
+
+	var archives []dbmodel.AuditLogArchive
+	err = s.Database.ForEachAuditLogArchive(ctx, func(a *dbmodel.AuditLogArchive) error {
+		archives = append(archives, *a)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(archives, qt.HasLen, 2)
+	c.Check(archives[0].ID, qt.Equals, a1.ID)
+	c.Check(archives[0].RestoredAt.Time.Equal(now), qt.IsTrue)
+	c.Check(archives[1].ID, qt.Equals, a2.ID)
+
+	err = s.Database.GetAuditLogArchive(ctx, &dbmodel.AuditLogArchive{ID: a2.ID + 1})
+	c.Check(err, qt.ErrorMatches, `audit log archive not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestAddRestoredAuditLogEntries(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	entries := []dbmodel.AuditLogEntry{{
+		ID:           10,
+		Time:         now.Add(-time.Hour),
+		FacadeName:   "JIMM",
+		FacadeMethod: "ListControllers",
+	}, {
+		ID:           11,
+		Time:         now.Add(-time.Minute),
+		FacadeName:   "JIMM",
+		FacadeMethod: "AddController",
+	}}
+	n, err := s.Database.AddRestoredAuditLogEntries(ctx, entries)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+
+	// Entries that have already been restored are skipped.
+	n, err = s.Database.AddRestoredAuditLogEntries(ctx, entries)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(0))
+
+	var restored []dbmodel.AuditLogEntry
+	err = s.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Restored: true, Method: "AddController"}, func(ale *dbmodel.AuditLogEntry) error {
+		restored = append(restored, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(restored, qt.HasLen, 1)
+	c.Check(restored[0].ID, qt.Equals, uint(11))
+
+	// Restored entries are not part of the audit log.
+	var count int
+	err = s.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{}, func(*dbmodel.AuditLogEntry) error {
+		count++
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 0)
+}
@@ -63,17 +63,41 @@ func TestForEachAuditLogEntryUnconfiguredDatabase(t *testing.T) {
 }
 
 var testAuditLogEntries = []dbmodel.AuditLogEntry{{
-	Time:        time.Date(2020, time.February, 20, 20, 2, 20, 0, time.UTC),
-	IdentityTag: names.NewUserTag("alice@canonical.com").String(),
+	Time:           time.Date(2020, time.February, 20, 20, 2, 20, 0, time.UTC),
+	IdentityTag:    names.NewUserTag("alice@canonical.com").String(),
+	ConversationId: "0000000000000001",
+	MessageId:      1,
+	FacadeName:     "ModelManager",
+	FacadeMethod:   "CreateModel",
+	ObjectId:       "model-1",
+	Params:         dbmodel.JSON(`{"name":"model-1","owner-tag":"user-alice@canonical.com"}`),
 }, {
-	Time:        time.Date(2020, time.February, 20, 20, 2, 21, 0, time.UTC),
-	IdentityTag: names.NewUserTag("alice@canonical.com").String(),
+	Time:           time.Date(2020, time.February, 20, 20, 2, 21, 0, time.UTC),
+	IdentityTag:    names.NewUserTag("alice@canonical.com").String(),
+	ConversationId: "0000000000000001",
+	MessageId:      1,
+	FacadeName:     "ModelManager",
+	FacadeMethod:   "CreateModel",
+	ObjectId:       "model-1",
+	IsResponse:     true,
+	Errors:         dbmodel.JSON(`{"results":[{"error":{"message":"model already exists","code":"already exists"}}]}`),
 }, {
-	Time:        time.Date(2020, time.February, 20, 20, 2, 21, 0, time.UTC),
-	IdentityTag: names.NewUserTag("bob@canonical.com").String(),
+	Time:           time.Date(2020, time.February, 20, 20, 2, 21, 0, time.UTC),
+	IdentityTag:    names.NewUserTag("bob@canonical.com").String(),
+	ConversationId: "0000000000000002",
+	MessageId:      1,
+	FacadeName:     "Client",
+	FacadeMethod:   "FullStatus",
+	Params:         dbmodel.JSON(`{"patterns":null}`),
 }, {
-	Time:        time.Date(2020, time.February, 20, 20, 2, 23, 0, time.UTC),
-	IdentityTag: names.NewUserTag("alice@canonical.com").String(),
+	Time:           time.Date(2020, time.February, 20, 20, 2, 23, 0, time.UTC),
+	IdentityTag:    names.NewUserTag("alice@canonical.com").String(),
+	ConversationId: "0000000000000002",
+	MessageId:      1,
+	FacadeName:     "Client",
+	FacadeMethod:   "FullStatus",
+	IsResponse:     true,
+	Errors:         dbmodel.JSON(`{"results":[{"error":{"message":"","code":""}}]}`),
 }}
 
 var forEachAuditLogEntryTests = []struct {
@@ -109,6 +133,36 @@ var forEachAuditLogEntryTests = []struct {
 		IdentityTag: names.NewUserTag("alice@canonical.com").String(),
 	},
 	expectEntries: []int{0, 1, 3},
+}, {
+	name: "FacadeFilter",
+	filter: db.AuditLogFilter{
+		FacadeName: "ModelManager",
+	},
+	expectEntries: []int{0, 1},
+}, {
+	name: "ConversationFilter",
+	filter: db.AuditLogFilter{
+		ConversationId: "0000000000000002",
+	},
+	expectEntries: []int{2, 3},
+}, {
+	name: "ObjectFilter",
+	filter: db.AuditLogFilter{
+		ObjectId: "model-1",
+	},
+	expectEntries: []int{0, 1},
+}, {
+	name: "ErrorsFilter",
+	filter: db.AuditLogFilter{
+		HasErrors: true,
+	},
+	expectEntries: []int{1},
+}, {
+	name: "ParamsFilter",
+	filter: db.AuditLogFilter{
+		ParamsContain: `{"name":"model-1"}`,
+	},
+	expectEntries: []int{0},
 }}
 
 func (s *dbSuite) TestForEachAuditLogEntry(c *qt.C) {
@@ -150,6 +204,55 @@ func (s *dbSuite) TestForEachAuditLogEntry(c *qt.C) {
 	c.Check(err, qt.DeepEquals, testError)
 }
 
+func (s *dbSuite) TestForEachAuditLogEntryKeyset(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Migrate(context.Background(), false)
+	c.Assert(err, qt.IsNil)
+
+	t0 := time.Date(2020, time.February, 20, 20, 2, 20, 0, time.UTC)
+	var entries []dbmodel.AuditLogEntry
+	for _, secs := range []int{2, 0, 1, 1, 3} {
+		ale := dbmodel.AuditLogEntry{Time: t0.Add(time.Duration(secs) * time.Second)}
+		err := s.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, qt.IsNil)
+		entries = append(entries, ale)
+	}
+
+	// page reads the IDs of the entries that match the filter.
+	page := func(filter db.AuditLogFilter) []uint {
+		var ids []uint
+		err := s.Database.ForEachAuditLogEntry(ctx, filter, func(ale *dbmodel.AuditLogEntry) error {
+			ids = append(ids, ale.ID)
+			return nil
+		})
+		c.Assert(err, qt.IsNil)
+		return ids
+	}
+
+	ids := page(db.AuditLogFilter{SortKeyset: true, Limit: 2})
+	c.Check(ids, qt.DeepEquals, []uint{entries[1].ID, entries[2].ID})
+	ids = page(db.AuditLogFilter{
+		Limit: 2,
+		After: &db.AuditLogPosition{Time: entries[2].Time, ID: entries[2].ID},
+	})
+	c.Check(ids, qt.DeepEquals, []uint{entries[3].ID, entries[0].ID})
+	ids = page(db.AuditLogFilter{
+		Limit: 2,
+		After: &db.AuditLogPosition{Time: entries[0].Time, ID: entries[0].ID},
+	})
+	c.Check(ids, qt.DeepEquals, []uint{entries[4].ID})
+
+	ids = page(db.AuditLogFilter{SortKeyset: true, SortTime: true, Limit: 2})
+	c.Check(ids, qt.DeepEquals, []uint{entries[4].ID, entries[0].ID})
+	ids = page(db.AuditLogFilter{
+		SortTime: true,
+		Limit:    2,
+		After:    &db.AuditLogPosition{Time: entries[0].Time, ID: entries[0].ID},
+	})
+	c.Check(ids, qt.DeepEquals, []uint{entries[3].ID, entries[2].ID})
+}
+
 func (s *dbSuite) TestDeleteAuditLogsBefore(c *qt.C) {
 	ctx := context.Background()
 	now := time.Now()
@@ -234,3 +337,102 @@ func (s *dbSuite) TestPurgeLogsFromDb(c *qt.C) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(deleted_count, qt.Equals, int64(2))
 }
+
+func (s *dbSuite) TestAddAuditLogEntryHashChain(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Migrate(context.Background(), false)
+	c.Assert(err, qt.IsNil)
+
+	// Entries are not chained unless the hash chain is enabled.
+	ale := dbmodel.AuditLogEntry{Time: time.Now()}
+	err = s.Database.AddAuditLogEntry(ctx, &ale)
+	c.Assert(err, qt.IsNil)
+	c.Check(ale.Hash, qt.Equals, "")
+	err = s.Database.DB.Delete(&ale).Error
+	c.Assert(err, qt.IsNil)
+
+	s.Database.AuditLogHashChain = true
+
+	// The time is truncated to the precision stored in the database
+	// so that the hash can be verified.
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		ale := dbmodel.AuditLogEntry{
+			Time:        now.Add(time.Duration(i) * time.Nanosecond),
+			IdentityTag: names.NewUserTag("alice@canonical.com").String(),
+			Params:      dbmodel.JSON(`{"a": "b"}`),
+		}
+		err := s.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, qt.IsNil)
+	}
+
+	var prev string
+	var count int
+	err = s.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{SortID: true}, func(ale *dbmodel.AuditLogEntry) error {
+		c.Check(ale.PreviousHash, qt.Equals, prev)
+		c.Check(ale.Hash, qt.Equals, ale.ComputeHash())
+		prev = ale.Hash
+		count++
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, 3)
+}
+
+func TestForEachAuditLogCheckpointUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.ForEachAuditLogCheckpoint(context.Background(), nil)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestDeleteAuditLogsBeforeRecordsCheckpoints(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	err := s.Database.Migrate(context.Background(), false)
+	c.Assert(err, qt.IsNil)
+	s.Database.AuditLogHashChain = true
+
+	var entries []dbmodel.AuditLogEntry
+	for _, days := range []int{-5, -4, -1, -3, -1} {
+		ale := dbmodel.AuditLogEntry{Time: now.AddDate(0, 0, days)}
+		err := s.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, qt.IsNil)
+		entries = append(entries, ale)
+	}
+
+	deleted, err := s.Database.DeleteAuditLogsBefore(ctx, now.AddDate(0, 0, -2))
+	c.Assert(err, qt.IsNil)
+	c.Check(deleted, qt.Equals, int64(3))
+
+	var checkpoints []dbmodel.AuditLogCheckpoint
+	err = s.Database.ForEachAuditLogCheckpoint(ctx, func(cp *dbmodel.AuditLogCheckpoint) error {
+		checkpoints = append(checkpoints, *cp)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(checkpoints, qt.HasLen, 2)
+	c.Check(checkpoints[0].EntryID, qt.Equals, entries[2].ID)
+	c.Check(checkpoints[0].PreviousHash, qt.Equals, entries[1].Hash)
+	c.Check(checkpoints[0].PrecedingHash, qt.Equals, "")
+	c.Check(checkpoints[1].EntryID, qt.Equals, entries[4].ID)
+	c.Check(checkpoints[1].PreviousHash, qt.Equals, entries[3].Hash)
+	c.Check(checkpoints[1].PrecedingHash, qt.Equals, entries[2].Hash)
+
+	// Purging the remaining entries removes their checkpoints.
+	deleted, err = s.Database.DeleteAuditLogsBefore(ctx, now)
+	c.Assert(err, qt.IsNil)
+	c.Check(deleted, qt.Equals, int64(2))
+
+	checkpoints = nil
+	err = s.Database.ForEachAuditLogCheckpoint(ctx, func(cp *dbmodel.AuditLogCheckpoint) error {
+		checkpoints = append(checkpoints, *cp)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(checkpoints, qt.HasLen, 0)
+}
@@ -212,3 +212,27 @@ func (d *Database) DeleteCloudRegionControllerPriority(ctx context.Context, c *d
 	}
 	return nil
 }
+
+// UpdateCloudRegionControllerPriority updates the priority of the given
+// cloud region controller priority entry.
+func (d *Database) UpdateCloudRegionControllerPriority(ctx context.Context, c *dbmodel.CloudRegionControllerPriority) (err error) {
+	const op = errors.Op("db.UpdateCloudRegionControllerPriority")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	result := db.Model(c).Update("priority", c.Priority)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "cloud region controller priority not found")
+	}
+	return nil
+}
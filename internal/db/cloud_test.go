@@ -388,3 +388,61 @@ controllers:
 		}
 	}
 }
+
+func TestUpdateCloudRegionControllerPriorityUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.UpdateCloudRegionControllerPriority(context.Background(), nil)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpdateCloudRegionControllerPriority(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Migrate(context.Background(), false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `clouds:
+- name: test-cloud-1
+  type: testp
+  regions:
+  - name: test-region-1
+controllers:
+- name: test
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud-1
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud-1
+    region: test-region-1
+    priority: 1
+`)
+	env.PopulateDB(c, *s.Database)
+
+	cl := dbmodel.Cloud{
+		Name: "test-cloud-1",
+	}
+	err = s.Database.GetCloud(ctx, &cl)
+	c.Assert(err, qt.IsNil)
+
+	crp := cl.Regions[0].Controllers[0]
+	crp.Priority = 5
+	err = s.Database.UpdateCloudRegionControllerPriority(ctx, &crp)
+	c.Assert(err, qt.IsNil)
+
+	cl2 := dbmodel.Cloud{
+		Name: cl.Name,
+	}
+	err = s.Database.GetCloud(ctx, &cl2)
+	c.Assert(err, qt.IsNil)
+	c.Check(cl2.Regions[0].Controllers[0].Priority, qt.Equals, uint(5))
+
+	err = s.Database.UpdateCloudRegionControllerPriority(ctx, &dbmodel.CloudRegionControllerPriority{
+		Model: gorm.Model{
+			ID: 1000,
+		},
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddControllerDrain stores the given controller drain, along with its
+// models, in the database. If a drain already exists for the controller
+// an error with a code of CodeAlreadyExists is returned.
+func (d *Database) AddControllerDrain(ctx context.Context, cd *dbmodel.ControllerDrain) (err error) {
+	const op = errors.Op("db.AddControllerDrain")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Omit("Controller").Create(cd).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetControllerDrain fills in the given controller drain. The drain is
+// looked up by controller ID. If no drain exists for the controller an
+// error with a code of CodeNotFound is returned.
+func (d *Database) GetControllerDrain(ctx context.Context, cd *dbmodel.ControllerDrain) (err error) {
+	const op = errors.Op("db.GetControllerDrain")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	db = preloadControllerDrain(db)
+	if err := db.Where("controller_id = ?", cd.ControllerID).First(cd).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "controller drain not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateControllerDrain updates the given controller drain and all of its
+// models. Any models not already stored are added.
+func (d *Database) UpdateControllerDrain(ctx context.Context, cd *dbmodel.ControllerDrain) (err error) {
+	const op = errors.Op("db.UpdateControllerDrain")
+
+	if cd.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "controller drain not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true})
+	if err := db.Omit("Controller").Save(cd).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ForEachControllerDrain iterates through every controller drain with the
+// given status calling the given function for each one. If the status is
+// empty all drains are iterated. If the given function returns an error
+// the iteration will stop immediately and the error will be returned
+// unmodified.
+func (d *Database) ForEachControllerDrain(ctx context.Context, status string, f func(*dbmodel.ControllerDrain) error) (err error) {
+	const op = errors.Op("db.ForEachControllerDrain")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	db = preloadControllerDrain(db)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	// Drains are loaded in full as the model associations cannot be
+	// preloaded when scanning rows.
+	var drains []dbmodel.ControllerDrain
+	if err := db.Order("id").Find(&drains).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	for i := range drains {
+		if err := f(&drains[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func preloadControllerDrain(db *gorm.DB) *gorm.DB {
+	return db.Preload("Controller").Preload("Models", func(db *gorm.DB) *gorm.DB {
+		return db.Order("model_name, model_uuid")
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddControllerDrainUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddControllerDrain(context.Background(), &dbmodel.ControllerDrain{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAddGetUpdateControllerDrain(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddControllerDrain(ctx, &dbmodel.ControllerDrain{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+		UUID: "00000001-0000-0000-0000-000000000001",
+	}
+	err = s.Database.AddController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	cd := dbmodel.ControllerDrain{
+		ControllerID: ctl.ID,
+		Initiator:    "alice@canonical.com",
+		Status:       dbmodel.DrainStatusRunning,
+		BatchSize:    2,
+		Models: []dbmodel.ControllerDrainModel{{
+			ModelUUID: "00000002-0000-0000-0000-000000000002",
+			ModelName: "model-b",
+			Status:    dbmodel.DrainModelStatusPending,
+		}, {
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			ModelName: "model-a",
+			Status:    dbmodel.DrainModelStatusPending,
+		}},
+	}
+	err = s.Database.AddControllerDrain(ctx, &cd)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddControllerDrain(ctx, &dbmodel.ControllerDrain{
+		ControllerID: ctl.ID,
+		Status:       dbmodel.DrainStatusRunning,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	cd2 := dbmodel.ControllerDrain{
+		ControllerID: ctl.ID,
+	}
+	err = s.Database.GetControllerDrain(ctx, &cd2)
+	c.Assert(err, qt.IsNil)
+	c.Check(cd2.Controller.Name, qt.Equals, "test-controller")
+	c.Check(cd2.Initiator, qt.Equals, "alice@canonical.com")
+	c.Assert(cd2.Models, qt.HasLen, 2)
+	c.Check(cd2.Models[0].ModelName, qt.Equals, "model-a")
+	c.Check(cd2.Models[1].ModelName, qt.Equals, "model-b")
+	c.Check(cd2.Finished(), qt.IsFalse)
+
+	cd2.Models[0].Status = dbmodel.DrainModelStatusMigrated
+	cd2.Models[1].Status = dbmodel.DrainModelStatusFailed
+	cd2.Models[1].Error = "test error"
+	cd2.Models = append(cd2.Models, dbmodel.ControllerDrainModel{
+		ModelUUID: "00000002-0000-0000-0000-000000000003",
+		ModelName: "model-c",
+		Status:    dbmodel.DrainModelStatusMigrated,
+	})
+	cd2.Status = dbmodel.DrainStatusFailed
+	err = s.Database.UpdateControllerDrain(ctx, &cd2)
+	c.Assert(err, qt.IsNil)
+
+	var drains []dbmodel.ControllerDrain
+	err = s.Database.ForEachControllerDrain(ctx, dbmodel.DrainStatusRunning, func(cd *dbmodel.ControllerDrain) error {
+		drains = append(drains, *cd)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(drains, qt.HasLen, 0)
+
+	err = s.Database.ForEachControllerDrain(ctx, "", func(cd *dbmodel.ControllerDrain) error {
+		drains = append(drains, *cd)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(drains, qt.HasLen, 1)
+	c.Check(drains[0].Status, qt.Equals, dbmodel.DrainStatusFailed)
+	c.Assert(drains[0].Models, qt.HasLen, 3)
+	c.Check(drains[0].Models[1].Error, qt.Equals, "test error")
+	c.Check(drains[0].Models[2].ModelName, qt.Equals, "model-c")
+	c.Check(drains[0].Finished(), qt.IsTrue)
+
+	err = s.Database.GetControllerDrain(ctx, &dbmodel.ControllerDrain{ControllerID: ctl.ID + 1})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -23,6 +23,15 @@ type Database struct {
 	// DB contains the gorm database storing the data.
 	DB *gorm.DB
 
+	// AuditLogHashChain, if true, causes audit log entries to be added
+	// to a tamper-evident hash chain. Extending the chain serializes all
+	// additions to the audit log, see auditLogLockID, so it should only
+	// be enabled when the tamper evidence is required. Every JIMM server
+	// sharing the database should use the same setting, entries added
+	// without a hash after the chain has started are reported as
+	// breaking the chain.
+	AuditLogHashChain bool
+
 	// migrated holds whether the database has been successfully migrated
 	// to the current database version. The value of migrated should always
 	// be read using atomic.LoadUint32 and will contain a 0 if the
@@ -4,12 +4,36 @@ package db
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
+// IdentityFilter can be used to find specific identities.
+type IdentityFilter struct {
+	// Match, if set, limits the identities to those whose name or
+	// display name contains the given string, ignoring case.
+	Match string
+
+	// Disabled is used to only list disabled identities.
+	Disabled bool
+
+	// LastLoginBefore, if set, limits the identities to those that have
+	// not logged in since the given time. Identities that have never
+	// logged in are included if they were created before the given time.
+	LastLoginBefore time.Time
+
+	// Offset is an offset that will be added when listing identities.
+	Offset int
+
+	// Limit is the maximum number of identities to return. A value of
+	// zero will ignore the limit.
+	Limit int
+}
+
 // GetIdentity loads the details for the identity identified by name. If
 // necessary the identity record will be created, in which case the identity will
 // have access to no resources and the default add-model access on JIMM.
@@ -63,7 +87,7 @@ func (d *Database) FetchIdentity(ctx context.Context, u *dbmodel.Identity) (err
 
 	db := d.DB.WithContext(ctx)
 	if err := db.Where("name = ?", u.Name).First(&u).Error; err != nil {
-		return errors.E(op, err)
+		return errors.E(op, dbError(err))
 	}
 	return nil
 }
@@ -119,3 +143,40 @@ func (d *Database) GetIdentityCloudCredentials(ctx context.Context, u *dbmodel.I
 	}
 	return credentials, nil
 }
+
+// ListIdentities returns the identities matching the given filter,
+// ordered by name.
+func (d *Database) ListIdentities(ctx context.Context, filter IdentityFilter) (_ []dbmodel.Identity, err error) {
+	const op = errors.Op("db.ListIdentities")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if filter.Match != "" {
+		match := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.Match) + "%"
+		db = db.Where("name ILIKE ? OR display_name ILIKE ?", match, match)
+	}
+	if filter.Disabled {
+		db = db.Where("disabled")
+	}
+	if !filter.LastLoginBefore.IsZero() {
+		db = db.Where("COALESCE(last_login, created_at) < ?", filter.LastLoginBefore)
+	}
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	var identities []dbmodel.Identity
+	if err := db.Order("name").Find(&identities).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return identities, nil
+}
@@ -4,7 +4,9 @@ package db_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 
@@ -181,3 +183,83 @@ func (s *dbSuite) TestGetIdentityCloudCredentials(c *qt.C) {
 	c.Check(err, qt.IsNil)
 	c.Assert(credentials, qt.DeepEquals, []dbmodel.CloudCredential{cred1, cred2})
 }
+
+func TestListIdentitiesUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.ListIdentities(context.Background(), db.IdentityFilter{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestListIdentities(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, name := range []string{"charlie@canonical.com", "alice@canonical.com", "bob@canonical.com", "malice@example.com"} {
+		i, err := dbmodel.NewIdentity(name)
+		c.Assert(err, qt.IsNil)
+		err = s.Database.GetIdentity(ctx, i)
+		c.Assert(err, qt.IsNil)
+		if name == "bob@canonical.com" {
+			i.Disabled = true
+			err = s.Database.UpdateIdentity(ctx, i)
+			c.Assert(err, qt.IsNil)
+		}
+	}
+	names := func(identities []dbmodel.Identity) []string {
+		var names []string
+		for _, i := range identities {
+			names = append(names, i.Name)
+		}
+		return names
+	}
+
+	identities, err := s.Database.ListIdentities(ctx, db.IdentityFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"alice@canonical.com", "bob@canonical.com", "charlie@canonical.com", "malice@example.com"})
+
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{Match: "ALICE"})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"alice@canonical.com", "malice@example.com"})
+
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{Match: "%"})
+	c.Assert(err, qt.IsNil)
+	c.Check(identities, qt.HasLen, 0)
+
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{Disabled: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"bob@canonical.com"})
+
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{Offset: 1, Limit: 2})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"bob@canonical.com", "charlie@canonical.com"})
+
+	alice := dbmodel.Identity{Name: "alice@canonical.com"}
+	err = s.Database.FetchIdentity(ctx, &alice)
+	c.Assert(err, qt.IsNil)
+	alice.LastLogin = sql.NullTime{Time: time.Now().Add(-48 * time.Hour), Valid: true}
+	err = s.Database.UpdateIdentity(ctx, &alice)
+	c.Assert(err, qt.IsNil)
+	charlie := dbmodel.Identity{Name: "charlie@canonical.com"}
+	err = s.Database.FetchIdentity(ctx, &charlie)
+	c.Assert(err, qt.IsNil)
+	charlie.LastLogin = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.Database.UpdateIdentity(ctx, &charlie)
+	c.Assert(err, qt.IsNil)
+
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{LastLoginBefore: time.Now().Add(-24 * time.Hour)})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"alice@canonical.com"})
+
+	// Identities that have never logged in are included once they are
+	// old enough.
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{LastLoginBefore: time.Now().Add(-time.Hour)})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"alice@canonical.com"})
+	identities, err = s.Database.ListIdentities(ctx, db.IdentityFilter{LastLoginBefore: time.Now().Add(time.Hour)})
+	c.Assert(err, qt.IsNil)
+	c.Check(names(identities), qt.DeepEquals, []string{"alice@canonical.com", "bob@canonical.com", "charlie@canonical.com", "malice@example.com"})
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// MigrationFilter can be used to find specific model migrations.
+type MigrationFilter struct {
+	// ModelUUID defines the model to list the migrations of, if this is
+	// empty the migrations of all models are listed.
+	ModelUUID string
+
+	// Active is used to only list migrations that have not ended.
+	Active bool
+
+	// Offset is an offset that will be added when listing migrations.
+	Offset int
+
+	// Limit is the maximum number of migrations to return. A value of
+	// zero will ignore the limit.
+	Limit int
+}
+
+// AddMigration stores the given migration in the database.
+func (d *Database) AddMigration(ctx context.Context, m *dbmodel.Migration) (err error) {
+	const op = errors.Op("db.AddMigration")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Create(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetMigration fills in the given migration. The migration is looked up
+// by ID, if that is set, then by MigrationID, if that is set. Otherwise
+// the most recent migration of the model with the given ModelUUID is
+// returned. If no matching migration exists an error with a code of
+// CodeNotFound is returned.
+func (d *Database) GetMigration(ctx context.Context, m *dbmodel.Migration) (err error) {
+	const op = errors.Op("db.GetMigration")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	switch {
+	case m.ID != 0:
+		db = db.Where("id = ?", m.ID)
+	case m.MigrationID != "":
+		db = db.Where("migration_id = ?", m.MigrationID)
+	case m.ModelUUID != "":
+		db = db.Where("model_uuid = ?", m.ModelUUID)
+	default:
+		return errors.E(op, errors.CodeNotFound, "migration not found")
+	}
+	if err := db.Order("started_at DESC, id DESC").First(m).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "migration not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateMigration updates the given migration.
+func (d *Database) UpdateMigration(ctx context.Context, m *dbmodel.Migration) (err error) {
+	const op = errors.Op("db.UpdateMigration")
+
+	if m.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "migration not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Save(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListMigrations returns the migrations matching the given filter, most
+// recent first.
+func (d *Database) ListMigrations(ctx context.Context, filter MigrationFilter) (_ []dbmodel.Migration, err error) {
+	const op = errors.Op("db.ListMigrations")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if filter.ModelUUID != "" {
+		db = db.Where("model_uuid = ?", filter.ModelUUID)
+	}
+	if filter.Active {
+		db = db.Where("ended_at IS NULL")
+	}
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		db = db.Offset(filter.Offset)
+	}
+	var migrations []dbmodel.Migration
+	if err := db.Order("started_at DESC, id DESC").Find(&migrations).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return migrations, nil
+}
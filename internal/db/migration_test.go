@@ -0,0 +1,130 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddMigrationUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddMigration(context.Background(), &dbmodel.Migration{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAddGetUpdateListMigrations(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddMigration(ctx, &dbmodel.Migration{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	m1 := dbmodel.Migration{
+		MigrationID:      "00000002-0000-0000-0000-000000000001:0",
+		ModelUUID:        "00000002-0000-0000-0000-000000000001",
+		ModelName:        "model-1",
+		SourceController: "controller-1",
+		TargetController: "controller-2",
+		Requester:        "alice@canonical.com",
+		Phase:            dbmodel.MigrationPhaseAborted,
+		StartedAt:        now.Add(-time.Hour),
+		EndedAt:          sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+	}
+	err = s.Database.AddMigration(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	m2 := dbmodel.Migration{
+		MigrationID:      "00000002-0000-0000-0000-000000000001:1",
+		ModelUUID:        "00000002-0000-0000-0000-000000000001",
+		ModelName:        "model-1",
+		SourceController: "controller-1",
+		TargetController: "controller-2",
+		Requester:        "alice@canonical.com",
+		Phase:            dbmodel.MigrationPhaseInitiated,
+		StartedAt:        now,
+	}
+	err = s.Database.AddMigration(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+	m3 := dbmodel.Migration{
+		MigrationID:      "00000002-0000-0000-0000-000000000002:0",
+		ModelUUID:        "00000002-0000-0000-0000-000000000002",
+		ModelName:        "model-2",
+		SourceController: "controller-2",
+		TargetController: "controller-1",
+		Requester:        "bob@canonical.com",
+		Phase:            dbmodel.MigrationPhaseRunning,
+		StartedAt:        now.Add(-time.Minute),
+	}
+	err = s.Database.AddMigration(ctx, &m3)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Migration{
+		ModelUUID: "00000002-0000-0000-0000-000000000001",
+	}
+	err = s.Database.GetMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.ID, qt.Equals, m2.ID)
+	c.Check(m.Active(), qt.IsTrue)
+
+	m = dbmodel.Migration{
+		MigrationID: "00000002-0000-0000-0000-000000000001:0",
+	}
+	err = s.Database.GetMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.ID, qt.Equals, m1.ID)
+	c.Check(m.Active(), qt.IsFalse)
+
+	err = s.Database.GetMigration(ctx, &dbmodel.Migration{MigrationID: "no-such-migration"})
+	c.Check(err, qt.ErrorMatches, `migration not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	m2.Phase = dbmodel.MigrationPhaseRunning
+	m2.Message = "importing"
+	err = s.Database.UpdateMigration(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+	m = dbmodel.Migration{ID: m2.ID}
+	err = s.Database.GetMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Phase, qt.Equals, dbmodel.MigrationPhaseRunning)
+	c.Check(m.Message, qt.Equals, "importing")
+
+	err = s.Database.UpdateMigration(ctx, &dbmodel.Migration{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	migrations, err := s.Database.ListMigrations(ctx, db.MigrationFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 3)
+	c.Check(migrations[0].ID, qt.Equals, m2.ID)
+	c.Check(migrations[1].ID, qt.Equals, m3.ID)
+	c.Check(migrations[2].ID, qt.Equals, m1.ID)
+
+	migrations, err = s.Database.ListMigrations(ctx, db.MigrationFilter{
+		ModelUUID: "00000002-0000-0000-0000-000000000001",
+		Active:    true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].ID, qt.Equals, m2.ID)
+
+	migrations, err = s.Database.ListMigrations(ctx, db.MigrationFilter{
+		Offset: 1,
+		Limit:  1,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].ID, qt.Equals, m3.ID)
+}
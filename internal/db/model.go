@@ -4,6 +4,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -217,6 +218,53 @@ func preloadModel(prefix string, db *gorm.DB) *gorm.DB {
 	return db
 }
 
+// GetModelsByOwner retrieves the models owned by the named identity,
+// ordered by name.
+func (d *Database) GetModelsByOwner(ctx context.Context, identityName string) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.GetModelsByOwner")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var models []dbmodel.Model
+	db := d.DB.WithContext(ctx)
+	db = preloadModel("", db)
+	if err := db.Where("owner_identity_name = ?", identityName).Order("name").Find(&models).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
+
+// GetStaleModels retrieves the models that have had no activity since the
+// given time, ordered from the least recently active. Models with no
+// recorded activity are included if they were created before the given
+// time.
+func (d *Database) GetStaleModels(ctx context.Context, before time.Time) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.GetStaleModels")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var models []dbmodel.Model
+	db := d.DB.WithContext(ctx)
+	db = preloadModel("", db)
+	db = db.Where("COALESCE(last_activity, created_at) < ?", before)
+	if err := db.Order("COALESCE(last_activity, created_at)").Order("name").Find(&models).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
+
 // GetModelsByController retrieves a list of models hosted on the specified controller.
 // Note that because we do not preload here, foreign key references will be empty.
 func (d *Database) GetModelsByController(ctx context.Context, ctl dbmodel.Controller) ([]dbmodel.Model, error) {
@@ -248,3 +296,30 @@ func (d *Database) CountModelsByController(ctx context.Context, ctl dbmodel.Cont
 	}
 	return int(count), nil
 }
+
+// GetControllerLoads returns the load on each of the given controllers,
+// keyed by controller ID. The unit and machine counts are those
+// maintained by the controller watcher. Controllers that host no models
+// have no entry in the returned map.
+func (d *Database) GetControllerLoads(ctx context.Context, controllerIDs []uint) (map[uint]dbmodel.ControllerLoad, error) {
+	const op = errors.Op("db.GetControllerLoads")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+	var loads []dbmodel.ControllerLoad
+	db := d.DB.WithContext(ctx)
+	err := db.Model(&dbmodel.Model{}).
+		Select("controller_id, COUNT(*) AS models, COALESCE(SUM(units), 0) AS units, COALESCE(SUM(machines), 0) AS machines").
+		Where("controller_id IN ?", controllerIDs).
+		Group("controller_id").
+		Scan(&loads).Error
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	m := make(map[uint]dbmodel.ControllerLoad, len(loads))
+	for _, l := range loads {
+		m[l.ControllerID] = l
+	}
+	return m, nil
+}
@@ -639,6 +639,81 @@ func (s *dbSuite) TestGetModelsByUUID(c *qt.C) {
 	c.Check(models[2].Controller.Name, qt.Not(qt.Equals), "")
 }
 
+func TestGetModelsByOwnerUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.GetModelsByOwner(context.Background(), "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestGetModelsByOwner(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testGetModelsByUUIDEnv)
+	env.PopulateDB(c, *s.Database)
+
+	models, err := s.Database.GetModelsByOwner(ctx, "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 2)
+	c.Check(models[0].Name, qt.Equals, "test-2")
+	c.Check(models[0].Controller.Name, qt.Equals, "test")
+	c.Check(models[1].Name, qt.Equals, "test-3")
+
+	models, err = s.Database.GetModelsByOwner(ctx, "charlie@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+}
+
+func TestGetStaleModelsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.GetStaleModels(context.Background(), time.Now())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestGetStaleModels(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testGetModelsByUUIDEnv)
+	env.PopulateDB(c, *s.Database)
+
+	now := time.Now().UTC()
+	lastActivity := map[string]time.Time{
+		"00000002-0000-0000-0000-000000000001": now.Add(-48 * time.Hour),
+		"00000002-0000-0000-0000-000000000002": now,
+		"00000002-0000-0000-0000-000000000003": now.Add(-72 * time.Hour),
+	}
+	for uuid, t := range lastActivity {
+		m := dbmodel.Model{UUID: sql.NullString{String: uuid, Valid: true}}
+		err := s.Database.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		m.LastActivity = sql.NullTime{Time: t, Valid: true}
+		err = s.Database.UpdateModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+	}
+
+	models, err := s.Database.GetStaleModels(ctx, now.Add(-24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 2)
+	c.Check(models[0].Name, qt.Equals, "test-3")
+	c.Check(models[0].Controller.Name, qt.Equals, "test")
+	c.Check(models[1].Name, qt.Equals, "test-1")
+
+	// Models without recorded activity are stale once they are old
+	// enough.
+	models, err = s.Database.GetStaleModels(ctx, now.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 3)
+}
+
 func (s *dbSuite) TestGetModelsByController(c *qt.C) {
 	err := s.Database.Migrate(context.Background(), true)
 	c.Assert(err, qt.Equals, nil)
@@ -788,3 +863,88 @@ func (s *dbSuite) TestCountModelsByController(c *qt.C) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(count, qt.Equals, 3)
 }
+
+const testGetControllerLoadsEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: test
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+- name: test-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test
+  region: test-region
+- name: test-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test
+  region: test-region
+- name: test-4
+  uuid: 00000001-0000-0000-0000-000000000004
+  cloud: test
+  region: test-region
+models:
+- name: test-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  units: 4
+  machines: 2
+- name: test-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  owner: bob@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  units: 3
+  machines: 1
+- name: test-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  owner: bob@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test-2
+  units: 1
+  machines: 1
+- name: test-4
+  uuid: 00000002-0000-0000-0000-000000000004
+  owner: bob@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test-3
+  units: 5
+  machines: 5
+`
+
+func (s *dbSuite) TestGetControllerLoads(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testGetControllerLoadsEnv)
+	env.PopulateDB(c, *s.Database)
+	ctl1 := env.Controller("test").DBObject(c, *s.Database)
+	ctl2 := env.Controller("test-2").DBObject(c, *s.Database)
+	ctl4 := env.Controller("test-4").DBObject(c, *s.Database)
+
+	loads, err := s.Database.GetControllerLoads(ctx, []uint{ctl1.ID, ctl2.ID, ctl4.ID})
+	c.Assert(err, qt.IsNil)
+	c.Check(loads, qt.DeepEquals, map[uint]dbmodel.ControllerLoad{
+		ctl1.ID: {ControllerID: ctl1.ID, Models: 2, Units: 7, Machines: 3},
+		ctl2.ID: {ControllerID: ctl2.ID, Models: 1, Units: 1, Machines: 1},
+	})
+}
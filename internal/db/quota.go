@@ -0,0 +1,199 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetQuota stores the given quota in the database. If a quota already
+// exists for the same subject, cloud and region its limits are replaced.
+func (d *Database) SetQuota(ctx context.Context, q *dbmodel.Quota) (err error) {
+	const op = errors.Op("db.SetQuota")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "subject"},
+			{Name: "cloud_name"},
+			{Name: "region"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "max_models", "max_machines", "max_cores", "max_units"}),
+	}).Create(q).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetQuota fills in the given quota. The quota is looked up by subject,
+// cloud name and region. If no matching quota can be found an error
+// with a code of CodeNotFound is returned.
+func (d *Database) GetQuota(ctx context.Context, q *dbmodel.Quota) (err error) {
+	const op = errors.Op("db.GetQuota")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	db = db.Where("subject = ? AND cloud_name = ? AND region = ?", q.Subject, q.CloudName, q.Region)
+	if err := db.First(q).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "quota not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// DeleteQuota removes the quota with the given subject, cloud name and
+// region from the database. If no matching quota can be found an error
+// with a code of CodeNotFound is returned.
+func (d *Database) DeleteQuota(ctx context.Context, q *dbmodel.Quota) (err error) {
+	const op = errors.Op("db.DeleteQuota")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	result := db.Where("subject = ? AND cloud_name = ? AND region = ?", q.Subject, q.CloudName, q.Region).Delete(&dbmodel.Quota{})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "quota not found")
+	}
+	return nil
+}
+
+// DeleteQuotasForSubject removes all quotas that apply to the given
+// subject.
+func (d *Database) DeleteQuotasForSubject(ctx context.Context, subject string) (err error) {
+	const op = errors.Op("db.DeleteQuotasForSubject")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("subject = ?", subject).Delete(&dbmodel.Quota{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetCloudQuotas returns all quotas defined for the named cloud.
+func (d *Database) GetCloudQuotas(ctx context.Context, cloudName string) (_ []dbmodel.Quota, err error) {
+	const op = errors.Op("db.GetCloudQuotas")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var quotas []dbmodel.Quota
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("cloud_name = ?", cloudName).Order("id").Find(&quotas).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return quotas, nil
+}
+
+// ForEachQuota iterates through every quota calling the given function
+// for each one. If the given function returns an error the iteration
+// will stop immediately and the error will be returned unmodified.
+func (d *Database) ForEachQuota(ctx context.Context, f func(q *dbmodel.Quota) error) (err error) {
+	const op = errors.Op("db.ForEachQuota")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	rows, err := db.Model(&dbmodel.Quota{}).Order("id").Rows()
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var q dbmodel.Quota
+		if err := db.ScanRows(rows, &q); err != nil {
+			return errors.E(op, dbError(err))
+		}
+		if err := f(&q); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetQuotaUsage returns the resources used by all models owned by any of
+// the given identities on the named cloud. If region is not empty only
+// models in that region are counted.
+func (d *Database) GetQuotaUsage(ctx context.Context, owners []string, cloudName, region string) (_ dbmodel.QuotaUsage, err error) {
+	const op = errors.Op("db.GetQuotaUsage")
+
+	if err := d.ready(); err != nil {
+		return dbmodel.QuotaUsage{}, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var usage dbmodel.QuotaUsage
+	if len(owners) == 0 {
+		return usage, nil
+	}
+
+	db := d.DB.WithContext(ctx)
+	db = db.Model(&dbmodel.Model{}).
+		Select("COUNT(*) AS models, COALESCE(SUM(models.machines), 0) AS machines, COALESCE(SUM(models.cores), 0) AS cores, COALESCE(SUM(models.units), 0) AS units").
+		Joins("JOIN cloud_regions ON cloud_regions.id = models.cloud_region_id").
+		Where("models.owner_identity_name IN ? AND cloud_regions.cloud_name = ?", owners, cloudName)
+	if region != "" {
+		db = db.Where("cloud_regions.name = ?", region)
+	}
+	if err := db.Scan(&usage).Error; err != nil {
+		return dbmodel.QuotaUsage{}, errors.E(op, dbError(err))
+	}
+	return usage, nil
+}
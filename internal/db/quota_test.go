@@ -0,0 +1,171 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/state"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetQuotaUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetQuota(context.Background(), &dbmodel.Quota{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestSetGetDeleteQuota(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.SetQuota(ctx, &dbmodel.Quota{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region",
+		}},
+	}
+	c.Assert(s.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	q := dbmodel.Quota{
+		Subject:   "user-bob@canonical.com",
+		CloudName: "test-cloud",
+		MaxModels: sql.NullInt64{Int64: 2, Valid: true},
+	}
+	err = s.Database.SetQuota(ctx, &q)
+	c.Assert(err, qt.IsNil)
+
+	q2 := dbmodel.Quota{
+		Subject:   "user-bob@canonical.com",
+		CloudName: "test-cloud",
+	}
+	err = s.Database.GetQuota(ctx, &q2)
+	c.Assert(err, qt.IsNil)
+	c.Check(q2.MaxModels, qt.Equals, sql.NullInt64{Int64: 2, Valid: true})
+	c.Check(q2.MaxMachines.Valid, qt.IsFalse)
+
+	err = s.Database.SetQuota(ctx, &dbmodel.Quota{
+		Subject:     "user-bob@canonical.com",
+		CloudName:   "test-cloud",
+		MaxMachines: sql.NullInt64{Int64: 10, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	q3 := dbmodel.Quota{
+		Subject:   "user-bob@canonical.com",
+		CloudName: "test-cloud",
+	}
+	err = s.Database.GetQuota(ctx, &q3)
+	c.Assert(err, qt.IsNil)
+	c.Check(q3.ID, qt.Equals, q2.ID)
+	c.Check(q3.MaxModels.Valid, qt.IsFalse)
+	c.Check(q3.MaxMachines, qt.Equals, sql.NullInt64{Int64: 10, Valid: true})
+
+	quotas, err := s.Database.GetCloudQuotas(ctx, "test-cloud")
+	c.Assert(err, qt.IsNil)
+	c.Check(quotas, qt.HasLen, 1)
+
+	err = s.Database.DeleteQuota(ctx, &q3)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetQuota(ctx, &q3)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteQuota(ctx, &q3)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestGetQuotaUsage(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(&u).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region-1",
+		}, {
+			Name: "test-region-2",
+		}},
+	}
+	c.Assert(s.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	cred := dbmodel.CloudCredential{
+		Name:     "test-cred",
+		Cloud:    cloud,
+		Owner:    *u,
+		AuthType: "empty",
+	}
+	c.Assert(s.Database.DB.Create(&cred).Error, qt.IsNil)
+
+	controller := dbmodel.Controller{
+		Name:        "test-controller",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region-1",
+	}
+	err = s.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	for i, m := range []struct {
+		name     string
+		region   int
+		machines int64
+		cores    int64
+		units    int64
+	}{
+		{"model-1", 0, 1, 2, 3},
+		{"model-2", 0, 2, 4, 6},
+		{"model-3", 1, 5, 5, 5},
+	} {
+		model := dbmodel.Model{
+			Name: m.name,
+			UUID: sql.NullString{
+				String: "00000001-0000-0000-0000-00000000000" + string(rune('1'+i)),
+				Valid:  true,
+			},
+			OwnerIdentityName: u.Name,
+			ControllerID:      controller.ID,
+			CloudRegionID:     cloud.Regions[m.region].ID,
+			CloudCredentialID: cred.ID,
+			Life:              state.Alive.String(),
+			Machines:          m.machines,
+			Cores:             m.cores,
+			Units:             m.units,
+		}
+		c.Assert(s.Database.AddModel(ctx, &model), qt.IsNil)
+	}
+
+	usage, err := s.Database.GetQuotaUsage(ctx, []string{u.Name}, "test-cloud", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, dbmodel.QuotaUsage{Models: 3, Machines: 8, Cores: 11, Units: 14})
+
+	usage, err = s.Database.GetQuotaUsage(ctx, []string{u.Name}, "test-cloud", "test-region-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, dbmodel.QuotaUsage{Models: 2, Machines: 3, Cores: 6, Units: 9})
+
+	usage, err = s.Database.GetQuotaUsage(ctx, []string{"alice@canonical.com"}, "test-cloud", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.DeepEquals, dbmodel.QuotaUsage{})
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetRelationExpiry stores the given relation expiry in the database. If
+// an expiry already exists for the same relation its expiry time and
+// granting identity are updated.
+func (d *Database) SetRelationExpiry(ctx context.Context, re *dbmodel.RelationExpiry) (err error) {
+	const op = errors.Op("db.SetRelationExpiry")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "object"}, {Name: "relation"}, {Name: "target"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "expires_at", "granted_by"}),
+	})
+	if err := db.Create(re).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// RemoveRelationExpiry removes the given relation expiry from the
+// database.
+func (d *Database) RemoveRelationExpiry(ctx context.Context, re *dbmodel.RelationExpiry) (err error) {
+	const op = errors.Op("db.RemoveRelationExpiry")
+
+	if re.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "relation expiry not found")
+	}
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(re).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ClearRelationExpiry removes the expiry, if there is one, of the
+// relation with the Object, Relation and Target of the given relation
+// expiry. It is not an error if the relation has no expiry.
+func (d *Database) ClearRelationExpiry(ctx context.Context, re *dbmodel.RelationExpiry) (err error) {
+	const op = errors.Op("db.ClearRelationExpiry")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("object = ? AND relation = ? AND target = ?", re.Object, re.Relation, re.Target)
+	if err := db.Delete(&dbmodel.RelationExpiry{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ForEachRelationExpiry iterates through every relation expiry that
+// expires at or before the given time, in order of expiry, calling the
+// given function for each one. If the given time is zero all expiries
+// are iterated. If the given function returns an error the iteration
+// will stop immediately and the error will be returned unmodified.
+func (d *Database) ForEachRelationExpiry(ctx context.Context, before time.Time, f func(*dbmodel.RelationExpiry) error) (err error) {
+	const op = errors.Op("db.ForEachRelationExpiry")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if !before.IsZero() {
+		db = db.Where("expires_at <= ?", before)
+	}
+	// Expiries are loaded in full so that the function may modify the
+	// table while iterating.
+	var expiries []dbmodel.RelationExpiry
+	if err := db.Order("expires_at, id").Find(&expiries).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	for i := range expiries {
+		if err := f(&expiries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetRelationExpiryUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetRelationExpiry(context.Background(), &dbmodel.RelationExpiry{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestRelationExpiries(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.SetRelationExpiry(ctx, &dbmodel.RelationExpiry{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	re1 := dbmodel.RelationExpiry{
+		Object:    "user:bob@canonical.com",
+		Relation:  "administrator",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		ExpiresAt: now.Add(2 * time.Hour),
+		GrantedBy: "alice@canonical.com",
+	}
+	err = s.Database.SetRelationExpiry(ctx, &re1)
+	c.Assert(err, qt.IsNil)
+	re2 := dbmodel.RelationExpiry{
+		Object:    "user:charlie@canonical.com",
+		Relation:  "reader",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		ExpiresAt: now.Add(time.Hour),
+		GrantedBy: "alice@canonical.com",
+	}
+	err = s.Database.SetRelationExpiry(ctx, &re2)
+	c.Assert(err, qt.IsNil)
+
+	// Setting the expiry of an existing relation updates it.
+	err = s.Database.SetRelationExpiry(ctx, &dbmodel.RelationExpiry{
+		Object:    "user:bob@canonical.com",
+		Relation:  "administrator",
+		Target:    "model:00000002-0000-0000-0000-000000000001",
+		ExpiresAt: now.Add(3 * time.Hour),
+		GrantedBy: "diane@canonical.com",
+	})
+	c.Assert(err, qt.IsNil)
+
+	var expiries []dbmodel.RelationExpiry
+	collect := func(re *dbmodel.RelationExpiry) error {
+		expiries = append(expiries, *re)
+		return nil
+	}
+	err = s.Database.ForEachRelationExpiry(ctx, time.Time{}, collect)
+	c.Assert(err, qt.IsNil)
+	c.Assert(expiries, qt.HasLen, 2)
+	c.Check(expiries[0].Object, qt.Equals, "user:charlie@canonical.com")
+	c.Check(expiries[1].Object, qt.Equals, "user:bob@canonical.com")
+	c.Check(expiries[1].ExpiresAt.Equal(now.Add(3*time.Hour)), qt.IsTrue)
+	c.Check(expiries[1].GrantedBy, qt.Equals, "diane@canonical.com")
+
+	expiries = nil
+	err = s.Database.ForEachRelationExpiry(ctx, now.Add(90*time.Minute), collect)
+	c.Assert(err, qt.IsNil)
+	c.Assert(expiries, qt.HasLen, 1)
+	c.Check(expiries[0].Object, qt.Equals, "user:charlie@canonical.com")
+
+	err = s.Database.RemoveRelationExpiry(ctx, &expiries[0])
+	c.Assert(err, qt.IsNil)
+	err = s.Database.RemoveRelationExpiry(ctx, &dbmodel.RelationExpiry{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	expiries = nil
+	err = s.Database.ForEachRelationExpiry(ctx, time.Time{}, collect)
+	c.Assert(err, qt.IsNil)
+	c.Assert(expiries, qt.HasLen, 1)
+	c.Check(expiries[0].Object, qt.Equals, "user:bob@canonical.com")
+
+	// Clearing the expiry of a relation without one does nothing.
+	err = s.Database.ClearRelationExpiry(ctx, &re2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.ClearRelationExpiry(ctx, &dbmodel.RelationExpiry{
+		Object:   "user:bob@canonical.com",
+		Relation: "administrator",
+		Target:   "model:00000002-0000-0000-0000-000000000001",
+	})
+	c.Assert(err, qt.IsNil)
+
+	expiries = nil
+	err = s.Database.ForEachRelationExpiry(ctx, time.Time{}, collect)
+	c.Assert(err, qt.IsNil)
+	c.Check(expiries, qt.HasLen, 0)
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// AccessRequestPending is the status of an access request that is
+	// waiting to be reviewed.
+	AccessRequestPending = "pending"
+
+	// AccessRequestApproved is the status of an access request that has
+	// been approved and the access granted.
+	AccessRequestApproved = "approved"
+
+	// AccessRequestDenied is the status of an access request that has
+	// been denied.
+	AccessRequestDenied = "denied"
+)
+
+// An AccessRequest is a request from an identity for access to a model,
+// cloud or application offer.
+type AccessRequest struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Requester is the name of the identity requesting access.
+	Requester string
+
+	// Resource is the tag of the resource as given by the requester.
+	Resource string
+
+	// Target is the OpenFGA object of the resource, for example
+	// "model:00000002-0000-0000-0000-000000000001".
+	Target string
+
+	// Relation is the OpenFGA relation requested on the target.
+	Relation string
+
+	// Reason is the justification given by the requester.
+	Reason string
+
+	// Status is the status of the request.
+	Status string
+
+	// ReviewedBy is the name of the identity that approved or denied
+	// the request.
+	ReviewedBy string
+
+	// Comment is the comment given by the reviewer.
+	Comment string
+
+	// ExpiresAt is the time at which access granted by approving the
+	// request is revoked.
+	ExpiresAt sql.NullTime
+}
+
+// ToAPIAccessRequest converts an access request to a JIMM API
+// AccessRequest.
+func (r AccessRequest) ToAPIAccessRequest() apiparams.AccessRequest {
+	ar := apiparams.AccessRequest{
+		ID:         r.ID,
+		Requester:  r.Requester,
+		Resource:   r.Resource,
+		Relation:   r.Relation,
+		Reason:     r.Reason,
+		Status:     r.Status,
+		ReviewedBy: r.ReviewedBy,
+		Comment:    r.Comment,
+		Created:    r.CreatedAt,
+		Updated:    r.UpdatedAt,
+	}
+	if r.ExpiresAt.Valid {
+		t := r.ExpiresAt.Time
+		ar.ExpiresAt = &t
+	}
+	return ar
+}
@@ -3,7 +3,12 @@
 package dbmodel
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 
 	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
@@ -51,6 +56,15 @@ type AuditLogEntry struct {
 
 	// Errors contains any errors from the controller.
 	Errors JSON
+
+	// PreviousHash contains the hash of the entry that was added before
+	// this one. This is empty for the first entry in the log.
+	PreviousHash string
+
+	// Hash contains the hash of this entry, as calculated by ComputeHash.
+	// Entries added before hash chaining was introduced have an empty
+	// hash.
+	Hash string
 }
 
 // TableName overrides the table name gorm will use to find
@@ -59,6 +73,43 @@ func (AuditLogEntry) TableName() string {
 	return "audit_log"
 }
 
+// ComputeHash calculates the hash of the entry. The hash covers the
+// contents of the entry and PreviousHash, but not the ID or Hash fields.
+// Each value is prefixed with its length so that the boundaries between
+// values cannot be moved without changing the hash.
+func (e AuditLogEntry) ComputeHash() string {
+	values := []string{
+		e.PreviousHash,
+		e.Time.UTC().Format(time.RFC3339Nano),
+		e.Model,
+		e.ConversationId,
+		strconv.FormatUint(e.MessageId, 10),
+		e.FacadeName,
+		e.FacadeMethod,
+		strconv.Itoa(e.FacadeVersion),
+		e.ObjectId,
+		e.IdentityTag,
+		strconv.FormatBool(e.IsResponse),
+		hashJSON(e.Params),
+		hashJSON(e.Errors),
+	}
+	h := sha256.New()
+	for _, v := range values {
+		fmt.Fprintf(h, "%d:%s", len(v), v)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashJSON returns the value of j to use when hashing. Empty values are
+// stored in the database as NULL which is read back as "null", so they
+// are treated as equivalent.
+func hashJSON(j JSON) string {
+	if len(j) == 0 {
+		return "null"
+	}
+	return string(j)
+}
+
 // ToAPIAuditEvent converts an AuditLogEntry to a JIMM API AuditEvent.
 func (e AuditLogEntry) ToAPIAuditEvent() apiparams.AuditEvent {
 	var ale apiparams.AuditEvent
@@ -87,3 +138,88 @@ func (e AuditLogEntry) ToAPIAuditEvent() apiparams.AuditEvent {
 	}
 	return ale
 }
+
+// An AuditLogCheckpoint records a link in the audit log hash chain that
+// was removed when old entries were purged. It allows the chain to be
+// verified after entries have been deleted by the retention policy.
+type AuditLogCheckpoint struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// EntryID is the ID of the remaining entry whose previous entry was
+	// purged.
+	EntryID uint
+
+	// PreviousHash is the hash of the purged entry that preceded the
+	// entry with EntryID.
+	PreviousHash string
+
+	// PrecedingHash is the hash of the nearest remaining entry before
+	// the entry with EntryID. This is empty if there is no such entry.
+	PrecedingHash string
+
+	// PurgedBefore is the time before which entries were purged.
+	PurgedBefore time.Time
+}
+
+// An AuditLogArchive records a segment of the audit log that has been
+// archived to a blob store before being purged.
+type AuditLogArchive struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Key is the key of the segment in the blob store.
+	Key string
+
+	// FirstEntryID and LastEntryID are the lowest and highest IDs of
+	// the entries in the segment.
+	FirstEntryID uint
+	LastEntryID  uint
+
+	// StartTime and EndTime are the earliest and latest times of the
+	// entries in the segment.
+	StartTime time.Time
+	EndTime   time.Time
+
+	// EntryCount is the number of entries in the segment.
+	EntryCount int64
+
+	// Size is the size, in bytes, of the compressed segment.
+	Size int64
+
+	// SHA256 is the hex encoded SHA-256 checksum of the compressed
+	// segment.
+	SHA256 string
+
+	// RestoredAt is the time the segment was last restored. This is
+	// not valid if the segment has never been restored.
+	RestoredAt sql.NullTime
+}
+
+// ToAPIAuditLogArchive converts an AuditLogArchive to a JIMM API
+// AuditLogArchive.
+func (a AuditLogArchive) ToAPIAuditLogArchive() apiparams.AuditLogArchive {
+	aa := apiparams.AuditLogArchive{
+		ID:           a.ID,
+		CreatedAt:    a.CreatedAt,
+		Key:          a.Key,
+		FirstEntryID: a.FirstEntryID,
+		LastEntryID:  a.LastEntryID,
+		StartTime:    a.StartTime,
+		EndTime:      a.EndTime,
+		EntryCount:   a.EntryCount,
+		Size:         a.Size,
+		SHA256:       a.SHA256,
+	}
+	if a.RestoredAt.Valid {
+		t := a.RestoredAt.Time
+		aa.RestoredAt = &t
+	}
+	return aa
+}
@@ -89,3 +89,46 @@ func TestToAPIAuditEvent(t *testing.T) {
 	expectedEvent.Errors = map[string]any{}
 	c.Check(event, qt.DeepEquals, expectedEvent)
 }
+
+func TestAuditLogEntryComputeHash(t *testing.T) {
+	c := qt.New(t)
+
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Date(2024, time.January, 2, 3, 4, 5, 6000, time.UTC),
+		ConversationId: "1234",
+		MessageId:      9876,
+		FacadeName:     "JIMM",
+		FacadeMethod:   "AddController",
+		FacadeVersion:  1,
+		IdentityTag:    names.NewUserTag("bob@canonical.com").String(),
+		Params:         dbmodel.JSON(`{"a":"b"}`),
+	}
+	hash := ale.ComputeHash()
+	c.Check(hash, qt.HasLen, 64)
+
+	// The hash does not depend on the ID, the stored hash or the time
+	// zone.
+	ale2 := ale
+	ale2.ID = 10
+	ale2.Hash = hash
+	ale2.Time = ale.Time.In(time.FixedZone("test", 3600))
+	c.Check(ale2.ComputeHash(), qt.Equals, hash)
+
+	// Empty JSON values are stored as null.
+	ale2.Errors = dbmodel.JSON("null")
+	c.Check(ale2.ComputeHash(), qt.Equals, hash)
+
+	ale2 = ale
+	ale2.PreviousHash = "abc"
+	c.Check(ale2.ComputeHash(), qt.Not(qt.Equals), hash)
+
+	ale2 = ale
+	ale2.Params = dbmodel.JSON(`{"a":"c"}`)
+	c.Check(ale2.ComputeHash(), qt.Not(qt.Equals), hash)
+
+	// Moving content between fields changes the hash.
+	ale2 = ale
+	ale2.ConversationId = "123"
+	ale2.FacadeName = "4JIMM"
+	c.Check(ale2.ComputeHash(), qt.Not(qt.Equals), hash)
+}
@@ -78,6 +78,22 @@ type Controller struct {
 	// unavailable, if it has.
 	UnavailableSince sql.NullTime
 
+	// MaxModels is the maximum number of models that may be placed on
+	// this controller. If this is not set the number of models is not
+	// limited.
+	MaxModels sql.NullInt64
+
+	// MaxUnits is the maximum number of units, across all models, that
+	// may be running on this controller before no more models are placed
+	// on it. If this is not set the number of units is not limited.
+	MaxUnits sql.NullInt64
+
+	// MaxMachines is the maximum number of machines, across all models,
+	// that may be running on this controller before no more models are
+	// placed on it. If this is not set the number of machines is not
+	// limited.
+	MaxMachines sql.NullInt64
+
 	// CloudRegions is the set of cloud-regions that are available on this
 	// controller.
 	CloudRegions []CloudRegionControllerPriority
@@ -179,6 +195,15 @@ const (
 	CloudRegionControllerPrioritySupported = 1
 )
 
+// A ControllerLoad holds the number of models hosted on a controller
+// and the number of units and machines running in them.
+type ControllerLoad struct {
+	ControllerID uint
+	Models       int
+	Units        int
+	Machines     int
+}
+
 // A CloudRegionControllerPriority entry specifies the priority with which
 // a controller should be chosen when deploying to a particular
 // cloud-region.
@@ -0,0 +1,139 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// DrainStatusRunning is the status of a controller drain that is
+	// still migrating models.
+	DrainStatusRunning = "running"
+
+	// DrainStatusCompleted is the status of a controller drain that has
+	// migrated all of its models.
+	DrainStatusCompleted = "completed"
+
+	// DrainStatusFailed is the status of a controller drain that has
+	// finished but failed to migrate some of its models.
+	DrainStatusFailed = "failed"
+)
+
+const (
+	// DrainModelStatusPending is the status of a model that is waiting
+	// to be migrated.
+	DrainModelStatusPending = "pending"
+
+	// DrainModelStatusMigrating is the status of a model whose migration
+	// has been initiated.
+	DrainModelStatusMigrating = "migrating"
+
+	// DrainModelStatusMigrated is the status of a model that has been
+	// migrated to its target controller.
+	DrainModelStatusMigrated = "migrated"
+
+	// DrainModelStatusFailed is the status of a model that could not be
+	// migrated.
+	DrainModelStatusFailed = "failed"
+)
+
+// A ControllerDrain tracks the migration of all models off a controller.
+type ControllerDrain struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Controller is the controller being drained.
+	ControllerID uint
+	Controller   Controller `gorm:"constraint:OnDelete:CASCADE"`
+
+	// Initiator is the name of the identity that started the drain,
+	// migrations are performed on behalf of this identity.
+	Initiator string
+
+	// Status is the status of the drain.
+	Status string
+
+	// BatchSize is the maximum number of models that are migrated
+	// concurrently.
+	BatchSize int
+
+	// Models are the models being migrated off the controller.
+	Models []ControllerDrainModel `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Finished returns whether all models in the drain have either been
+// migrated or have failed to migrate.
+func (d ControllerDrain) Finished() bool {
+	for _, m := range d.Models {
+		if m.Status == DrainModelStatusPending || m.Status == DrainModelStatusMigrating {
+			return false
+		}
+	}
+	return true
+}
+
+// ToAPIControllerDrainStatus converts a controller drain to a
+// JIMM API ControllerDrainStatus.
+func (d ControllerDrain) ToAPIControllerDrainStatus() apiparams.ControllerDrainStatus {
+	status := apiparams.ControllerDrainStatus{
+		Controller: d.Controller.Name,
+		Status:     d.Status,
+		BatchSize:  d.BatchSize,
+		Started:    d.CreatedAt,
+		Updated:    d.UpdatedAt,
+		Models:     make([]apiparams.ControllerDrainModel, len(d.Models)),
+	}
+	for i, m := range d.Models {
+		status.Models[i] = apiparams.ControllerDrainModel{
+			UUID:             m.ModelUUID,
+			Name:             m.ModelName,
+			TargetController: m.TargetController,
+			Status:           m.Status,
+			Error:            m.Error,
+		}
+	}
+	return status
+}
+
+// A ControllerDrainModel tracks the migration of a model as part of a
+// controller drain.
+type ControllerDrainModel struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ControllerDrainID is the drain this model is part of.
+	ControllerDrainID uint
+
+	// ModelUUID is the UUID of the model being migrated.
+	ModelUUID string
+
+	// ModelName is the name of the model being migrated.
+	ModelName string
+
+	// TargetController is the name of the controller the model is
+	// being migrated to. This is set when the migration is initiated.
+	TargetController string
+
+	// MigrationID is the ID of the migration returned by the source
+	// controller.
+	MigrationID string
+
+	// Status is the status of the model's migration.
+	Status string
+
+	// Error contains the reason the model failed to migrate.
+	Error string
+
+	// CheckFailures is the number of consecutive attempts to check the
+	// status of the model's migration that have failed.
+	CheckFailures int
+}
@@ -13,6 +13,8 @@ import (
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
 	"gorm.io/gorm"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 var (
@@ -111,9 +113,25 @@ func (i Identity) ToJujuUserInfo() jujuparams.UserInfo {
 	if i.LastLogin.Valid {
 		ui.LastConnection = &i.LastLogin.Time
 	}
+	ui.Disabled = i.Disabled
 	return ui
 }
 
+// ToAPIIdentity converts an identity to a JIMM API Identity.
+func (i Identity) ToAPIIdentity() apiparams.Identity {
+	identity := apiparams.Identity{
+		Name:        i.Name,
+		DisplayName: i.DisplayName,
+		Disabled:    i.Disabled,
+		Created:     i.CreatedAt,
+	}
+	if i.LastLogin.Valid {
+		t := i.LastLogin.Time
+		identity.LastLogin = &t
+	}
+	return identity
+}
+
 // SanitiseIdentityId ensures that the identity id persisted is safe
 // for use in Juju tags, this is done by replacing all of the unsafe
 // email characters AND underscores (despite being safe in emails) with
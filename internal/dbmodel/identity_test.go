@@ -145,6 +145,10 @@ func TestIdentityToJujuUserInfo(t *testing.T) {
 		DateCreated:    u.CreatedAt,
 		LastConnection: &u.LastLogin.Time,
 	})
+
+	u.Disabled = true
+	ui = u.ToJujuUserInfo()
+	c.Check(ui.Disabled, qt.IsTrue)
 }
 
 func TestNewIdentity(t *testing.T) {
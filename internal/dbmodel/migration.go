@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// MigrationPhaseInitiated is the phase of a migration that has been
+	// requested but has not yet been seen running on the source
+	// controller.
+	MigrationPhaseInitiated = "initiated"
+
+	// MigrationPhaseRunning is the phase of a migration that the source
+	// controller reports is in progress.
+	MigrationPhaseRunning = "running"
+
+	// MigrationPhaseCompleted is the phase of a migration where the
+	// model has left the source controller.
+	MigrationPhaseCompleted = "completed"
+
+	// MigrationPhaseAborted is the phase of a migration that was aborted
+	// leaving the model on the source controller.
+	MigrationPhaseAborted = "aborted"
+)
+
+// A Migration records the migration of a model between controllers.
+type Migration struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// MigrationID is the ID of the migration returned by the source
+	// controller.
+	MigrationID string
+
+	// ModelUUID is the UUID of the migrated model.
+	ModelUUID string
+
+	// ModelName is the name of the migrated model.
+	ModelName string
+
+	// SourceController is the name of the controller the model is
+	// migrated from.
+	SourceController string
+
+	// TargetController is the name of the controller the model is
+	// migrated to. If the target controller is not known to JIMM this
+	// is the controller's alias, or UUID.
+	TargetController string
+
+	// Requester is the name of the identity that requested the
+	// migration.
+	Requester string
+
+	// Phase is the current phase of the migration.
+	Phase string
+
+	// Message is the most recent status message reported by the source
+	// controller for the migration.
+	Message string
+
+	// StartedAt is the time the migration was initiated.
+	StartedAt time.Time
+
+	// EndedAt is the time the migration was seen to finish.
+	EndedAt sql.NullTime
+}
+
+// Active returns whether the migration is still in progress.
+func (m Migration) Active() bool {
+	return !m.EndedAt.Valid
+}
+
+// ToAPIMigration converts a migration to a JIMM API Migration.
+func (m Migration) ToAPIMigration() apiparams.Migration {
+	mig := apiparams.Migration{
+		MigrationID:      m.MigrationID,
+		ModelUUID:        m.ModelUUID,
+		ModelName:        m.ModelName,
+		SourceController: m.SourceController,
+		TargetController: m.TargetController,
+		Requester:        m.Requester,
+		Phase:            m.Phase,
+		Message:          m.Message,
+		StartedAt:        m.StartedAt,
+	}
+	if m.EndedAt.Valid {
+		t := m.EndedAt.Time
+		mig.EndedAt = &t
+	}
+	return mig
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/version/v2"
 
 	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // A Model is a juju model.
@@ -77,6 +78,11 @@ type Model struct {
 	// Units contains the count of machines in the model.
 	Units int64
 
+	// LastActivity is the time, to within an hour, the controller
+	// watcher last received a delta for the model. The state sent when
+	// the watcher starts is not counted as activity.
+	LastActivity sql.NullTime
+
 	// Offers are the ApplicationOffers attached to the model.
 	Offers []ApplicationOffer
 }
@@ -103,6 +109,18 @@ func (m *Model) SetTag(t names.ModelTag) {
 	m.UUID.Valid = true
 }
 
+// ToAPIIdentityModel converts a model to a JIMM API IdentityModel. The
+// model's Controller and CloudRegion must have been loaded.
+func (m Model) ToAPIIdentityModel() apiparams.IdentityModel {
+	return apiparams.IdentityModel{
+		Name:       m.Name,
+		UUID:       m.UUID.String,
+		Controller: m.Controller.Name,
+		Cloud:      m.CloudRegion.Cloud.Name,
+		Region:     m.CloudRegion.Name,
+	}
+}
+
 // FromModelUpdate updates the model from the given ModelUpdate.
 func (m *Model) SwitchOwner(u *Identity) {
 	m.OwnerIdentityName = u.Name
@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// A Quota limits the resources that the models owned by an identity, or
+// by the members of a group, may consume on a cloud. Quotas only gate the
+// creation of new models: a model is refused once the recorded usage
+// reaches a limit, but usage can still grow beyond the machine, core and
+// unit limits as existing models are scaled.
+type Quota struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Subject is the tag of the entity the quota applies to. This will
+	// either be a user tag (user-<name>) or a group tag
+	// (group-<uuid>).
+	Subject string
+
+	// CloudName is the name of the cloud the quota applies to.
+	CloudName string
+	Cloud     Cloud `gorm:"foreignKey:CloudName;references:Name"`
+
+	// Region is the name of the cloud region the quota applies to. An
+	// empty region means the quota applies to all regions of the cloud.
+	Region string
+
+	// MaxModels is the maximum number of models that may be created.
+	MaxModels sql.NullInt64
+
+	// MaxMachines is the maximum number of machines that may be in use
+	// across all models.
+	MaxMachines sql.NullInt64
+
+	// MaxCores is the maximum number of cores that may be in use across
+	// all models.
+	MaxCores sql.NullInt64
+
+	// MaxUnits is the maximum number of units that may be in use across
+	// all models.
+	MaxUnits sql.NullInt64
+}
+
+// QuotaUsage holds the resources currently in use by a set of models.
+type QuotaUsage struct {
+	Models   int64
+	Machines int64
+	Cores    int64
+	Units    int64
+}
+
+// Exceeded returns the name of the first limit in q that would be exceeded
+// by adding a new model to the given usage. If no limit would be
+// exceeded then an empty string is returned.
+func (q Quota) Exceeded(u QuotaUsage) string {
+	if q.MaxModels.Valid && u.Models+1 > q.MaxModels.Int64 {
+		return "models"
+	}
+	if q.MaxMachines.Valid && u.Machines >= q.MaxMachines.Int64 {
+		return "machines"
+	}
+	if q.MaxCores.Valid && u.Cores >= q.MaxCores.Int64 {
+		return "cores"
+	}
+	if q.MaxUnits.Valid && u.Units >= q.MaxUnits.Int64 {
+		return "units"
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+)
+
+func TestQuotaExceeded(t *testing.T) {
+	c := qt.New(t)
+
+	q := dbmodel.Quota{
+		MaxModels:   sql.NullInt64{Int64: 2, Valid: true},
+		MaxMachines: sql.NullInt64{Int64: 4, Valid: true},
+	}
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{}), qt.Equals, "")
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{Models: 1, Machines: 3, Cores: 100, Units: 100}), qt.Equals, "")
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{Models: 2}), qt.Equals, "models")
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{Machines: 4}), qt.Equals, "machines")
+
+	q = dbmodel.Quota{
+		MaxCores: sql.NullInt64{Int64: 8, Valid: true},
+		MaxUnits: sql.NullInt64{Int64: 0, Valid: true},
+	}
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{Cores: 8}), qt.Equals, "cores")
+	c.Check(q.Exceeded(dbmodel.QuotaUsage{}), qt.Equals, "units")
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A RelationExpiry records when a relation that was granted temporarily
+// in OpenFGA is due to be revoked.
+type RelationExpiry struct {
+	// Note that we do not use gorm.Model to avoid the use of soft-deletes.
+
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Object is the OpenFGA object of the relation, for example
+	// "user:alice@canonical.com".
+	Object string
+
+	// Relation is the OpenFGA relation between the object and the
+	// target.
+	Relation string
+
+	// Target is the OpenFGA target object of the relation, for example
+	// "model:00000002-0000-0000-0000-000000000001".
+	Target string
+
+	// ExpiresAt is the time at which the relation is revoked.
+	ExpiresAt time.Time
+
+	// GrantedBy is the name of the identity that granted the relation.
+	GrantedBy string
+}
@@ -0,0 +1,16 @@
+-- 1_12.sql is a migration that adds model quotas for identities and groups.
+CREATE TABLE IF NOT EXISTS quotas (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	subject TEXT NOT NULL,
+	cloud_name TEXT NOT NULL REFERENCES clouds (name) ON DELETE CASCADE,
+	region TEXT NOT NULL DEFAULT '',
+	max_models BIGINT,
+	max_machines BIGINT,
+	max_cores BIGINT,
+	max_units BIGINT,
+	UNIQUE (subject, cloud_name, region)
+);
+
+UPDATE versions SET major=1, minor=12 WHERE component='jimmdb';
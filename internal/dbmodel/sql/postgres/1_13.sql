@@ -0,0 +1,5 @@
+-- 1_13.sql is a migration that adds capacity limits to controllers.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS max_models BIGINT;
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS max_units BIGINT;
+
+UPDATE versions SET major=1, minor=13 WHERE component='jimmdb';
@@ -0,0 +1,26 @@
+-- 1_14.sql is a migration that adds tables to track controller drains.
+CREATE TABLE IF NOT EXISTS controller_drains (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	controller_id BIGINT NOT NULL UNIQUE REFERENCES controllers (id) ON DELETE CASCADE,
+	initiator TEXT NOT NULL,
+	status TEXT NOT NULL,
+	batch_size INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS controller_drain_models (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	controller_drain_id BIGINT NOT NULL REFERENCES controller_drains (id) ON DELETE CASCADE,
+	model_uuid TEXT NOT NULL,
+	model_name TEXT NOT NULL,
+	target_controller TEXT NOT NULL DEFAULT '',
+	migration_id TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	UNIQUE (controller_drain_id, model_uuid)
+);
+
+UPDATE versions SET major=1, minor=14 WHERE component='jimmdb';
@@ -0,0 +1,20 @@
+-- 1_15.sql is a migration that adds a table to record model migrations.
+CREATE TABLE IF NOT EXISTS migrations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	migration_id TEXT NOT NULL DEFAULT '',
+	model_uuid TEXT NOT NULL,
+	model_name TEXT NOT NULL,
+	source_controller TEXT NOT NULL,
+	target_controller TEXT NOT NULL,
+	requester TEXT NOT NULL,
+	phase TEXT NOT NULL,
+	message TEXT NOT NULL DEFAULT '',
+	started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	ended_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_migrations_model_uuid ON migrations (model_uuid);
+CREATE INDEX IF NOT EXISTS idx_migrations_migration_id ON migrations (migration_id);
+
+UPDATE versions SET major=1, minor=15 WHERE component='jimmdb';
@@ -0,0 +1,16 @@
+-- 1_16.sql is a migration that adds hash chaining to the audit log.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS previous_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS hash TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS audit_log_checkpoints (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	entry_id BIGINT NOT NULL,
+	previous_hash TEXT NOT NULL,
+	preceding_hash TEXT NOT NULL,
+	purged_before TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_checkpoints_entry_id ON audit_log_checkpoints (entry_id);
+
+UPDATE versions SET major=1, minor=16 WHERE component='jimmdb';
@@ -0,0 +1,8 @@
+-- 1_17.sql is a migration that adds indexes to support filtering the
+-- audit log by facade, conversation, object and parameters.
+CREATE INDEX IF NOT EXISTS idx_audit_log_facade_name ON audit_log (facade_name);
+CREATE INDEX IF NOT EXISTS idx_audit_log_conversation_id ON audit_log (conversation_id, message_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_object_id ON audit_log (object_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_params ON audit_log USING GIN ((params::jsonb) jsonb_path_ops);
+
+UPDATE versions SET major=1, minor=17 WHERE component='jimmdb';
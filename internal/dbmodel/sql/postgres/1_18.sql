@@ -0,0 +1,5 @@
+-- 1_18.sql is a migration that adds an index to support paginating the
+-- audit log by time and ID.
+CREATE INDEX IF NOT EXISTS idx_audit_log_time_id ON audit_log (time, id);
+
+UPDATE versions SET major=1, minor=18 WHERE component='jimmdb';
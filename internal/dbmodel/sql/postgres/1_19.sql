@@ -0,0 +1,20 @@
+-- 1_19.sql is a migration that adds the audit log archive manifest and a
+-- table to hold entries restored from archives.
+CREATE TABLE IF NOT EXISTS audit_log_archives (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	key TEXT NOT NULL UNIQUE,
+	first_entry_id BIGINT NOT NULL,
+	last_entry_id BIGINT NOT NULL,
+	start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+	end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+	entry_count BIGINT NOT NULL,
+	size BIGINT NOT NULL,
+	sha256 TEXT NOT NULL,
+	restored_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS restored_audit_log (LIKE audit_log INCLUDING INDEXES);
+
+UPDATE versions SET major=1, minor=19 WHERE component='jimmdb';
@@ -0,0 +1,17 @@
+-- 1_20.sql is a migration that adds a table recording when temporary
+-- relations granted in OpenFGA are due to be revoked.
+CREATE TABLE IF NOT EXISTS relation_expiries (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	object TEXT NOT NULL,
+	relation TEXT NOT NULL,
+	target TEXT NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	granted_by TEXT NOT NULL,
+	UNIQUE (object, relation, target)
+);
+
+CREATE INDEX IF NOT EXISTS idx_relation_expiries_expires_at ON relation_expiries (expires_at);
+
+UPDATE versions SET major=1, minor=20 WHERE component='jimmdb';
@@ -0,0 +1,21 @@
+-- 1_21.sql is a migration that adds a table holding requests from
+-- identities for access to resources.
+CREATE TABLE IF NOT EXISTS access_requests (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	requester TEXT NOT NULL,
+	resource TEXT NOT NULL,
+	target TEXT NOT NULL,
+	relation TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	reviewed_by TEXT NOT NULL DEFAULT '',
+	comment TEXT NOT NULL DEFAULT '',
+	expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_requests_status ON access_requests (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_requests_pending ON access_requests (requester, target, relation) WHERE status = 'pending';
+
+UPDATE versions SET major=1, minor=21 WHERE component='jimmdb';
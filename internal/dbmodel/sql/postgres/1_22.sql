@@ -0,0 +1,6 @@
+-- 1_22.sql is a migration that records the last activity seen in a model.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS last_activity TIMESTAMP WITH TIME ZONE;
+-- Existing models are assumed to have been active when last updated.
+UPDATE models SET last_activity = updated_at WHERE last_activity IS NULL;
+
+UPDATE versions SET major=1, minor=22 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_23.sql is a migration that counts the failed attempts to check the
+-- migration status of a drained model.
+ALTER TABLE controller_drain_models ADD COLUMN IF NOT EXISTS check_failures INTEGER NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=23 WHERE component='jimmdb';
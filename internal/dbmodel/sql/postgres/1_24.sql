@@ -0,0 +1,5 @@
+-- 1_24.sql is a migration that adds a machine capacity limit to
+-- controllers.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS max_machines BIGINT;
+
+UPDATE versions SET major=1, minor=24 WHERE component='jimmdb';
@@ -117,6 +117,7 @@ const (
 	CodeNotFound                     Code = jujuparams.CodeNotFound
 	CodeNotImplemented               Code = jujuparams.CodeNotImplemented
 	CodeNotSupported                 Code = jujuparams.CodeNotSupported
+	CodeQuotaLimitExceeded           Code = jujuparams.CodeQuotaLimitExceeded
 	CodeRedirect                     Code = jujuparams.CodeRedirect
 	CodeServerConfiguration          Code = "server configuration"
 	CodeStillAlive                   Code = apiparams.CodeStillAlive
@@ -323,10 +323,17 @@ func (j *JIMM) CheckPermission(ctx context.Context, user *openfga.User, cachedPe
 			if err != nil {
 				return cachedPerms, errors.E(op, fmt.Sprintf("failed to parse relation %s", stringVal), err)
 			}
-			check, err := openfga.CheckRelation(ctx, user, tag, relation)
+			tctx, checks := openfga.WithRelationTrace(ctx)
+			check, err := openfga.CheckRelation(tctx, user, tag, relation)
 			if err != nil {
 				return cachedPerms, errors.E(op, err)
 			}
+			j.auditAuthorizationDecision(ctx, user, tag, AuthorizationDecision{
+				Source:   "CheckPermission",
+				Relation: relation.String(),
+				Allowed:  check,
+				Checks:   checks(),
+			})
 			if !check {
 				return cachedPerms, errors.E(op, fmt.Sprintf("Missing permission for %s:%s", key, val))
 			}
@@ -741,6 +748,10 @@ func (j *JIMM) RemoveGroup(ctx context.Context, user *openfga.User, name string)
 		return errors.E(op, err)
 	}
 
+	if err := j.Database.DeleteQuotasForSubject(ctx, group.ResourceTag().String()); err != nil {
+		return errors.E(op, err)
+	}
+
 	if err := j.Database.RemoveGroup(ctx, group); err != nil {
 		return errors.E(op, err)
 	}
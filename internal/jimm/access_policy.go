@@ -0,0 +1,288 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// AccessPolicyAppliedAuditMethod is the facade method recorded in the
+// audit log when the changes required by an access policy are made.
+const AccessPolicyAppliedAuditMethod = "AccessPolicyApplied"
+
+// maxAccessPolicyWrite is the maximum number of tuples added and removed
+// in a single OpenFGA write, OpenFGA rejects writes of more than 100
+// tuples.
+const maxAccessPolicyWrite = 100
+
+// errAccessPolicyPlanOnly is returned from the access policy transaction
+// to roll back the groups created while planning.
+var errAccessPolicyPlanOnly = errors.E("access policy plan only")
+
+// policyTuple is a tuple declared in an access policy.
+type policyTuple struct {
+	tuple    openfga.Tuple
+	declared apiparams.RelationshipTuple
+}
+
+// tupleKey returns a key identifying the given tuple. The everyone user
+// is represented the same whether it was read from OpenFGA or converted
+// from a JIMM tag.
+func tupleKey(t openfga.Tuple) string {
+	object := *t.Object
+	if object.Kind == openfga.UserType && object.ID == "*" {
+		object.ID = ofganames.EveryoneUser
+	}
+	return object.String() + " " + string(t.Relation) + " " + t.Target.String()
+}
+
+// ApplyAccessPolicy compares the given policy with the groups and
+// relations in JIMM and returns the changes required to bring JIMM in
+// line with it. If apply is true the changes are made: missing groups are
+// created, then the relations are added and removed in OpenFGA writes of
+// at most 100 tuples, and the changes are recorded in the audit log. If
+// one of the writes fails those already made are undone, so that the
+// relations are left as they were, but the created groups are kept;
+// applying the policy again completes the changes. The failed attempt is
+// recorded in the audit log along with its error.
+//
+// The policy manages the members of the groups it declares and the
+// relations to every resource that is the target of one of its
+// relations. Relations to other resources are left as they are, as are
+// the relations linking resources to their controllers and models. Only
+// JIMM administrators may apply an access policy.
+func (j *JIMM) ApplyAccessPolicy(ctx context.Context, user *openfga.User, policy apiparams.AccessPolicy, apply bool) (*apiparams.AccessPolicyPlan, error) {
+	const op = errors.Op("jimm.ApplyAccessPolicy")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	groups := append([]string(nil), policy.Groups...)
+	for g := range policy.Memberships {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	declared := make([]apiparams.RelationshipTuple, 0, len(policy.Relations))
+	memberGroups := make([]string, 0, len(policy.Memberships))
+	for g := range policy.Memberships {
+		memberGroups = append(memberGroups, g)
+	}
+	sort.Strings(memberGroups)
+	for _, g := range memberGroups {
+		for _, m := range policy.Memberships[g] {
+			declared = append(declared, apiparams.RelationshipTuple{
+				Object:       m,
+				Relation:     string(ofganames.MemberRelation),
+				TargetObject: jimmnames.GroupTagKind + "-" + g,
+			})
+		}
+	}
+	declared = append(declared, policy.Relations...)
+
+	var plan apiparams.AccessPolicyPlan
+	var add, remove []openfga.Tuple
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		var managed []*openfga.Tag
+		seenGroups := make(map[string]bool)
+		for _, name := range groups {
+			if seenGroups[name] {
+				continue
+			}
+			seenGroups[name] = true
+			ge := dbmodel.GroupEntry{Name: name}
+			err := tx.GetGroup(ctx, &ge)
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				if !jimmnames.IsValidGroupName(name) {
+					return errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid group name %q", name))
+				}
+				var added *dbmodel.GroupEntry
+				added, err = tx.AddGroup(ctx, name)
+				if err == nil {
+					ge = *added
+					plan.CreateGroups = append(plan.CreateGroups, name)
+				}
+			}
+			if err != nil {
+				return err
+			}
+			managed = append(managed, ofganames.ConvertTag(ge.ResourceTag()))
+		}
+
+		wanted, err := j.resolvePolicyTuples(ctx, tx, declared)
+		if err != nil {
+			return err
+		}
+		wantedKeys := make(map[string]bool)
+		for _, pt := range wanted {
+			if !wantedKeys[tupleKey(pt.tuple)] {
+				managed = append(managed, pt.tuple.Target)
+			}
+			wantedKeys[tupleKey(pt.tuple)] = true
+		}
+
+		existingKeys := make(map[string]bool)
+		seenTargets := make(map[string]bool)
+		for _, target := range managed {
+			if seenTargets[target.String()] {
+				continue
+			}
+			seenTargets[target.String()] = true
+			tuples, err := readAllTuples(ctx, j.OpenFGAClient, openfga.Tuple{Target: target})
+			if err != nil {
+				return errors.E(errors.CodeOpenFGARequestFailed, err)
+			}
+			for _, t := range tuples {
+				if t.Relation == ofganames.ControllerRelation || t.Relation == ofganames.ModelRelation {
+					continue
+				}
+				key := tupleKey(t)
+				existingKeys[key] = true
+				if wantedKeys[key] {
+					continue
+				}
+				if t.Object.Kind == openfga.UserType && t.Object.ID == ofganames.EveryoneUser {
+					object := *t.Object
+					object.ID = "*"
+					t.Object = &object
+				}
+				remove = append(remove, t)
+				plan.Remove = append(plan.Remove, apiparams.RelationshipTuple{
+					Object:       j.jaasTagOrString(ctx, t.Object),
+					Relation:     string(t.Relation),
+					TargetObject: j.jaasTagOrString(ctx, t.Target),
+				})
+			}
+		}
+		sort.Slice(plan.Remove, func(i, j int) bool {
+			ri, rj := plan.Remove[i], plan.Remove[j]
+			if ri.TargetObject != rj.TargetObject {
+				return ri.TargetObject < rj.TargetObject
+			}
+			if ri.Object != rj.Object {
+				return ri.Object < rj.Object
+			}
+			return ri.Relation < rj.Relation
+		})
+
+		for _, pt := range wanted {
+			key := tupleKey(pt.tuple)
+			if existingKeys[key] {
+				continue
+			}
+			existingKeys[key] = true
+			add = append(add, pt.tuple)
+			plan.Add = append(plan.Add, pt.declared)
+		}
+
+		if !apply {
+			return errAccessPolicyPlanOnly
+		}
+		return nil
+	})
+	if err == errAccessPolicyPlanOnly {
+		return &plan, nil
+	}
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	// The relations are written once the groups have been committed so
+	// that OpenFGA never refers to a group that does not exist.
+	if err := j.writeAccessPolicyTuples(ctx, add, remove); err != nil {
+		err = errors.E(op, errors.CodeOpenFGARequestFailed, err)
+		j.auditFailedJIMMOperation(ctx, user, AccessPolicyAppliedAuditMethod, "", &plan, err)
+		return nil, err
+	}
+	plan.Applied = true
+	if len(plan.CreateGroups) > 0 || len(plan.Add) > 0 || len(plan.Remove) > 0 {
+		j.auditJIMMOperation(ctx, user, AccessPolicyAppliedAuditMethod, "", &plan)
+	}
+	return &plan, nil
+}
+
+// writeAccessPolicyTuples adds and removes the given tuples in OpenFGA
+// writes of at most maxAccessPolicyWrite tuples. If a write fails the
+// writes already made are undone, most recent first.
+func (j *JIMM) writeAccessPolicyTuples(ctx context.Context, add, remove []openfga.Tuple) error {
+	type write struct {
+		add, remove []openfga.Tuple
+	}
+	var written []write
+	for len(add) > 0 || len(remove) > 0 {
+		var w write
+		n := min(len(remove), maxAccessPolicyWrite)
+		w.remove, remove = remove[:n], remove[n:]
+		n = min(len(add), maxAccessPolicyWrite-len(w.remove))
+		w.add, add = add[:n], add[n:]
+		if err := j.OpenFGAClient.AddRemoveRelations(ctx, w.add, w.remove); err != nil {
+			for i := len(written) - 1; i >= 0; i-- {
+				if uerr := j.OpenFGAClient.AddRemoveRelations(ctx, written[i].remove, written[i].add); uerr != nil {
+					zapctx.Error(ctx, "failed to undo access policy changes", zap.Error(uerr))
+				}
+			}
+			return err
+		}
+		written = append(written, w)
+	}
+	return nil
+}
+
+// resolvePolicyTuples resolves the tags in the given tuples, declared in
+// an access policy, using the given database.
+func (j *JIMM) resolvePolicyTuples(ctx context.Context, tx *db.Database, declared []apiparams.RelationshipTuple) ([]policyTuple, error) {
+	tuples := make([]policyTuple, 0, len(declared))
+	for _, d := range declared {
+		relation, err := ofganames.ParseRelation(d.Relation)
+		if err != nil {
+			return nil, errors.E(errors.CodeBadRequest, err)
+		}
+		switch relation {
+		case ofganames.NoRelation:
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("relation not specified for %s", d.TargetObject))
+		case ofganames.ControllerRelation, ofganames.ModelRelation:
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("relation %q cannot be managed by an access policy", relation))
+		}
+		object, err := resolveTag(j.UUID, tx, d.Object)
+		if err != nil {
+			return nil, errors.E(errors.CodeFailedToResolveTupleResource, fmt.Sprintf("failed to resolve %q", d.Object), err)
+		}
+		target, err := resolveTag(j.UUID, tx, d.TargetObject)
+		if err != nil {
+			return nil, errors.E(errors.CodeFailedToResolveTupleResource, fmt.Sprintf("failed to resolve %q", d.TargetObject), err)
+		}
+		tuples = append(tuples, policyTuple{
+			tuple: openfga.Tuple{
+				Object:   object,
+				Relation: relation,
+				Target:   target,
+			},
+			declared: d,
+		})
+	}
+	return tuples, nil
+}
+
+// jaasTagOrString returns the JAAS representation of the given tag, or
+// its OpenFGA representation if it cannot be resolved.
+func (j *JIMM) jaasTagOrString(ctx context.Context, tag *openfga.Tag) string {
+	s, err := j.ToJAASTag(ctx, tag, true)
+	if err != nil {
+		return tag.String()
+	}
+	return s
+}
@@ -0,0 +1,291 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestApplyAccessPolicy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// bob becomes a writer through the devs group, and charlie loses
+	// access to the model.
+	policy := apiparams.AccessPolicy{
+		Memberships: map[string][]string{
+			"devs": {"user-bob@canonical.com"},
+		},
+		Relations: []apiparams.RelationshipTuple{{
+			Object:       "user-alice@canonical.com",
+			Relation:     "administrator",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}, {
+			Object:       "group-devs#member",
+			Relation:     "writer",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}},
+	}
+	expectedPlan := apiparams.AccessPolicyPlan{
+		CreateGroups: []string{"devs"},
+		Add: []apiparams.RelationshipTuple{{
+			Object:       "user-bob@canonical.com",
+			Relation:     "member",
+			TargetObject: "group-devs",
+		}, {
+			Object:       "group-devs#member",
+			Relation:     "writer",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}},
+		Remove: []apiparams.RelationshipTuple{{
+			Object:       "user-bob@canonical.com",
+			Relation:     "writer",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}, {
+			Object:       "user-charlie@canonical.com",
+			Relation:     "reader",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}},
+	}
+
+	_, err = j.ApplyAccessPolicy(ctx, bob, policy, true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	plan, err := j.ApplyAccessPolicy(ctx, diane, policy, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(*plan, qt.DeepEquals, expectedPlan)
+
+	// Planning makes no changes.
+	err = j.Database.GetGroup(ctx, &dbmodel.GroupEntry{Name: "devs"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.ReaderRelation)
+
+	plan, err = j.ApplyAccessPolicy(ctx, diane, policy, true)
+	c.Assert(err, qt.IsNil)
+	expectedPlan.Applied = true
+	c.Check(*plan, qt.DeepEquals, expectedPlan)
+
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.WriterRelation)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+	allowed, err := client.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob.ResourceTag()),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(allowed, qt.IsTrue)
+
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: jimm.AccessPolicyAppliedAuditMethod}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+	c.Check(entries[0].IdentityTag, qt.Equals, "user-diane@canonical.com")
+	var audited apiparams.AccessPolicyPlan
+	err = json.Unmarshal(entries[0].Params, &audited)
+	c.Assert(err, qt.IsNil)
+	c.Check(audited, qt.DeepEquals, expectedPlan)
+
+	// The policy is now in effect, so nothing remains to be done.
+	plan, err = j.ApplyAccessPolicy(ctx, diane, policy, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(*plan, qt.DeepEquals, apiparams.AccessPolicyPlan{})
+}
+
+func TestApplyLargeAccessPolicy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// The changes are more than can be made in a single OpenFGA write.
+	var members []string
+	for i := 0; i < 250; i++ {
+		members = append(members, fmt.Sprintf("user-user%d@canonical.com", i))
+	}
+	plan, err := j.ApplyAccessPolicy(ctx, diane, apiparams.AccessPolicy{
+		Memberships: map[string][]string{
+			"staff": members,
+		},
+	}, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(plan.Applied, qt.IsTrue)
+	c.Check(plan.Add, qt.HasLen, 250)
+
+	ge := dbmodel.GroupEntry{Name: "staff"}
+	err = j.Database.GetGroup(ctx, &ge)
+	c.Assert(err, qt.IsNil)
+	for _, i := range []int{0, 99, 100, 249} {
+		allowed, err := client.CheckRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(fmt.Sprintf("user%d@canonical.com", i))),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(ge.ResourceTag()),
+		}, false)
+		c.Assert(err, qt.IsNil)
+		c.Check(allowed, qt.IsTrue, qt.Commentf("user%d", i))
+	}
+
+	// Removing the members is also split across writes.
+	plan, err = j.ApplyAccessPolicy(ctx, diane, apiparams.AccessPolicy{
+		Groups: []string{"staff"},
+	}, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(plan.Remove, qt.HasLen, 250)
+}
+
+func TestApplyAccessPolicyInvalidRelation(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	_, err = j.ApplyAccessPolicy(ctx, diane, apiparams.AccessPolicy{
+		Groups: []string{"devs"},
+		Relations: []apiparams.RelationshipTuple{{
+			Object:       "controller-controller-1",
+			Relation:     "controller",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}},
+	}, true)
+	c.Check(err, qt.ErrorMatches, `relation "controller" cannot be managed by an access policy`)
+
+	// The transaction is rolled back.
+	err = j.Database.GetGroup(ctx, &dbmodel.GroupEntry{Name: "devs"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestApplyAccessPolicyWriteFailure(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// OpenFGA rejects the relation as models have no members.
+	_, err = j.ApplyAccessPolicy(ctx, diane, apiparams.AccessPolicy{
+		Groups: []string{"devs"},
+		Relations: []apiparams.RelationshipTuple{{
+			Object:       "user-bob@canonical.com",
+			Relation:     "member",
+			TargetObject: "model-alice@canonical.com/model-1",
+		}},
+	}, true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeOpenFGARequestFailed)
+
+	// The failed attempt is audited.
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: jimm.AccessPolicyAppliedAuditMethod}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+	c.Check(entries[0].IdentityTag, qt.Equals, "user-diane@canonical.com")
+	var results jujuparams.ErrorResults
+	err = json.Unmarshal(entries[0].Errors, &results)
+	c.Assert(err, qt.IsNil)
+	c.Assert(results.Results, qt.HasLen, 1)
+	c.Check(results.Results[0].Error.Code, qt.Equals, string(errors.CodeOpenFGARequestFailed))
+	var audited apiparams.AccessPolicyPlan
+	err = json.Unmarshal(entries[0].Params, &audited)
+	c.Assert(err, qt.IsNil)
+	c.Check(audited.Applied, qt.IsFalse)
+	c.Check(audited.CreateGroups, qt.DeepEquals, []string{"devs"})
+}
@@ -0,0 +1,357 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// Sources of access reported in an AccessGrant.
+const (
+	// AccessSourceDirect is reported for relations granted directly to
+	// the identity.
+	AccessSourceDirect = "direct"
+	// AccessSourceGroup is reported for relations granted to a group
+	// the identity is a member of.
+	AccessSourceGroup = "group"
+	// AccessSourceInherited is reported for administrator relations
+	// inherited from a parent resource, such as a controller.
+	AccessSourceInherited = "inherited"
+)
+
+// tupleReadPageSize is the number of tuples read from OpenFGA in each
+// request by readAllTuples.
+const tupleReadPageSize = 100
+
+// accessReportKinds holds the kinds of resource included in an identity
+// access report along with the weakest relation an identity may have to
+// a resource of that kind.
+var accessReportKinds = []struct {
+	kind     openfga.Kind
+	relation openfga.Relation
+}{
+	{openfga.ControllerType, ofganames.AuditLogViewerRelation},
+	{openfga.CloudType, ofganames.CanAddModelRelation},
+	{openfga.ModelType, ofganames.ReaderRelation},
+	{openfga.ApplicationOfferType, ofganames.ReaderRelation},
+	{openfga.ServiceAccountType, ofganames.AdministratorRelation},
+}
+
+// An AccessGrant describes one way in which an identity has a relation
+// to a resource.
+type AccessGrant struct {
+	// Identity is the identity that has the relation.
+	Identity *openfga.Tag
+
+	// Relation is the relation granted.
+	Relation openfga.Relation
+
+	// Resource is the resource the identity has the relation to.
+	Resource *openfga.Tag
+
+	// Via holds the groups and parent resources through which the
+	// relation is granted, ordered from the identity to the resource.
+	// Via is empty for relations granted directly.
+	Via []*openfga.Tag
+}
+
+// Source returns the source of the access, one of AccessSourceDirect,
+// AccessSourceGroup or AccessSourceInherited.
+func (g AccessGrant) Source() string {
+	source := AccessSourceDirect
+	for _, t := range g.Via {
+		if t.Kind != openfga.GroupType {
+			return AccessSourceInherited
+		}
+		source = AccessSourceGroup
+	}
+	return source
+}
+
+// ResourceAccessReport returns every identity that has a relation to the
+// given resource, expanding group membership and relations inherited
+// from parent resources. Only JIMM administrators may retrieve an access
+// report.
+func (j *JIMM) ResourceAccessReport(ctx context.Context, user *openfga.User, resource *openfga.Tag) ([]AccessGrant, error) {
+	const op = errors.Op("jimm.ResourceAccessReport")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	r := newAccessReporter(j.OpenFGAClient)
+	rgrants, err := r.resourceGrants(ctx, resource)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	var grants []AccessGrant
+	for _, g := range rgrants {
+		if g.Identity.Kind != openfga.GroupType {
+			grants = append(grants, g)
+		}
+	}
+	sortAccessGrants(grants)
+	return grants, nil
+}
+
+// IdentityAccessReport returns every relation the given identity has to
+// controllers, clouds, models, application offers and service accounts,
+// including those granted to the groups it is a member of and those
+// inherited from parent resources. Relations granted to everyone are
+// included. Only JIMM administrators may retrieve an access report.
+func (j *JIMM) IdentityAccessReport(ctx context.Context, user *openfga.User, identity *openfga.Tag) ([]AccessGrant, error) {
+	const op = errors.Op("jimm.IdentityAccessReport")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if identity.Kind != openfga.UserType {
+		return nil, errors.E(op, errors.CodeBadRequest, "identity must be a user")
+	}
+
+	r := newAccessReporter(j.OpenFGAClient)
+	var grants []AccessGrant
+	for _, k := range accessReportKinds {
+		resources, err := j.OpenFGAClient.ListObjects(ctx, identity, k.relation, k.kind, nil)
+		if err != nil {
+			return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+		}
+		for i := range resources {
+			rgrants, err := r.resourceGrants(ctx, &resources[i])
+			if err != nil {
+				return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+			}
+			for _, g := range rgrants {
+				if g.Identity.Kind == openfga.UserType && (g.Identity.ID == identity.ID || g.Identity.ID == ofganames.EveryoneUser) {
+					grants = append(grants, g)
+				}
+			}
+		}
+	}
+	sortAccessGrants(grants)
+	return grants, nil
+}
+
+// sortAccessGrants sorts the given grants by resource, identity, relation
+// and path.
+func sortAccessGrants(grants []AccessGrant) {
+	key := func(g AccessGrant) []string {
+		via := make([]string, len(g.Via))
+		for i, t := range g.Via {
+			via[i] = t.String()
+		}
+		return []string{g.Resource.String(), g.Identity.String(), string(g.Relation), strings.Join(via, " ")}
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		ki, kj := key(grants[i]), key(grants[j])
+		for n := range ki {
+			if ki[n] != kj[n] {
+				return ki[n] < kj[n]
+			}
+		}
+		return false
+	})
+}
+
+// An accessReporter expands the tuples stored in OpenFGA into the
+// effective access identities have to resources. Results are cached so
+// that parent resources and groups shared by many resources are only
+// read once.
+type accessReporter struct {
+	client    *openfga.OFGAClient
+	resources map[string][]AccessGrant
+	groups    map[string][]groupMember
+
+	// expanding holds the resources and groups currently being
+	// expanded, to guard against cycles.
+	expanding map[string]bool
+}
+
+// A groupMember is an identity, or nested group, that is a member of a
+// group, possibly through membership of nested groups.
+type groupMember struct {
+	identity *openfga.Tag
+	// via holds the groups through which the identity is a member,
+	// ordered from the identity to the group.
+	via []*openfga.Tag
+}
+
+func newAccessReporter(client *openfga.OFGAClient) *accessReporter {
+	return &accessReporter{
+		client:    client,
+		resources: make(map[string][]AccessGrant),
+		groups:    make(map[string][]groupMember),
+		expanding: make(map[string]bool),
+	}
+}
+
+// resourceGrants returns every grant of a relation to the given resource.
+func (r *accessReporter) resourceGrants(ctx context.Context, resource *openfga.Tag) ([]AccessGrant, error) {
+	grants, _, err := r.expandResource(ctx, resource)
+	return grants, err
+}
+
+// expandResource returns every grant of a relation to the given resource
+// and whether the grants are complete. The grants are incomplete if a
+// cycle in the parent relations or group membership was cut while they
+// were expanded, incomplete grants are not cached.
+func (r *accessReporter) expandResource(ctx context.Context, resource *openfga.Tag) (_ []AccessGrant, complete bool, _ error) {
+	key := resource.String()
+	if grants, ok := r.resources[key]; ok {
+		return grants, true, nil
+	}
+	expandingKey := "resource " + key
+	if r.expanding[expandingKey] {
+		return nil, false, nil
+	}
+	r.expanding[expandingKey] = true
+	defer delete(r.expanding, expandingKey)
+
+	tuples, err := readAllTuples(ctx, r.client, openfga.Tuple{Target: resource})
+	if err != nil {
+		return nil, false, err
+	}
+	complete = true
+	var grants []AccessGrant
+	for _, t := range tuples {
+		switch {
+		case t.Relation == ofganames.ControllerRelation || t.Relation == ofganames.ModelRelation:
+			// Administrators of the parent controller, or model
+			// for application offers, administer the resource.
+			parentGrants, parentComplete, err := r.expandResource(ctx, t.Object)
+			if err != nil {
+				return nil, false, err
+			}
+			complete = complete && parentComplete
+			for _, g := range parentGrants {
+				if g.Relation != ofganames.AdministratorRelation {
+					continue
+				}
+				grants = append(grants, AccessGrant{
+					Identity: g.Identity,
+					Relation: ofganames.AdministratorRelation,
+					Resource: resource,
+					Via:      appendTags(g.Via, g.Resource),
+				})
+			}
+		case t.Object.Kind == openfga.GroupType:
+			// The group itself is recorded so that relations of
+			// groups can be explained, it is not included in
+			// access reports.
+			grants = append(grants, AccessGrant{
+				Identity: t.Object,
+				Relation: t.Relation,
+				Resource: resource,
+			})
+			members, membersComplete, err := r.expandGroup(ctx, &openfga.Tag{Kind: openfga.GroupType, ID: t.Object.ID})
+			if err != nil {
+				return nil, false, err
+			}
+			complete = complete && membersComplete
+			for _, m := range members {
+				grants = append(grants, AccessGrant{
+					Identity: m.identity,
+					Relation: t.Relation,
+					Resource: resource,
+					Via:      m.via,
+				})
+			}
+		default:
+			grants = append(grants, AccessGrant{
+				Identity: t.Object,
+				Relation: t.Relation,
+				Resource: resource,
+			})
+		}
+	}
+	if complete {
+		r.resources[key] = grants
+	}
+	return grants, complete, nil
+}
+
+// expandGroup returns every member of the given group, including the
+// members of nested groups, and whether the members are complete. The
+// members are incomplete if a cycle in group membership was cut while
+// they were expanded, incomplete members are not cached.
+func (r *accessReporter) expandGroup(ctx context.Context, group *openfga.Tag) (_ []groupMember, complete bool, _ error) {
+	key := group.String()
+	if members, ok := r.groups[key]; ok {
+		return members, true, nil
+	}
+	expandingKey := "group " + key
+	if r.expanding[expandingKey] {
+		return nil, false, nil
+	}
+	r.expanding[expandingKey] = true
+	defer delete(r.expanding, expandingKey)
+
+	tuples, err := readAllTuples(ctx, r.client, openfga.Tuple{
+		Relation: ofganames.MemberRelation,
+		Target:   group,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	complete = true
+	var members []groupMember
+	for _, t := range tuples {
+		if t.Object.Kind != openfga.GroupType {
+			members = append(members, groupMember{
+				identity: t.Object,
+				via:      []*openfga.Tag{group},
+			})
+			continue
+		}
+		members = append(members, groupMember{
+			identity: t.Object,
+			via:      []*openfga.Tag{group},
+		})
+		nested, nestedComplete, err := r.expandGroup(ctx, &openfga.Tag{Kind: openfga.GroupType, ID: t.Object.ID})
+		if err != nil {
+			return nil, false, err
+		}
+		complete = complete && nestedComplete
+		for _, m := range nested {
+			members = append(members, groupMember{
+				identity: m.identity,
+				via:      appendTags(m.via, group),
+			})
+		}
+	}
+	if complete {
+		r.groups[key] = members
+	}
+	return members, complete, nil
+}
+
+// appendTags returns a new slice holding the given tags followed by tag,
+// leaving the original slice unmodified.
+func appendTags(tags []*openfga.Tag, tag *openfga.Tag) []*openfga.Tag {
+	res := make([]*openfga.Tag, 0, len(tags)+1)
+	res = append(res, tags...)
+	return append(res, tag)
+}
+
+// readAllTuples reads every tuple matching the given tuple, following
+// continuation tokens until all pages have been read.
+func readAllTuples(ctx context.Context, client *openfga.OFGAClient, key openfga.Tuple) ([]openfga.Tuple, error) {
+	var tuples []openfga.Tuple
+	var ct string
+	for {
+		page, next, err := client.ReadRelatedObjects(ctx, key, tupleReadPageSize, ct)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, page...)
+		if next == "" {
+			return tuples, nil
+		}
+		ct = next
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// accessGrantSummary is a comparable summary of an AccessGrant.
+type accessGrantSummary struct {
+	Identity string
+	Relation string
+	Resource string
+	Source   string
+	Via      []string
+}
+
+func summariseAccessGrants(grants []jimm.AccessGrant) []accessGrantSummary {
+	summaries := make([]accessGrantSummary, len(grants))
+	for i, g := range grants {
+		summaries[i] = accessGrantSummary{
+			Identity: g.Identity.String(),
+			Relation: string(g.Relation),
+			Resource: g.Resource.String(),
+			Source:   g.Source(),
+		}
+		for _, t := range g.Via {
+			summaries[i].Via = append(summaries[i].Via, t.String())
+		}
+	}
+	return summaries
+}
+
+func TestAccessReport(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	ctlUUID := env.Controller("controller-1").DBObject(c, j.Database).UUID
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// bob is also a writer through the devs group, and charlie is a
+	// member of devs through the nested ops group.
+	devs, err := j.Database.AddGroup(ctx, "devs")
+	c.Assert(err, qt.IsNil)
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(devs.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(ops.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(devs.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(devs.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.ResourceAccessReport(ctx, bob, ofganames.ConvertTag(mt))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+	_, err = j.IdentityAccessReport(ctx, bob, ofganames.ConvertTag(bob.ResourceTag()))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	model := "model:" + mt.Id()
+	devsTag := "group:" + devs.UUID
+	opsTag := "group:" + ops.UUID
+	grants, err := j.ResourceAccessReport(ctx, diane, ofganames.ConvertTag(mt))
+	c.Assert(err, qt.IsNil)
+	c.Check(summariseAccessGrants(grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:alice@canonical.com",
+		Relation: "administrator",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:bob@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:bob@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{devsTag},
+	}, {
+		Identity: "user:charlie@canonical.com",
+		Relation: "reader",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:charlie@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{opsTag, devsTag},
+	}, {
+		Identity: "user:diane@canonical.com",
+		Relation: "administrator",
+		Resource: model,
+		Source:   jimm.AccessSourceInherited,
+		Via:      []string{"controller:" + j.UUID, "controller:" + ctlUUID},
+	}})
+
+	_, err = j.IdentityAccessReport(ctx, diane, ofganames.ConvertTag(devs.ResourceTag()))
+	c.Check(err, qt.ErrorMatches, `identity must be a user`)
+
+	grants, err = j.IdentityAccessReport(ctx, diane, ofganames.ConvertTag(bob.ResourceTag()))
+	c.Assert(err, qt.IsNil)
+	c.Check(summariseAccessGrants(grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:bob@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:bob@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{devsTag},
+	}})
+}
+
+func TestAccessReportGroupCycle(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// devs and ops are members of each other. The cloud, which is
+	// reported first, is granted to ops and the model to devs, so devs
+	// is first expanded with the cycle back to ops cut and must not be
+	// reused for the model.
+	devs, err := j.Database.AddGroup(ctx, "devs")
+	c.Assert(err, qt.IsNil)
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(ops.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(devs.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(devs.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(ops.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.CanAddModelRelation,
+		Target:   ofganames.ConvertTag(names.NewCloudTag("test-cloud")),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(devs.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	c.Assert(err, qt.IsNil)
+
+	grants, err := j.IdentityAccessReport(ctx, diane, ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")))
+	c.Assert(err, qt.IsNil)
+	var modelGrants []jimm.AccessGrant
+	for _, g := range grants {
+		if g.Resource.Kind == openfga.ModelType {
+			modelGrants = append(modelGrants, g)
+		}
+	}
+	model := "model:" + mt.Id()
+	c.Check(summariseAccessGrants(modelGrants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:charlie@canonical.com",
+		Relation: "reader",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:charlie@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{"group:" + ops.UUID, "group:" + devs.UUID},
+	}})
+}
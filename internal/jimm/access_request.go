@@ -0,0 +1,280 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// Facade methods recorded in the audit log for changes to access
+// requests.
+const (
+	AccessRequestCreatedAuditMethod  = "AccessRequestCreated"
+	AccessRequestApprovedAuditMethod = "AccessRequestApproved"
+	AccessRequestDeniedAuditMethod   = "AccessRequestDenied"
+)
+
+// accessRequestRelation returns the relation corresponding to the given
+// access level on an object of the given kind. Access may only be
+// requested to models, clouds and application offers.
+func accessRequestRelation(kind openfga.Kind, access string) (openfga.Relation, error) {
+	var relation openfga.Relation
+	var err error
+	switch kind {
+	case openfga.ModelType:
+		relation, err = ToModelRelation(access)
+	case openfga.CloudType:
+		relation, err = ToCloudRelation(access)
+	case openfga.ApplicationOfferType:
+		relation, err = ToOfferRelation(access)
+	default:
+		return ofganames.NoRelation, errors.E(errors.CodeBadRequest, "access may only be requested to models, clouds and application offers")
+	}
+	if err != nil {
+		return ofganames.NoRelation, errors.E(errors.CodeBadRequest, err)
+	}
+	if relation == ofganames.NoRelation {
+		return ofganames.NoRelation, errors.E(errors.CodeBadRequest, "access not specified")
+	}
+	return relation, nil
+}
+
+// RequestAccess files a request from the given user for the given access
+// level, as used by "juju grant", on the model, cloud or application
+// offer with the given tag. If the user already has the requested access
+// an error with a code of CodeAlreadyExists is returned.
+func (j *JIMM) RequestAccess(ctx context.Context, user *openfga.User, resource, access, reason string) (*dbmodel.AccessRequest, error) {
+	const op = errors.Op("jimm.RequestAccess")
+
+	target, err := j.ParseTag(ctx, resource)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	relation, err := accessRequestRelation(target.Kind, access)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	allowed, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(user.ResourceTag()),
+		Relation: relation,
+		Target:   target,
+	}, false)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	if allowed {
+		return nil, errors.E(op, errors.CodeAlreadyExists, "access already granted")
+	}
+
+	ar := dbmodel.AccessRequest{
+		Requester: user.Name,
+		Resource:  resource,
+		Target:    target.String(),
+		Relation:  string(relation),
+		Reason:    reason,
+		Status:    dbmodel.AccessRequestPending,
+	}
+	if err := j.Database.AddAccessRequest(ctx, &ar); err != nil {
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return nil, errors.E(op, err, "access request already pending")
+		}
+		return nil, errors.E(op, err)
+	}
+	j.auditAccessRequest(ctx, user, AccessRequestCreatedAuditMethod, &ar)
+	return &ar, nil
+}
+
+// ListAccessRequests returns the access requests with the given status,
+// or all access requests if the status is empty. If mine is true the
+// requests made by the given user are returned, otherwise the requests
+// the user may review are returned. A user may review the requests for
+// resources they administer, JIMM administrators may review all
+// requests.
+func (j *JIMM) ListAccessRequests(ctx context.Context, user *openfga.User, status string, mine bool) ([]dbmodel.AccessRequest, error) {
+	const op = errors.Op("jimm.ListAccessRequests")
+
+	filter := db.AccessRequestFilter{
+		Status: status,
+	}
+	if mine {
+		filter.Requester = user.Name
+	}
+	// Cache the review decisions as many requests are likely to be for
+	// the same resources.
+	reviewable := make(map[string]bool)
+	var requests []dbmodel.AccessRequest
+	err := j.Database.ForEachAccessRequest(ctx, filter, func(ar *dbmodel.AccessRequest) error {
+		if !mine {
+			ok, found := reviewable[ar.Target]
+			if !found {
+				var err error
+				if ok, err = j.canReviewAccessRequest(ctx, user, ar); err != nil {
+					return err
+				}
+				reviewable[ar.Target] = ok
+			}
+			if !ok {
+				return nil
+			}
+		}
+		requests = append(requests, *ar)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return requests, nil
+}
+
+// ApproveAccessRequest approves the access request with the given ID,
+// granting the requested access. If expiresAt is not zero the granted
+// access is revoked at that time, unless the access had already been
+// granted, in which case the existing grant is left unchanged and no
+// expiry is recorded on the request. Only administrators of the
+// requested resource may approve a request.
+func (j *JIMM) ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string, expiresAt time.Time) (*dbmodel.AccessRequest, error) {
+	const op = errors.Op("jimm.ApproveAccessRequest")
+
+	if err := validateRelationExpiry(expiresAt); err != nil {
+		return nil, errors.E(op, err)
+	}
+	ar, err := j.getPendingAccessRequest(ctx, user, id)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	target, err := openfga.ParseTag(ar.Target)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	// The review is stored before access is granted so that a concurrent
+	// review of the same request fails rather than also succeeding.
+	ar.Status = dbmodel.AccessRequestApproved
+	ar.ReviewedBy = user.Name
+	ar.Comment = comment
+	if err := j.Database.ReviewAccessRequest(ctx, ar); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	tuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag(ar.Requester)),
+		Relation: openfga.Relation(ar.Relation),
+		Target:   &target,
+	}
+	err = j.OpenFGAClient.AddRelation(ctx, tuple)
+	switch {
+	case err == nil:
+		if err := j.recordRelationExpiry(ctx, user, expiresAt, tuple); err != nil {
+			j.reopenAccessRequest(ctx, ar)
+			return nil, errors.E(op, err)
+		}
+		if !expiresAt.IsZero() {
+			ar.ExpiresAt = sql.NullTime{Time: expiresAt.UTC(), Valid: true}
+			if err := j.Database.UpdateAccessRequest(ctx, ar); err != nil {
+				return nil, errors.E(op, err)
+			}
+		}
+	case strings.Contains(err.Error(), "cannot write a tuple which already exists"):
+		// The access has been granted since the request was made,
+		// an expiry is not recorded so that the existing grant is
+		// left as it is.
+		// TODO we should opt to check against specific errors via checking their code/metadata.
+	default:
+		j.reopenAccessRequest(ctx, ar)
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	j.auditAccessRequest(ctx, user, AccessRequestApprovedAuditMethod, ar)
+	return ar, nil
+}
+
+// DenyAccessRequest denies the access request with the given ID. Only
+// administrators of the requested resource may deny a request.
+func (j *JIMM) DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) (*dbmodel.AccessRequest, error) {
+	const op = errors.Op("jimm.DenyAccessRequest")
+
+	ar, err := j.getPendingAccessRequest(ctx, user, id)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	ar.Status = dbmodel.AccessRequestDenied
+	ar.ReviewedBy = user.Name
+	ar.Comment = comment
+	if err := j.Database.ReviewAccessRequest(ctx, ar); err != nil {
+		return nil, errors.E(op, err)
+	}
+	j.auditAccessRequest(ctx, user, AccessRequestDeniedAuditMethod, ar)
+	return ar, nil
+}
+
+// reopenAccessRequest returns the given access request to pending after
+// access could not be granted, so that it may be reviewed again.
+func (j *JIMM) reopenAccessRequest(ctx context.Context, ar *dbmodel.AccessRequest) {
+	ar.Status = dbmodel.AccessRequestPending
+	ar.ReviewedBy = ""
+	ar.Comment = ""
+	if err := j.Database.UpdateAccessRequest(ctx, ar); err != nil {
+		zapctx.Error(ctx, "failed to reopen access request", zap.Uint("id", ar.ID), zap.Error(err))
+	}
+}
+
+// getPendingAccessRequest retrieves the pending access request with the
+// given ID for review by the given user.
+func (j *JIMM) getPendingAccessRequest(ctx context.Context, user *openfga.User, id uint) (*dbmodel.AccessRequest, error) {
+	ar := dbmodel.AccessRequest{ID: id}
+	if err := j.Database.GetAccessRequest(ctx, &ar); err != nil {
+		return nil, err
+	}
+	ok, err := j.canReviewAccessRequest(ctx, user, &ar)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	if ar.Status != dbmodel.AccessRequestPending {
+		return nil, errors.E(errors.CodeBadRequest, "access request is "+ar.Status)
+	}
+	return &ar, nil
+}
+
+// canReviewAccessRequest returns whether the given user may approve or
+// deny the given access request.
+func (j *JIMM) canReviewAccessRequest(ctx context.Context, user *openfga.User, ar *dbmodel.AccessRequest) (bool, error) {
+	if user.JimmAdmin {
+		return true, nil
+	}
+	target, err := openfga.ParseTag(ar.Target)
+	if err != nil {
+		return false, err
+	}
+	allowed, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(user.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   &target,
+	}, false)
+	if err != nil {
+		return false, errors.E(errors.CodeOpenFGARequestFailed, err)
+	}
+	return allowed, nil
+}
+
+// auditAccessRequest records a change to the given access request, made
+// by the given user, in the audit log.
+func (j *JIMM) auditAccessRequest(ctx context.Context, user *openfga.User, method string, ar *dbmodel.AccessRequest) {
+	j.auditJIMMOperation(ctx, user, method, ar.Resource, ar.ToAPIAccessRequest())
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func TestAccessRequests(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+
+	const resource = "model-alice@canonical.com/model-1"
+
+	_, err = j.RequestAccess(ctx, charlie, resource, "superuser", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.RequestAccess(ctx, charlie, "controller-controller-1", "superuser", "")
+	c.Check(err, qt.ErrorMatches, `access may only be requested to models, clouds and application offers`)
+	_, err = j.RequestAccess(ctx, charlie, resource, "read", "")
+	c.Check(err, qt.ErrorMatches, `access already granted`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	ar, err := j.RequestAccess(ctx, charlie, resource, "write", "deploying a fix")
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Requester, qt.Equals, "charlie@canonical.com")
+	c.Check(ar.Target, qt.Equals, "model:"+mt.Id())
+	c.Check(ar.Relation, qt.Equals, "writer")
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestPending)
+
+	_, err = j.RequestAccess(ctx, charlie, resource, "write", "")
+	c.Check(err, qt.ErrorMatches, `access request already pending`)
+
+	// Only administrators of the model see the request for review.
+	requests, err := j.ListAccessRequests(ctx, bob, dbmodel.AccessRequestPending, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 0)
+	requests, err = j.ListAccessRequests(ctx, alice, dbmodel.AccessRequestPending, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 1)
+	c.Check(requests[0].ID, qt.Equals, ar.ID)
+	requests, err = j.ListAccessRequests(ctx, charlie, "", true)
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 1)
+
+	_, err = j.ApproveAccessRequest(ctx, bob, ar.ID, "", time.Time{})
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+	_, err = j.DenyAccessRequest(ctx, charlie, ar.ID, "")
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	ar, err = j.ApproveAccessRequest(ctx, alice, ar.ID, "for today only", expiresAt)
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestApproved)
+	c.Check(ar.ReviewedBy, qt.Equals, "alice@canonical.com")
+	c.Check(ar.Comment, qt.Equals, "for today only")
+	c.Check(ar.ExpiresAt.Time.Equal(expiresAt), qt.IsTrue)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.WriterRelation)
+	c.Check(relationExpiries(c, j), qt.HasLen, 1)
+
+	_, err = j.DenyAccessRequest(ctx, alice, ar.ID, "")
+	c.Check(err, qt.ErrorMatches, `access request is approved`)
+
+	ar, err = j.RequestAccess(ctx, bob, resource, "admin", "")
+	c.Assert(err, qt.IsNil)
+	ar, err = j.DenyAccessRequest(ctx, alice, ar.ID, "no")
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestDenied)
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.WriterRelation)
+
+	// Approving a request for access that has since been granted leaves
+	// the existing grant, and the request, without an expiry.
+	ar, err = j.RequestAccess(ctx, charlie, resource, "admin", "")
+	c.Assert(err, qt.IsNil)
+	err = charlie.SetModelAccess(ctx, mt, ofganames.AdministratorRelation)
+	c.Assert(err, qt.IsNil)
+	ar, err = j.ApproveAccessRequest(ctx, alice, ar.ID, "", expiresAt)
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestApproved)
+	c.Check(ar.ExpiresAt.Valid, qt.IsFalse)
+	c.Check(relationExpiries(c, j), qt.HasLen, 1)
+
+	var methods []string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{FacadeName: "JIMM"}, func(ale *dbmodel.AuditLogEntry) error {
+		c.Check(ale.ObjectId, qt.Equals, resource)
+		methods = append(methods, ale.FacadeMethod)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(methods, qt.DeepEquals, []string{
+		jimm.AccessRequestCreatedAuditMethod,
+		jimm.AccessRequestApprovedAuditMethod,
+		jimm.AccessRequestCreatedAuditMethod,
+		jimm.AccessRequestDeniedAuditMethod,
+		jimm.AccessRequestCreatedAuditMethod,
+		jimm.AccessRequestApprovedAuditMethod,
+	})
+}
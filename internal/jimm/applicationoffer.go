@@ -359,7 +359,11 @@ func (j *JIMM) GetApplicationOffer(ctx context.Context, user *openfga.User, offe
 	return &offerDetails, nil
 }
 
-// GrantOfferAccess grants rights for an application offer.
+// GrantOfferAccess grants rights for an application offer. The access is
+// granted permanently, any pending expiry of the relation is removed. The
+// Juju grant facade has no way to carry an expiry, temporary access is
+// granted with the AddRelation method of the JIMM facade (jimmctl auth
+// relation add --expires-in).
 func (j *JIMM) GrantOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error {
 	const op = errors.Op("jimm.GrantOfferAccess")
 
@@ -374,19 +378,21 @@ func (j *JIMM) GrantOfferAccess(ctx context.Context, user *openfga.User, offerUR
 		currentAccessLevel := ToOfferAccessString(currentRelation)
 		targetAccessLevel := determineAccessLevelAfterGrant(currentAccessLevel, string(access))
 
+		relation, err := ToOfferRelation(targetAccessLevel)
+		if err != nil {
+			return errors.E(op, err)
+		}
 		// NOTE (alesstimec) not removing the current access level as it might be an
 		// indirect relation.
 		if targetAccessLevel != currentAccessLevel {
-			relation, err := ToOfferRelation(targetAccessLevel)
-			if err != nil {
-				return errors.E(op, err)
-			}
 			err = tUser.SetApplicationOfferAccess(ctx, offer.ResourceTag(), relation)
 			if err != nil {
 				return errors.E(op, err)
 			}
 		}
-
+		if err := j.clearUserRelationExpiries(ctx, tUser, ofganames.ConvertTag(offer.ResourceTag()), relation); err != nil {
+			return errors.E(op, err)
+		}
 		return nil
 	})
 
@@ -440,6 +446,9 @@ func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerU
 		if err != nil {
 			return errors.E(op, err, "failed to unset given access")
 		}
+		if err := j.clearUserRelationExpiries(ctx, tUser, ofganames.ConvertTag(offer.ResourceTag()), targetRelation); err != nil {
+			return errors.E(op, err)
+		}
 
 		// Checking if the target user still has the given access to the
 		// application offer (which is possible because of indirect relations),
@@ -0,0 +1,328 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// DefaultAuditArchiveSegmentSize is the number of entries held in an
+// audit log archive segment if no size is configured.
+const DefaultAuditArchiveSegmentSize = 10000
+
+// An AuditArchiveStore is a blob store that holds audit log archive
+// segments.
+type AuditArchiveStore interface {
+	// Put stores the given data with the given key, replacing any
+	// existing data with that key.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get returns the data stored with the given key. An error with a
+	// code of errors.CodeNotFound is returned if there is no such data.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// An AuditLogArchiver archives audit log entries to an AuditArchiveStore
+// before they are purged, and restores archived entries so that they can
+// be queried.
+type AuditLogArchiver struct {
+	// Database is the database holding the audit log and the archive
+	// manifest.
+	Database db.Database
+
+	// Store is the store the archive segments are written to.
+	Store AuditArchiveStore
+
+	// SegmentSize is the maximum number of entries in a segment. If
+	// this is 0 DefaultAuditArchiveSegmentSize is used.
+	SegmentSize int
+}
+
+// An archiveRecord is the format of an audit log entry in an archive
+// segment. Params and Errors are held as strings so that the stored
+// JSON, and therefore the entry hash, is preserved exactly.
+type archiveRecord struct {
+	ID             uint      `json:"id"`
+	Time           time.Time `json:"time"`
+	Model          string    `json:"model,omitempty"`
+	ConversationId string    `json:"conversation-id,omitempty"`
+	MessageId      uint64    `json:"message-id,omitempty"`
+	FacadeName     string    `json:"facade-name,omitempty"`
+	FacadeMethod   string    `json:"facade-method,omitempty"`
+	FacadeVersion  int       `json:"facade-version,omitempty"`
+	ObjectId       string    `json:"object-id,omitempty"`
+	IdentityTag    string    `json:"identity-tag,omitempty"`
+	IsResponse     bool      `json:"is-response,omitempty"`
+	Params         string    `json:"params,omitempty"`
+	Errors         string    `json:"errors,omitempty"`
+	PreviousHash   string    `json:"previous-hash,omitempty"`
+	Hash           string    `json:"hash,omitempty"`
+}
+
+// A segmentWriter builds a compressed archive segment.
+type segmentWriter struct {
+	buf     bytes.Buffer
+	zw      *gzip.Writer
+	enc     *json.Encoder
+	archive dbmodel.AuditLogArchive
+}
+
+func newSegmentWriter() *segmentWriter {
+	w := new(segmentWriter)
+	w.zw = gzip.NewWriter(&w.buf)
+	w.enc = json.NewEncoder(w.zw)
+	return w
+}
+
+// add adds the given entry to the segment.
+func (w *segmentWriter) add(ale *dbmodel.AuditLogEntry) error {
+	err := w.enc.Encode(archiveRecord{
+		ID:             ale.ID,
+		Time:           ale.Time,
+		Model:          ale.Model,
+		ConversationId: ale.ConversationId,
+		MessageId:      ale.MessageId,
+		FacadeName:     ale.FacadeName,
+		FacadeMethod:   ale.FacadeMethod,
+		FacadeVersion:  ale.FacadeVersion,
+		ObjectId:       ale.ObjectId,
+		IdentityTag:    ale.IdentityTag,
+		IsResponse:     ale.IsResponse,
+		Params:         string(ale.Params),
+		Errors:         string(ale.Errors),
+		PreviousHash:   ale.PreviousHash,
+		Hash:           ale.Hash,
+	})
+	if err != nil {
+		return err
+	}
+	a := &w.archive
+	if a.EntryCount == 0 || ale.ID < a.FirstEntryID {
+		a.FirstEntryID = ale.ID
+	}
+	if ale.ID > a.LastEntryID {
+		a.LastEntryID = ale.ID
+	}
+	if a.EntryCount == 0 || ale.Time.Before(a.StartTime) {
+		a.StartTime = ale.Time
+	}
+	if ale.Time.After(a.EndTime) {
+		a.EndTime = ale.Time
+	}
+	a.EntryCount++
+	return nil
+}
+
+// close completes the segment, returning the compressed data.
+func (w *segmentWriter) close() ([]byte, error) {
+	if err := w.zw.Close(); err != nil {
+		return nil, err
+	}
+	data := w.buf.Bytes()
+	sum := sha256.Sum256(data)
+	w.archive.Size = int64(len(data))
+	w.archive.SHA256 = hex.EncodeToString(sum[:])
+	return data, nil
+}
+
+// Archive writes all audit log entries from before the given time to the
+// store, in segments of at most SegmentSize entries, and records each
+// segment in the archive manifest. The archives created are returned. If
+// the entries are not purged after they have been archived they will be
+// archived again by the next call, so entries may appear in more than
+// one segment but an entry is never purged without being archived.
+func (a *AuditLogArchiver) Archive(ctx context.Context, before time.Time) ([]dbmodel.AuditLogArchive, error) {
+	const op = errors.Op("jimm.AuditLogArchiver.Archive")
+
+	segmentSize := a.SegmentSize
+	if segmentSize <= 0 {
+		segmentSize = DefaultAuditArchiveSegmentSize
+	}
+	prefix := "audit-log/" + time.Now().UTC().Format("20060102T150405.000000000Z")
+
+	var archives []dbmodel.AuditLogArchive
+	var w *segmentWriter
+	flush := func() error {
+		data, err := w.close()
+		if err != nil {
+			return err
+		}
+		w.archive.Key = fmt.Sprintf("%s/%020d-%020d.ndjson.gz", prefix, w.archive.FirstEntryID, w.archive.LastEntryID)
+		if err := a.Store.Put(ctx, w.archive.Key, data); err != nil {
+			return err
+		}
+		if err := a.Database.AddAuditLogArchive(ctx, &w.archive); err != nil {
+			return err
+		}
+		zapctx.Info(ctx, "archived audit log segment", zap.String("key", w.archive.Key), zap.Int64("entries", w.archive.EntryCount))
+		archives = append(archives, w.archive)
+		w = nil
+		return nil
+	}
+
+	filter := db.AuditLogFilter{
+		End:    latestTimeBefore(before),
+		SortID: true,
+	}
+	err := a.Database.ForEachAuditLogEntry(ctx, filter, func(ale *dbmodel.AuditLogEntry) error {
+		if w == nil {
+			w = newSegmentWriter()
+		}
+		if err := w.add(ale); err != nil {
+			return err
+		}
+		if w.archive.EntryCount >= int64(segmentSize) {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && w != nil {
+		err = flush()
+	}
+	if err != nil {
+		return archives, errors.E(op, err)
+	}
+	return archives, nil
+}
+
+// latestTimeBefore returns the latest time, at the precision stored in
+// the database, that is before t.
+func latestTimeBefore(t time.Time) time.Time {
+	t1 := t.Truncate(time.Microsecond)
+	if t1.Equal(t) {
+		t1 = t1.Add(-time.Microsecond)
+	}
+	return t1
+}
+
+// Restore reads the given archive segment from the store, checks its
+// checksum and the hash of every entry, and adds the entries to the
+// restored audit log so that they can be queried. The number of entries
+// added is returned, entries that have already been restored are not
+// added again.
+func (a *AuditLogArchiver) Restore(ctx context.Context, archive *dbmodel.AuditLogArchive) (int64, error) {
+	const op = errors.Op("jimm.AuditLogArchiver.Restore")
+
+	data, err := a.Store.Get(ctx, archive.Key)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != archive.SHA256 {
+		return 0, errors.E(op, fmt.Sprintf("archive %q checksum mismatch", archive.Key))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	var entries []dbmodel.AuditLogEntry
+	dec := json.NewDecoder(zr)
+	for {
+		var r archiveRecord
+		if err := dec.Decode(&r); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, errors.E(op, err)
+		}
+		ale := dbmodel.AuditLogEntry{
+			ID:             r.ID,
+			Time:           r.Time,
+			Model:          r.Model,
+			ConversationId: r.ConversationId,
+			MessageId:      r.MessageId,
+			FacadeName:     r.FacadeName,
+			FacadeMethod:   r.FacadeMethod,
+			FacadeVersion:  r.FacadeVersion,
+			ObjectId:       r.ObjectId,
+			IdentityTag:    r.IdentityTag,
+			IsResponse:     r.IsResponse,
+			PreviousHash:   r.PreviousHash,
+			Hash:           r.Hash,
+		}
+		if r.Params != "" {
+			ale.Params = dbmodel.JSON(r.Params)
+		}
+		if r.Errors != "" {
+			ale.Errors = dbmodel.JSON(r.Errors)
+		}
+		if ale.Hash != "" && ale.ComputeHash() != ale.Hash {
+			return 0, errors.E(op, fmt.Sprintf("archive %q entry %d hash mismatch", archive.Key, ale.ID))
+		}
+		entries = append(entries, ale)
+	}
+	if int64(len(entries)) != archive.EntryCount {
+		return 0, errors.E(op, fmt.Sprintf("archive %q holds %d entries, expected %d", archive.Key, len(entries), archive.EntryCount))
+	}
+
+	n, err := a.Database.AddRestoredAuditLogEntries(ctx, entries)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	archive.RestoredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := a.Database.UpdateAuditLogArchive(ctx, archive); err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
+
+// ListAuditLogArchives returns all of the audit log archive segments.
+// Only JIMM administrators can perform this operation.
+func (j *JIMM) ListAuditLogArchives(ctx context.Context, user *openfga.User) ([]dbmodel.AuditLogArchive, error) {
+	const op = errors.Op("jimm.ListAuditLogArchives")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var archives []dbmodel.AuditLogArchive
+	err := j.Database.ForEachAuditLogArchive(ctx, func(a *dbmodel.AuditLogArchive) error {
+		archives = append(archives, *a)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return archives, nil
+}
+
+// RestoreAuditLogArchive restores the audit log archive segment with the
+// given ID so that its entries can be found by FindAuditEvents with the
+// Restored filter. The number of entries restored is returned. Only JIMM
+// administrators can perform this operation.
+func (j *JIMM) RestoreAuditLogArchive(ctx context.Context, user *openfga.User, id uint) (int64, error) {
+	const op = errors.Op("jimm.RestoreAuditLogArchive")
+
+	if !user.JimmAdmin {
+		return 0, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if j.AuditArchiver == nil {
+		return 0, errors.E(op, errors.CodeNotSupported, "audit log archiving is not configured")
+	}
+
+	archive := dbmodel.AuditLogArchive{ID: id}
+	if err := j.Database.GetAuditLogArchive(ctx, &archive); err != nil {
+		return 0, errors.E(op, err)
+	}
+	n, err := j.AuditArchiver.Restore(ctx, &archive)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// memArchiveStore is an in-memory jimm.AuditArchiveStore.
+type memArchiveStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *memArchiveStore) Put(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blobs == nil {
+		s.blobs = make(map[string][]byte)
+	}
+	s.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memArchiveStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, errors.E(errors.CodeNotFound, "archive not found")
+	}
+	return data, nil
+}
+
+func TestAuditLogArchive(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	store := new(memArchiveStore)
+	j := &jimm.JIMM{
+		Database: db.Database{
+			DB:                jimmtest.PostgresDB(c, nil),
+			AuditLogHashChain: true,
+		},
+	}
+	j.AuditArchiver = &jimm.AuditLogArchiver{
+		Database:    j.Database,
+		Store:       store,
+		SegmentSize: 2,
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	var entries []dbmodel.AuditLogEntry
+	for _, days := range []int{-5, -4, -3, -2, -1} {
+		ale := dbmodel.AuditLogEntry{
+			Time:         now.AddDate(0, 0, days),
+			FacadeName:   "JIMM",
+			FacadeMethod: "ListControllers",
+			Params:       dbmodel.JSON(`{"b": 1,  "a": "x"}`),
+		}
+		err := j.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, qt.IsNil)
+		entries = append(entries, ale)
+	}
+
+	alice := openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil)
+	alice.JimmAdmin = true
+
+	// Purging archives the purged entries first.
+	n, err := j.PurgeLogs(ctx, alice, now.AddDate(0, 0, -2))
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(3))
+	c.Check(store.blobs, qt.HasLen, 2)
+
+	archives, err := j.ListAuditLogArchives(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Assert(archives, qt.HasLen, 2)
+	c.Check(archives[0].FirstEntryID, qt.Equals, entries[0].ID)
+	c.Check(archives[0].LastEntryID, qt.Equals, entries[1].ID)
+	c.Check(archives[0].EntryCount, qt.Equals, int64(2))
+	c.Check(archives[0].StartTime.Equal(entries[0].Time), qt.IsTrue)
+	c.Check(archives[0].EndTime.Equal(entries[1].Time), qt.IsTrue)
+	c.Check(archives[1].FirstEntryID, qt.Equals, entries[2].ID)
+	c.Check(archives[1].LastEntryID, qt.Equals, entries[2].ID)
+	c.Check(archives[1].EntryCount, qt.Equals, int64(1))
+	for _, a := range archives {
+		c.Check(a.SHA256, qt.HasLen, 64)
+		c.Check(a.Size, qt.Equals, int64(len(store.blobs[a.Key])))
+		c.Check(a.RestoredAt.Valid, qt.IsFalse)
+	}
+
+	// Restoring an archive makes its entries available to query.
+	n, err = j.RestoreAuditLogArchive(ctx, alice, archives[0].ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+
+	var restored []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Restored: true}, func(ale *dbmodel.AuditLogEntry) error {
+		restored = append(restored, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(restored, qt.HasLen, 2)
+	for i, ale := range restored {
+		c.Check(ale.ID, qt.Equals, entries[i].ID)
+		c.Check(ale.Hash, qt.Equals, entries[i].Hash)
+		c.Check(ale.ComputeHash(), qt.Equals, ale.Hash)
+	}
+
+	// Restoring an archive again does not duplicate its entries.
+	n, err = j.RestoreAuditLogArchive(ctx, alice, archives[0].ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(0))
+
+	archives, err = j.ListAuditLogArchives(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(archives[0].RestoredAt.Valid, qt.IsTrue)
+	c.Check(archives[1].RestoredAt.Valid, qt.IsFalse)
+
+	// A corrupted archive is not restored.
+	store.blobs[archives[1].Key][0] ^= 0xff
+	_, err = j.RestoreAuditLogArchive(ctx, alice, archives[1].ID)
+	c.Check(err, qt.ErrorMatches, `archive ".*" checksum mismatch`)
+
+	_, err = j.RestoreAuditLogArchive(ctx, alice, archives[1].ID+1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestAuditLogArchiveUnauthorized(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	bob := openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, nil)
+	_, err = j.ListAuditLogArchives(ctx, bob)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.RestoreAuditLogArchive(ctx, bob, 1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice := openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil)
+	alice.JimmAdmin = true
+	_, err = j.RestoreAuditLogArchive(ctx, alice, 1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// AuthorizationAuditFacade is the facade name of the audit log entries
+// that record authorization decisions.
+const AuthorizationAuditFacade = "Authorization"
+
+// maxPendingAuthorizationPaths is the maximum number of allowed
+// authorization decisions whose path may be resolved in the background at
+// once. While this many are being resolved further decisions are
+// recorded without a path.
+const maxPendingAuthorizationPaths = 16
+
+// An AuthorizationDecision records why a user was allowed or denied
+// access to a resource. It is stored as the parameters of an audit log
+// entry with the facade name AuthorizationAuditFacade.
+type AuthorizationDecision struct {
+	// Source is the name of the operation that made the decision, it is
+	// also used as the facade method of the audit log entry.
+	Source string `json:"source"`
+
+	// Object is the OpenFGA object, usually the user, the decision was
+	// made for.
+	Object string `json:"object"`
+
+	// Relation is the OpenFGA relation that was required.
+	Relation string `json:"relation"`
+
+	// Target is the OpenFGA object access was required to.
+	Target string `json:"target"`
+
+	// Allowed is the outcome of the decision.
+	Allowed bool `json:"allowed"`
+
+	// Checks holds the relation checks JIMM made against OpenFGA to
+	// reach the decision, in the order they were made. OpenFGA resolves
+	// group membership and inherited relations within each check, so
+	// these do not show how access was granted.
+	Checks []openfga.RelationCheck `json:"checks,omitempty"`
+
+	// Path holds, for allowed decisions, the chain of relations from
+	// the object to the target through which access was granted. If
+	// access is granted in more than one way only the first, in the
+	// order of an access report, is recorded.
+	Path []AuthorizationStep `json:"path,omitempty"`
+}
+
+// An AuthorizationStep is a single step in the chain of relations
+// through which access was granted.
+type AuthorizationStep struct {
+	// Relation is the relation the previous object in the chain has to
+	// the target.
+	Relation string `json:"relation"`
+
+	// Target is the OpenFGA object the relation is to.
+	Target string `json:"target"`
+}
+
+// authorizationPaths tracks the authorization decisions whose path is
+// being resolved in the background.
+type authorizationPaths struct {
+	pending atomic.Int32
+	wg      sync.WaitGroup
+}
+
+// auditAuthorizationDecision records the given decision about the given
+// user's access to the given resource in the audit log. The decision is
+// associated with the request held in the context by
+// utils.ContextWithConversation, if there is one. Nothing is recorded
+// unless AuditAuthorizationDecisions is set.
+//
+// The path of an allowed decision is resolved by expanding the relations
+// to the resource in OpenFGA. As this may take many reads the path is
+// resolved, and the decision recorded, in the background. At most
+// maxPendingAuthorizationPaths paths are resolved at once, decisions made
+// while that many are pending are recorded without a path.
+func (j *JIMM) auditAuthorizationDecision(ctx context.Context, user *openfga.User, resource names.Tag, d AuthorizationDecision) {
+	if !j.AuditAuthorizationDecisions {
+		return
+	}
+	object := ofganames.ConvertTag(user.ResourceTag())
+	target := ofganames.ConvertGenericTag(resource)
+	d.Object = object.String()
+	d.Target = target.String()
+	ale := dbmodel.AuditLogEntry{
+		Time:         time.Now().UTC().Round(time.Millisecond),
+		FacadeName:   AuthorizationAuditFacade,
+		FacadeMethod: d.Source,
+		ObjectId:     resource.String(),
+		IdentityTag:  user.ResourceTag().String(),
+	}
+	if !d.Allowed || d.Relation == "" {
+		j.auditOperation(ctx, ale, d)
+		return
+	}
+	if j.authorizationPaths.pending.Add(1) > maxPendingAuthorizationPaths {
+		j.authorizationPaths.pending.Add(-1)
+		zapctx.Warn(ctx, "too many authorization paths being resolved, recording decision without a path")
+		j.auditOperation(ctx, ale, d)
+		return
+	}
+	tuple := openfga.Tuple{
+		Object:   object,
+		Relation: openfga.Relation(d.Relation),
+		Target:   target,
+	}
+	// The decision is still recorded if the request finishes first.
+	ctx = context.WithoutCancel(ctx)
+	j.authorizationPaths.wg.Add(1)
+	go func() {
+		defer j.authorizationPaths.wg.Done()
+		defer j.authorizationPaths.pending.Add(-1)
+		grants, err := j.relationGrants(ctx, tuple)
+		if err != nil {
+			zapctx.Error(ctx, "failed to resolve authorization path", zap.Error(err))
+		}
+		if len(grants) > 0 {
+			for _, step := range grants[0].Steps() {
+				d.Path = append(d.Path, AuthorizationStep{
+					Relation: string(step.Relation),
+					Target:   step.Target.String(),
+				})
+			}
+		}
+		j.auditOperation(ctx, ale, d)
+	}()
+}
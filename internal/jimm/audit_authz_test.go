@@ -0,0 +1,183 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/utils"
+)
+
+// authorizationDecisions returns the authorization decisions recorded in
+// the audit log.
+func authorizationDecisions(c *qt.C, j *jimm.JIMM) ([]dbmodel.AuditLogEntry, []jimm.AuthorizationDecision) {
+	jimm.WaitForAuthorizationAudits(j)
+	var entries []dbmodel.AuditLogEntry
+	var decisions []jimm.AuthorizationDecision
+	err := j.Database.ForEachAuditLogEntry(context.Background(), db.AuditLogFilter{FacadeName: jimm.AuthorizationAuditFacade}, func(ale *dbmodel.AuditLogEntry) error {
+		var d jimm.AuthorizationDecision
+		if err := json.Unmarshal(ale.Params, &d); err != nil {
+			return err
+		}
+		entries = append(entries, *ale)
+		decisions = append(decisions, d)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	return entries, decisions
+}
+
+func TestAuditAuthorizationDecisionsDoModel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				ModelStatus_: func(context.Context, *jujuparams.ModelStatus) error {
+					return nil
+				},
+			},
+		},
+		AuditAuthorizationDecisions: true,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	// bob is a model writer so may not get the model status.
+	_, err = j.ModelStatus(utils.ContextWithConversation(ctx, "conversation-1", 1), bob, mt)
+	c.Assert(err, qt.ErrorMatches, "unauthorized")
+	_, err = j.ModelStatus(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+
+	entries, decisions := authorizationDecisions(c, j)
+	c.Assert(entries, qt.HasLen, 2)
+	c.Check(entries[0].FacadeMethod, qt.Equals, "doModel")
+	c.Check(entries[0].IdentityTag, qt.Equals, bob.ResourceTag().String())
+	c.Check(entries[0].ObjectId, qt.Equals, mt.String())
+	c.Check(entries[0].ConversationId, qt.Equals, "conversation-1")
+	c.Check(entries[0].MessageId, qt.Equals, uint64(1))
+	c.Check(decisions[0], qt.DeepEquals, jimm.AuthorizationDecision{
+		Source:   "doModel",
+		Object:   "user:bob@canonical.com",
+		Relation: "administrator",
+		Target:   "model:" + mt.Id(),
+		Allowed:  false,
+		Checks: []openfga.RelationCheck{{
+			Object:   "user:bob@canonical.com",
+			Relation: "administrator",
+			Target:   "model:" + mt.Id(),
+			Allowed:  false,
+		}, {
+			Object:   "user:bob@canonical.com",
+			Relation: "writer",
+			Target:   "model:" + mt.Id(),
+			Allowed:  true,
+		}},
+	})
+	c.Check(entries[1].IdentityTag, qt.Equals, alice.ResourceTag().String())
+	c.Check(entries[1].ConversationId, qt.Not(qt.Equals), "")
+	c.Check(decisions[1].Allowed, qt.IsTrue)
+	c.Check(decisions[1].Checks, qt.HasLen, 1)
+	c.Check(decisions[1].Path, qt.DeepEquals, []jimm.AuthorizationStep{{
+		Relation: "administrator",
+		Target:   "model:" + mt.Id(),
+	}})
+
+	// Decisions are not recorded unless enabled.
+	j.AuditAuthorizationDecisions = false
+	_, err = j.ModelStatus(ctx, bob, mt)
+	c.Assert(err, qt.ErrorMatches, "unauthorized")
+	entries, _ = authorizationDecisions(c, j)
+	c.Check(entries, qt.HasLen, 2)
+}
+
+func TestAuditAuthorizationDecisionsCheckPermission(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		AuditAuthorizationDecisions: true,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+
+	ctx = utils.ContextWithConversation(ctx, "conversation-2", 7)
+	_, err = j.CheckPermission(ctx, charlie, map[string]string{}, map[string]interface{}{
+		mt.String(): "read",
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = j.CheckPermission(ctx, charlie, map[string]string{}, map[string]interface{}{
+		mt.String(): "write",
+	})
+	c.Assert(err, qt.ErrorMatches, `Missing permission for .*`)
+
+	entries, decisions := authorizationDecisions(c, j)
+	c.Assert(entries, qt.HasLen, 2)
+	for _, ale := range entries {
+		c.Check(ale.FacadeMethod, qt.Equals, "CheckPermission")
+		c.Check(ale.ObjectId, qt.Equals, mt.String())
+		c.Check(ale.ConversationId, qt.Equals, "conversation-2")
+		c.Check(ale.MessageId, qt.Equals, uint64(7))
+	}
+	c.Check(decisions[0].Relation, qt.Equals, "reader")
+	c.Check(decisions[0].Allowed, qt.IsTrue)
+	c.Check(decisions[0].Checks, qt.HasLen, 1)
+	c.Check(decisions[0].Path, qt.DeepEquals, []jimm.AuthorizationStep{{
+		Relation: "reader",
+		Target:   "model:" + mt.Id(),
+	}})
+	c.Check(decisions[1].Relation, qt.Equals, "writer")
+	c.Check(decisions[1].Allowed, qt.IsFalse)
+	c.Check(decisions[1].Path, qt.IsNil)
+	c.Check(decisions[1].Checks, qt.DeepEquals, []openfga.RelationCheck{{
+		Object:   "user:charlie@canonical.com",
+		Relation: "writer",
+		Target:   "model:" + mt.Id(),
+		Allowed:  false,
+	}})
+}
@@ -13,8 +13,11 @@ import (
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
 	"github.com/canonical/jimm/v3/internal/servermon"
 	"github.com/canonical/jimm/v3/internal/utils"
 )
@@ -25,22 +28,109 @@ type AuditLoggerBackend interface {
 	AddAuditLogEntry(*dbmodel.AuditLogEntry)
 }
 
+// An AuditSink receives a copy of every audit log entry stored by JIMM so
+// that the entries can be exported to an external system.
+type AuditSink interface {
+	// Write writes the given audit log entry to the sink. Write is
+	// called for every audit log entry so implementations should avoid
+	// blocking for extended periods.
+	Write(ctx context.Context, ale *dbmodel.AuditLogEntry) error
+
+	// Close flushes any buffered entries and releases any resources
+	// held by the sink.
+	Close() error
+}
+
+// auditOperation records an operation performed by JIMM in the audit
+// log. The time, if not already set, and conversation of the given entry
+// are set, using the conversation of the given context if there is one,
+// and params is recorded as the operation's parameters.
+func (j *JIMM) auditOperation(ctx context.Context, ale dbmodel.AuditLogEntry, params any) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		zapctx.Error(ctx, "failed to marshal audit parameters", zap.String("method", ale.FacadeMethod), zap.Error(err))
+		return
+	}
+	if ale.Time.IsZero() {
+		ale.Time = time.Now().UTC().Round(time.Millisecond)
+	}
+	ale.ConversationId, ale.MessageId = utils.ConversationFromContext(ctx)
+	if ale.ConversationId == "" {
+		ale.ConversationId = utils.NewConversationID()
+	}
+	ale.Params = b
+	j.AddAuditLogEntry(&ale)
+}
+
+// auditJIMMOperation records an operation performed through the JIMM
+// facade by the given user in the audit log. The given params are
+// recorded as the operation's parameters and objectID, if set, as the
+// object the operation acted on. If user is nil the operation was
+// performed by JIMM itself.
+func (j *JIMM) auditJIMMOperation(ctx context.Context, user *openfga.User, method, objectID string, params any) {
+	j.auditOperation(ctx, jimmAuditLogEntry(user, method, objectID), params)
+}
+
+// auditFailedJIMMOperation records an operation performed through the
+// JIMM facade by the given user that failed with the given error. The
+// error is recorded in the same form as errors returned by controllers.
+func (j *JIMM) auditFailedJIMMOperation(ctx context.Context, user *openfga.User, method, objectID string, args any, opErr error) {
+	ale := jimmAuditLogEntry(user, method, objectID)
+	b, err := json.Marshal(params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{
+			Message: opErr.Error(),
+			Code:    string(errors.ErrorCode(opErr)),
+		},
+	}}})
+	if err != nil {
+		zapctx.Error(ctx, "failed to marshal audit errors", zap.String("method", method), zap.Error(err))
+		return
+	}
+	ale.Errors = b
+	j.auditOperation(ctx, ale, args)
+}
+
+// jimmAuditLogEntry returns the audit log entry for an operation
+// performed through the JIMM facade.
+func jimmAuditLogEntry(user *openfga.User, method, objectID string) dbmodel.AuditLogEntry {
+	ale := dbmodel.AuditLogEntry{
+		FacadeName:    "JIMM",
+		FacadeMethod:  method,
+		FacadeVersion: 4,
+		ObjectId:      objectID,
+	}
+	if user != nil {
+		ale.IdentityTag = user.ResourceTag().String()
+	}
+	return ale
+}
+
 type DbAuditLogger struct {
 	backend        AuditLoggerBackend
+	redactor       *auditredact.Redactor
 	conversationId string
 	getUser        func() names.UserTag
 }
 
 // NewDbAuditLogger returns a new audit logger that logs to the database.
-func NewDbAuditLogger(backend AuditLoggerBackend, getUserFunc func() names.UserTag) DbAuditLogger {
+// Request parameters are redacted using the given redactor, if this is
+// nil the default redaction rules are used.
+func NewDbAuditLogger(backend AuditLoggerBackend, redactor *auditredact.Redactor, getUserFunc func() names.UserTag) DbAuditLogger {
 	logger := DbAuditLogger{
 		backend:        backend,
+		redactor:       redactor,
 		conversationId: utils.NewConversationID(),
 		getUser:        getUserFunc,
 	}
 	return logger
 }
 
+// ConversationID returns the ID of the conversation the logger records
+// entries for.
+func (r DbAuditLogger) ConversationID() string {
+	return r.conversationId
+}
+
 func (r DbAuditLogger) newAuditLogEntry(header *rpc.Header) dbmodel.AuditLogEntry {
 	ale := dbmodel.AuditLogEntry{
 		Time:           time.Now().UTC().Round(time.Millisecond),
@@ -66,6 +156,7 @@ func (r DbAuditLogger) LogRequest(header *rpc.Header, body interface{}) error {
 		}
 		ale.Params = jsonBody
 	}
+	r.redactor.Redact(&ale)
 	r.backend.AddAuditLogEntry(&ale)
 	return nil
 }
@@ -131,6 +222,7 @@ func (o recorder) HandleReply(r rpc.Request, header *rpc.Header, body interface{
 type auditLogCleanupService struct {
 	auditLogRetentionPeriodInDays int
 	db                            db.Database
+	archiver                      *AuditLogArchiver
 }
 
 // pollTimeOfDay holds the time hour, minutes and seconds to poll at.
@@ -145,11 +237,13 @@ var pollDuration = pollTimeOfDay{
 }
 
 // NewAuditLogCleanupService returns a service capable of cleaning up audit logs
-// on a defined retention period. The retention period is in DAYS.
-func NewAuditLogCleanupService(db db.Database, auditLogRetentionPeriodInDays int) *auditLogCleanupService {
+// on a defined retention period. The retention period is in DAYS. If
+// archiver is not nil logs are archived before they are cleaned up.
+func NewAuditLogCleanupService(db db.Database, archiver *AuditLogArchiver, auditLogRetentionPeriodInDays int) *auditLogCleanupService {
 	return &auditLogCleanupService{
 		auditLogRetentionPeriodInDays: auditLogRetentionPeriodInDays,
 		db:                            db,
+		archiver:                      archiver,
 	}
 }
 
@@ -168,6 +262,12 @@ func (a *auditLogCleanupService) poll(ctx context.Context) {
 		select {
 		case <-time.After(calculateNextPollDuration(time.Now().UTC())):
 			retentionDate := time.Now().AddDate(0, 0, -(a.auditLogRetentionPeriodInDays))
+			if a.archiver != nil {
+				if _, err := a.archiver.Archive(ctx, retentionDate); err != nil {
+					zapctx.Error(ctx, "failed to archive audit logs", zap.Error(err))
+					continue
+				}
+			}
 			deleted, err := a.db.DeleteAuditLogsBefore(ctx, retentionDate)
 			if err != nil {
 				zapctx.Error(ctx, "failed to cleanup audit logs", zap.Error(err))
@@ -8,7 +8,10 @@ import (
 	"time"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/names/v5"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
@@ -58,7 +61,7 @@ func TestAuditLogCleanupServicePurgesLogs(t *testing.T) {
 	jimm.PollDuration.Hours = now.Hour()
 	jimm.PollDuration.Minutes = now.Minute()
 	jimm.PollDuration.Seconds = now.Second() + 2
-	svc := jimm.NewAuditLogCleanupService(db, 1)
+	svc := jimm.NewAuditLogCleanupService(db, nil, 1)
 	svc.Start(ctx)
 
 	// Check 2 were purged
@@ -81,3 +84,45 @@ func TestCalculateNextPollDuration(t *testing.T) {
 	d = jimm.CalculateNextPollDuration(startingTime)
 	c.Assert(d, qt.Equals, time.Hour*2)
 }
+
+type testAuditLoggerBackend struct {
+	entries []dbmodel.AuditLogEntry
+}
+
+func (b *testAuditLoggerBackend) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
+	b.entries = append(b.entries, *ale)
+}
+
+func TestDbAuditLoggerRedactsRequests(t *testing.T) {
+	c := qt.New(t)
+
+	redactor, err := auditredact.New([]auditredact.Rule{{
+		Facade: "ModelManager",
+		Method: "CreateModel",
+		Paths:  []string{"$.config.secret"},
+	}})
+	c.Assert(err, qt.IsNil)
+
+	getUser := func() names.UserTag { return names.NewUserTag("alice@canonical.com") }
+
+	backend := new(testAuditLoggerBackend)
+	logger := jimm.NewDbAuditLogger(backend, redactor, getUser)
+	err = logger.LogRequest(&rpc.Header{
+		RequestId: 1,
+		Request:   rpc.Request{Type: "ModelManager", Version: 9, Action: "CreateModel"},
+	}, map[string]any{"name": "model-1", "config": map[string]any{"secret": "s3cr3t"}})
+	c.Assert(err, qt.IsNil)
+	c.Assert(backend.entries, qt.HasLen, 1)
+	c.Check(string(backend.entries[0].Params), qt.Equals, `{"config":{"secret":"redacted"},"name":"model-1"}`)
+
+	// Without a redactor the default rules are used.
+	backend = new(testAuditLoggerBackend)
+	logger = jimm.NewDbAuditLogger(backend, nil, getUser)
+	err = logger.LogRequest(&rpc.Header{
+		RequestId: 1,
+		Request:   rpc.Request{Type: "Cloud", Version: 7, Action: "AddCredentials"},
+	}, map[string]any{"credentials": []any{}})
+	c.Assert(err, qt.IsNil)
+	c.Assert(backend.entries, qt.HasLen, 1)
+	c.Check(string(backend.entries[0].Params), qt.Equals, `{"params":"redacted"}`)
+}
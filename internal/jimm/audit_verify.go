@@ -0,0 +1,104 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// AuditLogVerification holds the result of verifying the audit log hash
+// chain.
+type AuditLogVerification struct {
+	// Verified is the number of entries whose hash was verified.
+	Verified int64
+
+	// Unhashed is the number of entries that were added before hash
+	// chaining was introduced and so could not be verified.
+	Unhashed int64
+
+	// BrokenEntryID is the ID of the first entry at which the hash
+	// chain is broken. If the chain is intact this is 0.
+	BrokenEntryID uint
+
+	// Reason describes why the chain is broken at BrokenEntryID.
+	Reason string
+}
+
+// Valid returns whether the verified hash chain is intact.
+func (v AuditLogVerification) Valid() bool {
+	return v.BrokenEntryID == 0
+}
+
+// VerifyAuditLog walks the audit log in the order the entries were added
+// checking that the hash of every entry matches its contents and that
+// every entry is chained to the entry before it. Links removed by purging
+// old entries are accepted if a checkpoint was recorded for them. The
+// first broken link found is reported. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) VerifyAuditLog(ctx context.Context, user *openfga.User) (*AuditLogVerification, error) {
+	const op = errors.Op("jimm.VerifyAuditLog")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	checkpoints := make(map[uint][]dbmodel.AuditLogCheckpoint)
+	err := j.Database.ForEachAuditLogCheckpoint(ctx, func(cp *dbmodel.AuditLogCheckpoint) error {
+		checkpoints[cp.EntryID] = append(checkpoints[cp.EntryID], *cp)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	var v AuditLogVerification
+	var prev string
+	var chained bool
+	errBroken := errors.E("broken")
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{SortID: true}, func(ale *dbmodel.AuditLogEntry) error {
+		broken := func(reason string) error {
+			v.BrokenEntryID = ale.ID
+			v.Reason = reason
+			return errBroken
+		}
+		if ale.Hash == "" {
+			if chained {
+				return broken("entry has no hash")
+			}
+			v.Unhashed++
+			return nil
+		}
+		chained = true
+		if ale.PreviousHash != prev && !purged(checkpoints[ale.ID], ale.PreviousHash, prev) {
+			return broken(fmt.Sprintf("previous hash %q does not match the preceding entry, an entry has been removed or modified", ale.PreviousHash))
+		}
+		if ale.ComputeHash() != ale.Hash {
+			return broken("hash does not match the entry contents, the entry has been modified")
+		}
+		prev = ale.Hash
+		v.Verified++
+		return nil
+	})
+	if err != nil && err != errBroken {
+		return nil, errors.E(op, err)
+	}
+	return &v, nil
+}
+
+// purged returns whether any of the given checkpoints records that the
+// entry with the given previous hash was purged, leaving the entry with
+// the preceding hash before it.
+func purged(checkpoints []dbmodel.AuditLogCheckpoint, previousHash, precedingHash string) bool {
+	for _, cp := range checkpoints {
+		if cp.PreviousHash == previousHash && cp.PrecedingHash == precedingHash {
+			return true
+		}
+	}
+	return false
+}
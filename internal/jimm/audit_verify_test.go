@@ -0,0 +1,105 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestVerifyAuditLog(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	j := &jimm.JIMM{
+		Database: db.Database{
+			DB:                jimmtest.PostgresDB(c, nil),
+			AuditLogHashChain: true,
+		},
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	bob := openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, nil)
+	_, err = j.VerifyAuditLog(ctx, bob)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice := openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil)
+	alice.JimmAdmin = true
+
+	// An entry added before hash chaining was introduced.
+	err = j.Database.DB.Create(&dbmodel.AuditLogEntry{Time: now.AddDate(0, 0, -6)}).Error
+	c.Assert(err, qt.IsNil)
+
+	var entries []dbmodel.AuditLogEntry
+	for _, days := range []int{-5, -4, -1, -3, -1} {
+		ale := dbmodel.AuditLogEntry{
+			Time:         now.AddDate(0, 0, days),
+			FacadeName:   "JIMM",
+			FacadeMethod: "ListControllers",
+			Params:       dbmodel.JSON(`{"a": "b"}`),
+		}
+		err := j.Database.AddAuditLogEntry(ctx, &ale)
+		c.Assert(err, qt.IsNil)
+		entries = append(entries, ale)
+	}
+
+	v, err := j.VerifyAuditLog(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.DeepEquals, &jimm.AuditLogVerification{
+		Verified: 5,
+		Unhashed: 1,
+	})
+	c.Check(v.Valid(), qt.IsTrue)
+
+	// Purging entries does not break the chain.
+	_, err = j.Database.DeleteAuditLogsBefore(ctx, now.AddDate(0, 0, -2))
+	c.Assert(err, qt.IsNil)
+	v, err = j.VerifyAuditLog(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.DeepEquals, &jimm.AuditLogVerification{
+		Verified: 2,
+	})
+
+	// Adding more entries does not break the chain.
+	err = j.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{Time: now})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{Time: now})
+	c.Assert(err, qt.IsNil)
+	v, err = j.VerifyAuditLog(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(v.Valid(), qt.IsTrue)
+	c.Check(v.Verified, qt.Equals, int64(4))
+
+	// Modifying an entry is detected.
+	err = j.Database.DB.Model(&entries[2]).Update("facade_method", "AddController").Error
+	c.Assert(err, qt.IsNil)
+	v, err = j.VerifyAuditLog(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(v.Valid(), qt.IsFalse)
+	c.Check(v.BrokenEntryID, qt.Equals, entries[2].ID)
+	c.Check(v.Reason, qt.Matches, `hash does not match the entry contents.*`)
+
+	err = j.Database.DB.Model(&entries[2]).Update("facade_method", "ListControllers").Error
+	c.Assert(err, qt.IsNil)
+
+	// Deleting an entry is detected.
+	err = j.Database.DB.Delete(&entries[2]).Error
+	c.Assert(err, qt.IsNil)
+	v, err = j.VerifyAuditLog(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(v.Valid(), qt.IsFalse)
+	c.Check(v.BrokenEntryID, qt.Equals, entries[4].ID)
+	c.Check(v.Reason, qt.Matches, `previous hash .* does not match the preceding entry.*`)
+}
@@ -385,7 +385,10 @@ func (j *JIMM) doCloudAdmin(ctx context.Context, user *openfga.User, ct names.Cl
 // given user. If the cloud is not found then an error with the code
 // CodeNotFound is returned. If the authenticated user does not have admin
 // access to the cloud then an error with the code CodeUnauthorized is
-// returned.
+// returned. The access is granted permanently, any pending expiry of the
+// relation is removed. The Juju grant facade has no way to carry an
+// expiry, temporary access is granted with the AddRelation method of the
+// JIMM facade (jimmctl auth relation add --expires-in).
 func (j *JIMM) GrantCloudAccess(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error {
 	const op = errors.Op("jimm.GrantCloudAccess")
 
@@ -408,6 +411,10 @@ func (j *JIMM) GrantCloudAccess(ctx context.Context, user *openfga.User, ct name
 		}
 		targetOfgaUser := openfga.NewUser(targetUser, j.OpenFGAClient)
 
+		clearExpiry := func() error {
+			return j.clearUserRelationExpiries(ctx, targetOfgaUser, ofganames.ConvertTag(ct), targetRelation)
+		}
+
 		currentRelation := targetOfgaUser.GetCloudAccess(ctx, ct)
 		switch targetRelation {
 		case ofganames.CanAddModelRelation:
@@ -415,21 +422,21 @@ func (j *JIMM) GrantCloudAccess(ctx context.Context, user *openfga.User, ct name
 			case ofganames.NoRelation:
 				break
 			default:
-				return nil
+				return clearExpiry()
 			}
 		case ofganames.AdministratorRelation:
 			switch currentRelation {
 			case ofganames.NoRelation, ofganames.CanAddModelRelation:
 				break
 			default:
-				return nil
+				return clearExpiry()
 			}
 		}
 
 		if err := targetOfgaUser.SetCloudAccess(ctx, ct, targetRelation); err != nil {
 			return errors.E(err, op, "failed to set cloud access")
 		}
-		return nil
+		return clearExpiry()
 	})
 
 	if err != nil {
@@ -504,7 +511,7 @@ func (j *JIMM) RevokeCloudAccess(ctx context.Context, user *openfga.User, ct nam
 		if err := targetOfgaUser.UnsetCloudAccess(ctx, ct, relationsToRevoke...); err != nil {
 			return errors.E(err, op, "failed to unset cloud access")
 		}
-		return nil
+		return j.clearUserRelationExpiries(ctx, targetOfgaUser, ofganames.ConvertTag(ct), relationsToRevoke...)
 	})
 
 	if err != nil {
@@ -7,6 +7,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/controller/controller"
@@ -411,10 +413,143 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 	}
 	defer api.Close()
 
+	if err := j.importModel(ctx, controller, api, modelTag, newOwner); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// An ImportModelResult holds the result of importing a single model
+// with ImportModels.
+type ImportModelResult struct {
+	// ModelTag is the tag of the model.
+	ModelTag names.ModelTag
+
+	// Name is the name of the model.
+	Name string
+
+	// Owner is the name of the owner of the imported model.
+	Owner string
+
+	// Err is the error encountered importing the model, if any.
+	Err error
+}
+
+// ImportModels imports all models running on the controller that are not
+// yet known to JIMM. If ownerPattern or namePattern are not empty only
+// models whose owner or name, respectively, match the glob pattern are
+// imported. The owners of imported models are mapped to new owners using
+// ownerMap, any owner not in the map is kept. Each model is imported
+// independently, the result of importing each model is returned. Models
+// with an invalid owner cannot match an owner pattern, if there is no
+// owner pattern they are reported as failing to import.
+func (j *JIMM) ImportModels(ctx context.Context, user *openfga.User, controllerName, ownerPattern, namePattern string, ownerMap map[string]string) ([]ImportModelResult, error) {
+	const op = errors.Op("jimm.ImportModels")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, err
+	}
+	for _, pattern := range []string{ownerPattern, namePattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid pattern %q", pattern))
+		}
+	}
+
+	controller, err := j.getControllerByName(ctx, controllerName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	api, err := j.dialController(ctx, controller)
+	if err != nil {
+		return nil, errors.E(op, "failed to dial the controller", err)
+	}
+	defer api.Close()
+
+	summaries, err := api.AllModelSummaries(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	sort.Slice(summaries, func(i, k int) bool {
+		if summaries[i].OwnerTag != summaries[k].OwnerTag {
+			return summaries[i].OwnerTag < summaries[k].OwnerTag
+		}
+		return summaries[i].Name < summaries[k].Name
+	})
+
+	var results []ImportModelResult
+	for _, ms := range summaries {
+		if ms.IsController {
+			continue
+		}
+		ownerTag, err := names.ParseUserTag(ms.OwnerTag)
+		if err != nil {
+			if ownerPattern != "" || !globMatch(namePattern, ms.Name) {
+				continue
+			}
+			zapctx.Error(ctx, "failed to import model", zap.String("model", ms.UUID), zap.Error(err))
+			results = append(results, ImportModelResult{
+				ModelTag: names.NewModelTag(ms.UUID),
+				Name:     ms.Name,
+				Err:      errors.E(op, errors.CodeBadRequest, err),
+			})
+			continue
+		}
+		owner := ownerTag.Id()
+		if !globMatch(ownerPattern, owner) || !globMatch(namePattern, ms.Name) {
+			continue
+		}
+		if newOwner, ok := ownerMap[owner]; ok {
+			owner = newOwner
+		}
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: ms.UUID,
+				Valid:  true,
+			},
+		}
+		if err := j.Database.GetModel(ctx, &model); err == nil {
+			// The model is already known to JIMM.
+			continue
+		} else if errors.ErrorCode(err) != errors.CodeNotFound {
+			return nil, errors.E(op, err)
+		}
+
+		result := ImportModelResult{
+			ModelTag: names.NewModelTag(ms.UUID),
+			Name:     ms.Name,
+			Owner:    owner,
+		}
+		result.Err = j.importModel(ctx, controller, api, result.ModelTag, owner)
+		if result.Err != nil {
+			zapctx.Error(ctx, "failed to import model", zap.String("model", ms.UUID), zap.Error(result.Err))
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// globMatch returns whether the given name matches the glob pattern. An
+// empty pattern matches every name.
+func globMatch(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// importModel imports the model with the specified UUID from the given
+// controller using the given API connection to the controller. The model
+// is added to the database in a single transaction.
+func (j *JIMM) importModel(ctx context.Context, controller *dbmodel.Controller, api API, modelTag names.ModelTag, newOwner string) error {
+	const op = errors.Op("jimm.importModel")
+
 	modelInfo := jujuparams.ModelInfo{
 		UUID: modelTag.Id(),
 	}
-	err = api.ModelInfo(ctx, &modelInfo)
+	err := api.ModelInfo(ctx, &modelInfo)
 	if err != nil {
 		return errors.E(op, err)
 	}
@@ -452,19 +587,6 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 	}
 	model.SwitchOwner(&ownerUser)
 
-	// Note that only the new owner is given access. All previous users that had access according to Juju
-	// are discarded as access must now be governed by JIMM and OpenFGA.
-	ofgaUser := openfga.NewUser(&ownerUser, j.OpenFGAClient)
-	if err := ofgaUser.SetModelAccess(ctx, modelTag, ofganames.AdministratorRelation); err != nil {
-		zapctx.Error(
-			ctx,
-			"failed to set model admin",
-			zap.String("owner", ownerUser.Name),
-			zap.String("model", modelTag.String()),
-			zap.Error(err),
-		)
-	}
-
 	// TODO(CSS-5458): Remove the below section on cloud credentials once we no longer persist the relation between
 	// cloud credentials and models
 
@@ -508,29 +630,57 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 	model.CloudRegionID = cr.ID
 	model.CloudRegion = cr
 
-	err = j.Database.AddModel(ctx, &model)
+	// Fetch the current state of the model before starting the
+	// transaction so that it is not held open while waiting for the
+	// controller.
+	deltas, err := j.getModelDeltas(ctx, controller, modelTag)
 	if err != nil {
-		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
-			return errors.E(op, err, "model already exists")
+		return errors.E(op, err)
+	}
+
+	err = j.Database.Transaction(func(tx *db.Database) error {
+		err := tx.AddModel(ctx, &model)
+		if err != nil {
+			if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+				return errors.E(err, "model already exists")
+			}
+			return err
 		}
+		return handleModelDeltas(ctx, tx, controller, model, deltas)
+	})
+	if err != nil {
 		return errors.E(op, err)
 	}
 
-	return j.handleModelDeltas(ctx, controller, modelTag, model)
+	// Note that only the new owner is given access. All previous users that had access according to Juju
+	// are discarded as access must now be governed by JIMM and OpenFGA.
+	ofgaUser := openfga.NewUser(&ownerUser, j.OpenFGAClient)
+	if err := ofgaUser.SetModelAccess(ctx, modelTag, ofganames.AdministratorRelation); err != nil {
+		zapctx.Error(
+			ctx,
+			"failed to set model admin",
+			zap.String("owner", ownerUser.Name),
+			zap.String("model", modelTag.String()),
+			zap.Error(err),
+		)
+	}
+	return nil
 }
 
-func (j *JIMM) handleModelDeltas(ctx context.Context, controller *dbmodel.Controller, modelTag names.ModelTag, model dbmodel.Model) error {
+// getModelDeltas returns the deltas describing the current state of the
+// model with the given tag.
+func (j *JIMM) getModelDeltas(ctx context.Context, controller *dbmodel.Controller, modelTag names.ModelTag) ([]jujuparams.Delta, error) {
 	const op = errors.Op("jimm.getModelDeltas")
 
 	modelAPI, err := j.dialModel(ctx, controller, modelTag)
 	if err != nil {
-		return errors.E(op, err)
+		return nil, errors.E(op, err)
 	}
 	defer modelAPI.Close()
 
 	watcherID, err := modelAPI.WatchAll(ctx)
 	if err != nil {
-		return errors.E(op, err)
+		return nil, errors.E(op, err)
 	}
 	defer func() {
 		if err := modelAPI.ModelWatcherStop(ctx, watcherID); err != nil {
@@ -540,22 +690,30 @@ func (j *JIMM) handleModelDeltas(ctx context.Context, controller *dbmodel.Contro
 
 	deltas, err := modelAPI.ModelWatcherNext(ctx, watcherID)
 	if err != nil {
-		return errors.E(op, err)
+		return nil, errors.E(op, err)
 	}
+	return deltas, nil
+}
+
+// handleModelDeltas applies the given deltas, as returned by
+// getModelDeltas, to the given newly imported model.
+func handleModelDeltas(ctx context.Context, tx *db.Database, controller *dbmodel.Controller, model dbmodel.Model, deltas []jujuparams.Delta) error {
+	const op = errors.Op("jimm.handleModelDeltas")
 
 	modelIDf := func(uuid string) *modelState {
 		if uuid == model.UUID.String {
 			return &modelState{
-				id:       model.ID,
-				machines: make(map[string]int64),
-				units:    make(map[string]bool),
+				id:           model.ID,
+				controllerID: controller.ID,
+				machines:     make(map[string]int64),
+				units:        make(map[string]bool),
 			}
 		}
 		return nil
 	}
 
 	w := &Watcher{
-		Database: j.Database,
+		Database: *tx,
 	}
 	for _, d := range deltas {
 		if err := w.handleDelta(ctx, modelIDf, d); err != nil {
@@ -661,9 +819,10 @@ func (j *JIMM) UpdateMigratedModel(ctx context.Context, user *openfga.User, mode
 		return errors.E(op, err)
 	}
 
-	model.Controller = targetController
-	model.ControllerID = targetController.ID
-	err = j.Database.UpdateModel(ctx, &model)
+	err = j.Database.Transaction(func(tx *db.Database) error {
+		_, err := completeMigration(ctx, tx, &model, &targetController)
+		return err
+	})
 	if err != nil {
 		zapctx.Error(ctx, "failed to update model", zap.String("model", model.UUID.String), zaputil.Error(err))
 		return errors.E(op, err)
@@ -739,5 +898,9 @@ func (j *JIMM) InitiateMigration(ctx context.Context, user *openfga.User, spec j
 	if err != nil {
 		return result, errors.E(op, err)
 	}
+	if err := j.recordMigration(ctx, user, &model, spec.TargetInfo, targetControllerTag.Id(), result.MigrationId); err != nil {
+		// The migration is underway, so don't report an error.
+		zapctx.Error(ctx, "failed to record migration", zap.String("model", mt.Id()), zap.Error(err))
+	}
 	return result, nil
 }
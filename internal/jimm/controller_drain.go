@@ -0,0 +1,331 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// DefaultDrainBatchSize is the number of models migrated concurrently by
+// a controller drain if no batch size is specified.
+const DefaultDrainBatchSize = 5
+
+// MaxDrainCheckFailures is the number of consecutive times the status of
+// a drain model's migration can fail to be checked before the model is
+// considered to have failed to migrate.
+const MaxDrainCheckFailures = 30
+
+// DrainController starts draining all models off the named controller.
+// The controller is deprecated so that no new models are placed on it and
+// its models are migrated, batchSize at a time, to other controllers
+// serving the same cloud regions. If the controller has previously been
+// drained the drain is resumed: models that failed to migrate are retried
+// and any models added since are included. The migrations are performed
+// by RunControllerDrains.
+func (j *JIMM) DrainController(ctx context.Context, user *openfga.User, controllerName string, batchSize int) (*dbmodel.ControllerDrain, error) {
+	const op = errors.Op("jimm.DrainController")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultDrainBatchSize
+	}
+
+	var cd dbmodel.ControllerDrain
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		ctl := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := tx.GetController(ctx, &ctl); err != nil {
+			return err
+		}
+		if !ctl.Deprecated {
+			ctl.Deprecated = true
+			if err := tx.UpdateController(ctx, &ctl); err != nil {
+				return err
+			}
+		}
+		models, err := tx.GetModelsByController(ctx, ctl)
+		if err != nil {
+			return err
+		}
+
+		cd = dbmodel.ControllerDrain{
+			ControllerID: ctl.ID,
+		}
+		err = tx.GetControllerDrain(ctx, &cd)
+		if err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+			return err
+		}
+		exists := err == nil
+
+		cd.Initiator = user.Name
+		cd.Status = dbmodel.DrainStatusRunning
+		cd.BatchSize = batchSize
+		known := make(map[string]bool, len(cd.Models))
+		for i := range cd.Models {
+			m := &cd.Models[i]
+			known[m.ModelUUID] = true
+			if m.Status == dbmodel.DrainModelStatusFailed {
+				m.Status = dbmodel.DrainModelStatusPending
+				m.TargetController = ""
+				m.MigrationID = ""
+				m.Error = ""
+				m.CheckFailures = 0
+			}
+		}
+		for _, m := range models {
+			if known[m.UUID.String] {
+				continue
+			}
+			cd.Models = append(cd.Models, dbmodel.ControllerDrainModel{
+				ModelUUID: m.UUID.String,
+				ModelName: m.Name,
+				Status:    dbmodel.DrainModelStatusPending,
+			})
+		}
+		if exists {
+			err = tx.UpdateControllerDrain(ctx, &cd)
+		} else {
+			err = tx.AddControllerDrain(ctx, &cd)
+		}
+		if err != nil {
+			return err
+		}
+		return tx.GetControllerDrain(ctx, &cd)
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &cd, nil
+}
+
+// ControllerDrainStatus returns the progress of the drain of the named
+// controller.
+func (j *JIMM) ControllerDrainStatus(ctx context.Context, user *openfga.User, controllerName string) (*dbmodel.ControllerDrain, error) {
+	const op = errors.Op("jimm.ControllerDrainStatus")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	ctl := dbmodel.Controller{
+		Name: controllerName,
+	}
+	if err := j.Database.GetController(ctx, &ctl); err != nil {
+		return nil, errors.E(op, err)
+	}
+	cd := dbmodel.ControllerDrain{
+		ControllerID: ctl.ID,
+	}
+	if err := j.Database.GetControllerDrain(ctx, &cd); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &cd, nil
+}
+
+// RunControllerDrains progresses all running controller drains at the
+// given interval. As the state of each drain is stored in the database
+// any drains that were running when JIMM stopped are resumed.
+// RunControllerDrains blocks until either the given context is closed, or
+// there is an error querying the database. Only a single JIMM instance
+// should run the controller drains.
+func (j *JIMM) RunControllerDrains(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.RunControllerDrains")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := j.Database.ForEachControllerDrain(ctx, dbmodel.DrainStatusRunning, func(cd *dbmodel.ControllerDrain) error {
+			ctx := zapctx.WithFields(ctx, zap.String("controller", cd.Controller.Name))
+			if err := j.progressControllerDrain(ctx, cd); err != nil {
+				zapctx.Error(ctx, "failed to progress controller drain", zap.Error(err))
+			}
+			return nil
+		})
+		if err != nil {
+			// Ignore temporary database errors.
+			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error polling for controller drains", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// progressControllerDrain checks the migrations in progress for the given
+// drain and initiates migrations for pending models until the batch is
+// full. The updated state of the drain is stored in the database.
+func (j *JIMM) progressControllerDrain(ctx context.Context, cd *dbmodel.ControllerDrain) error {
+	const op = errors.Op("jimm.progressControllerDrain")
+
+	user, err := j.getUser(ctx, cd.Initiator)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	migrating := 0
+	for i := range cd.Models {
+		m := &cd.Models[i]
+		if m.Status != dbmodel.DrainModelStatusMigrating {
+			continue
+		}
+		j.checkDrainMigration(ctx, user, &cd.Controller, m)
+		if m.Status == dbmodel.DrainModelStatusMigrating {
+			migrating++
+		}
+	}
+	for i := range cd.Models {
+		if migrating >= cd.BatchSize {
+			break
+		}
+		m := &cd.Models[i]
+		if m.Status != dbmodel.DrainModelStatusPending {
+			continue
+		}
+		j.startDrainMigration(ctx, user, cd, m)
+		if m.Status == dbmodel.DrainModelStatusMigrating {
+			migrating++
+		}
+	}
+
+	if cd.Finished() {
+		cd.Status = dbmodel.DrainStatusCompleted
+		for _, m := range cd.Models {
+			if m.Status == dbmodel.DrainModelStatusFailed {
+				cd.Status = dbmodel.DrainStatusFailed
+				break
+			}
+		}
+		zapctx.Info(ctx, "controller drain finished", zap.String("status", cd.Status))
+	}
+	if err := j.Database.UpdateControllerDrain(ctx, cd); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// startDrainMigration initiates the migration of the given drain model to
+// the best available controller serving the model's cloud region.
+func (j *JIMM) startDrainMigration(ctx context.Context, user *openfga.User, cd *dbmodel.ControllerDrain, m *dbmodel.ControllerDrainModel) {
+	model := dbmodel.Model{
+		UUID: sql.NullString{
+			String: m.ModelUUID,
+			Valid:  true,
+		},
+	}
+	if err := j.Database.GetModel(ctx, &model); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			// The model has been destroyed so there is nothing
+			// left to migrate.
+			m.Status = dbmodel.DrainModelStatusMigrated
+			return
+		}
+		drainModelFailed(ctx, m, err)
+		return
+	}
+	if model.ControllerID != cd.ControllerID {
+		// The model has already been moved off the controller.
+		m.Status = dbmodel.DrainModelStatusMigrated
+		return
+	}
+
+	cloud := dbmodel.Cloud{
+		Name: model.CloudRegion.CloudName,
+	}
+	if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+		drainModelFailed(ctx, m, err)
+		return
+	}
+	var candidates []dbmodel.CloudRegionControllerPriority
+	for _, crp := range cloud.Region(model.CloudRegion.Name).Controllers {
+		if crp.ControllerID != cd.ControllerID {
+			candidates = append(candidates, crp)
+		}
+	}
+	target, err := j.selectController(ctx, candidates)
+	if err != nil {
+		drainModelFailed(ctx, m, err)
+		return
+	}
+
+	result, err := j.InitiateInternalMigration(ctx, user, model.ResourceTag(), target.Controller.Name)
+	if err == nil && result.Error != nil {
+		err = result.Error
+	}
+	if err != nil {
+		drainModelFailed(ctx, m, err)
+		return
+	}
+	zapctx.Info(ctx, "initiated model migration", zap.String("model", m.ModelUUID), zap.String("target", target.Controller.Name))
+	m.TargetController = target.Controller.Name
+	m.MigrationID = result.MigrationId
+	m.Status = dbmodel.DrainModelStatusMigrating
+}
+
+// checkDrainMigration checks whether the migration of the given drain
+// model has finished. If the model has arrived on the target controller
+// the model is updated to reference its new controller. If the status of
+// the migration cannot be determined MaxDrainCheckFailures times in a row
+// the model is marked as failed.
+func (j *JIMM) checkDrainMigration(ctx context.Context, user *openfga.User, source *dbmodel.Controller, m *dbmodel.ControllerDrainModel) {
+	mt := names.NewModelTag(m.ModelUUID)
+	err := j.UpdateMigratedModel(ctx, user, mt, m.TargetController)
+	if err == nil {
+		m.Status = dbmodel.DrainModelStatusMigrated
+		return
+	}
+	if errors.ErrorCode(err) == errors.CodeModelNotFound {
+		// The model has been destroyed.
+		m.Status = dbmodel.DrainModelStatusMigrated
+		return
+	}
+
+	// The model is not yet known to the target controller, check
+	// whether the migration has been aborted.
+	mi := jujuparams.ModelInfo{
+		UUID: m.ModelUUID,
+	}
+	api, err := j.dial(ctx, source, names.ModelTag{})
+	if err == nil {
+		defer api.Close()
+		err = api.ModelInfo(ctx, &mi)
+	}
+	if err != nil {
+		m.CheckFailures++
+		if m.CheckFailures >= MaxDrainCheckFailures {
+			drainModelFailed(ctx, m, errors.E("cannot check migration status: "+err.Error()))
+			return
+		}
+		zapctx.Warn(ctx, "cannot check migration status", zap.String("model", m.ModelUUID), zap.Error(err))
+		return
+	}
+	m.CheckFailures = 0
+	if mi.Migration != nil && mi.Migration.End != nil {
+		drainModelFailed(ctx, m, errors.E("migration failed: "+mi.Migration.Status))
+	}
+}
+
+func drainModelFailed(ctx context.Context, m *dbmodel.ControllerDrainModel, err error) {
+	zapctx.Error(ctx, "failed to migrate model", zap.String("model", m.ModelUUID), zap.Error(err))
+	m.Status = dbmodel.DrainModelStatusFailed
+	m.Error = err.Error()
+}
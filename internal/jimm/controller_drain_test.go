@@ -0,0 +1,201 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestDrainController(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	err := store.PutControllerCredentials(ctx, "controller-2", "admin", "test-secret")
+	c.Assert(err, qt.IsNil)
+	j.CredentialStore = store
+
+	migrated := false
+	j.Dialer = &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				if !migrated {
+					return errors.E(errors.CodeNotFound, "model not found")
+				}
+				mi.Name = "model-1"
+				return nil
+			},
+		},
+	}
+	var migrations []jujuparams.MigrationSpec
+	c.Patch(jimm.InitiateMigration, func(ctx context.Context, j *jimm.JIMM, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error) {
+		migrations = append(migrations, spec)
+		return jujuparams.InitiateMigrationResult{MigrationId: "migration-1"}, nil
+	})
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+
+	_, err = j.DrainController(ctx, u, "controller-1", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	u.JimmAdmin = true
+	_, err = j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	cd, err := j.DrainController(ctx, u, "controller-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusRunning)
+	c.Check(cd.BatchSize, qt.Equals, jimm.DefaultDrainBatchSize)
+	c.Assert(cd.Models, qt.HasLen, 1)
+	c.Check(cd.Models[0].ModelName, qt.Equals, "model-1")
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusPending)
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.Deprecated, qt.IsTrue)
+
+	// The first pass initiates the migration.
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].ModelTag, qt.Equals, "model-00000002-0000-0000-0000-000000000001")
+	cd, err = j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusMigrating)
+	c.Check(cd.Models[0].TargetController, qt.Equals, "controller-2")
+	c.Check(cd.Models[0].MigrationID, qt.Equals, "migration-1")
+
+	// The migration is still in progress.
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	cd, err = j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusRunning)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusMigrating)
+
+	// The model arrives on the target controller.
+	migrated = true
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	cd, err = j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusCompleted)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusMigrated)
+
+	model := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000002-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err = j.Database.GetModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	c.Check(model.Controller.Name, qt.Equals, "controller-2")
+	c.Check(migrations, qt.HasLen, 1)
+
+	// Draining the controller again resumes the drain.
+	cd, err = j.DrainController(ctx, u, "controller-1", 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusRunning)
+	c.Check(cd.BatchSize, qt.Equals, 2)
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	cd, err = j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusCompleted)
+}
+
+func TestDrainControllerNoTarget(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	// With both controllers deprecated there is nowhere to migrate to.
+	err := j.SetControllerDeprecated(ctx, u, "controller-2", true)
+	c.Assert(err, qt.IsNil)
+	_, err = j.DrainController(ctx, u, "controller-1", 1)
+	c.Assert(err, qt.IsNil)
+
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	cd, err := j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusFailed)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusFailed)
+	c.Check(cd.Models[0].Error, qt.Equals, "no controller with available capacity for the cloud region")
+}
+
+func TestDrainControllerCheckFailures(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	err := store.PutControllerCredentials(ctx, "controller-2", "admin", "test-secret")
+	c.Assert(err, qt.IsNil)
+	j.CredentialStore = store
+
+	j.Dialer = &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ModelInfo_: func(context.Context, *jujuparams.ModelInfo) error {
+				return errors.E("connection refused")
+			},
+		},
+	}
+	c.Patch(jimm.InitiateMigration, func(ctx context.Context, j *jimm.JIMM, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error) {
+		return jujuparams.InitiateMigrationResult{MigrationId: "migration-1"}, nil
+	})
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	_, err = j.DrainController(ctx, u, "controller-1", 1)
+	c.Assert(err, qt.IsNil)
+
+	// The first pass initiates the migration, subsequent passes fail to
+	// determine its status.
+	for i := 0; i < jimm.MaxDrainCheckFailures; i++ {
+		err = j.ProgressControllerDrains(ctx)
+		c.Assert(err, qt.IsNil)
+		cd, err := j.ControllerDrainStatus(ctx, u, "controller-1")
+		c.Assert(err, qt.IsNil)
+		c.Assert(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusMigrating)
+		c.Assert(cd.Models[0].CheckFailures, qt.Equals, i)
+	}
+
+	err = j.ProgressControllerDrains(ctx)
+	c.Assert(err, qt.IsNil)
+	cd, err := j.ControllerDrainStatus(ctx, u, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Status, qt.Equals, dbmodel.DrainStatusFailed)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusFailed)
+	c.Check(cd.Models[0].Error, qt.Equals, "cannot check migration status: connection refused")
+
+	// Resuming the drain retries the model.
+	cd, err = j.DrainController(ctx, u, "controller-1", 1)
+	c.Assert(err, qt.IsNil)
+	c.Check(cd.Models[0].Status, qt.Equals, dbmodel.DrainModelStatusPending)
+	c.Check(cd.Models[0].CheckFailures, qt.Equals, 0)
+}
@@ -953,6 +953,131 @@ func TestImportModel(t *testing.T) {
 	}
 }
 
+func TestImportModels(t *testing.T) {
+	c := qt.New(t)
+	trueValue := true
+
+	summaries := []jujuparams.ModelSummary{{
+		Name:         "controller",
+		UUID:         "00000002-0000-0000-0000-000000000000",
+		OwnerTag:     names.NewUserTag("admin").String(),
+		IsController: true,
+	}, {
+		Name:     "model-1",
+		UUID:     "00000002-0000-0000-0000-000000000002",
+		OwnerTag: names.NewUserTag("alice@canonical.com").String(),
+	}, {
+		Name:     "prod-1",
+		UUID:     "00000002-0000-0000-0000-000000000003",
+		OwnerTag: names.NewUserTag("admin").String(),
+	}, {
+		Name:     "prod-2",
+		UUID:     "00000002-0000-0000-0000-000000000004",
+		OwnerTag: names.NewUserTag("bob").String(),
+	}, {
+		Name:     "dev-1",
+		UUID:     "00000002-0000-0000-0000-000000000005",
+		OwnerTag: names.NewUserTag("admin").String(),
+	}, {
+		Name:     "prod-0",
+		UUID:     "00000002-0000-0000-0000-000000000006",
+		OwnerTag: "not-a-user-tag",
+	}}
+
+	api := &jimmtest.API{
+		AllModelSummaries_: func(context.Context) ([]jujuparams.ModelSummary, error) {
+			return summaries, nil
+		},
+		ModelInfo_: func(_ context.Context, info *jujuparams.ModelInfo) error {
+			for _, ms := range summaries {
+				if ms.UUID != info.UUID {
+					continue
+				}
+				info.Name = ms.Name
+				info.Type = "iaas"
+				info.ControllerUUID = "00000001-0000-0000-0000-000000000001"
+				info.CloudTag = names.NewCloudTag("test-cloud").String()
+				info.CloudRegion = "test-region"
+				info.CloudCredentialTag = names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential").String()
+				info.CloudCredentialValidity = &trueValue
+				info.OwnerTag = ms.OwnerTag
+				info.Life = life.Alive
+				return nil
+			}
+			return errors.E(errors.CodeNotFound, "model not found")
+		},
+		ModelWatcherNext_: func(context.Context, string) ([]jujuparams.Delta, error) {
+			return nil, nil
+		},
+		ModelWatcherStop_: func(context.Context, string) error {
+			return nil
+		},
+		WatchAll_: func(context.Context) (string, error) {
+			return "1", nil
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		OpenFGAClient: client,
+	}
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, testImportModelEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dbUser := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&dbUser, client)
+
+	_, err = j.ImportModels(ctx, user, "test-controller", "", "", nil)
+	c.Assert(err, qt.ErrorMatches, `unauthorized`)
+
+	user.JimmAdmin = true
+	_, err = j.ImportModels(ctx, user, "test-controller", "[", "", nil)
+	c.Assert(err, qt.ErrorMatches, `invalid pattern "\["`)
+
+	results, err := j.ImportModels(ctx, user, "test-controller", "", "prod-*", map[string]string{"admin": "alice@canonical.com"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.HasLen, 3)
+	// A model with an invalid owner does not stop the other models
+	// being imported.
+	c.Check(results[0].ModelTag, qt.Equals, names.NewModelTag("00000002-0000-0000-0000-000000000006"))
+	c.Check(results[0].Name, qt.Equals, "prod-0")
+	c.Check(results[0].Err, qt.ErrorMatches, `"not-a-user-tag" is not a valid tag`)
+	c.Check(errors.ErrorCode(results[0].Err), qt.Equals, errors.CodeBadRequest)
+	c.Check(results[1].ModelTag, qt.Equals, names.NewModelTag("00000002-0000-0000-0000-000000000003"))
+	c.Check(results[1].Owner, qt.Equals, "alice@canonical.com")
+	c.Check(results[1].Err, qt.IsNil)
+	c.Check(results[2].ModelTag, qt.Equals, names.NewModelTag("00000002-0000-0000-0000-000000000004"))
+	c.Check(results[2].Owner, qt.Equals, "bob")
+	c.Check(results[2].Err, qt.ErrorMatches, `cannot import model from local user, try --owner to switch the model owner`)
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{String: "00000002-0000-0000-0000-000000000003", Valid: true},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(user.GetModelAccess(ctx, m.ResourceTag()), qt.Equals, ofganames.AdministratorRelation)
+
+	m = dbmodel.Model{
+		UUID: sql.NullString{String: "00000002-0000-0000-0000-000000000004", Valid: true},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
 const testControllerConfigEnv = `
 users:
 - username: alice@canonical.com
@@ -21,8 +21,15 @@ var (
 	FillMigrationTarget            = fillMigrationTarget
 	InitiateMigration              = &initiateMigration
 	ResolveTag                     = resolveTag
+	UpdateMigration                = updateMigration
 )
 
+// WaitForAuthorizationAudits waits for the authorization decisions whose
+// path is being resolved in the background to be recorded.
+func WaitForAuthorizationAudits(j *JIMM) {
+	j.authorizationPaths.wg.Wait()
+}
+
 func WatchController(w *Watcher, ctx context.Context, ctl *dbmodel.Controller) error {
 	return w.watchController(ctx, ctl)
 }
@@ -60,3 +67,9 @@ func (j *JIMM) UpdateUserLastLogin(ctx context.Context, identifier string) error
 func (j *JIMM) EveryoneUser() *openfga.User {
 	return j.everyoneUser()
 }
+
+func (j *JIMM) ProgressControllerDrains(ctx context.Context) error {
+	return j.Database.ForEachControllerDrain(ctx, dbmodel.DrainStatusRunning, func(cd *dbmodel.ControllerDrain) error {
+		return j.progressControllerDrain(ctx, cd)
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// IdentityDetails holds the details of an identity along with the
+// resources it owns and the groups it is a member of.
+type IdentityDetails struct {
+	// Identity is the identity's database record.
+	Identity dbmodel.Identity
+
+	// CloudCredentials holds the cloud credentials owned by the identity.
+	CloudCredentials []dbmodel.CloudCredential
+
+	// Models holds the models owned by the identity.
+	Models []dbmodel.Model
+
+	// Groups holds the groups the identity is a member of, including
+	// through membership of nested groups, ordered by name.
+	Groups []dbmodel.GroupEntry
+}
+
+// ListIdentities returns the identities known to JIMM that match the
+// given filter. Only JIMM administrators may list identities.
+func (j *JIMM) ListIdentities(ctx context.Context, user *openfga.User, filter db.IdentityFilter) ([]dbmodel.Identity, error) {
+	const op = errors.Op("jimm.ListIdentities")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	identities, err := j.Database.ListIdentities(ctx, filter)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return identities, nil
+}
+
+// GetIdentity returns the details of the named identity, including the
+// cloud credentials and models it owns and the groups it is a member of.
+// JIMM administrators may retrieve the details of any identity, other
+// users may only retrieve their own.
+func (j *JIMM) GetIdentity(ctx context.Context, user *openfga.User, identityName string) (*IdentityDetails, error) {
+	const op = errors.Op("jimm.GetIdentity")
+
+	if !user.JimmAdmin && user.Name != identityName {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	details := IdentityDetails{
+		Identity: dbmodel.Identity{Name: identityName},
+	}
+	if err := j.Database.FetchIdentity(ctx, &details.Identity); err != nil {
+		return nil, errors.E(op, err)
+	}
+	var err error
+	details.CloudCredentials, err = j.ownedCloudCredentials(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	details.Models, err = j.Database.GetModelsByOwner(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	groups, err := j.OpenFGAClient.ListObjects(ctx, ofganames.ConvertTag(details.Identity.ResourceTag()), ofganames.MemberRelation, openfga.GroupType, nil)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	for _, g := range groups {
+		ge := dbmodel.GroupEntry{UUID: g.ID}
+		if err := j.Database.GetGroup(ctx, &ge); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				// The group has been removed but the
+				// relation has not been cleaned up yet.
+				continue
+			}
+			return nil, errors.E(op, err)
+		}
+		details.Groups = append(details.Groups, ge)
+	}
+	sort.Slice(details.Groups, func(i, j int) bool {
+		return details.Groups[i].Name < details.Groups[j].Name
+	})
+	return &details, nil
+}
+
+// ownedCloudCredentials returns the cloud credentials owned by the named
+// identity.
+func (j *JIMM) ownedCloudCredentials(ctx context.Context, identityName string) ([]dbmodel.CloudCredential, error) {
+	var creds []dbmodel.CloudCredential
+	err := j.Database.ForEachCloudCredential(ctx, identityName, "", func(cred *dbmodel.CloudCredential) error {
+		creds = append(creds, *cred)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
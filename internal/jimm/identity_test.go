@@ -0,0 +1,129 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func TestListIdentities(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	_, err = j.ListIdentities(ctx, bob, db.IdentityFilter{})
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	identities, err := j.ListIdentities(ctx, diane, db.IdentityFilter{Limit: 2})
+	c.Assert(err, qt.IsNil)
+	c.Assert(identities, qt.HasLen, 2)
+	c.Check(identities[0].Name, qt.Equals, "alice@canonical.com")
+	c.Check(identities[1].Name, qt.Equals, "bob@canonical.com")
+
+	identities, err = j.ListIdentities(ctx, diane, db.IdentityFilter{Match: "DIANE"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(identities, qt.HasLen, 1)
+	c.Check(identities[0].Name, qt.Equals, "diane@canonical.com")
+}
+
+func TestGetIdentity(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// alice is a member of devs through the nested ops group.
+	devs, err := j.Database.AddGroup(ctx, "devs")
+	c.Assert(err, qt.IsNil)
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(ops.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(devs.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.GetIdentity(ctx, bob, "alice@canonical.com")
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	_, err = j.GetIdentity(ctx, diane, "eve@canonical.com")
+	c.Check(err, qt.ErrorMatches, `record not found`)
+
+	details, err := j.GetIdentity(ctx, alice, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(details.Identity.Name, qt.Equals, "alice@canonical.com")
+	c.Assert(details.CloudCredentials, qt.HasLen, 1)
+	c.Check(details.CloudCredentials[0].Path(), qt.Equals, "test-cloud/alice@canonical.com/cred-1")
+	c.Assert(details.Models, qt.HasLen, 1)
+	c.Check(details.Models[0].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(details.Models[0].Controller.Name, qt.Equals, "controller-1")
+	c.Check(details.Models[0].CloudRegion.Cloud.Name, qt.Equals, "test-cloud")
+	c.Assert(details.Groups, qt.HasLen, 2)
+	c.Check(details.Groups[0].Name, qt.Equals, "devs")
+	c.Check(details.Groups[1].Name, qt.Equals, "ops")
+
+	details, err = j.GetIdentity(ctx, diane, "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(details.Identity.Name, qt.Equals, "bob@canonical.com")
+	c.Check(details.CloudCredentials, qt.HasLen, 0)
+	c.Check(details.Models, qt.HasLen, 0)
+	c.Check(details.Groups, qt.HasLen, 0)
+}
@@ -8,7 +8,6 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -23,6 +22,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
@@ -85,6 +85,41 @@ type JIMM struct {
 	// OAuthAuthenticator is responsible for handling authentication
 	// via OAuth2.0 AND JWT access tokens to JIMM.
 	OAuthAuthenticator OAuthAuthenticator
+
+	// PlacementStrategy determines the controller on which new models
+	// are placed. If this is nil models are placed according to the
+	// controller's priority for the cloud region.
+	PlacementStrategy PlacementStrategy
+
+	// AuditSinks are the sinks that audit log entries are written to
+	// in addition to the database.
+	AuditSinks []AuditSink
+
+	// AuditRedactor redacts sensitive request parameters before they
+	// are written to the audit log. If this is nil the default rules
+	// are used.
+	AuditRedactor *auditredact.Redactor
+
+	// AuditArchiver archives audit log entries before they are purged.
+	// If this is nil entries are purged without being archived.
+	AuditArchiver *AuditLogArchiver
+
+	// AuditAuthorizationDecisions, if true, causes the decisions made
+	// when checking a user's access to a model, or the permissions
+	// requested by a controller, to be recorded in the audit log. To
+	// record how access was granted each allowed decision expands the
+	// relations to the resource, its parents and any groups involved,
+	// which may take many OpenFGA reads. This is done in the background
+	// so requests are not delayed, but it adds to the load on OpenFGA.
+	AuditAuthorizationDecisions bool
+
+	// sessions holds the sessions identities have open on this JIMM
+	// server.
+	sessions sessionRegistry
+
+	// authorizationPaths tracks the authorization decisions whose path
+	// is being resolved in the background.
+	authorizationPaths authorizationPaths
 }
 
 // ResourceTag returns JIMM's controller tag stating its UUID.
@@ -211,6 +246,10 @@ type API interface {
 	// AddCloud adds a new cloud.
 	AddCloud(context.Context, names.CloudTag, jujuparams.Cloud, bool) error
 
+	// AllModelSummaries returns the summaries of all models on the
+	// controller.
+	AllModelSummaries(context.Context) ([]jujuparams.ModelSummary, error)
+
 	// AllModelWatcherNext returns the next set of deltas from an
 	// all-model watcher.
 	AllModelWatcherNext(context.Context, string) ([]jujuparams.Delta, error)
@@ -399,30 +438,16 @@ func (j *JIMM) forEachController(ctx context.Context, controllers []dbmodel.Cont
 // addAuditLogEntry causes an entry to be added the the audit log.
 func (j *JIMM) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
 	ctx := context.Background()
-	redactSensitiveParams(ale)
+	// Entries are normally redacted when they are created, redacting
+	// them again ensures no entry is stored or exported unredacted.
+	j.AuditRedactor.Redact(ale)
 	if err := j.Database.AddAuditLogEntry(ctx, ale); err != nil {
 		zapctx.Error(ctx, "cannot store audit log entry", zap.Error(err), zap.Any("entry", *ale))
 	}
-}
-
-var sensitiveMethods = map[string]struct{}{
-	"login":                 {},
-	"logindevice":           {},
-	"getdevicesessiontoken": {},
-	"loginwithsessiontoken": {},
-	"addcredentials":        {},
-	"updatecredentials":     {}}
-var redactJSON = dbmodel.JSON(`{"params":"redacted"}`)
-
-func redactSensitiveParams(ale *dbmodel.AuditLogEntry) {
-	if ale.Params == nil {
-		return
-	}
-	method := strings.ToLower(ale.FacadeMethod)
-	if _, ok := sensitiveMethods[method]; ok {
-		newRedactMessage := make(dbmodel.JSON, len(redactJSON))
-		copy(newRedactMessage, redactJSON)
-		ale.Params = newRedactMessage
+	for _, sink := range j.AuditSinks {
+		if err := sink.Write(ctx, ale); err != nil {
+			zapctx.Error(ctx, "cannot write audit log entry to sink", zap.Error(err), zap.Any("entry", *ale))
+		}
 	}
 }
 
@@ -447,6 +472,34 @@ func (j *JIMM) FindAuditEvents(ctx context.Context, user *openfga.User, filter d
 	return entries, nil
 }
 
+// ForEachAuditEvent calls f for each audit event matching the given
+// filter, without holding the events in memory. If f returns an error
+// iteration stops immediately and the error is returned unmodified.
+func (j *JIMM) ForEachAuditEvent(ctx context.Context, user *openfga.User, filter db.AuditLogFilter, f func(*dbmodel.AuditLogEntry) error) error {
+	const op = errors.Op("jimm.ForEachAuditEvent")
+
+	access := user.GetAuditLogViewerAccess(ctx, j.ResourceTag())
+	if access != ofganames.AuditLogViewerRelation {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var ferr error
+	err := j.Database.ForEachAuditLogEntry(ctx, filter, func(entry *dbmodel.AuditLogEntry) error {
+		if err := f(entry); err != nil {
+			ferr = err
+			return err
+		}
+		return nil
+	})
+	if ferr != nil {
+		return ferr
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // ListControllers returns a list of controllers the user has access to.
 func (j *JIMM) ListControllers(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error) {
 	const op = errors.Op("jimm.ListControllers")
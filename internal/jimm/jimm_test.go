@@ -167,11 +167,68 @@ func TestFindAuditEvents(t *testing.T) {
 					c.Assert(err, qt.Equals, nil)
 					c.Assert(events, qt.DeepEquals, test.expectedEvents)
 				}
+
+				events = nil
+				err = j.ForEachAuditEvent(context.Background(), user, test.filter, func(ale *dbmodel.AuditLogEntry) error {
+					events = append(events, *ale)
+					return nil
+				})
+				if test.expectedError != "" {
+					c.Assert(err, qt.ErrorMatches, test.expectedError)
+				} else {
+					c.Assert(err, qt.Equals, nil)
+					c.Assert(events, qt.DeepEquals, test.expectedEvents)
+				}
 			}
 		})
 	}
 }
 
+type testAuditSink struct {
+	entries []dbmodel.AuditLogEntry
+}
+
+func (s *testAuditSink) Write(_ context.Context, ale *dbmodel.AuditLogEntry) error {
+	s.entries = append(s.entries, *ale)
+	return nil
+}
+
+func (s *testAuditSink) Close() error {
+	return nil
+}
+
+func TestAddAuditLogEntryWritesToSinks(t *testing.T) {
+	c := qt.New(t)
+
+	sink1 := new(testAuditSink)
+	sink2 := new(testAuditSink)
+	// The database is not configured, entries are still written to the
+	// sinks when they cannot be stored.
+	j := &jimm.JIMM{
+		AuditSinks: []jimm.AuditSink{sink1, sink2},
+	}
+
+	j.AddAuditLogEntry(&dbmodel.AuditLogEntry{
+		IdentityTag:  "user-alice@canonical.com",
+		FacadeMethod: "AddModel",
+		Params:       dbmodel.JSON(`{"name":"model-1"}`),
+	})
+	j.AddAuditLogEntry(&dbmodel.AuditLogEntry{
+		IdentityTag:  "user-alice@canonical.com",
+		FacadeMethod: "Login",
+		Params:       dbmodel.JSON(`{"password":"secret"}`),
+	})
+
+	for _, sink := range []*testAuditSink{sink1, sink2} {
+		c.Assert(sink.entries, qt.HasLen, 2)
+		c.Check(sink.entries[0].FacadeMethod, qt.Equals, "AddModel")
+		c.Check(string(sink.entries[0].Params), qt.Equals, `{"name":"model-1"}`)
+		// Sensitive parameters are redacted before being written.
+		c.Check(sink.entries[1].FacadeMethod, qt.Equals, "Login")
+		c.Check(string(sink.entries[1].Params), qt.Equals, `{"params":"redacted"}`)
+	}
+}
+
 const testListCoControllersEnv = `clouds:
 - name: test
   type: test
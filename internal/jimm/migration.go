@@ -0,0 +1,206 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// migratingStatusPrefix is the prefix of the model status message set by
+// juju while a model is being migrated.
+const migratingStatusPrefix = "migrating: "
+
+// migrationSuccessfulStatusPrefix is the prefix of the model status
+// message set by juju once a migration has succeeded and the model is
+// active on the target controller.
+const migrationSuccessfulStatusPrefix = migratingStatusPrefix + "successful"
+
+// ListMigrations returns the model migrations that match the given
+// filter, most recent first.
+func (j *JIMM) ListMigrations(ctx context.Context, user *openfga.User, filter db.MigrationFilter) ([]dbmodel.Migration, error) {
+	const op = errors.Op("jimm.ListMigrations")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	migrations, err := j.Database.ListMigrations(ctx, filter)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return migrations, nil
+}
+
+// MigrationStatus returns the migration with the given migration ID. If
+// the migration ID is empty the most recent migration of the given model
+// is returned.
+func (j *JIMM) MigrationStatus(ctx context.Context, user *openfga.User, migrationID string, modelTag names.ModelTag) (*dbmodel.Migration, error) {
+	const op = errors.Op("jimm.MigrationStatus")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	m := dbmodel.Migration{
+		MigrationID: migrationID,
+	}
+	if migrationID == "" {
+		m.ModelUUID = modelTag.Id()
+	}
+	if err := j.Database.GetMigration(ctx, &m); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &m, nil
+}
+
+// recordMigration stores the migration of the given model, which has just
+// been initiated by the given user, so that its progress can be followed.
+// If the target controller is known to JIMM the model is marked as
+// migrating to that controller.
+func (j *JIMM) recordMigration(ctx context.Context, user *openfga.User, model *dbmodel.Model, targetInfo jujuparams.MigrationTargetInfo, targetControllerUUID, migrationID string) error {
+	const op = errors.Op("jimm.recordMigration")
+
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		target := dbmodel.Controller{
+			UUID: targetControllerUUID,
+		}
+		err := tx.GetController(ctx, &target)
+		switch {
+		case err == nil:
+			model.MigrationControllerID = sql.NullInt32{
+				//nolint:gosec // Controller IDs are expected to fit into int32.
+				Int32: int32(target.ID),
+				Valid: true,
+			}
+			if err := tx.UpdateModel(ctx, model); err != nil {
+				return err
+			}
+		case errors.ErrorCode(err) == errors.CodeNotFound:
+			// The model is leaving JIMM.
+			target.Name = targetInfo.ControllerAlias
+			if target.Name == "" {
+				target.Name = targetControllerUUID
+			}
+		default:
+			return err
+		}
+
+		return tx.AddMigration(ctx, &dbmodel.Migration{
+			MigrationID:      migrationID,
+			ModelUUID:        model.UUID.String,
+			ModelName:        model.Name,
+			SourceController: model.Controller.Name,
+			TargetController: target.Name,
+			Requester:        user.Name,
+			Phase:            dbmodel.MigrationPhaseInitiated,
+			StartedAt:        time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// updateMigration updates the active migration, if any, of the given
+// model from the state reported by the model's controller. If removed is
+// true the model has been removed from the controller, otherwise info
+// holds the latest model update. If the migration was aborted the model
+// is updated to no longer be migrating, it is the caller's responsibility
+// to store the model.
+func updateMigration(ctx context.Context, tx *db.Database, model *dbmodel.Model, removed bool, info *jujuparams.ModelUpdate) error {
+	if !model.UUID.Valid {
+		// The model is not known to JIMM.
+		return nil
+	}
+	migrations, err := tx.ListMigrations(ctx, db.MigrationFilter{
+		ModelUUID: model.UUID.String,
+		Active:    true,
+	})
+	if err != nil || len(migrations) == 0 {
+		return err
+	}
+	m := migrations[0]
+	switch {
+	case removed:
+		m.Phase = dbmodel.MigrationPhaseCompleted
+	case strings.HasPrefix(info.Status.Message, migratingStatusPrefix):
+		m.Phase = dbmodel.MigrationPhaseRunning
+		m.Message = strings.TrimPrefix(info.Status.Message, migratingStatusPrefix)
+	case m.Phase == dbmodel.MigrationPhaseRunning:
+		// The model was migrating but is no longer, as it
+		// has not left the controller the migration must
+		// have been aborted.
+		m.Phase = dbmodel.MigrationPhaseAborted
+		model.MigrationControllerID = sql.NullInt32{}
+	default:
+		// The migration has not yet started.
+		return nil
+	}
+	if m.Phase != dbmodel.MigrationPhaseRunning {
+		m.EndedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		zapctx.Info(ctx, "model migration ended", zap.String("migration-id", m.MigrationID), zap.String("phase", m.Phase))
+	}
+	return tx.UpdateMigration(ctx, &m)
+}
+
+// completeMigration updates the given model, which has been migrated to
+// the given target controller, to reference the target controller. The
+// active migration of the model is marked as completed, if the migration
+// was not initiated through JIMM a completed migration is recorded. The
+// model must have its Controller association fetched. The recorded
+// migration is returned.
+func completeMigration(ctx context.Context, tx *db.Database, model *dbmodel.Model, target *dbmodel.Controller) (*dbmodel.Migration, error) {
+	source := model.Controller.Name
+	model.Controller = *target
+	model.ControllerID = target.ID
+	model.MigrationControllerID = sql.NullInt32{}
+	if err := tx.UpdateModel(ctx, model); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	migrations, err := tx.ListMigrations(ctx, db.MigrationFilter{
+		ModelUUID: model.UUID.String,
+		Active:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		m := dbmodel.Migration{
+			ModelUUID:        model.UUID.String,
+			ModelName:        model.Name,
+			SourceController: source,
+			TargetController: target.Name,
+			Phase:            dbmodel.MigrationPhaseCompleted,
+			StartedAt:        now,
+			EndedAt:          sql.NullTime{Time: now, Valid: true},
+		}
+		if err := tx.AddMigration(ctx, &m); err != nil {
+			return nil, err
+		}
+		return &m, nil
+	}
+	m := migrations[0]
+	m.Phase = dbmodel.MigrationPhaseCompleted
+	m.TargetController = target.Name
+	m.EndedAt = sql.NullTime{Time: now, Valid: true}
+	if err := tx.UpdateMigration(ctx, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/api/base"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestMigrationTracking(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	migrationID := mt.Id() + ":0"
+	c.Patch(jimm.NewControllerClient, func(api base.APICallCloser) jimm.ControllerClient {
+		return &testControllerClient{
+			initiateMigrationResults: []result{{result: migrationID}},
+		}
+	})
+
+	_, err := j.ListMigrations(ctx, u, db.MigrationFilter{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.MigrationStatus(ctx, u, migrationID, names.ModelTag{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	target := dbmodel.Controller{Name: "controller-2"}
+	err = j.Database.GetController(ctx, &target)
+	c.Assert(err, qt.IsNil)
+	_, err = j.InitiateMigration(ctx, u, jujuparams.MigrationSpec{
+		ModelTag: mt.String(),
+		TargetInfo: jujuparams.MigrationTargetInfo{
+			ControllerTag: target.ResourceTag().String(),
+			AuthTag:       names.NewUserTag("admin").String(),
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	u.JimmAdmin = true
+	m, err := j.MigrationStatus(ctx, u, "", mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.MigrationID, qt.Equals, migrationID)
+	c.Check(m.ModelName, qt.Equals, "model-1")
+	c.Check(m.SourceController, qt.Equals, "controller-1")
+	c.Check(m.TargetController, qt.Equals, "controller-2")
+	c.Check(m.Requester, qt.Equals, "alice@canonical.com")
+	c.Check(m.Phase, qt.Equals, dbmodel.MigrationPhaseInitiated)
+	c.Check(m.Active(), qt.IsTrue)
+
+	model := dbmodel.Model{UUID: sql.NullString{String: mt.Id(), Valid: true}}
+	err = j.Database.GetModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	c.Check(model.MigrationControllerID.Valid, qt.IsTrue)
+	c.Check(uint(model.MigrationControllerID.Int32), qt.Equals, target.ID)
+
+	// Model updates from the source controller progress the migration.
+	err = jimm.UpdateMigration(ctx, &j.Database, &model, false, &jujuparams.ModelUpdate{
+		Status: jujuparams.StatusInfo{Current: "available"},
+	})
+	c.Assert(err, qt.IsNil)
+	m, err = j.MigrationStatus(ctx, u, migrationID, names.ModelTag{})
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Phase, qt.Equals, dbmodel.MigrationPhaseInitiated)
+
+	err = jimm.UpdateMigration(ctx, &j.Database, &model, false, &jujuparams.ModelUpdate{
+		Status: jujuparams.StatusInfo{Current: "busy", Message: "migrating: importing"},
+	})
+	c.Assert(err, qt.IsNil)
+	m, err = j.MigrationStatus(ctx, u, migrationID, names.ModelTag{})
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Phase, qt.Equals, dbmodel.MigrationPhaseRunning)
+	c.Check(m.Message, qt.Equals, "importing")
+
+	err = jimm.UpdateMigration(ctx, &j.Database, &model, true, nil)
+	c.Assert(err, qt.IsNil)
+	migrations, err := j.ListMigrations(ctx, u, db.MigrationFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].Phase, qt.Equals, dbmodel.MigrationPhaseCompleted)
+	c.Check(migrations[0].Active(), qt.IsFalse)
+
+	migrations, err = j.ListMigrations(ctx, u, db.MigrationFilter{Active: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(migrations, qt.HasLen, 0)
+}
+
+func TestMigrationAborted(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, _, _ := setupPlacementTest(c)
+
+	model := dbmodel.Model{UUID: sql.NullString{String: "00000002-0000-0000-0000-000000000001", Valid: true}}
+	err := j.Database.GetModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	model.MigrationControllerID = sql.NullInt32{Int32: int32(model.ControllerID), Valid: true}
+	err = j.Database.AddMigration(ctx, &dbmodel.Migration{
+		MigrationID:      model.UUID.String + ":0",
+		ModelUUID:        model.UUID.String,
+		ModelName:        model.Name,
+		SourceController: "controller-1",
+		TargetController: "controller-2",
+		Requester:        "alice@canonical.com",
+		Phase:            dbmodel.MigrationPhaseRunning,
+		StartedAt:        model.CreatedAt,
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = jimm.UpdateMigration(ctx, &j.Database, &model, false, &jujuparams.ModelUpdate{
+		Status: jujuparams.StatusInfo{Current: "available"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(model.MigrationControllerID.Valid, qt.IsFalse)
+
+	m := dbmodel.Migration{ModelUUID: model.UUID.String}
+	err = j.Database.GetMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Phase, qt.Equals, dbmodel.MigrationPhaseAborted)
+	c.Check(m.Active(), qt.IsFalse)
+}
@@ -258,13 +258,15 @@ func (b *modelBuilder) WithCloudRegion(region string) *modelBuilder {
 			b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("unsupported cloud region %s/%s", b.cloud.Name, region))
 			return b
 		}
-		// shuffle controllers
-		shuffleRegionControllers(regionControllers)
-
-		// and select the first controller in the slice
+		// select a controller using the placement strategy
+		crp, err := b.jimm.selectController(b.ctx, regionControllers)
+		if err != nil {
+			b.err = err
+			return b
+		}
 		b.cloudRegion = region
-		b.cloudRegionID = regionControllers[0].CloudRegionID
-		b.controller = &regionControllers[0].Controller
+		b.cloudRegionID = crp.CloudRegionID
+		b.controller = &crp.Controller
 
 		break
 	}
@@ -421,11 +423,13 @@ func (b *modelBuilder) selectCloudRegion() error {
 		return errors.E(fmt.Sprintf("unsupported cloud %s", b.cloud.Name))
 	}
 
-	// shuffle controllers according to their priority
-	shuffleRegionControllers(regionControllers)
-
-	b.cloudRegionID = regionControllers[0].CloudRegionID
-	b.controller = &regionControllers[0].Controller
+	// select a controller using the placement strategy
+	crp, err := b.jimm.selectController(b.ctx, regionControllers)
+	if err != nil {
+		return err
+	}
+	b.cloudRegionID = crp.CloudRegionID
+	b.controller = &crp.Controller
 
 	return nil
 }
@@ -617,6 +621,12 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
+	// check that the new model will not take the owner over any
+	// of the quotas defined for the cloud.
+	if err := j.checkModelQuotas(ctx, owner, builder.cloud.Name, builder.cloudRegion); err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	// fetch cloud region defaults
 	if args.Cloud != (names.CloudTag{}) && builder.cloudRegion != "" {
 		cloudRegionDefaults := dbmodel.CloudDefaults{
@@ -868,7 +878,10 @@ func (j *JIMM) ForEachModel(ctx context.Context, user *openfga.User, f func(*dbm
 // the given user. If the model is not found then an error with the code
 // CodeNotFound is returned. If the authenticated user does not have
 // admin access to the model then an error with the code CodeUnauthorized
-// is returned.
+// is returned. The access is granted permanently, any pending expiry of
+// the relation is removed. The Juju grant facade has no way to carry an
+// expiry, temporary access is granted with the AddRelation method of the
+// JIMM facade (jimmctl auth relation add --expires-in).
 func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
 	const op = errors.Op("jimm.GrantModelAccess")
 
@@ -891,6 +904,10 @@ func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt name
 		}
 		targetOfgaUser := openfga.NewUser(targetUser, j.OpenFGAClient)
 
+		clearExpiry := func() error {
+			return j.clearUserRelationExpiries(ctx, targetOfgaUser, ofganames.ConvertTag(mt), targetRelation)
+		}
+
 		currentRelation := targetOfgaUser.GetModelAccess(ctx, mt)
 		switch targetRelation {
 		case ofganames.ReaderRelation:
@@ -898,28 +915,28 @@ func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt name
 			case ofganames.NoRelation:
 				break
 			default:
-				return nil
+				return clearExpiry()
 			}
 		case ofganames.WriterRelation:
 			switch currentRelation {
 			case ofganames.NoRelation, ofganames.ReaderRelation:
 				break
 			default:
-				return nil
+				return clearExpiry()
 			}
 		case ofganames.AdministratorRelation:
 			switch currentRelation {
 			case ofganames.NoRelation, ofganames.ReaderRelation, ofganames.WriterRelation:
 				break
 			default:
-				return nil
+				return clearExpiry()
 			}
 		}
 
 		if err := targetOfgaUser.SetModelAccess(ctx, mt, targetRelation); err != nil {
 			return errors.E(err, op, "failed to set model access")
 		}
-		return nil
+		return clearExpiry()
 	})
 
 	if err != nil {
@@ -1008,7 +1025,7 @@ func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt nam
 		if err := targetOfgaUser.UnsetModelAccess(ctx, mt, relationsToRevoke...); err != nil {
 			return errors.E(err, op, "failed to unset model access")
 		}
-		return nil
+		return j.clearUserRelationExpiries(ctx, targetOfgaUser, ofganames.ConvertTag(mt), relationsToRevoke...)
 	})
 
 	if err != nil {
@@ -1143,11 +1160,21 @@ func (j *JIMM) doModel(ctx context.Context, user *openfga.User, mt names.ModelTa
 		return errors.E(op, err)
 	}
 
-	accessLevel, err := j.GetUserModelAccess(ctx, user, mt)
+	tctx, checks := openfga.WithRelationTrace(ctx)
+	accessLevel, err := j.GetUserModelAccess(tctx, user, mt)
 	if err != nil {
 		return errors.E(op, err)
 	}
-	if !allowedModelAccess[access][accessLevel] {
+	decision := AuthorizationDecision{
+		Source:  "doModel",
+		Allowed: allowedModelAccess[access][accessLevel],
+		Checks:  checks(),
+	}
+	if relation, err := ofganames.ConvertJujuRelation(access); err == nil {
+		decision.Relation = relation.String()
+	}
+	j.auditAuthorizationDecision(ctx, user, mt, decision)
+	if !decision.Allowed {
 		// If the user doesn't have correct access on the model return
 		// an unauthorized error.
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// TransferModelOwnershipAuditMethod is the facade method recorded in the
+// audit log when the ownership of a model is transferred.
+const TransferModelOwnershipAuditMethod = "TransferModelOwnership"
+
+// TransferModelOwnership transfers the ownership of the model with the
+// given tag to the identity newOwnerName. The model is switched to use a
+// cloud credential owned by the new owner, either the one specified by
+// credTag or, if credTag is empty, the first, by name, of the new owner's
+// credentials for the model's cloud. The new owner is made an
+// administrator of the model and the previous owner's administrator
+// relation is removed. A summary of the transfer is returned and recorded
+// in the audit log.
+//
+// As model names are unique per owner the transfer fails if the new owner
+// already owns a model with the same name. The user must be an
+// administrator of the model and either a JIMM administrator or the new
+// owner, so that a model administrator cannot charge the model to another
+// identity's cloud credential.
+func (j *JIMM) TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwnerName string, credTag names.CloudCredentialTag) (*apiparams.ModelOwnershipTransfer, error) {
+	const op = errors.Op("jimm.TransferModelOwnership")
+
+	if !user.JimmAdmin && user.Name != newOwnerName {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	newOwner := dbmodel.Identity{Name: newOwnerName}
+	if err := j.Database.FetchIdentity(ctx, &newOwner); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if newOwner.Disabled {
+		return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("new owner %q is disabled", newOwnerName))
+	}
+	if credTag.Id() != "" && credTag.Owner().Id() != newOwnerName {
+		return nil, errors.E(op, errors.CodeBadRequest, "cloud credential must be owned by the new owner")
+	}
+
+	var transfer apiparams.ModelOwnershipTransfer
+	err := j.doModelAdmin(ctx, user, mt, func(m *dbmodel.Model, api API) error {
+		if m.OwnerIdentityName == newOwnerName {
+			return errors.E(errors.CodeBadRequest, fmt.Sprintf("model already owned by %q", newOwnerName))
+		}
+		// The model cannot be renamed to avoid a collision as the
+		// name is kept in sync with the controller.
+		if err := j.checkOwnershipConflicts(ctx, newOwnerName, nil, []dbmodel.Model{*m}); err != nil {
+			return err
+		}
+
+		cred, err := j.modelTransferCloudCredential(ctx, m, &newOwner, credTag)
+		if err != nil {
+			return err
+		}
+		if _, err := j.updateControllerCloudCredential(ctx, cred, api.UpdateCredential); err != nil {
+			return err
+		}
+		if err := api.ChangeModelCredential(ctx, mt, cred.ResourceTag()); err != nil {
+			return err
+		}
+
+		transfer.PreviousOwner = m.OwnerIdentityName
+		transfer.PreviousCloudCredential = m.CloudCredential.Path()
+		oldOwner := m.Owner
+		m.CloudCredentialID = cred.ID
+		m.CloudCredential = *cred
+		if err := j.transferModel(ctx, api, m, &oldOwner, &newOwner); err != nil {
+			return err
+		}
+		transfer.Model = m.ToAPIIdentityModel()
+		transfer.NewOwner = newOwnerName
+		transfer.CloudCredential = cred.Path()
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	j.auditJIMMOperation(ctx, user, TransferModelOwnershipAuditMethod, mt.String(), &transfer)
+	return &transfer, nil
+}
+
+// modelTransferCloudCredential returns the cloud credential owned by
+// newOwner that the given model will use once transferred. If credTag is
+// specified that credential is used, otherwise the first, by name, of the
+// new owner's credentials for the model's cloud is used.
+func (j *JIMM) modelTransferCloudCredential(ctx context.Context, m *dbmodel.Model, newOwner *dbmodel.Identity, credTag names.CloudCredentialTag) (*dbmodel.CloudCredential, error) {
+	cloudName := m.CloudRegion.Cloud.Name
+	if credTag.Id() != "" {
+		if credTag.Cloud().Id() != cloudName {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("cloud credential not for cloud %q", cloudName))
+		}
+		var cred dbmodel.CloudCredential
+		cred.SetTag(credTag)
+		if err := j.Database.GetCloudCredential(ctx, &cred); err != nil {
+			return nil, err
+		}
+		return &cred, nil
+	}
+
+	creds, err := j.Database.GetIdentityCloudCredentials(ctx, newOwner, cloudName)
+	if err != nil {
+		return nil, err
+	}
+	if len(creds) == 0 {
+		return nil, errors.E(errors.CodeNotFound, fmt.Sprintf("%s has no cloud credential for cloud %q", newOwner.Name, cloudName))
+	}
+	sort.Slice(creds, func(i, j int) bool {
+		return creds[i].Name < creds[j].Name
+	})
+	return &creds[0], nil
+}
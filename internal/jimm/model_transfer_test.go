@@ -0,0 +1,192 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestTransferModelOwnership(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(_ context.Context, cred jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					record("UpdateCredential " + cred.Tag)
+					return nil, nil
+				},
+				ChangeModelCredential_: func(_ context.Context, mt names.ModelTag, ct names.CloudCredentialTag) error {
+					record("ChangeModelCredential " + mt.String() + " " + ct.String())
+					return nil
+				},
+				GrantModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+					record("GrantModelAccess " + mt.String() + " " + ut.String() + " " + string(access))
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+
+	var noCred names.CloudCredentialTag
+	_, err = j.TransferModelOwnership(ctx, bob, mt, "charlie@canonical.com", noCred)
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	// A model administrator that is neither a JIMM administrator nor
+	// the new owner cannot charge the model to another identity's
+	// cloud credential.
+	_, err = j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", noCred)
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	_, err = j.TransferModelOwnership(ctx, alice, mt, "alice@canonical.com", noCred)
+	c.Check(err, qt.ErrorMatches, `model already owned by "alice@canonical.com"`)
+
+	alice.JimmAdmin = true
+
+	_, err = j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", noCred)
+	c.Check(err, qt.ErrorMatches, `charlie@canonical.com has no cloud credential for cloud "test-cloud"`)
+
+	_, err = j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-1"))
+	c.Check(err, qt.ErrorMatches, `cloud credential must be owned by the new owner`)
+
+	for _, name := range []string{"cred-b", "cred-a"} {
+		err = j.Database.SetCloudCredential(ctx, &dbmodel.CloudCredential{
+			Name:              name,
+			CloudName:         "test-cloud",
+			OwnerIdentityName: "charlie@canonical.com",
+			AuthType:          "empty",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	m := dbmodel.Model{}
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	// A new owner that already owns a model with the same name is
+	// refused before any change is made.
+	conflict := dbmodel.Model{
+		Name: "model-1",
+		UUID: sql.NullString{
+			String: "00000002-0000-0000-0000-000000000002",
+			Valid:  true,
+		},
+		OwnerIdentityName: "charlie@canonical.com",
+		ControllerID:      m.ControllerID,
+		CloudRegionID:     m.CloudRegionID,
+		CloudCredentialID: m.CloudCredentialID,
+	}
+	err = j.Database.AddModel(ctx, &conflict)
+	c.Assert(err, qt.IsNil)
+	_, err = j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", noCred)
+	c.Check(err, qt.ErrorMatches, `charlie@canonical.com already owns model model-1`)
+	c.Check(calls, qt.HasLen, 0)
+	err = j.Database.DeleteModel(ctx, &conflict)
+	c.Assert(err, qt.IsNil)
+
+	transfer, err := j.TransferModelOwnership(ctx, alice, mt, "charlie@canonical.com", noCred)
+	c.Assert(err, qt.IsNil)
+	c.Check(transfer, qt.DeepEquals, &apiparams.ModelOwnershipTransfer{
+		Model: apiparams.IdentityModel{
+			Name:       "model-1",
+			UUID:       mt.Id(),
+			Controller: "controller-1",
+			Cloud:      "test-cloud",
+			Region:     "test-cloud-region",
+		},
+		PreviousOwner:           "alice@canonical.com",
+		NewOwner:                "charlie@canonical.com",
+		PreviousCloudCredential: "test-cloud/alice@canonical.com/cred-1",
+		CloudCredential:         "test-cloud/charlie@canonical.com/cred-a",
+	})
+	c.Check(calls, qt.DeepEquals, []string{
+		"UpdateCredential cloudcred-test-cloud_charlie@canonical.com_cred-a",
+		"ChangeModelCredential " + mt.String() + " cloudcred-test-cloud_charlie@canonical.com_cred-a",
+		"GrantModelAccess " + mt.String() + " user-charlie@canonical.com admin",
+	})
+
+	m = dbmodel.Model{}
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "charlie@canonical.com")
+	c.Check(m.CloudCredential.Path(), qt.Equals, "test-cloud/charlie@canonical.com/cred-a")
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+	c.Check(alice.GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+
+	// The new owner may take over a model they administer using a
+	// specific cloud credential.
+	calls = nil
+	err = j.Database.SetCloudCredential(ctx, &dbmodel.CloudCredential{
+		Name:              "cred-2",
+		CloudName:         "test-cloud",
+		OwnerIdentityName: "bob@canonical.com",
+		AuthType:          "empty",
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = j.TransferModelOwnership(ctx, charlie, mt, "bob@canonical.com", names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-2"))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+	c.Check(calls, qt.HasLen, 0)
+	err = bob.SetModelAccess(ctx, mt, ofganames.AdministratorRelation)
+	c.Assert(err, qt.IsNil)
+	transfer, err = j.TransferModelOwnership(ctx, bob, mt, "bob@canonical.com", names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-2"))
+	c.Assert(err, qt.IsNil)
+	c.Check(transfer.PreviousOwner, qt.Equals, "charlie@canonical.com")
+	c.Check(transfer.CloudCredential, qt.Equals, "test-cloud/bob@canonical.com/cred-2")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.ReaderRelation)
+
+	var objectIDs []string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: jimm.TransferModelOwnershipAuditMethod}, func(ale *dbmodel.AuditLogEntry) error {
+		objectIDs = append(objectIDs, ale.ObjectId)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(objectIDs, qt.DeepEquals, []string{mt.String(), mt.String()})
+}
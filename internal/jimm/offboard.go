@@ -0,0 +1,351 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// OffboardIdentityAuditMethod is the facade method recorded in the audit
+// log when an identity is offboarded.
+const OffboardIdentityAuditMethod = "OffboardIdentity"
+
+// tupleRemoveBatchSize is the maximum number of tuples removed from
+// OpenFGA in a single write, OpenFGA limits the number of writes in a
+// request to 100.
+const tupleRemoveBatchSize = 50
+
+// offboardRelationKinds holds the kinds of resource an identity may have
+// a relation to.
+var offboardRelationKinds = []openfga.Kind{
+	openfga.ControllerType,
+	openfga.CloudType,
+	openfga.ModelType,
+	openfga.ApplicationOfferType,
+	openfga.GroupType,
+	openfga.ServiceAccountType,
+}
+
+// OffboardIdentity removes a departing identity from JIMM. The models and
+// cloud credentials owned by the identity are transferred to newOwner, on
+// the controllers hosting them as well as in JIMM, every relation the
+// identity has is removed and the identity is disabled. A summary of the
+// changes is returned and recorded in the audit log.
+//
+// Offboarding fails, before any change is made, if newOwner already owns
+// a model or cloud credential with the same name as one being
+// transferred. If offboarding fails part way through it may be run again
+// to complete it. Only JIMM administrators may offboard identities, and
+// they may not offboard themselves.
+func (j *JIMM) OffboardIdentity(ctx context.Context, user *openfga.User, identityName, newOwnerName string) (*apiparams.OffboardSummary, error) {
+	const op = errors.Op("jimm.OffboardIdentity")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if identityName == user.Name {
+		return nil, errors.E(op, errors.CodeBadRequest, "cannot offboard own identity")
+	}
+	if identityName == newOwnerName {
+		return nil, errors.E(op, errors.CodeBadRequest, "new owner must be a different identity")
+	}
+
+	identity := dbmodel.Identity{Name: identityName}
+	if err := j.Database.FetchIdentity(ctx, &identity); err != nil {
+		return nil, errors.E(op, err)
+	}
+	newOwner := dbmodel.Identity{Name: newOwnerName}
+	if err := j.Database.FetchIdentity(ctx, &newOwner); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if newOwner.Disabled {
+		return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("new owner %q is disabled", newOwnerName))
+	}
+
+	creds, err := j.ownedCloudCredentials(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	models, err := j.Database.GetModelsByOwner(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := j.checkOwnershipConflicts(ctx, newOwnerName, creds, models); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	summary := apiparams.OffboardSummary{
+		Identity: identityName,
+		NewOwner: newOwnerName,
+	}
+	for i := range creds {
+		cred, err := j.transferCloudCredential(ctx, &creds[i], &newOwner)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		summary.CloudCredentials = append(summary.CloudCredentials, cred.Path())
+	}
+
+	// The models are loaded again as transferring the cloud
+	// credentials may have changed the credentials they use.
+	models, err = j.Database.GetModelsByOwner(ctx, identityName)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	for i := range models {
+		err := func() error {
+			api, err := j.dial(ctx, &models[i].Controller, names.ModelTag{})
+			if err != nil {
+				return err
+			}
+			defer api.Close()
+			return j.transferModel(ctx, api, &models[i], &identity, &newOwner)
+		}()
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		summary.Models = append(summary.Models, models[i].ToAPIIdentityModel())
+	}
+
+	summary.RemovedRelations, err = j.removeIdentityRelations(ctx, &identity)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	if err := j.SetIdentityDisabled(ctx, user, identityName, true); err != nil {
+		return nil, errors.E(op, err)
+	}
+	summary.Disabled = true
+
+	j.auditJIMMOperation(ctx, user, OffboardIdentityAuditMethod, identity.ResourceTag().String(), &summary)
+	return &summary, nil
+}
+
+// checkOwnershipConflicts returns an error with a code of
+// CodeAlreadyExists if the named identity already owns a cloud credential
+// or model with the same name as one of those given.
+func (j *JIMM) checkOwnershipConflicts(ctx context.Context, identityName string, creds []dbmodel.CloudCredential, models []dbmodel.Model) error {
+	ownedCreds, err := j.ownedCloudCredentials(ctx, identityName)
+	if err != nil {
+		return err
+	}
+	ownedModels, err := j.Database.GetModelsByOwner(ctx, identityName)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[string]bool)
+	for _, cred := range ownedCreds {
+		owned["cloud credential "+cred.CloudName+"/"+cred.Name] = true
+	}
+	for _, m := range ownedModels {
+		owned["model "+m.Name] = true
+	}
+	var conflicts []string
+	for _, cred := range creds {
+		if key := "cloud credential " + cred.CloudName + "/" + cred.Name; owned[key] {
+			conflicts = append(conflicts, key)
+		}
+	}
+	for _, m := range models {
+		if key := "model " + m.Name; owned[key] {
+			conflicts = append(conflicts, key)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return errors.E(errors.CodeAlreadyExists, fmt.Sprintf("%s already owns %s", identityName, strings.Join(conflicts, ", ")))
+	}
+	return nil
+}
+
+// transferCloudCredential moves the given cloud credential to newOwner. A
+// copy of the credential owned by newOwner is created, the models using
+// the credential are switched to the copy, both on their controllers and
+// in the database, and the original credential is revoked and removed.
+// The new credential is returned.
+func (j *JIMM) transferCloudCredential(ctx context.Context, cred *dbmodel.CloudCredential, newOwner *dbmodel.Identity) (*dbmodel.CloudCredential, error) {
+	attrs, err := j.getCloudCredentialAttributes(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	newCred := dbmodel.CloudCredential{
+		Name:              cred.Name,
+		CloudName:         cred.CloudName,
+		OwnerIdentityName: newOwner.Name,
+		AuthType:          cred.AuthType,
+		Label:             cred.Label,
+		Attributes:        attrs,
+		Valid:             cred.Valid,
+	}
+	if err := j.updateCredential(ctx, &newCred); err != nil {
+		return nil, err
+	}
+	// Reload the credential as updateCredential does not set the ID
+	// when the attributes are stored in vault.
+	if err := j.Database.GetCloudCredential(ctx, &newCred); err != nil {
+		return nil, err
+	}
+
+	models, err := j.Database.GetModelsUsingCredential(ctx, cred.ID)
+	if err != nil {
+		return nil, err
+	}
+	var controllers []*dbmodel.Controller
+	controllerModels := make(map[uint][]*dbmodel.Model)
+	for i := range models {
+		m := &models[i]
+		if _, ok := controllerModels[m.ControllerID]; !ok {
+			controllers = append(controllers, &m.Controller)
+		}
+		controllerModels[m.ControllerID] = append(controllerModels[m.ControllerID], m)
+	}
+	for _, ctl := range controllers {
+		if err := j.switchControllerCloudCredential(ctx, ctl, controllerModels[ctl.ID], cred, &newCred); err != nil {
+			return nil, err
+		}
+	}
+
+	if cred.AttributesInVault && j.CredentialStore != nil {
+		// Storing no attributes removes the credential from vault.
+		if err := j.CredentialStore.Put(ctx, cred.ResourceTag(), nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := j.Database.DeleteCloudCredential(ctx, cred); err != nil {
+		return nil, err
+	}
+	return &newCred, nil
+}
+
+// switchControllerCloudCredential switches the given models, all hosted
+// on the given controller, from the credential oldCred to newCred, which
+// is first uploaded to the controller. The old credential is revoked on
+// the controller once no models use it.
+func (j *JIMM) switchControllerCloudCredential(ctx context.Context, ctl *dbmodel.Controller, models []*dbmodel.Model, oldCred, newCred *dbmodel.CloudCredential) error {
+	api, err := j.dial(ctx, ctl, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if _, err := j.updateControllerCloudCredential(ctx, newCred, api.UpdateCredential); err != nil {
+		return err
+	}
+	for _, m := range models {
+		if err := api.ChangeModelCredential(ctx, m.ResourceTag(), newCred.ResourceTag()); err != nil {
+			return err
+		}
+		m.CloudCredentialID = newCred.ID
+		m.CloudCredential = *newCred
+		if err := j.Database.UpdateModel(ctx, m); err != nil {
+			return err
+		}
+	}
+	if err := api.RevokeCredential(ctx, oldCred.ResourceTag()); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		return err
+	}
+	return nil
+}
+
+// transferModel transfers ownership of the given model from oldOwner to
+// newOwner. The new owner is made an administrator of the model, on the
+// controller hosting it, using the given API connection, as well as in
+// OpenFGA, and the old owner's direct administrator relation to the model
+// is removed. Any other changes made to the model, such as its cloud
+// credential, are saved along with the new owner.
+func (j *JIMM) transferModel(ctx context.Context, api API, m *dbmodel.Model, oldOwner, newOwner *dbmodel.Identity) error {
+	mt := m.ResourceTag()
+
+	if err := api.GrantModelAccess(ctx, mt, newOwner.ResourceTag(), jujuparams.ModelAdminAccess); err != nil {
+		if !strings.Contains(err.Error(), "already has") {
+			return err
+		}
+	}
+
+	m.SwitchOwner(newOwner)
+	if err := j.Database.UpdateModel(ctx, m); err != nil {
+		return err
+	}
+
+	add := []openfga.Tuple{{
+		Object:   ofganames.ConvertTag(newOwner.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}}
+	existing, err := readAllTuples(ctx, j.OpenFGAClient, openfga.Tuple{
+		Object:   ofganames.ConvertTag(newOwner.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	if err != nil {
+		return errors.E(errors.CodeOpenFGARequestFailed, err)
+	}
+	if len(existing) > 0 {
+		add = nil
+	}
+	remove, err := readAllTuples(ctx, j.OpenFGAClient, openfga.Tuple{
+		Object:   ofganames.ConvertTag(oldOwner.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	if err != nil {
+		return errors.E(errors.CodeOpenFGARequestFailed, err)
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	if err := j.OpenFGAClient.AddRemoveRelations(ctx, add, remove); err != nil {
+		return errors.E(errors.CodeOpenFGARequestFailed, err)
+	}
+	return nil
+}
+
+// removeIdentityRelations removes every relation the given identity has
+// to any resource and returns the relations removed.
+func (j *JIMM) removeIdentityRelations(ctx context.Context, identity *dbmodel.Identity) ([]apiparams.RelationshipTuple, error) {
+	var tuples []openfga.Tuple
+	for _, kind := range offboardRelationKinds {
+		kt, err := ofganames.BlankKindTag(string(kind))
+		if err != nil {
+			return nil, err
+		}
+		t, err := readAllTuples(ctx, j.OpenFGAClient, openfga.Tuple{
+			Object: ofganames.ConvertTag(identity.ResourceTag()),
+			Target: kt,
+		})
+		if err != nil {
+			return nil, errors.E(errors.CodeOpenFGARequestFailed, err)
+		}
+		tuples = append(tuples, t...)
+	}
+
+	removed := make([]apiparams.RelationshipTuple, len(tuples))
+	for i, t := range tuples {
+		removed[i] = apiparams.RelationshipTuple{
+			Object:       j.jaasTagOrString(ctx, t.Object),
+			Relation:     string(t.Relation),
+			TargetObject: j.jaasTagOrString(ctx, t.Target),
+		}
+	}
+	for len(tuples) > 0 {
+		n := min(len(tuples), tupleRemoveBatchSize)
+		if err := j.OpenFGAClient.RemoveRelation(ctx, tuples[:n]...); err != nil {
+			return nil, errors.E(errors.CodeOpenFGARequestFailed, err)
+		}
+		tuples = tuples[n:]
+	}
+	return removed, nil
+}
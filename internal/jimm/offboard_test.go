@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestOffboardIdentity(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(_ context.Context, cred jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					record("UpdateCredential " + cred.Tag)
+					return nil, nil
+				},
+				ChangeModelCredential_: func(_ context.Context, mt names.ModelTag, ct names.CloudCredentialTag) error {
+					record("ChangeModelCredential " + mt.String() + " " + ct.String())
+					return nil
+				},
+				RevokeCredential_: func(_ context.Context, ct names.CloudCredentialTag) error {
+					record("RevokeCredential " + ct.String())
+					return nil
+				},
+				GrantModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+					record("GrantModelAccess " + mt.String() + " " + ut.String() + " " + string(access))
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	group, err := j.Database.AddGroup(ctx, "devs")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("alice@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.OffboardIdentity(ctx, bob, "alice@canonical.com", "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	_, err = j.OffboardIdentity(ctx, diane, "diane@canonical.com", "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, `cannot offboard own identity`)
+
+	_, err = j.OffboardIdentity(ctx, diane, "alice@canonical.com", "alice@canonical.com")
+	c.Check(err, qt.ErrorMatches, `new owner must be a different identity`)
+
+	// A new owner that already owns a cloud credential with the same
+	// name is refused before any change is made.
+	conflict := dbmodel.CloudCredential{
+		Name:              "cred-1",
+		CloudName:         "test-cloud",
+		OwnerIdentityName: "bob@canonical.com",
+		AuthType:          "empty",
+	}
+	err = j.Database.SetCloudCredential(ctx, &conflict)
+	c.Assert(err, qt.IsNil)
+	_, err = j.OffboardIdentity(ctx, diane, "alice@canonical.com", "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, `bob@canonical.com already owns cloud credential test-cloud/cred-1`)
+	c.Check(calls, qt.HasLen, 0)
+	// The credential is removed permanently so that it does not conflict
+	// with the transferred credential.
+	err = j.Database.DB.Unscoped().Delete(&conflict).Error
+	c.Assert(err, qt.IsNil)
+
+	summary, err := j.OffboardIdentity(ctx, diane, "alice@canonical.com", "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(summary, qt.DeepEquals, &apiparams.OffboardSummary{
+		Identity: "alice@canonical.com",
+		NewOwner: "bob@canonical.com",
+		Models: []apiparams.IdentityModel{{
+			Name:       "model-1",
+			UUID:       mt.Id(),
+			Controller: "controller-1",
+			Cloud:      "test-cloud",
+			Region:     "test-cloud-region",
+		}},
+		CloudCredentials: []string{"test-cloud/bob@canonical.com/cred-1"},
+		RemovedRelations: []apiparams.RelationshipTuple{{
+			Object:       "user-alice@canonical.com",
+			Relation:     "member",
+			TargetObject: "group-devs",
+		}},
+		Disabled: true,
+	})
+	c.Check(calls, qt.DeepEquals, []string{
+		"UpdateCredential cloudcred-test-cloud_bob@canonical.com_cred-1",
+		"ChangeModelCredential " + mt.String() + " cloudcred-test-cloud_bob@canonical.com_cred-1",
+		"RevokeCredential cloudcred-test-cloud_alice@canonical.com_cred-1",
+		"GrantModelAccess " + mt.String() + " user-bob@canonical.com admin",
+	})
+
+	m := dbmodel.Model{}
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "bob@canonical.com")
+	c.Check(m.CloudCredential.Path(), qt.Equals, "test-cloud/bob@canonical.com/cred-1")
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+
+	alice := dbmodel.Identity{Name: "alice@canonical.com"}
+	err = j.Database.FetchIdentity(ctx, &alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(alice.Disabled, qt.IsTrue)
+	c.Check(openfga.NewUser(&alice, client).GetModelAccess(ctx, mt), qt.Equals, ofganames.NoRelation)
+
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: jimm.OffboardIdentityAuditMethod}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+	c.Check(entries[0].ObjectId, qt.Equals, "user-alice@canonical.com")
+
+	_, err = j.OffboardIdentity(ctx, diane, "bob@canonical.com", "alice@canonical.com")
+	c.Check(err, qt.ErrorMatches, `new owner "alice@canonical.com" is disabled`)
+}
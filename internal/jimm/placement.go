@@ -0,0 +1,350 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const (
+	// PlacementStrategyPriority places new models on the controller with
+	// the highest priority for the cloud region, choosing randomly
+	// between controllers with equal priority. This is the default.
+	PlacementStrategyPriority = "priority"
+
+	// PlacementStrategyLeastModels places new models on the controller
+	// hosting the fewest models.
+	PlacementStrategyLeastModels = "least-models"
+
+	// PlacementStrategyLeastUnits places new models on the controller
+	// running the fewest units.
+	PlacementStrategyLeastUnits = "least-units"
+
+	// PlacementStrategyLeastMachines places new models on the controller
+	// running the fewest machines.
+	PlacementStrategyLeastMachines = "least-machines"
+
+	// PlacementStrategyRoundRobin places new models on each of the
+	// highest priority controllers in turn.
+	PlacementStrategyRoundRobin = "round-robin"
+)
+
+// A PlacementCandidate is a controller that is able to host a new model
+// in a cloud region along with the current load on that controller.
+type PlacementCandidate struct {
+	dbmodel.CloudRegionControllerPriority
+
+	// Models is the number of models hosted on the controller.
+	Models int
+
+	// Units is the number of units running in all models hosted on the
+	// controller.
+	Units int
+
+	// Machines is the number of machines running in all models hosted on
+	// the controller.
+	Machines int
+}
+
+// A PlacementStrategy determines the controller on which a new model is
+// placed.
+type PlacementStrategy interface {
+	// Order sorts the given candidates such that the preferred
+	// controller is first.
+	Order(candidates []PlacementCandidate)
+
+	// UsesLoad reports whether the strategy uses the load of the
+	// candidates. If it does not, and no candidate has a capacity cap,
+	// the load is not read from the database and is left as zero.
+	UsesLoad() bool
+}
+
+// NewPlacementStrategy returns the PlacementStrategy with the given name.
+// If the name is empty the priority strategy is returned.
+func NewPlacementStrategy(name string) (PlacementStrategy, error) {
+	switch name {
+	case "", PlacementStrategyPriority:
+		return priorityStrategy{}, nil
+	case PlacementStrategyLeastModels:
+		return leastLoadedStrategy{load: func(c PlacementCandidate) int { return c.Models }}, nil
+	case PlacementStrategyLeastUnits:
+		return leastLoadedStrategy{load: func(c PlacementCandidate) int { return c.Units }}, nil
+	case PlacementStrategyLeastMachines:
+		return leastLoadedStrategy{load: func(c PlacementCandidate) int { return c.Machines }}, nil
+	case PlacementStrategyRoundRobin:
+		return &roundRobinStrategy{}, nil
+	default:
+		return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("unknown placement strategy %q", name))
+	}
+}
+
+func shuffleCandidates(candidates []PlacementCandidate) {
+	shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+}
+
+// priorityStrategy orders candidates by their cloud-region priority.
+type priorityStrategy struct{}
+
+// UsesLoad implements PlacementStrategy.
+func (priorityStrategy) UsesLoad() bool {
+	return false
+}
+
+// Order implements PlacementStrategy.
+func (priorityStrategy) Order(candidates []PlacementCandidate) {
+	shuffleCandidates(candidates)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+}
+
+// leastLoadedStrategy orders candidates by increasing load, candidates
+// with equal load are ordered by priority.
+type leastLoadedStrategy struct {
+	load func(PlacementCandidate) int
+}
+
+// UsesLoad implements PlacementStrategy.
+func (leastLoadedStrategy) UsesLoad() bool {
+	return true
+}
+
+// Order implements PlacementStrategy.
+func (s leastLoadedStrategy) Order(candidates []PlacementCandidate) {
+	shuffleCandidates(candidates)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		li, lj := s.load(candidates[i]), s.load(candidates[j])
+		if li != lj {
+			return li < lj
+		}
+		return candidates[i].Priority > candidates[j].Priority
+	})
+}
+
+// roundRobinStrategy cycles through the highest priority candidates.
+type roundRobinStrategy struct {
+	mu   sync.Mutex
+	next int
+}
+
+// UsesLoad implements PlacementStrategy.
+func (*roundRobinStrategy) UsesLoad() bool {
+	return false
+}
+
+// Order implements PlacementStrategy.
+func (s *roundRobinStrategy) Order(candidates []PlacementCandidate) {
+	if len(candidates) == 0 {
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		return candidates[i].ControllerID < candidates[j].ControllerID
+	})
+	n := 1
+	for n < len(candidates) && candidates[n].Priority == candidates[0].Priority {
+		n++
+	}
+
+	s.mu.Lock()
+	offset := s.next % n
+	s.next++
+	s.mu.Unlock()
+
+	top := append([]PlacementCandidate(nil), candidates[:n]...)
+	for i := range top {
+		candidates[i] = top[(i+offset)%n]
+	}
+}
+
+// placementStrategy returns the placement strategy configured for JIMM.
+func (j *JIMM) placementStrategy() PlacementStrategy {
+	if j.PlacementStrategy == nil {
+		return priorityStrategy{}
+	}
+	return j.PlacementStrategy
+}
+
+// selectController chooses the controller on which to place a new model
+// from the given cloud-region controllers using the configured placement
+// strategy. Controllers that are deprecated or have reached their
+// capacity are not considered. The load on the controllers is only read,
+// in a single query, when the strategy or a capacity cap needs it.
+func (j *JIMM) selectController(ctx context.Context, controllers []dbmodel.CloudRegionControllerPriority) (*dbmodel.CloudRegionControllerPriority, error) {
+	strategy := j.placementStrategy()
+	needLoad := strategy.UsesLoad()
+
+	candidates := make([]PlacementCandidate, 0, len(controllers))
+	controllerIDs := make([]uint, 0, len(controllers))
+	for _, crp := range controllers {
+		if crp.Controller.Deprecated {
+			continue
+		}
+		if crp.Controller.MaxModels.Valid || crp.Controller.MaxUnits.Valid || crp.Controller.MaxMachines.Valid {
+			needLoad = true
+		}
+		candidates = append(candidates, PlacementCandidate{CloudRegionControllerPriority: crp})
+		controllerIDs = append(controllerIDs, crp.Controller.ID)
+	}
+
+	if needLoad && len(candidates) > 0 {
+		loads, err := j.Database.GetControllerLoads(ctx, controllerIDs)
+		if err != nil {
+			return nil, errors.E(err)
+		}
+		available := candidates[:0]
+		for _, c := range candidates {
+			load := loads[c.Controller.ID]
+			if atCapacity(&c.Controller, load) {
+				continue
+			}
+			c.Models, c.Units, c.Machines = load.Models, load.Units, load.Machines
+			available = append(available, c)
+		}
+		candidates = available
+	}
+	if len(candidates) == 0 {
+		return nil, errors.E(errors.CodeQuotaLimitExceeded, "no controller with available capacity for the cloud region")
+	}
+	strategy.Order(candidates)
+	return &candidates[0].CloudRegionControllerPriority, nil
+}
+
+// atCapacity reports whether the given controller has reached any of its
+// capacity caps with the given load.
+func atCapacity(ctl *dbmodel.Controller, load dbmodel.ControllerLoad) bool {
+	if ctl.MaxModels.Valid && int64(load.Models) >= ctl.MaxModels.Int64 {
+		return true
+	}
+	if ctl.MaxUnits.Valid && int64(load.Units) >= ctl.MaxUnits.Int64 {
+		return true
+	}
+	if ctl.MaxMachines.Valid && int64(load.Machines) >= ctl.MaxMachines.Int64 {
+		return true
+	}
+	return false
+}
+
+// SetControllerCapacity sets the capacity caps of the named controller.
+// New models are not placed on a controller hosting maxModels models,
+// running maxUnits units or running maxMachines machines. An invalid
+// value removes the corresponding cap.
+func (j *JIMM) SetControllerCapacity(ctx context.Context, user *openfga.User, controllerName string, maxModels, maxUnits, maxMachines sql.NullInt64) error {
+	const op = errors.Op("jimm.SetControllerCapacity")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if (maxModels.Valid && maxModels.Int64 < 0) || (maxUnits.Valid && maxUnits.Int64 < 0) || (maxMachines.Valid && maxMachines.Int64 < 0) {
+		return errors.E(op, errors.CodeBadRequest, "capacity cannot be negative")
+	}
+
+	err := j.Database.Transaction(func(db *db.Database) error {
+		ctl := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := db.GetController(ctx, &ctl); err != nil {
+			return err
+		}
+		ctl.MaxModels = maxModels
+		ctl.MaxUnits = maxUnits
+		ctl.MaxMachines = maxMachines
+		return db.UpdateController(ctx, &ctl)
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SetControllerRegionPriority sets the priority with which the named
+// controller is chosen when placing new models in the given cloud region.
+func (j *JIMM) SetControllerRegionPriority(ctx context.Context, user *openfga.User, controllerName, cloudName, regionName string, priority uint) error {
+	const op = errors.Op("jimm.SetControllerRegionPriority")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	err := j.Database.Transaction(func(db *db.Database) error {
+		ctl := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := db.GetController(ctx, &ctl); err != nil {
+			return err
+		}
+		for _, crp := range ctl.CloudRegions {
+			if crp.CloudRegion.CloudName != cloudName || crp.CloudRegion.Name != regionName {
+				continue
+			}
+			crp.Priority = priority
+			return db.UpdateCloudRegionControllerPriority(ctx, &crp)
+		}
+		return errors.E(errors.CodeNotFound, fmt.Sprintf("controller %q does not serve cloud region %s/%s", controllerName, cloudName, regionName))
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListControllerRegionPriorities returns the cloud region priorities of the
+// named controller. If the controller name is empty the priorities of all
+// controllers are returned. The returned priorities are ordered by
+// controller name, cloud name and region name.
+func (j *JIMM) ListControllerRegionPriorities(ctx context.Context, user *openfga.User, controllerName string) ([]dbmodel.CloudRegionControllerPriority, error) {
+	const op = errors.Op("jimm.ListControllerRegionPriorities")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var controllerNames []string
+	if controllerName != "" {
+		controllerNames = append(controllerNames, controllerName)
+	} else {
+		err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			controllerNames = append(controllerNames, ctl.Name)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+
+	var priorities []dbmodel.CloudRegionControllerPriority
+	for _, name := range controllerNames {
+		ctl := dbmodel.Controller{
+			Name: name,
+		}
+		if err := j.Database.GetController(ctx, &ctl); err != nil {
+			return nil, errors.E(op, err)
+		}
+		crps := ctl.CloudRegions
+		ctl.CloudRegions = nil
+		sort.Slice(crps, func(i, j int) bool {
+			if crps[i].CloudRegion.CloudName != crps[j].CloudRegion.CloudName {
+				return crps[i].CloudRegion.CloudName < crps[j].CloudRegion.CloudName
+			}
+			return crps[i].CloudRegion.Name < crps[j].CloudRegion.Name
+		})
+		for _, crp := range crps {
+			crp.Controller = ctl
+			priorities = append(priorities, crp)
+		}
+	}
+	return priorities, nil
+}
@@ -0,0 +1,412 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func placementCandidate(id uint, priority uint, models, units int) jimm.PlacementCandidate {
+	return jimm.PlacementCandidate{
+		CloudRegionControllerPriority: dbmodel.CloudRegionControllerPriority{
+			ControllerID: id,
+			Priority:     priority,
+		},
+		Models: models,
+		Units:  units,
+	}
+}
+
+func withMachines(c jimm.PlacementCandidate, machines int) jimm.PlacementCandidate {
+	c.Machines = machines
+	return c
+}
+
+func candidateIDs(candidates []jimm.PlacementCandidate) []uint {
+	ids := make([]uint, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.ControllerID
+	}
+	return ids
+}
+
+var placementStrategyTests = []struct {
+	name       string
+	strategy   string
+	candidates []jimm.PlacementCandidate
+	expectIDs  [][]uint
+}{{
+	name:     "priority",
+	strategy: "",
+	candidates: []jimm.PlacementCandidate{
+		placementCandidate(1, 1, 0, 0),
+		placementCandidate(2, 3, 10, 100),
+		placementCandidate(3, 2, 5, 50),
+	},
+	expectIDs: [][]uint{{2, 3, 1}},
+}, {
+	name:     "least-models",
+	strategy: jimm.PlacementStrategyLeastModels,
+	candidates: []jimm.PlacementCandidate{
+		placementCandidate(1, 1, 4, 0),
+		placementCandidate(2, 3, 10, 0),
+		placementCandidate(3, 1, 2, 100),
+		placementCandidate(4, 2, 2, 0),
+	},
+	expectIDs: [][]uint{{4, 3, 1, 2}},
+}, {
+	name:     "least-units",
+	strategy: jimm.PlacementStrategyLeastUnits,
+	candidates: []jimm.PlacementCandidate{
+		placementCandidate(1, 1, 0, 40),
+		placementCandidate(2, 3, 0, 100),
+		placementCandidate(3, 1, 20, 2),
+		placementCandidate(4, 2, 0, 40),
+	},
+	expectIDs: [][]uint{{3, 4, 1, 2}},
+}, {
+	name:     "least-machines",
+	strategy: jimm.PlacementStrategyLeastMachines,
+	candidates: []jimm.PlacementCandidate{
+		withMachines(placementCandidate(1, 1, 0, 0), 10),
+		withMachines(placementCandidate(2, 3, 0, 0), 30),
+		withMachines(placementCandidate(3, 1, 20, 200), 2),
+		withMachines(placementCandidate(4, 2, 0, 0), 10),
+	},
+	expectIDs: [][]uint{{3, 4, 1, 2}},
+}, {
+	name:     "round-robin",
+	strategy: jimm.PlacementStrategyRoundRobin,
+	candidates: []jimm.PlacementCandidate{
+		placementCandidate(4, 1, 0, 0),
+		placementCandidate(3, 2, 0, 0),
+		placementCandidate(2, 2, 0, 0),
+		placementCandidate(1, 2, 0, 0),
+	},
+	expectIDs: [][]uint{
+		{1, 2, 3, 4},
+		{2, 3, 1, 4},
+		{3, 1, 2, 4},
+		{1, 2, 3, 4},
+	},
+}}
+
+func TestPlacementStrategies(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range placementStrategyTests {
+		c.Run(test.name, func(c *qt.C) {
+			s, err := jimm.NewPlacementStrategy(test.strategy)
+			c.Assert(err, qt.IsNil)
+			for _, expect := range test.expectIDs {
+				candidates := append([]jimm.PlacementCandidate(nil), test.candidates...)
+				s.Order(candidates)
+				c.Check(candidateIDs(candidates), qt.DeepEquals, expect)
+			}
+		})
+	}
+}
+
+func TestNewPlacementStrategyUnknown(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := jimm.NewPlacementStrategy("no-such-strategy")
+	c.Check(err, qt.ErrorMatches, `unknown placement strategy "no-such-strategy"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+const placementTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: alice@canonical.com
+    access: add-model
+cloud-credentials:
+- name: cred-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+- name: controller-2
+  uuid: 00000000-0000-0000-0000-0000-0000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  life: alive
+  units: 3
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+`
+
+func setupPlacementTest(c *qt.C) (*jimm.JIMM, *jimmtest.Environment, *openfga.OFGAClient) {
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+					return nil
+				},
+				CreateModel_: createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+`[1:]),
+			},
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, placementTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+	return j, env, client
+}
+
+func TestAddModelPlacement(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+
+	addModel := func(name string) (*jujuparams.ModelInfo, error) {
+		args := jimm.ModelCreateArgs{}
+		err := args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+			Name:               name,
+			OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+			CloudTag:           names.NewCloudTag("test-cloud").String(),
+			CloudRegion:        "test-region-1",
+			CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1").String(),
+		})
+		c.Assert(err, qt.IsNil)
+		return j.AddModel(ctx, u, &args)
+	}
+
+	// The least-units strategy prefers the lower priority controller
+	// as it is running fewer units.
+	var err error
+	j.PlacementStrategy, err = jimm.NewPlacementStrategy(jimm.PlacementStrategyLeastUnits)
+	c.Assert(err, qt.IsNil)
+	mi, err := addModel("model-2")
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ControllerUUID, qt.Equals, "00000000-0000-0000-0000-0000-0000000000002")
+
+	// The priority strategy does not use the higher priority controller
+	// once it reaches its machine cap.
+	j.PlacementStrategy = nil
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.MaxMachines = sql.NullInt64{Int64: 2, Valid: true}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	model := dbmodel.Model{UUID: sql.NullString{String: "00000002-0000-0000-0000-000000000001", Valid: true}}
+	err = j.Database.GetModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	model.Machines = 2
+	err = j.Database.UpdateModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	mi, err = addModel("model-3")
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ControllerUUID, qt.Equals, "00000000-0000-0000-0000-0000-0000000000002")
+
+	// Controllers at capacity are not used.
+	for _, name := range []string{"controller-1", "controller-2"} {
+		ctl := dbmodel.Controller{Name: name}
+		err = j.Database.GetController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+		ctl.MaxModels = sql.NullInt64{Int64: 1, Valid: true}
+		err = j.Database.UpdateController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+	}
+	_, err = addModel("model-4")
+	c.Check(err, qt.ErrorMatches, `no controller with available capacity for the cloud region`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+}
+
+func TestAddModelPlacementDeprecated(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	// Deprecated controllers are not used, even if they have the
+	// highest priority.
+	err := j.SetControllerDeprecated(ctx, u, "controller-1", true)
+	c.Assert(err, qt.IsNil)
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "model-2",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+	mi, err := j.AddModel(ctx, u, &args)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ControllerUUID, qt.Equals, "00000000-0000-0000-0000-0000-0000000000002")
+
+	err = j.SetControllerDeprecated(ctx, u, "controller-2", true)
+	c.Assert(err, qt.IsNil)
+	args.Name = "model-3"
+	_, err = j.AddModel(ctx, u, &args)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+}
+
+func TestSetControllerCapacity(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+
+	maxModels := sql.NullInt64{Int64: 5, Valid: true}
+	maxUnits := sql.NullInt64{Int64: 50, Valid: true}
+	maxMachines := sql.NullInt64{Int64: 20, Valid: true}
+	err := j.SetControllerCapacity(ctx, u, "controller-1", maxModels, maxUnits, maxMachines)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	u.JimmAdmin = true
+	err = j.SetControllerCapacity(ctx, u, "controller-1", sql.NullInt64{Int64: -1, Valid: true}, maxUnits, maxMachines)
+	c.Check(err, qt.ErrorMatches, `capacity cannot be negative`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.SetControllerCapacity(ctx, u, "controller-1", maxModels, maxUnits, sql.NullInt64{Int64: -1, Valid: true})
+	c.Check(err, qt.ErrorMatches, `capacity cannot be negative`)
+
+	err = j.SetControllerCapacity(ctx, u, "controller-1", maxModels, maxUnits, maxMachines)
+	c.Assert(err, qt.IsNil)
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.MaxModels, qt.Equals, maxModels)
+	c.Check(ctl.MaxUnits, qt.Equals, maxUnits)
+	c.Check(ctl.MaxMachines, qt.Equals, maxMachines)
+
+	// Caps that are not specified are removed.
+	err = j.SetControllerCapacity(ctx, u, "controller-1", sql.NullInt64{}, maxUnits, sql.NullInt64{})
+	c.Assert(err, qt.IsNil)
+	ctl = dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.MaxModels, qt.Equals, sql.NullInt64{})
+	c.Check(ctl.MaxUnits, qt.Equals, maxUnits)
+	c.Check(ctl.MaxMachines, qt.Equals, sql.NullInt64{})
+
+	err = j.SetControllerCapacity(ctx, u, "no-such-controller", maxModels, maxUnits, maxMachines)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestControllerRegionPriorities(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupPlacementTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+
+	err := j.SetControllerRegionPriority(ctx, u, "controller-2", "test-cloud", "test-region-1", 20)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListControllerRegionPriorities(ctx, u, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	u.JimmAdmin = true
+	err = j.SetControllerRegionPriority(ctx, u, "controller-2", "test-cloud", "test-region-1", 20)
+	c.Assert(err, qt.IsNil)
+
+	err = j.SetControllerRegionPriority(ctx, u, "controller-2", "test-cloud", "no-such-region", 20)
+	c.Check(err, qt.ErrorMatches, `controller "controller-2" does not serve cloud region test-cloud/no-such-region`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.SetControllerRegionPriority(ctx, u, "no-such-controller", "test-cloud", "test-region-1", 20)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	crps, err := j.ListControllerRegionPriorities(ctx, u, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(crps, qt.HasLen, 2)
+	c.Check(crps[0].Controller.Name, qt.Equals, "controller-1")
+	c.Check(crps[0].CloudRegion.Name, qt.Equals, "test-region-1")
+	c.Check(crps[0].Priority, qt.Equals, uint(10))
+	c.Check(crps[1].Controller.Name, qt.Equals, "controller-2")
+	c.Check(crps[1].CloudRegion.Name, qt.Equals, "test-region-1")
+	c.Check(crps[1].Priority, qt.Equals, uint(20))
+
+	crps, err = j.ListControllerRegionPriorities(ctx, u, "controller-2")
+	c.Assert(err, qt.IsNil)
+	c.Assert(crps, qt.HasLen, 1)
+	c.Check(crps[0].Priority, qt.Equals, uint(20))
+
+	// The higher priority controller is now preferred.
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "model-2",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+	mi, err := j.AddModel(ctx, u, &args)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ControllerUUID, qt.Equals, "00000000-0000-0000-0000-0000-0000000000002")
+}
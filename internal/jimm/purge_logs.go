@@ -21,6 +21,12 @@ func (j *JIMM) PurgeLogs(ctx context.Context, user *openfga.User, before time.Ti
 	if !user.JimmAdmin {
 		return 0, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
+	if j.AuditArchiver != nil {
+		if _, err := j.AuditArchiver.Archive(ctx, before); err != nil {
+			zapctx.Error(ctx, "failed to archive logs", zap.Error(err))
+			return 0, errors.E(op, "failed to archive logs", err)
+		}
+	}
 	count, err := j.Database.DeleteAuditLogsBefore(ctx, before)
 	if err != nil {
 		zapctx.Error(ctx, "failed to purge logs", zap.Error(err))
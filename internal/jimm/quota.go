@@ -0,0 +1,236 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// A QuotaStatus holds a quota along with the current usage that is
+// measured against it.
+type QuotaStatus struct {
+	Quota dbmodel.Quota
+	Usage dbmodel.QuotaUsage
+}
+
+// SetQuota sets the limits of a quota. The subject of the quota may be
+// given as either a user tag or a group tag, groups may be specified
+// by name or UUID. If the quota already exists its limits are replaced.
+// Only JIMM administrators are allowed to set quotas.
+func (j *JIMM) SetQuota(ctx context.Context, user *openfga.User, q *dbmodel.Quota) error {
+	const op = errors.Op("jimm.SetQuota")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	subject, err := j.resolveQuotaSubject(ctx, q.Subject)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	q.Subject = subject
+
+	cloud := dbmodel.Cloud{
+		Name: q.CloudName,
+	}
+	if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+		return errors.E(op, err)
+	}
+	if q.Region != "" {
+		found := false
+		for _, r := range cloud.Regions {
+			if r.Name == q.Region {
+				found = true
+			}
+		}
+		if !found {
+			return errors.E(op, errors.CodeNotFound, "region not found")
+		}
+	}
+
+	if err := j.Database.SetQuota(ctx, q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveQuota removes the quota for the given subject on the given cloud
+// and region. Only JIMM administrators are allowed to remove quotas.
+func (j *JIMM) RemoveQuota(ctx context.Context, user *openfga.User, subject string, cloud names.CloudTag, region string) error {
+	const op = errors.Op("jimm.RemoveQuota")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	s, err := j.resolveQuotaSubject(ctx, subject)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	q := dbmodel.Quota{
+		Subject:   s,
+		CloudName: cloud.Id(),
+		Region:    region,
+	}
+	if err := j.Database.DeleteQuota(ctx, &q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListQuotas returns all quotas known to JIMM along with their current
+// usage. Only JIMM administrators are allowed to list quotas.
+func (j *JIMM) ListQuotas(ctx context.Context, user *openfga.User) ([]QuotaStatus, error) {
+	const op = errors.Op("jimm.ListQuotas")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var quotas []dbmodel.Quota
+	err := j.Database.ForEachQuota(ctx, func(q *dbmodel.Quota) error {
+		quotas = append(quotas, *q)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	statuses := make([]QuotaStatus, len(quotas))
+	for i, q := range quotas {
+		owners, err := j.quotaSubjectIdentities(ctx, q.Subject)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		usage, err := j.Database.GetQuotaUsage(ctx, owners, q.CloudName, q.Region)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		statuses[i] = QuotaStatus{
+			Quota: q,
+			Usage: usage,
+		}
+	}
+	return statuses, nil
+}
+
+// QuotaSubjectTag returns the JAAS representation of the subject of the
+// given quota, resolving group UUIDs to group names.
+func (j *JIMM) QuotaSubjectTag(ctx context.Context, q *dbmodel.Quota) (string, error) {
+	kind, id, _ := strings.Cut(q.Subject, "-")
+	return j.ToJAASTag(ctx, &ofganames.Tag{Kind: openfga.Kind(kind), ID: id}, true)
+}
+
+// checkModelQuotas checks whether adding a new model for the given owner
+// in the given cloud region would exceed any of the quotas that apply to
+// the owner. If a quota would be exceeded an error with a code of
+// CodeQuotaLimitExceeded is returned.
+//
+// The machine, core and unit usage is that last reported by the watcher,
+// and is only checked here, so it may grow beyond the limits after the
+// model is created. The check is not atomic with the creation of the
+// model, so concurrent calls for the same owner may all succeed.
+func (j *JIMM) checkModelQuotas(ctx context.Context, owner *dbmodel.Identity, cloudName, region string) error {
+	const op = errors.Op("jimm.checkModelQuotas")
+
+	quotas, err := j.Database.GetCloudQuotas(ctx, cloudName)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	ownerSubject := owner.ResourceTag().String()
+	for _, q := range quotas {
+		if q.Region != "" && q.Region != region {
+			continue
+		}
+		var owners []string
+		switch {
+		case q.Subject == ownerSubject:
+			owners = []string{owner.Name}
+		case strings.HasPrefix(q.Subject, jimmnames.GroupTagKind+"-"):
+			isMember, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+				Object:   ofganames.ConvertTag(owner.ResourceTag()),
+				Relation: ofganames.MemberRelation,
+				Target:   &ofganames.Tag{Kind: openfga.GroupType, ID: strings.TrimPrefix(q.Subject, jimmnames.GroupTagKind+"-")},
+			}, false)
+			if err != nil {
+				return errors.E(op, err)
+			}
+			if !isMember {
+				continue
+			}
+			owners, err = j.quotaSubjectIdentities(ctx, q.Subject)
+			if err != nil {
+				return errors.E(op, err)
+			}
+		default:
+			continue
+		}
+
+		usage, err := j.Database.GetQuotaUsage(ctx, owners, q.CloudName, q.Region)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if limit := q.Exceeded(usage); limit != "" {
+			subject, err := j.QuotaSubjectTag(ctx, &q)
+			if err != nil {
+				zapctx.Error(ctx, "failed to resolve quota subject", zap.Error(err), zap.String("subject", q.Subject))
+				subject = q.Subject
+			}
+			return errors.E(op, errors.CodeQuotaLimitExceeded, fmt.Sprintf("%s quota for %s on cloud %q exceeded", limit, subject, q.CloudName))
+		}
+	}
+	return nil
+}
+
+// resolveQuotaSubject converts the given user or group tag into the form
+// stored in the database.
+func (j *JIMM) resolveQuotaSubject(ctx context.Context, subject string) (string, error) {
+	tag, err := j.ParseTag(ctx, subject)
+	if err != nil {
+		return "", err
+	}
+	if tag.Relation != "" {
+		return "", errors.E(errors.CodeBadRequest, "quota subject cannot specify a relation")
+	}
+	switch tag.Kind {
+	case openfga.UserType, openfga.GroupType:
+		return tag.Kind.String() + "-" + tag.ID, nil
+	default:
+		return "", errors.E(errors.CodeBadRequest, fmt.Sprintf("quota subject must be a user or group, not %s", tag.Kind))
+	}
+}
+
+// quotaSubjectIdentities returns the names of all identities whose models
+// count towards a quota with the given subject.
+func (j *JIMM) quotaSubjectIdentities(ctx context.Context, subject string) ([]string, error) {
+	kind, id, _ := strings.Cut(subject, "-")
+	if kind != jimmnames.GroupTagKind {
+		return []string{id}, nil
+	}
+	members, err := openfga.ListUsersWithAccess(ctx, j.OpenFGAClient, jimmnames.NewGroupTag(id), ofganames.MemberRelation)
+	if err != nil {
+		return nil, errors.E(err)
+	}
+	owners := make([]string, 0, len(members))
+	for _, m := range members {
+		if m.Name == ofganames.EveryoneUser {
+			continue
+		}
+		owners = append(owners, m.Name)
+	}
+	return owners, nil
+}
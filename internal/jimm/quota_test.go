@@ -0,0 +1,310 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+const quotaTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  - name: test-region-2
+  users:
+  - user: alice@canonical.com
+    access: add-model
+  - user: bob@canonical.com
+    access: add-model
+cloud-credentials:
+- name: cred-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+- name: cred-1
+  owner: bob@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 1
+  - cloud: test-cloud
+    region: test-region-2
+    priority: 1
+models:
+- name: model-1
+  owner: alice@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-region-1
+  cloud-credential: cred-1
+  life: alive
+  machines: 2
+  cores: 4
+  units: 3
+- name: model-2
+  owner: bob@canonical.com
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-region-2
+  cloud-credential: cred-1
+  life: alive
+  machines: 1
+  cores: 1
+  units: 1
+`
+
+func setupQuotaTest(c *qt.C) (*jimm.JIMM, *jimmtest.Environment, *openfga.OFGAClient) {
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+					return nil
+				},
+				CreateModel_: createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+`[1:]),
+			},
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, quotaTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+	return j, env, client
+}
+
+func TestSetQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupQuotaTest(c)
+
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	err := j.SetQuota(ctx, openfga.NewUser(&bob, client), &dbmodel.Quota{
+		Subject:   "user-bob@canonical.com",
+		CloudName: "test-cloud",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	group, err := j.AddGroup(ctx, u, "test-group")
+	c.Assert(err, qt.IsNil)
+
+	q := dbmodel.Quota{
+		Subject:   "group-test-group",
+		CloudName: "test-cloud",
+		Region:    "test-region-1",
+		MaxModels: sql.NullInt64{Int64: 3, Valid: true},
+	}
+	err = j.SetQuota(ctx, u, &q)
+	c.Assert(err, qt.IsNil)
+	c.Check(q.Subject, qt.Equals, group.ResourceTag().String())
+
+	err = j.SetQuota(ctx, u, &dbmodel.Quota{
+		Subject:   "group-test-group",
+		CloudName: "test-cloud",
+		Region:    "no-such-region",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.SetQuota(ctx, u, &dbmodel.Quota{
+		Subject:   "cloud-test-cloud",
+		CloudName: "test-cloud",
+	})
+	c.Check(err, qt.ErrorMatches, `quota subject must be a user or group, not cloud`)
+
+	err = j.RemoveQuota(ctx, u, "group-test-group", names.NewCloudTag("test-cloud"), "test-region-1")
+	c.Assert(err, qt.IsNil)
+
+	err = j.RemoveQuota(ctx, u, "group-test-group", names.NewCloudTag("test-cloud"), "test-region-1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestListQuotas(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupQuotaTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	group, err := j.AddGroup(ctx, u, "test-group")
+	c.Assert(err, qt.IsNil)
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.SetQuota(ctx, u, &dbmodel.Quota{
+		Subject:     "user-alice@canonical.com",
+		CloudName:   "test-cloud",
+		MaxMachines: sql.NullInt64{Int64: 10, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.SetQuota(ctx, u, &dbmodel.Quota{
+		Subject:   "group-test-group",
+		CloudName: "test-cloud",
+		MaxModels: sql.NullInt64{Int64: 10, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	quotas, err := j.ListQuotas(ctx, u)
+	c.Assert(err, qt.IsNil)
+	c.Assert(quotas, qt.HasLen, 2)
+	c.Check(quotas[0].Quota.Subject, qt.Equals, "user-alice@canonical.com")
+	c.Check(quotas[0].Usage, qt.DeepEquals, dbmodel.QuotaUsage{Models: 1, Machines: 2, Cores: 4, Units: 3})
+	c.Check(quotas[1].Quota.Subject, qt.Equals, group.ResourceTag().String())
+	c.Check(quotas[1].Usage, qt.DeepEquals, dbmodel.QuotaUsage{Models: 2, Machines: 3, Cores: 5, Units: 4})
+
+	subject, err := j.QuotaSubjectTag(ctx, &quotas[1].Quota)
+	c.Assert(err, qt.IsNil)
+	c.Check(subject, qt.Equals, "group-test-group")
+
+	_, err = j.ListQuotas(ctx, openfga.NewUser(&bob, client))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestAddModelQuotaExceeded(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupQuotaTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	admin := openfga.NewUser(&alice, client)
+	admin.JimmAdmin = true
+
+	err := j.SetQuota(ctx, admin, &dbmodel.Quota{
+		Subject:     "user-alice@canonical.com",
+		CloudName:   "test-cloud",
+		Region:      "test-region-1",
+		MaxMachines: sql.NullInt64{Int64: 2, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AddModel(ctx, openfga.NewUser(&alice, client), &args)
+	c.Assert(err, qt.ErrorMatches, `machines quota for user-alice@canonical.com on cloud "test-cloud" exceeded`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+
+	// The quota only applies to test-region-1.
+	args.CloudRegion = "test-region-2"
+	_, err = j.AddModel(ctx, openfga.NewUser(&alice, client), &args)
+	c.Assert(err, qt.IsNil)
+}
+
+func TestAddModelGroupQuotaExceeded(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j, env, client := setupQuotaTest(c)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	admin := openfga.NewUser(&alice, client)
+	admin.JimmAdmin = true
+
+	group, err := j.AddGroup(ctx, admin, "test-group")
+	c.Assert(err, qt.IsNil)
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.SetQuota(ctx, admin, &dbmodel.Quota{
+		Subject:   "group-test-group",
+		CloudName: "test-cloud",
+		MaxModels: sql.NullInt64{Int64: 2, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("bob@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AddModel(ctx, openfga.NewUser(&bob, client), &args)
+	c.Assert(err, qt.ErrorMatches, `models quota for group-test-group on cloud "test-cloud" exceeded`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+
+	// Removing the group removes its quotas.
+	err = j.RemoveGroup(ctx, admin, "test-group")
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AddModel(ctx, openfga.NewUser(&bob, client), &args)
+	c.Assert(err, qt.IsNil)
+}
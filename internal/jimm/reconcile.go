@@ -0,0 +1,452 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// The kinds of discrepancy found by the reconciler.
+const (
+	// DiscrepancyModelNotOnController is reported for models JIMM
+	// believes are running on a controller that the controller does
+	// not know about. Fixing removes the model from JIMM.
+	DiscrepancyModelNotOnController = "model-not-on-controller"
+
+	// DiscrepancyModelNotInJIMM is reported for models running on a
+	// controller that are unknown to JIMM. Fixing imports the model.
+	DiscrepancyModelNotInJIMM = "model-not-in-jimm"
+
+	// DiscrepancyModelRelationMissing is reported for models that
+	// have no controller relation in OpenFGA. Fixing adds the relation.
+	DiscrepancyModelRelationMissing = "model-relation-missing"
+
+	// DiscrepancyOfferNotOnController is reported for application
+	// offers JIMM knows about that the controller does not. Fixing
+	// removes the offer from JIMM.
+	DiscrepancyOfferNotOnController = "offer-not-on-controller"
+
+	// DiscrepancyOfferNotInJIMM is reported for application offers
+	// created on the controller outside of JIMM. Fixing adds the offer
+	// to JIMM.
+	DiscrepancyOfferNotInJIMM = "offer-not-in-jimm"
+
+	// DiscrepancyOfferRelationMissing is reported for application
+	// offers that have no model relation in OpenFGA. Fixing adds the
+	// relation.
+	DiscrepancyOfferRelationMissing = "offer-relation-missing"
+
+	// DiscrepancyCloudNotOnController is reported for clouds JIMM
+	// believes are available on a controller that the controller does
+	// not know about. These cannot be fixed automatically.
+	DiscrepancyCloudNotOnController = "cloud-not-on-controller"
+
+	// DiscrepancyCloudNotInJIMM is reported for clouds added to a
+	// controller outside of JIMM. Fixing adds the cloud to JIMM.
+	DiscrepancyCloudNotInJIMM = "cloud-not-in-jimm"
+
+	// DiscrepancyCloudRelationMissing is reported for clouds available
+	// on a controller that have no controller relation in OpenFGA.
+	// Fixing adds the relation.
+	DiscrepancyCloudRelationMissing = "cloud-relation-missing"
+)
+
+// A Discrepancy is a difference between the state of a controller held
+// by JIMM and the state reported by the controller itself.
+type Discrepancy struct {
+	// Kind is the kind of discrepancy.
+	Kind string
+
+	// Tag is the tag of the entity the discrepancy applies to.
+	Tag names.Tag
+
+	// Description describes the discrepancy.
+	Description string
+
+	// Fixed is true if the discrepancy has been fixed.
+	Fixed bool
+
+	// FixErr is the error encountered attempting to fix the
+	// discrepancy, if any.
+	FixErr error
+}
+
+// A ReconcileReport holds the discrepancies found on a single
+// controller.
+type ReconcileReport struct {
+	// Controller is the name of the controller.
+	Controller string
+
+	// Err is the error that prevented the controller from being
+	// reconciled, if any.
+	Err error
+
+	// Discrepancies are the discrepancies found on the controller.
+	Discrepancies []Discrepancy
+}
+
+// Reconcile compares the models, application offers and clouds JIMM
+// holds for the named controller, both in the database and in OpenFGA,
+// with those that the controller reports. If controllerName is empty
+// every controller is reconciled. If fix is true JIMM attempts to
+// resolve any discrepancy found by bringing its own state in line with
+// the controller's. Only JIMM administrators may reconcile controllers.
+// A failure to reconcile a controller is reported in the controller's
+// report rather than stopping the reconciliation.
+func (j *JIMM) Reconcile(ctx context.Context, user *openfga.User, controllerName string, fix bool) ([]ReconcileReport, error) {
+	const op = errors.Op("jimm.Reconcile")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, err
+	}
+
+	var controllers []*dbmodel.Controller
+	if controllerName != "" {
+		ctl, err := j.getControllerByName(ctx, controllerName)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		controllers = append(controllers, ctl)
+	} else {
+		err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			controllers = append(controllers, ctl)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+
+	reports := make([]ReconcileReport, len(controllers))
+	for i, ctl := range controllers {
+		reports[i].Controller = ctl.Name
+		if controllerName == "" {
+			// ForEachController does not fetch the cloud regions.
+			if err := j.Database.GetController(ctx, ctl); err != nil {
+				reports[i].Err = err
+				continue
+			}
+		}
+		r := reconciler{
+			jimm:       j,
+			controller: ctl,
+			fix:        fix,
+			report:     &reports[i],
+		}
+		if err := r.run(ctx); err != nil {
+			zapctx.Error(ctx, "failed to reconcile controller", zap.String("controller", ctl.Name), zap.Error(err))
+			reports[i].Err = err
+		}
+	}
+	return reports, nil
+}
+
+// A reconciler reconciles the state of a single controller.
+type reconciler struct {
+	jimm       *JIMM
+	controller *dbmodel.Controller
+	api        API
+	fix        bool
+	report     *ReconcileReport
+}
+
+// run reconciles the controller, adding any discrepancies found to the
+// report.
+func (r *reconciler) run(ctx context.Context) error {
+	api, err := r.jimm.dialController(ctx, r.controller)
+	if err != nil {
+		return errors.E("failed to dial the controller", err)
+	}
+	defer api.Close()
+	r.api = api
+
+	if err := r.reconcileClouds(ctx); err != nil {
+		return err
+	}
+	return r.reconcileModels(ctx)
+}
+
+// addDiscrepancy adds a discrepancy to the report. If the reconciler is
+// fixing discrepancies and fixf is not nil, fixf is called to fix the
+// discrepancy.
+func (r *reconciler) addDiscrepancy(ctx context.Context, kind string, tag names.Tag, description string, fixf func() error) {
+	d := Discrepancy{
+		Kind:        kind,
+		Tag:         tag,
+		Description: description,
+	}
+	if r.fix && fixf != nil {
+		if err := fixf(); err != nil {
+			zapctx.Error(ctx, "failed to fix discrepancy", zap.String("kind", kind), zap.String("tag", tag.String()), zap.Error(err))
+			d.FixErr = err
+		} else {
+			d.Fixed = true
+		}
+	}
+	r.report.Discrepancies = append(r.report.Discrepancies, d)
+}
+
+// hasRelation returns whether the given tuple exists in OpenFGA.
+func (r *reconciler) hasRelation(ctx context.Context, object *ofganames.Tag, relation openfga.Relation, target *ofganames.Tag) (bool, error) {
+	return r.jimm.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   object,
+		Relation: relation,
+		Target:   target,
+	}, false)
+}
+
+// reconcileClouds compares the clouds available on the controller with
+// those JIMM associates with the controller.
+func (r *reconciler) reconcileClouds(ctx context.Context) error {
+	clouds, err := r.api.Clouds(ctx)
+	if err != nil {
+		return errors.E("failed to fetch controller clouds", err)
+	}
+	tags := make([]names.CloudTag, 0, len(clouds))
+	for tag := range clouds {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, k int) bool { return tags[i].Id() < tags[k].Id() })
+
+	known := make(map[string]bool)
+	for _, cr := range r.controller.CloudRegions {
+		known[cr.CloudRegion.Cloud.Name] = true
+	}
+
+	for _, tag := range tags {
+		if !known[tag.Id()] {
+			cloud := dbmodel.Cloud{
+				Name: tag.Id(),
+			}
+			err := r.jimm.Database.GetCloud(ctx, &cloud)
+			switch {
+			case errors.ErrorCode(err) == errors.CodeNotFound:
+				jujuCloud := clouds[tag]
+				r.addDiscrepancy(ctx, DiscrepancyCloudNotInJIMM, tag, fmt.Sprintf("cloud %q is not known to JIMM", tag.Id()), func() error {
+					return r.addCloud(ctx, tag, jujuCloud)
+				})
+				continue
+			case err != nil:
+				return err
+			}
+			// The cloud is known to JIMM, but hosted on other
+			// controllers. Changing where a cloud is hosted is
+			// left to the administrator.
+			continue
+		}
+		ok, err := r.hasRelation(ctx, ofganames.ConvertTag(r.controller.ResourceTag()), ofganames.ControllerRelation, ofganames.ConvertTag(tag))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			r.addDiscrepancy(ctx, DiscrepancyCloudRelationMissing, tag, fmt.Sprintf("cloud %q has no controller relation", tag.Id()), func() error {
+				return r.jimm.OpenFGAClient.AddCloudController(ctx, tag, r.controller.ResourceTag())
+			})
+		}
+	}
+
+	var missing []string
+	for name := range known {
+		if _, ok := clouds[names.NewCloudTag(name)]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		r.addDiscrepancy(ctx, DiscrepancyCloudNotOnController, names.NewCloudTag(name), fmt.Sprintf("cloud %q is not available on the controller", name), nil)
+	}
+	return nil
+}
+
+// addCloud adds the given cloud, found on the controller, to JIMM.
+func (r *reconciler) addCloud(ctx context.Context, tag names.CloudTag, jujuCloud jujuparams.Cloud) error {
+	var cloud dbmodel.Cloud
+	cloud.FromJujuCloud(jujuCloud)
+	cloud.Name = tag.Id()
+	for i := range cloud.Regions {
+		cloud.Regions[i].Controllers = []dbmodel.CloudRegionControllerPriority{{
+			ControllerID: r.controller.ID,
+			Priority:     dbmodel.CloudRegionControllerPrioritySupported,
+		}}
+	}
+	if err := r.jimm.Database.AddCloud(ctx, &cloud); err != nil {
+		return err
+	}
+	return r.jimm.OpenFGAClient.AddCloudController(ctx, tag, r.controller.ResourceTag())
+}
+
+// reconcileModels compares the models running on the controller, and
+// their application offers, with those JIMM holds for the controller.
+func (r *reconciler) reconcileModels(ctx context.Context) error {
+	summaries, err := r.api.AllModelSummaries(ctx)
+	if err != nil {
+		return errors.E("failed to fetch controller models", err)
+	}
+	running := make(map[string]jujuparams.ModelSummary)
+	for _, ms := range summaries {
+		if ms.IsController {
+			continue
+		}
+		running[ms.UUID] = ms
+	}
+
+	models, err := r.jimm.Database.GetModelsByController(ctx, *r.controller)
+	if err != nil {
+		return err
+	}
+	sort.Slice(models, func(i, k int) bool { return models[i].UUID.String < models[k].UUID.String })
+
+	known := make(map[string]bool)
+	for i := range models {
+		m := &models[i]
+		if !m.UUID.Valid {
+			// The model is still being created on the
+			// controller.
+			continue
+		}
+		known[m.UUID.String] = true
+		ms, ok := running[m.UUID.String]
+		if !ok {
+			if m.MigrationControllerID.Valid {
+				// The model is being migrated, the watcher
+				// will update it once the migration completes.
+				continue
+			}
+			if m.Life == state.Dying.String() || m.Life == state.Dead.String() {
+				// The model is being destroyed, the watcher
+				// will remove it once it leaves the controller.
+				continue
+			}
+			r.addDiscrepancy(ctx, DiscrepancyModelNotOnController, m.ResourceTag(), fmt.Sprintf("model %q is not running on the controller", m.Name), func() error {
+				return r.removeModel(ctx, m)
+			})
+			continue
+		}
+		ok, err := r.hasRelation(ctx, ofganames.ConvertTag(r.controller.ResourceTag()), ofganames.ControllerRelation, ofganames.ConvertTag(m.ResourceTag()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			r.addDiscrepancy(ctx, DiscrepancyModelRelationMissing, m.ResourceTag(), fmt.Sprintf("model %q has no controller relation", m.Name), func() error {
+				return r.jimm.OpenFGAClient.AddControllerModel(ctx, r.controller.ResourceTag(), m.ResourceTag())
+			})
+		}
+		if err := r.reconcileOffers(ctx, m, ms); err != nil {
+			return err
+		}
+	}
+
+	for _, ms := range summaries {
+		if ms.IsController || known[ms.UUID] {
+			continue
+		}
+		// The model may have been moved to this controller from
+		// another controller known to JIMM.
+		m := dbmodel.Model{
+			UUID: sql.NullString{
+				String: ms.UUID,
+				Valid:  true,
+			},
+		}
+		err := r.jimm.Database.GetModel(ctx, &m)
+		if err == nil {
+			continue
+		} else if errors.ErrorCode(err) != errors.CodeNotFound {
+			return err
+		}
+		tag := names.NewModelTag(ms.UUID)
+		r.addDiscrepancy(ctx, DiscrepancyModelNotInJIMM, tag, fmt.Sprintf("model %q is not known to JIMM", ms.Name), func() error {
+			return r.jimm.importModel(ctx, r.controller, r.api, tag, "")
+		})
+	}
+	return nil
+}
+
+// removeModel removes the given model, which is no longer running on
+// the controller, from JIMM.
+func (r *reconciler) removeModel(ctx context.Context, m *dbmodel.Model) error {
+	if err := r.jimm.Database.DeleteModel(ctx, m); err != nil {
+		return err
+	}
+	return r.jimm.OpenFGAClient.RemoveModel(ctx, m.ResourceTag())
+}
+
+// reconcileOffers compares the application offers of the given model
+// reported by the controller with those held by JIMM.
+func (r *reconciler) reconcileOffers(ctx context.Context, m *dbmodel.Model, ms jujuparams.ModelSummary) error {
+	// Fetch the model's application offers.
+	if err := r.jimm.Database.GetModel(ctx, m); err != nil {
+		return err
+	}
+	owner, err := names.ParseUserTag(ms.OwnerTag)
+	if err != nil {
+		return err
+	}
+	offers, err := r.api.ListApplicationOffers(ctx, []jujuparams.OfferFilter{{
+		OwnerName: owner.Id(),
+		ModelName: ms.Name,
+	}})
+	if err != nil {
+		return errors.E(fmt.Sprintf("failed to fetch application offers of model %q", m.Name), err)
+	}
+	running := make(map[string]bool)
+	for _, offer := range offers {
+		running[offer.OfferUUID] = true
+	}
+
+	known := make(map[string]bool)
+	for i := range m.Offers {
+		offer := &m.Offers[i]
+		known[offer.UUID] = true
+		if !running[offer.UUID] {
+			r.addDiscrepancy(ctx, DiscrepancyOfferNotOnController, offer.ResourceTag(), fmt.Sprintf("application offer %q is not on the controller", offer.URL), func() error {
+				if err := r.jimm.Database.DeleteApplicationOffer(ctx, offer); err != nil {
+					return err
+				}
+				return r.jimm.OpenFGAClient.RemoveApplicationOffer(ctx, offer.ResourceTag())
+			})
+			continue
+		}
+		ok, err := r.hasRelation(ctx, ofganames.ConvertTag(m.ResourceTag()), ofganames.ModelRelation, ofganames.ConvertTag(offer.ResourceTag()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			r.addDiscrepancy(ctx, DiscrepancyOfferRelationMissing, offer.ResourceTag(), fmt.Sprintf("application offer %q has no model relation", offer.URL), func() error {
+				return r.jimm.OpenFGAClient.AddModelApplicationOffer(ctx, m.ResourceTag(), offer.ResourceTag())
+			})
+		}
+	}
+
+	for _, details := range offers {
+		if known[details.OfferUUID] {
+			continue
+		}
+		details := details
+		tag := names.NewApplicationOfferTag(details.OfferUUID)
+		r.addDiscrepancy(ctx, DiscrepancyOfferNotInJIMM, tag, fmt.Sprintf("application offer %q is not known to JIMM", details.OfferURL), func() error {
+			var offer dbmodel.ApplicationOffer
+			offer.FromJujuApplicationOfferAdminDetailsV5(details)
+			offer.ModelID = m.ID
+			if err := r.jimm.Database.AddApplicationOffer(ctx, &offer); err != nil {
+				return err
+			}
+			return r.jimm.OpenFGAClient.AddModelApplicationOffer(ctx, m.ResourceTag(), offer.ResourceTag())
+		})
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+const testReconcileEnv = `
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: bob@canonical.com
+clouds:
+- name: test-cloud
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-credential
+  cloud: test-cloud
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: test-controller
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region
+    priority: 1
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: test-controller
+  cloud: test-cloud
+  region: test-region
+  cloud-credential: test-credential
+  owner: alice@canonical.com
+  life: alive
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: test-controller
+  cloud: test-cloud
+  region: test-region
+  cloud-credential: test-credential
+  owner: alice@canonical.com
+  life: alive
+`
+
+func TestReconcile(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	api := &jimmtest.API{
+		Clouds_: func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error) {
+			return map[names.CloudTag]jujuparams.Cloud{
+				names.NewCloudTag("test-cloud"): {
+					Type:    "test",
+					Regions: []jujuparams.CloudRegion{{Name: "test-region"}},
+				},
+				names.NewCloudTag("other-cloud"): {
+					Type:    "test",
+					Regions: []jujuparams.CloudRegion{{Name: "other-region"}},
+				},
+			}, nil
+		},
+		AllModelSummaries_: func(context.Context) ([]jujuparams.ModelSummary, error) {
+			return []jujuparams.ModelSummary{{
+				Name:         "controller",
+				UUID:         "00000002-0000-0000-0000-000000000000",
+				OwnerTag:     names.NewUserTag("admin").String(),
+				IsController: true,
+			}, {
+				Name:     "model-1",
+				UUID:     "00000002-0000-0000-0000-000000000001",
+				OwnerTag: names.NewUserTag("alice@canonical.com").String(),
+			}, {
+				Name:     "model-3",
+				UUID:     "00000002-0000-0000-0000-000000000003",
+				OwnerTag: names.NewUserTag("alice@canonical.com").String(),
+			}}, nil
+		},
+		ListApplicationOffers_: func(_ context.Context, filters []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
+			if len(filters) != 1 || filters[0].ModelName != "model-1" || filters[0].OwnerName != "alice@canonical.com" {
+				return nil, errors.E("unexpected filter")
+			}
+			return []jujuparams.ApplicationOfferAdminDetailsV5{{
+				ApplicationOfferDetailsV5: jujuparams.ApplicationOfferDetailsV5{
+					SourceModelTag: names.NewModelTag("00000002-0000-0000-0000-000000000001").String(),
+					OfferUUID:      "00000010-0000-0000-0000-000000000002",
+					OfferURL:       "test-controller:alice@canonical.com/model-1.offer-2",
+					OfferName:      "offer-2",
+				},
+				ApplicationName: "app-2",
+			}}, nil
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, testReconcileEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	model1 := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	model2 := env.Model("alice@canonical.com", "model-2").DBObject(c, j.Database)
+	ctl := env.Controller("test-controller").DBObject(c, j.Database)
+
+	offer1 := dbmodel.ApplicationOffer{
+		ModelID:         model1.ID,
+		Name:            "offer-1",
+		UUID:            "00000010-0000-0000-0000-000000000001",
+		URL:             "test-controller:alice@canonical.com/model-1.offer-1",
+		ApplicationName: "app-1",
+	}
+	err = j.Database.AddApplicationOffer(ctx, &offer1)
+	c.Assert(err, qt.IsNil)
+
+	modelRelation := openfga.Tuple{
+		Object:   ofganames.ConvertTag(ctl.ResourceTag()),
+		Relation: ofganames.ControllerRelation,
+		Target:   ofganames.ConvertTag(model1.ResourceTag()),
+	}
+	err = client.RemoveRelation(ctx, modelRelation)
+	c.Assert(err, qt.IsNil)
+
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	_, err = j.Reconcile(ctx, openfga.NewUser(&bob, client), "", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	expected := []jimm.Discrepancy{{
+		Kind:        jimm.DiscrepancyCloudNotInJIMM,
+		Tag:         names.NewCloudTag("other-cloud"),
+		Description: `cloud "other-cloud" is not known to JIMM`,
+	}, {
+		Kind:        jimm.DiscrepancyModelRelationMissing,
+		Tag:         model1.ResourceTag(),
+		Description: `model "model-1" has no controller relation`,
+	}, {
+		Kind:        jimm.DiscrepancyOfferNotOnController,
+		Tag:         offer1.ResourceTag(),
+		Description: `application offer "test-controller:alice@canonical.com/model-1.offer-1" is not on the controller`,
+	}, {
+		Kind:        jimm.DiscrepancyOfferNotInJIMM,
+		Tag:         names.NewApplicationOfferTag("00000010-0000-0000-0000-000000000002"),
+		Description: `application offer "test-controller:alice@canonical.com/model-1.offer-2" is not known to JIMM`,
+	}, {
+		Kind:        jimm.DiscrepancyModelNotOnController,
+		Tag:         model2.ResourceTag(),
+		Description: `model "model-2" is not running on the controller`,
+	}, {
+		Kind:        jimm.DiscrepancyModelNotInJIMM,
+		Tag:         names.NewModelTag("00000002-0000-0000-0000-000000000003"),
+		Description: `model "model-3" is not known to JIMM`,
+	}}
+
+	reports, err := j.Reconcile(ctx, u, "", false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reports, qt.HasLen, 1)
+	c.Check(reports[0].Controller, qt.Equals, "test-controller")
+	c.Check(reports[0].Err, qt.IsNil)
+	c.Check(reports[0].Discrepancies, qt.DeepEquals, expected)
+
+	reports, err = j.Reconcile(ctx, u, "test-controller", true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reports, qt.HasLen, 1)
+	c.Assert(reports[0].Discrepancies, qt.HasLen, len(expected))
+	for i, d := range reports[0].Discrepancies[:len(expected)-1] {
+		c.Check(d.FixErr, qt.IsNil, qt.Commentf("%s", expected[i].Kind))
+		c.Check(d.Fixed, qt.IsTrue, qt.Commentf("%s", expected[i].Kind))
+	}
+	// The model cannot be imported as the controller does not
+	// return any model information.
+	c.Check(reports[0].Discrepancies[len(expected)-1].Fixed, qt.IsFalse)
+	c.Check(reports[0].Discrepancies[len(expected)-1].FixErr, qt.Not(qt.IsNil))
+
+	ok, err := client.CheckRelation(ctx, modelRelation, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+
+	cloud := dbmodel.Cloud{Name: "other-cloud"}
+	err = j.Database.GetCloud(ctx, &cloud)
+	c.Assert(err, qt.IsNil)
+
+	err = j.Database.GetApplicationOffer(ctx, &offer1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	offer2 := dbmodel.ApplicationOffer{UUID: "00000010-0000-0000-0000-000000000002"}
+	err = j.Database.GetApplicationOffer(ctx, &offer2)
+	c.Assert(err, qt.IsNil)
+	c.Check(offer2.ModelID, qt.Equals, model1.ID)
+
+	m := dbmodel.Model{UUID: sql.NullString{String: model2.UUID.String, Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	reports, err = j.Reconcile(ctx, u, "test-controller", false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reports, qt.HasLen, 1)
+	c.Check(reports[0].Discrepancies, qt.DeepEquals, expected[len(expected)-1:])
+}
+
+const testReconcileTransientModelsEnv = `
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+clouds:
+- name: test-cloud
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-credential
+  cloud: test-cloud
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: test-controller
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region
+models:
+- name: model-1
+  uuid: %s
+  controller: test-controller
+  cloud: test-cloud
+  region: test-region
+  cloud-credential: test-credential
+  owner: alice@canonical.com
+  life: %s
+`
+
+// reconcileTransientModel reconciles a controller that is not running
+// the single model in the environment, which has the given UUID and
+// life. The discrepancies found are returned.
+func reconcileTransientModel(c *qt.C, modelUUID, life string) []jimm.Discrepancy {
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				Clouds_: func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error) {
+					return map[names.CloudTag]jujuparams.Cloud{
+						names.NewCloudTag("test-cloud"): {
+							Type:    "test",
+							Regions: []jujuparams.CloudRegion{{Name: "test-region"}},
+						},
+					}, nil
+				},
+				AllModelSummaries_: func(context.Context) ([]jujuparams.ModelSummary, error) {
+					return nil, nil
+				},
+			},
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// Only the database is populated as a model without a UUID
+	// cannot have any relations.
+	env := jimmtest.ParseEnvironment(c, fmt.Sprintf(testReconcileTransientModelsEnv, modelUUID, life))
+	env.PopulateDB(c, j.Database)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	u := openfga.NewUser(&alice, client)
+	u.JimmAdmin = true
+
+	reports, err := j.Reconcile(ctx, u, "test-controller", true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reports, qt.HasLen, 1)
+	c.Check(reports[0].Err, qt.IsNil)
+
+	// The model is not removed.
+	models, err := j.Database.GetModelsByController(ctx, env.Controller("test-controller").DBObject(c, j.Database))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 1)
+	return reports[0].Discrepancies
+}
+
+func TestReconcileIgnoresModelWithoutUUID(t *testing.T) {
+	c := qt.New(t)
+
+	discrepancies := reconcileTransientModel(c, `""`, "alive")
+	c.Check(discrepancies, qt.HasLen, 0)
+}
+
+func TestReconcileIgnoresDyingModel(t *testing.T) {
+	c := qt.New(t)
+
+	discrepancies := reconcileTransientModel(c, "00000002-0000-0000-0000-000000000001", "dying")
+	c.Check(discrepancies, qt.HasLen, 0)
+}
+
+func TestReconcileIgnoresDeadModel(t *testing.T) {
+	c := qt.New(t)
+
+	discrepancies := reconcileTransientModel(c, "00000002-0000-0000-0000-000000000001", "dead")
+	c.Check(discrepancies, qt.HasLen, 0)
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ExpireRelationAuditMethod is the facade method recorded in the audit
+// log when a temporary relation is revoked on expiry.
+const ExpireRelationAuditMethod = "ExpireRelation"
+
+// validateRelationExpiry checks that the given expiry time, if set, is in
+// the future.
+func validateRelationExpiry(expiresAt time.Time) error {
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return errors.E(errors.CodeBadRequest, "expiry time must be in the future")
+	}
+	return nil
+}
+
+// recordRelationExpiry records that the given tuples, which have just
+// been written to OpenFGA, are to be revoked at the given time. If the
+// expiry time is zero the tuples are permanent and any pending expiry is
+// removed instead. If the expiry cannot be recorded the tuples are
+// removed again so that a temporary grant never becomes a permanent one.
+func (j *JIMM) recordRelationExpiry(ctx context.Context, user *openfga.User, expiresAt time.Time, tuples ...openfga.Tuple) error {
+	const op = errors.Op("jimm.recordRelationExpiry")
+
+	if expiresAt.IsZero() {
+		if err := j.ClearRelationExpiries(ctx, tuples...); err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	}
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		for _, t := range tuples {
+			re := dbmodel.RelationExpiry{
+				Object:    t.Object.String(),
+				Relation:  string(t.Relation),
+				Target:    t.Target.String(),
+				ExpiresAt: expiresAt.UTC(),
+				GrantedBy: user.Name,
+			}
+			if err := tx.SetRelationExpiry(ctx, &re); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if rerr := j.OpenFGAClient.RemoveRelation(ctx, tuples...); rerr != nil {
+			zapctx.Error(ctx, "failed to remove temporary relations", zap.Error(rerr))
+		}
+		return errors.E(op, err, "failed to record relation expiry")
+	}
+	return nil
+}
+
+// ClearRelationExpiries removes any pending expiry of the given tuples.
+// It is called when the tuples are granted permanently, or revoked, so
+// that a later expiry does not remove a permanent grant.
+func (j *JIMM) ClearRelationExpiries(ctx context.Context, tuples ...openfga.Tuple) error {
+	const op = errors.Op("jimm.ClearRelationExpiries")
+
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		for _, t := range tuples {
+			re := dbmodel.RelationExpiry{
+				Object:   t.Object.String(),
+				Relation: string(t.Relation),
+				Target:   t.Target.String(),
+			}
+			if err := tx.ClearRelationExpiry(ctx, &re); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// clearUserRelationExpiries removes any pending expiry of the given
+// relations between the given user and resource.
+func (j *JIMM) clearUserRelationExpiries(ctx context.Context, u *openfga.User, resource *ofganames.Tag, relations ...openfga.Relation) error {
+	tuples := make([]openfga.Tuple, len(relations))
+	for i, relation := range relations {
+		tuples[i] = openfga.Tuple{
+			Object:   ofganames.ConvertTag(u.ResourceTag()),
+			Relation: relation,
+			Target:   resource,
+		}
+	}
+	return j.ClearRelationExpiries(ctx, tuples...)
+}
+
+// AddTemporaryRelations adds the given tuples to OpenFGA and arranges for
+// them to be revoked at the given time. Only JIMM administrators may add
+// temporary relations.
+func (j *JIMM) AddTemporaryRelations(ctx context.Context, user *openfga.User, expiresAt time.Time, tuples ...openfga.Tuple) error {
+	const op = errors.Op("jimm.AddTemporaryRelations")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if expiresAt.IsZero() {
+		return errors.E(op, errors.CodeBadRequest, "expiry time not specified")
+	}
+	if err := validateRelationExpiry(expiresAt); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.OpenFGAClient.AddRelation(ctx, tuples...); err != nil {
+		return errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	if err := j.recordRelationExpiry(ctx, user, expiresAt, tuples...); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RevokeExpiredRelations removes every temporary relation that expired at
+// or before the given time from OpenFGA. An audit log entry is written
+// for each relation revoked. Relations that cannot be revoked are logged
+// and retried on the next call.
+func (j *JIMM) RevokeExpiredRelations(ctx context.Context, now time.Time) error {
+	const op = errors.Op("jimm.RevokeExpiredRelations")
+
+	err := j.Database.ForEachRelationExpiry(ctx, now, func(re *dbmodel.RelationExpiry) error {
+		ctx := zapctx.WithFields(ctx, zap.String("object", re.Object), zap.String("relation", re.Relation), zap.String("target", re.Target))
+		if err := j.revokeExpiredRelation(ctx, re); err != nil {
+			zapctx.Error(ctx, "failed to revoke expired relation", zap.Error(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// revokeExpiredRelation removes the relation described by the given
+// expiry from OpenFGA and records the revocation in the audit log.
+func (j *JIMM) revokeExpiredRelation(ctx context.Context, re *dbmodel.RelationExpiry) error {
+	const op = errors.Op("jimm.revokeExpiredRelation")
+
+	object, err := openfga.ParseTag(re.Object)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	target, err := openfga.ParseTag(re.Target)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	err = j.OpenFGAClient.RemoveRelation(ctx, openfga.Tuple{
+		Object:   &object,
+		Relation: openfga.Relation(re.Relation),
+		Target:   &target,
+	})
+	// The relation may already have been removed by hand, in which
+	// case there is nothing to revoke.
+	// TODO we should opt to check against specific errors via checking their code/metadata.
+	if err != nil && !strings.Contains(err.Error(), "cannot delete a tuple which does not exist") {
+		return errors.E(op, err)
+	}
+	if err := j.Database.RemoveRelationExpiry(ctx, re); err != nil {
+		return errors.E(op, err)
+	}
+
+	// The expiry is recorded as performed by the identity that granted
+	// the relation.
+	var grantedBy *openfga.User
+	if names.IsValidUser(re.GrantedBy) {
+		grantedBy = openfga.NewUser(&dbmodel.Identity{Name: re.GrantedBy}, j.OpenFGAClient)
+	}
+	j.auditJIMMOperation(ctx, grantedBy, ExpireRelationAuditMethod, "", apiparams.RelationshipTuple{
+		Object:       re.Object,
+		Relation:     re.Relation,
+		TargetObject: re.Target,
+	})
+	zapctx.Info(ctx, "revoked expired relation")
+	return nil
+}
+
+// RunRelationExpiry revokes expired temporary relations every interval.
+// RunRelationExpiry finishes when the given context is canceled, or
+// there is a fatal error querying the database.
+func (j *JIMM) RunRelationExpiry(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.RunRelationExpiry")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := j.RevokeExpiredRelations(ctx, time.Now()); err != nil {
+			// Ignore temporary database errors.
+			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error revoking expired relations", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
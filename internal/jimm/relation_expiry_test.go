@@ -0,0 +1,197 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func relationExpiries(c *qt.C, j *jimm.JIMM) []dbmodel.RelationExpiry {
+	var expiries []dbmodel.RelationExpiry
+	err := j.Database.ForEachRelationExpiry(context.Background(), time.Time{}, func(re *dbmodel.RelationExpiry) error {
+		expiries = append(expiries, *re)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	return expiries
+}
+
+func TestRevokeExpiredRelations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	bobTuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob.ResourceTag()),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}
+	expiresAt := time.Now().Add(4 * time.Hour).UTC().Truncate(time.Millisecond)
+	err = j.AddTemporaryRelations(ctx, diane, expiresAt, bobTuple)
+	c.Assert(err, qt.IsNil)
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+
+	expiries := relationExpiries(c, j)
+	c.Assert(expiries, qt.HasLen, 1)
+	c.Check(expiries[0].Object, qt.Equals, "user:bob@canonical.com")
+	c.Check(expiries[0].Relation, qt.Equals, "administrator")
+	c.Check(expiries[0].Target, qt.Equals, "model:"+mt.Id())
+	c.Check(expiries[0].ExpiresAt.Equal(expiresAt), qt.IsTrue)
+	c.Check(expiries[0].GrantedBy, qt.Equals, "diane@canonical.com")
+
+	// Nothing has expired yet.
+	err = j.RevokeExpiredRelations(ctx, time.Now())
+	c.Assert(err, qt.IsNil)
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+	c.Check(relationExpiries(c, j), qt.HasLen, 1)
+
+	err = j.RevokeExpiredRelations(ctx, expiresAt)
+	c.Assert(err, qt.IsNil)
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.WriterRelation)
+	c.Check(relationExpiries(c, j), qt.HasLen, 0)
+
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: jimm.ExpireRelationAuditMethod}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+	c.Check(entries[0].FacadeName, qt.Equals, "JIMM")
+	c.Check(entries[0].IdentityTag, qt.Equals, "user-diane@canonical.com")
+	var tuple apiparams.RelationshipTuple
+	err = json.Unmarshal(entries[0].Params, &tuple)
+	c.Assert(err, qt.IsNil)
+	c.Check(tuple, qt.DeepEquals, apiparams.RelationshipTuple{
+		Object:       "user:bob@canonical.com",
+		Relation:     "administrator",
+		TargetObject: "model:" + mt.Id(),
+	})
+
+	// Granting a temporary relation permanently removes its expiry.
+	err = j.AddTemporaryRelations(ctx, diane, expiresAt, bobTuple)
+	c.Assert(err, qt.IsNil)
+	err = j.GrantModelAccess(ctx, alice, mt, bob.ResourceTag(), jujuparams.ModelAdminAccess)
+	c.Assert(err, qt.IsNil)
+	c.Check(relationExpiries(c, j), qt.HasLen, 0)
+	err = j.RevokeExpiredRelations(ctx, expiresAt)
+	c.Assert(err, qt.IsNil)
+	c.Check(bob.GetModelAccess(ctx, mt), qt.Equals, ofganames.AdministratorRelation)
+
+	// Revoking a temporary relation removes its expiry.
+	err = j.AddTemporaryRelations(ctx, diane, expiresAt, openfga.Tuple{
+		Object:   ofganames.ConvertTag(charlie.ResourceTag()),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(relationExpiries(c, j), qt.HasLen, 1)
+	err = j.RevokeModelAccess(ctx, alice, mt, charlie.ResourceTag(), jujuparams.ModelWriteAccess)
+	c.Assert(err, qt.IsNil)
+	c.Check(relationExpiries(c, j), qt.HasLen, 0)
+	c.Check(charlie.GetModelAccess(ctx, mt), qt.Equals, ofganames.ReaderRelation)
+}
+
+func TestAddTemporaryRelations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	charlieTuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	}
+	expiresAt := time.Now().Add(time.Hour)
+
+	err = j.AddTemporaryRelations(ctx, bob, expiresAt, charlieTuple)
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+	err = j.AddTemporaryRelations(ctx, diane, time.Time{}, charlieTuple)
+	c.Check(err, qt.ErrorMatches, `expiry time not specified`)
+	err = j.AddTemporaryRelations(ctx, diane, time.Now().Add(-time.Minute), charlieTuple)
+	c.Check(err, qt.ErrorMatches, `expiry time must be in the future`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.AddTemporaryRelations(ctx, diane, expiresAt, charlieTuple)
+	c.Assert(err, qt.IsNil)
+	allowed, err := client.CheckRelation(ctx, charlieTuple, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(allowed, qt.IsTrue)
+	c.Check(relationExpiries(c, j), qt.HasLen, 1)
+
+	// A relation that has already been removed is still cleared from
+	// the pending expiries.
+	err = client.RemoveRelation(ctx, charlieTuple)
+	c.Assert(err, qt.IsNil)
+	err = j.RevokeExpiredRelations(ctx, expiresAt)
+	c.Assert(err, qt.IsNil)
+	c.Check(relationExpiries(c, j), qt.HasLen, 0)
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+// relationHierarchies holds, for each kind of resource, the relations
+// defined in the authorisation model ordered from strongest to weakest.
+// Each relation implies every relation after it.
+var relationHierarchies = map[openfga.Kind][]openfga.Relation{
+	openfga.ModelType:            {ofganames.AdministratorRelation, ofganames.WriterRelation, ofganames.ReaderRelation},
+	openfga.ApplicationOfferType: {ofganames.AdministratorRelation, ofganames.ConsumerRelation, ofganames.ReaderRelation},
+	openfga.CloudType:            {ofganames.AdministratorRelation, ofganames.CanAddModelRelation},
+	openfga.ControllerType:       {ofganames.AdministratorRelation, ofganames.AuditLogViewerRelation},
+}
+
+// impliesRelation returns whether the granted relation to a resource of
+// the given kind implies the wanted relation.
+func impliesRelation(kind openfga.Kind, granted, wanted openfga.Relation) bool {
+	if granted == wanted {
+		return true
+	}
+	grantedIdx, wantedIdx := -1, -1
+	for i, r := range relationHierarchies[kind] {
+		switch r {
+		case granted:
+			grantedIdx = i
+		case wanted:
+			wantedIdx = i
+		}
+	}
+	return grantedIdx >= 0 && wantedIdx > grantedIdx
+}
+
+// An AccessStep is a single step in the chain of relations through which
+// access is granted.
+type AccessStep struct {
+	// Relation is the relation the previous entity in the chain has to
+	// the target.
+	Relation openfga.Relation
+
+	// Target is the entity the relation is to.
+	Target *openfga.Tag
+}
+
+// Steps returns the chain of relations from the identity to the resource
+// through which the access is granted.
+func (g AccessGrant) Steps() []AccessStep {
+	steps := make([]AccessStep, 0, len(g.Via)+1)
+	for _, t := range g.Via {
+		// Groups are joined by membership, only administrators are
+		// inherited from parent resources.
+		relation := ofganames.AdministratorRelation
+		if t.Kind == openfga.GroupType {
+			relation = ofganames.MemberRelation
+		}
+		steps = append(steps, AccessStep{Relation: relation, Target: t})
+	}
+	return append(steps, AccessStep{Relation: g.Relation, Target: g.Resource})
+}
+
+// A RelationExplanation explains the result of a relation check.
+type RelationExplanation struct {
+	// Allowed is the result of checking the relation with OpenFGA.
+	Allowed bool
+
+	// Grants holds each grant through which the relation is allowed.
+	// The relation granted may be stronger than the one checked.
+	Grants []AccessGrant
+}
+
+// ExplainRelation checks the given tuple and returns every chain of
+// relations through which it is allowed. The object of the tuple must be
+// a user or a group. JIMM administrators may explain any relation, other
+// users may only explain their own.
+func (j *JIMM) ExplainRelation(ctx context.Context, user *openfga.User, tuple openfga.Tuple) (*RelationExplanation, error) {
+	const op = errors.Op("jimm.ExplainRelation")
+
+	if tuple.Object == nil || tuple.Target == nil {
+		return nil, errors.E(op, errors.CodeBadRequest, "object and target must be specified")
+	}
+	userExplainingSelf := tuple.Object.Kind == openfga.UserType && tuple.Object.ID == user.Name
+	if !(user.JimmAdmin || userExplainingSelf) {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if tuple.Object.Kind != openfga.UserType && tuple.Object.Kind != openfga.GroupType {
+		return nil, errors.E(op, errors.CodeBadRequest, "object must be a user or a group")
+	}
+
+	allowed, err := j.OpenFGAClient.CheckRelation(ctx, tuple, false)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	explanation := RelationExplanation{
+		Allowed: allowed,
+	}
+	if !allowed {
+		return &explanation, nil
+	}
+
+	explanation.Grants, err = j.relationGrants(ctx, tuple)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	return &explanation, nil
+}
+
+// relationGrants returns, sorted, every grant through which the object of
+// the given tuple has the relation to the target.
+func (j *JIMM) relationGrants(ctx context.Context, tuple openfga.Tuple) ([]AccessGrant, error) {
+	r := newAccessReporter(j.OpenFGAClient)
+	grants, err := r.resourceGrants(ctx, tuple.Target)
+	if err != nil {
+		return nil, err
+	}
+	var matched []AccessGrant
+	for _, g := range grants {
+		if g.Identity.Kind != tuple.Object.Kind {
+			continue
+		}
+		if g.Identity.ID != tuple.Object.ID && !(g.Identity.Kind == openfga.UserType && g.Identity.ID == ofganames.EveryoneUser) {
+			continue
+		}
+		if !impliesRelation(tuple.Target.Kind, g.Relation, tuple.Relation) {
+			continue
+		}
+		matched = append(matched, g)
+	}
+	sortAccessGrants(matched)
+	return matched, nil
+}
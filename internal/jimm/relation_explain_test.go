@@ -0,0 +1,147 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func TestExplainRelation(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	ctlUUID := env.Controller("controller-1").DBObject(c, j.Database).UUID
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// charlie is a member of devs, which are model writers, through
+	// the nested ops group.
+	devs, err := j.Database.AddGroup(ctx, "devs")
+	c.Assert(err, qt.IsNil)
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(charlie.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(ops.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(devs.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(devs.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(mt),
+	})
+	c.Assert(err, qt.IsNil)
+
+	model := "model:" + mt.Id()
+	devsTag := "group:" + devs.UUID
+	opsTag := "group:" + ops.UUID
+	tuple := func(object *openfga.Tag, relation openfga.Relation) openfga.Tuple {
+		return openfga.Tuple{
+			Object:   object,
+			Relation: relation,
+			Target:   ofganames.ConvertTag(mt),
+		}
+	}
+
+	_, err = j.ExplainRelation(ctx, bob, tuple(ofganames.ConvertTag(charlie.ResourceTag()), ofganames.WriterRelation))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	explanation, err := j.ExplainRelation(ctx, charlie, tuple(ofganames.ConvertTag(charlie.ResourceTag()), ofganames.WriterRelation))
+	c.Assert(err, qt.IsNil)
+	c.Check(explanation.Allowed, qt.IsTrue)
+	c.Check(summariseAccessGrants(explanation.Grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:charlie@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{opsTag, devsTag},
+	}})
+	steps := explanation.Grants[0].Steps()
+	c.Assert(steps, qt.HasLen, 3)
+	c.Check(steps[0].Relation, qt.Equals, ofganames.MemberRelation)
+	c.Check(steps[0].Target.String(), qt.Equals, opsTag)
+	c.Check(steps[1].Relation, qt.Equals, ofganames.MemberRelation)
+	c.Check(steps[1].Target.String(), qt.Equals, devsTag)
+	c.Check(steps[2].Relation, qt.Equals, ofganames.WriterRelation)
+	c.Check(steps[2].Target.String(), qt.Equals, model)
+
+	// Stronger relations that imply the one checked are included.
+	explanation, err = j.ExplainRelation(ctx, diane, tuple(ofganames.ConvertTag(charlie.ResourceTag()), ofganames.ReaderRelation))
+	c.Assert(err, qt.IsNil)
+	c.Check(explanation.Allowed, qt.IsTrue)
+	c.Check(summariseAccessGrants(explanation.Grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:charlie@canonical.com",
+		Relation: "reader",
+		Resource: model,
+		Source:   jimm.AccessSourceDirect,
+	}, {
+		Identity: "user:charlie@canonical.com",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{opsTag, devsTag},
+	}})
+
+	explanation, err = j.ExplainRelation(ctx, diane, tuple(ofganames.ConvertTag(diane.ResourceTag()), ofganames.AdministratorRelation))
+	c.Assert(err, qt.IsNil)
+	c.Check(explanation.Allowed, qt.IsTrue)
+	c.Check(summariseAccessGrants(explanation.Grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: "user:diane@canonical.com",
+		Relation: "administrator",
+		Resource: model,
+		Source:   jimm.AccessSourceInherited,
+		Via:      []string{"controller:" + j.UUID, "controller:" + ctlUUID},
+	}})
+
+	explanation, err = j.ExplainRelation(ctx, diane, tuple(ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation), ofganames.WriterRelation))
+	c.Assert(err, qt.IsNil)
+	c.Check(explanation.Allowed, qt.IsTrue)
+	c.Check(summariseAccessGrants(explanation.Grants), qt.DeepEquals, []accessGrantSummary{{
+		Identity: opsTag + "#member",
+		Relation: "writer",
+		Resource: model,
+		Source:   jimm.AccessSourceGroup,
+		Via:      []string{devsTag},
+	}})
+
+	explanation, err = j.ExplainRelation(ctx, bob, tuple(ofganames.ConvertTag(bob.ResourceTag()), ofganames.AdministratorRelation))
+	c.Assert(err, qt.IsNil)
+	c.Check(explanation.Allowed, qt.IsFalse)
+	c.Check(explanation.Grants, qt.HasLen, 0)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A sessionRegistry records the sessions identities have open on a JIMM
+// server so that they can be terminated. The zero value is ready to use.
+type sessionRegistry struct {
+	mu       sync.Mutex
+	nextID   uint64
+	sessions map[string]map[uint64]func()
+}
+
+// add records a session for the named identity that is terminated by
+// calling terminate. The returned function removes the session.
+func (r *sessionRegistry) add(identityName string, terminate func()) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.sessions == nil {
+		r.sessions = make(map[string]map[uint64]func())
+	}
+	if r.sessions[identityName] == nil {
+		r.sessions[identityName] = make(map[uint64]func())
+	}
+	r.nextID++
+	id := r.nextID
+	r.sessions[identityName][id] = terminate
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.sessions[identityName], id)
+		if len(r.sessions[identityName]) == 0 {
+			delete(r.sessions, identityName)
+		}
+	}
+}
+
+// terminate terminates every session of the named identity and returns
+// the number terminated.
+func (r *sessionRegistry) terminate(identityName string) int {
+	r.mu.Lock()
+	sessions := r.sessions[identityName]
+	delete(r.sessions, identityName)
+	r.mu.Unlock()
+
+	for _, terminate := range sessions {
+		terminate()
+	}
+	return len(sessions)
+}
+
+// TrackSession records that the named identity has a session open on
+// this JIMM server, which is ended by calling terminate. Sessions are
+// terminated when the identity is disabled, immediately if it is disabled
+// through this server, otherwise by the next RunSessionCheck. The
+// returned function must be called when the session ends.
+func (j *JIMM) TrackSession(identityName string, terminate func()) (untrack func()) {
+	return j.sessions.add(identityName, terminate)
+}
+
+// TerminateDisabledSessions terminates the sessions open on this JIMM
+// server of every disabled identity. Identities may have been disabled
+// through another JIMM server, which can only terminate its own sessions.
+func (j *JIMM) TerminateDisabledSessions(ctx context.Context) error {
+	const op = errors.Op("jimm.TerminateDisabledSessions")
+
+	identities, err := j.Database.ListIdentities(ctx, db.IdentityFilter{Disabled: true})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for _, identity := range identities {
+		if n := j.sessions.terminate(identity.Name); n > 0 {
+			zapctx.Info(ctx, "terminated sessions of disabled identity", zap.String("identity", identity.Name), zap.Int("sessions", n))
+		}
+	}
+	return nil
+}
+
+// RunSessionCheck terminates the sessions of disabled identities every
+// interval. It must be run on every JIMM server. RunSessionCheck finishes
+// when the given context is canceled, or there is a fatal error querying
+// the database.
+func (j *JIMM) RunSessionCheck(ctx context.Context, interval time.Duration) error {
+	const op = errors.Op("jimm.RunSessionCheck")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := j.TerminateDisabledSessions(ctx); err != nil {
+			// Ignore temporary database errors.
+			if errors.ErrorCode(err) != errors.CodeDatabaseLocked {
+				return errors.E(op, err)
+			}
+			zapctx.Warn(ctx, "temporary error checking sessions", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
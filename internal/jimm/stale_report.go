@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// A StaleReport holds the identities that have not logged in, and the
+// models that have had no activity, since a given time.
+type StaleReport struct {
+	// Before is the time before which identities must have last logged
+	// in, and models last been active, to be reported.
+	Before time.Time
+
+	// Identities holds the inactive identities, ordered by name.
+	Identities []InactiveIdentity
+
+	// Models holds the stale models, ordered from the least recently
+	// active.
+	Models []dbmodel.Model
+}
+
+// An InactiveIdentity is an identity that has not logged in recently
+// along with the models it owns.
+type InactiveIdentity struct {
+	// Identity is the identity's database record.
+	Identity dbmodel.Identity
+
+	// Models holds the models owned by the identity.
+	Models []dbmodel.Model
+}
+
+// StaleReport returns a report of the identities that have not logged in
+// for the given number of days and the models that have had no activity,
+// as seen by the controller watcher, for the same period. Only JIMM
+// administrators may retrieve the report.
+func (j *JIMM) StaleReport(ctx context.Context, user *openfga.User, days int) (*StaleReport, error) {
+	const op = errors.Op("jimm.StaleReport")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if days <= 0 {
+		return nil, errors.E(op, errors.CodeBadRequest, "days must be positive")
+	}
+
+	report := StaleReport{
+		Before: time.Now().UTC().AddDate(0, 0, -days),
+	}
+	identities, err := j.Database.ListIdentities(ctx, db.IdentityFilter{LastLoginBefore: report.Before})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	for _, identity := range identities {
+		models, err := j.Database.GetModelsByOwner(ctx, identity.Name)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		report.Identities = append(report.Identities, InactiveIdentity{
+			Identity: identity,
+			Models:   models,
+		})
+	}
+	report.Models, err = j.Database.GetStaleModels(ctx, report.Before)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &report, nil
+}
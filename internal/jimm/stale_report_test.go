@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestStaleReport(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	_, err = j.StaleReport(ctx, alice, 30)
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	_, err = j.StaleReport(ctx, diane, 0)
+	c.Check(err, qt.ErrorMatches, `days must be positive`)
+
+	aliceIdentity.LastLogin = sql.NullTime{Time: time.Now().AddDate(0, 0, -60), Valid: true}
+	err = j.Database.UpdateIdentity(ctx, &aliceIdentity)
+	c.Assert(err, qt.IsNil)
+	dianeIdentity.LastLogin = sql.NullTime{Time: time.Now(), Valid: true}
+	err = j.Database.UpdateIdentity(ctx, &dianeIdentity)
+	c.Assert(err, qt.IsNil)
+
+	report, err := j.StaleReport(ctx, diane, 30)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Identities, qt.HasLen, 1)
+	c.Check(report.Models, qt.HasLen, 0)
+
+	m := dbmodel.Model{UUID: sql.NullString{String: "00000002-0000-0000-0000-000000000001", Valid: true}}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	m.LastActivity = sql.NullTime{Time: time.Now().AddDate(0, 0, -45), Valid: true}
+	m.Machines = 2
+	m.Cores = 8
+	m.Units = 3
+	err = j.Database.UpdateModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	report, err = j.StaleReport(ctx, diane, 30)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Before.Before(time.Now().AddDate(0, 0, -29)), qt.IsTrue)
+	c.Assert(report.Identities, qt.HasLen, 1)
+	c.Check(report.Identities[0].Identity.Name, qt.Equals, "alice@canonical.com")
+	c.Assert(report.Identities[0].Models, qt.HasLen, 1)
+	c.Check(report.Identities[0].Models[0].Name, qt.Equals, "model-1")
+	c.Assert(report.Models, qt.HasLen, 1)
+	c.Check(report.Models[0].Name, qt.Equals, "model-1")
+	c.Check(report.Models[0].Cores, qt.Equals, int64(8))
+
+	report, err = j.StaleReport(ctx, diane, 50)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Identities, qt.HasLen, 1)
+	c.Check(report.Models, qt.HasLen, 0)
+}
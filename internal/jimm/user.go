@@ -13,12 +13,16 @@ import (
 )
 
 // UserLogin fetches a user based on their identityName and updates their last login time.
+// Disabled identities are refused with an error with the code CodeUnauthorized.
 func (j *JIMM) UserLogin(ctx context.Context, identityName string) (*openfga.User, error) {
 	const op = errors.Op("jimm.UserLogin")
 	user, err := j.getUser(ctx, identityName)
 	if err != nil {
 		return nil, errors.E(op, err, errors.CodeUnauthorized)
 	}
+	if user.Disabled {
+		return nil, errors.E(op, errors.CodeUnauthorized, "identity disabled")
+	}
 	err = j.updateUserLastLogin(ctx, identityName)
 	if err != nil {
 		return nil, errors.E(op, err, errors.CodeUnauthorized)
@@ -71,3 +75,38 @@ func (j *JIMM) updateUserLastLogin(ctx context.Context, identifier string) error
 	}
 	return nil
 }
+
+// SetIdentityDisabled disables, or re-enables, the named identity.
+// Disabled identities may not log in, and any sessions they have open on
+// this JIMM server are terminated when they are disabled. Sessions open
+// on other JIMM servers are terminated by their RunSessionCheck, within
+// its interval. Only JIMM administrators may disable identities, and they
+// may not disable themselves.
+func (j *JIMM) SetIdentityDisabled(ctx context.Context, user *openfga.User, identityName string, disabled bool) error {
+	const op = errors.Op("jimm.SetIdentityDisabled")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if disabled && identityName == user.Name {
+		return errors.E(op, errors.CodeBadRequest, "cannot disable own identity")
+	}
+
+	identity := dbmodel.Identity{Name: identityName}
+	if err := j.Database.Transaction(func(tx *db.Database) error {
+		if err := tx.FetchIdentity(ctx, &identity); err != nil {
+			return err
+		}
+		if identity.Disabled == disabled {
+			return nil
+		}
+		identity.Disabled = disabled
+		return tx.UpdateIdentity(ctx, &identity)
+	}); err != nil {
+		return errors.E(op, err)
+	}
+	if disabled {
+		j.sessions.terminate(identityName)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
@@ -93,3 +94,71 @@ func TestUpdateUserLastLogin(t *testing.T) {
 	c.Assert(user.LastLogin.Time, qt.Equals, now)
 	c.Assert(user.LastLogin.Valid, qt.IsTrue)
 }
+
+func TestSetIdentityDisabled(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	j := &jimm.JIMM{
+		UUID: "test",
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	admin, err := j.UserLogin(ctx, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin.JimmAdmin = true
+	bob, err := j.UserLogin(ctx, "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+
+	var terminated int
+	untrack := j.TrackSession("bob@canonical.com", func() { terminated++ })
+	defer untrack()
+	j.TrackSession("bob@canonical.com", func() { terminated++ })
+	j.TrackSession("charlie@canonical.com", func() { terminated++ })
+
+	err = j.SetIdentityDisabled(ctx, bob, "alice@canonical.com", true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.SetIdentityDisabled(ctx, admin, "alice@canonical.com", true)
+	c.Check(err, qt.ErrorMatches, `cannot disable own identity`)
+	err = j.SetIdentityDisabled(ctx, admin, "diane@canonical.com", true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.SetIdentityDisabled(ctx, admin, "bob@canonical.com", true)
+	c.Assert(err, qt.IsNil)
+	c.Check(terminated, qt.Equals, 2)
+	identity := dbmodel.Identity{Name: "bob@canonical.com"}
+	err = j.Database.FetchIdentity(ctx, &identity)
+	c.Assert(err, qt.IsNil)
+	c.Check(identity.Disabled, qt.IsTrue)
+
+	_, err = j.UserLogin(ctx, "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, `identity disabled`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.SetIdentityDisabled(ctx, admin, "bob@canonical.com", false)
+	c.Assert(err, qt.IsNil)
+	_, err = j.UserLogin(ctx, "bob@canonical.com")
+	c.Check(err, qt.IsNil)
+	c.Check(terminated, qt.Equals, 2)
+
+	// Sessions of identities disabled through another JIMM server are
+	// terminated by the next check.
+	j.TrackSession("bob@canonical.com", func() { terminated++ })
+	identity.Disabled = true
+	err = j.Database.UpdateIdentity(ctx, &identity)
+	c.Assert(err, qt.IsNil)
+	c.Check(terminated, qt.Equals, 2)
+	err = j.TerminateDisabledSessions(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(terminated, qt.Equals, 3)
+	err = j.TerminateDisabledSessions(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(terminated, qt.Equals, 3)
+}
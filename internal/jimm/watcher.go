@@ -5,6 +5,8 @@ package jimm
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
@@ -13,10 +15,13 @@ import (
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/servermon"
+	"github.com/canonical/jimm/v3/internal/utils"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // Publisher defines the interface used by the Watcher
@@ -38,6 +43,15 @@ type Watcher struct {
 	// model summaries.
 	Pubsub Publisher
 
+	// AuditLog records the audit log entries for changes made by the
+	// watcher, such as completed migrations. If this is not configured
+	// entries are written directly to the Database.
+	AuditLog AuditLoggerBackend
+
+	// AuditRedactor redacts sensitive parameters from audit log
+	// entries. If this is nil the default redaction rules are used.
+	AuditRedactor *auditredact.Redactor
+
 	controllerUnavailableChan chan error
 	deltaProcessedChan        chan bool
 }
@@ -165,12 +179,28 @@ func (w *Watcher) dialController(ctx context.Context, ctl *dbmodel.Controller) (
 	return api, nil
 }
 
+// lastActivityInterval is the minimum time between updates of a model's
+// LastActivity. Activity is only used to find models that have been
+// unused for days, so it is not recorded for every delta.
+const lastActivityInterval = time.Hour
+
 // A modelState holds the in-memory state of a model for the watcher.
 type modelState struct {
 	// id is the database id of the model.
-	id      uint
+	id uint
+
+	// controllerID is the database id of the controller the model is
+	// being watched on.
+	controllerID uint
+
 	changed bool
 
+	// active is set when a delta has been received for the model.
+	active bool
+
+	// lastActivity is the LastActivity last stored for the model.
+	lastActivity time.Time
+
 	// machines maps the Id of all the machines that have been seen to
 	// the number of cores reported.
 	machines map[string]int64
@@ -200,9 +230,11 @@ func (w *Watcher) checkControllerModels(ctx context.Context, ctl *dbmodel.Contro
 			}
 		}
 		modelStates[m.UUID.String] = &modelState{
-			id:       m.ID,
-			machines: make(map[string]int64),
-			units:    make(map[string]bool),
+			id:           m.ID,
+			controllerID: ctl.ID,
+			lastActivity: m.LastActivity.Time,
+			machines:     make(map[string]int64),
+			units:        make(map[string]bool),
 		}
 		return nil
 	})
@@ -293,9 +325,11 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 		switch {
 		case err == nil:
 			st := modelState{
-				id:       m.ID,
-				machines: make(map[string]int64),
-				units:    make(map[string]bool),
+				id:           m.ID,
+				controllerID: ctl.ID,
+				lastActivity: m.LastActivity.Time,
+				machines:     make(map[string]int64),
+				units:        make(map[string]bool),
 			}
 			modelStates[uuid] = &st
 		case errors.ErrorCode(err) == errors.CodeNotFound:
@@ -306,6 +340,9 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 		return modelStates[uuid]
 	}
 
+	// The first set of deltas received from the all watcher describes
+	// the initial state of the models, rather than activity in them.
+	initial := true
 	for {
 		// wait for updates from the all watcher.
 		deltas, err := api.AllModelWatcherNext(ctx, id)
@@ -328,7 +365,11 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 				delete(modelStates, k)
 				continue
 			}
-			if v.changed {
+			now := time.Now().UTC()
+			active := v.active && !initial && now.Sub(v.lastActivity) >= lastActivityInterval
+			v.active = false
+			if v.changed || active {
+				changed := v.changed
 				v.changed = false
 				// Update changed model.
 				err := w.Database.Transaction(func(tx *db.Database) error {
@@ -338,14 +379,22 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 					if err := tx.GetModel(ctx, &m); err != nil {
 						return err
 					}
-					var machines, cores int64
-					for _, n := range v.machines {
-						machines++
-						cores += n
+					if changed {
+						var machines, cores int64
+						for _, n := range v.machines {
+							machines++
+							cores += n
+						}
+						m.Cores = cores
+						m.Machines = machines
+						m.Units = int64(len(v.units))
+					}
+					if active {
+						m.LastActivity = sql.NullTime{
+							Time:  now,
+							Valid: true,
+						}
 					}
-					m.Cores = cores
-					m.Machines = machines
-					m.Units = int64(len(v.units))
 					if err := tx.UpdateModel(ctx, &m); err != nil {
 						return err
 					}
@@ -355,8 +404,12 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 					zapctx.Error(ctx, "cannot get model for update", zap.Error(err))
 					continue
 				}
+				if active {
+					v.lastActivity = now
+				}
 			}
 		}
+		initial = false
 	}
 }
 
@@ -457,6 +510,7 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 	if state == nil {
 		return nil
 	}
+	state.active = true
 	switch eid.Kind {
 	case "application":
 		if d.Removed {
@@ -485,9 +539,9 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 			ID: state.id,
 		}
 		if d.Removed {
-			return w.deleteModel(ctx, &model)
+			return w.deleteModel(ctx, state.controllerID, &model)
 		}
-		return w.updateModel(ctx, &model, d.Entity.(*jujuparams.ModelUpdate))
+		return w.updateModel(ctx, state.controllerID, &model, d.Entity.(*jujuparams.ModelUpdate))
 	case "unit":
 		if d.Removed {
 			state.changed = true
@@ -502,17 +556,26 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 	return nil
 }
 
-func (w *Watcher) deleteModel(ctx context.Context, model *dbmodel.Model) error {
+func (w *Watcher) deleteModel(ctx context.Context, controllerID uint, model *dbmodel.Model) error {
 	const op = errors.Op("watcher.deleteModel")
 
+	var migrated bool
 	err := w.Database.Transaction(func(db *db.Database) error {
 		if err := db.GetModel(ctx, model); err != nil {
 			if errors.ErrorCode(err) != errors.CodeNotFound {
 				return err
 			}
 		}
+		if model.ControllerID != controllerID {
+			// The model has already been migrated away from
+			// the controller.
+			return nil
+		}
 		if !(model.Life == state.Dying.String() || model.Life == state.Dead.String()) {
-			// If the model hasn't been marked as dying, don't remove it.
+			// If the model hasn't been marked as dying, don't
+			// remove it. A live model that leaves its
+			// controller has been migrated.
+			migrated = model.UUID.Valid
 			return nil
 		}
 		return db.DeleteModel(ctx, model)
@@ -520,27 +583,182 @@ func (w *Watcher) deleteModel(ctx context.Context, model *dbmodel.Model) error {
 	if err != nil {
 		return errors.E(op, err)
 	}
+	if migrated {
+		if err := w.detectMigration(ctx, model, true); err != nil {
+			return errors.E(op, err)
+		}
+	}
 	return nil
 }
 
-func (w *Watcher) updateModel(ctx context.Context, model *dbmodel.Model, info *jujuparams.ModelUpdate) error {
+func (w *Watcher) updateModel(ctx context.Context, controllerID uint, model *dbmodel.Model, info *jujuparams.ModelUpdate) error {
 	const op = errors.Op("watcher.updateModel")
 
+	var skip bool
 	err := w.Database.Transaction(func(db *db.Database) error {
 		if err := db.GetModel(ctx, model); err != nil {
 			if errors.ErrorCode(err) != errors.CodeNotFound {
 				return err
 			}
 		}
+		if model.ID != 0 && model.ControllerID != controllerID {
+			// The model has already been migrated away from
+			// the controller, ignore any further updates.
+			skip = true
+			return nil
+		}
 		model.FromJujuModelUpdate(*info)
+		if err := updateMigration(ctx, db, model, false, info); err != nil {
+			return err
+		}
 		return db.UpdateModel(ctx, model)
 	})
 	if err != nil {
 		return errors.E(op, err)
 	}
+	if !skip && strings.HasPrefix(info.Status.Message, migrationSuccessfulStatusPrefix) {
+		if err := w.detectMigration(ctx, model, false); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	return nil
+}
+
+// detectMigration checks whether the given model, which is leaving its
+// controller, is running on another controller known to JIMM. If the
+// model has been recorded as migrating to a controller only that
+// controller is checked. Otherwise, if removed is true, the model has
+// been removed from its controller and every other known controller is
+// searched for it. As this dials each controller the search is not made
+// until the model is removed. If the model is found the model is updated
+// to reference its new controller and an audit log entry is written.
+func (w *Watcher) detectMigration(ctx context.Context, model *dbmodel.Model, removed bool) error {
+	const op = errors.Op("watcher.detectMigration")
+
+	var target *dbmodel.Controller
+	if model.MigrationControllerID.Valid {
+		ctl := dbmodel.Controller{
+			ID: uint(model.MigrationControllerID.Int32),
+		}
+		if err := w.Database.GetController(ctx, &ctl); err != nil {
+			return errors.E(op, err)
+		}
+		if !w.modelRunningOn(ctx, &ctl, model.UUID.String) {
+			// The target controller may be temporarily
+			// unavailable, the migration can be completed with
+			// UpdateMigratedModel.
+			zapctx.Info(ctx, "migrated model not found on target controller", zap.String("model", model.UUID.String), zap.String("target", ctl.Name))
+			return nil
+		}
+		target = &ctl
+	} else {
+		if !removed {
+			return nil
+		}
+		var err error
+		target, err = w.findMigratedModel(ctx, model)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if target == nil {
+			zapctx.Info(ctx, "model migrated to an unknown controller", zap.String("model", model.UUID.String))
+			// Complete any migration to a controller that is
+			// not known to JIMM.
+			err := w.Database.Transaction(func(tx *db.Database) error {
+				return updateMigration(ctx, tx, model, true, nil)
+			})
+			if err != nil {
+				return errors.E(op, err)
+			}
+			return nil
+		}
+	}
+
+	var m *dbmodel.Migration
+	err := w.Database.Transaction(func(tx *db.Database) error {
+		var err error
+		m, err = completeMigration(ctx, tx, model, target)
+		return err
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	zapctx.Info(ctx, "model migration completed", zap.String("model", model.UUID.String), zap.String("target", target.Name))
+
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		Model:          model.UUID.String,
+		ConversationId: utils.NewConversationID(),
+		FacadeName:     "JIMM",
+		FacadeMethod:   "UpdateMigratedModel",
+		FacadeVersion:  4,
+	}
+	if m.Requester != "" {
+		ale.IdentityTag = names.NewUserTag(m.Requester).String()
+	}
+	params, err := json.Marshal(apiparams.UpdateMigratedModelRequest{
+		ModelTag:         model.ResourceTag().String(),
+		TargetController: target.Name,
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	ale.Params = params
+	w.addAuditLogEntry(ctx, &ale)
 	return nil
 }
 
+// addAuditLogEntry redacts the given entry and records it in the
+// configured AuditLog, or the database if there is no AuditLog.
+func (w *Watcher) addAuditLogEntry(ctx context.Context, ale *dbmodel.AuditLogEntry) {
+	w.AuditRedactor.Redact(ale)
+	if w.AuditLog != nil {
+		w.AuditLog.AddAuditLogEntry(ale)
+		return
+	}
+	if err := w.Database.AddAuditLogEntry(ctx, ale); err != nil {
+		zapctx.Error(ctx, "cannot store audit log entry", zap.Error(err))
+	}
+}
+
+// findMigratedModel searches the controllers known to JIMM, other than the
+// model's current controller, for the given model. The controller running
+// the model is returned, if no controller is running the model nil is
+// returned.
+func (w *Watcher) findMigratedModel(ctx context.Context, model *dbmodel.Model) (*dbmodel.Controller, error) {
+	var candidates []dbmodel.Controller
+	err := w.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if ctl.ID != model.ControllerID {
+			candidates = append(candidates, *ctl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		if w.modelRunningOn(ctx, &candidates[i], model.UUID.String) {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// modelRunningOn returns whether the model with the given UUID is running
+// on the given controller.
+func (w *Watcher) modelRunningOn(ctx context.Context, ctl *dbmodel.Controller, uuid string) bool {
+	api, err := w.dialController(ctx, ctl)
+	if err != nil {
+		zapctx.Warn(ctx, "cannot check for migrated model", zap.String("controller", ctl.Name), zap.Error(err))
+		return false
+	}
+	defer api.Close()
+	mi := jujuparams.ModelInfo{
+		UUID: uuid,
+	}
+	return api.ModelInfo(ctx, &mi) == nil
+}
+
 func (w *Watcher) updateApplication(ctx context.Context, modelID uint, info *jujuparams.ApplicationInfo) error {
 	err := w.Database.Transaction(func(tx *db.Database) error {
 		m := dbmodel.Model{
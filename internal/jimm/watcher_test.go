@@ -156,6 +156,110 @@ var watcherTests = []struct {
 		c.Check(model.Machines, qt.Equals, int64(1))
 		c.Check(model.Cores, qt.Equals, int64(4))
 	},
+}, {
+	name: "InitialDeltasAreNotActivity",
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		c.Check(model.Units, qt.Equals, int64(1))
+		c.Check(model.LastActivity.Valid, qt.IsFalse)
+	},
+}, {
+	name: "RecordActivity",
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+			},
+		}}, {{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		c.Check(model.Units, qt.Equals, int64(1))
+		c.Check(model.LastActivity.Valid, qt.IsTrue)
+		c.Check(time.Since(model.LastActivity.Time) < time.Minute, qt.IsTrue)
+	},
+}, {
+	name: "RecentActivityNotRecorded",
+	initDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		var m dbmodel.Model
+		m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+		err := db.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		m.LastActivity = sql.NullTime{
+			Time:  time.Now().Add(-10 * time.Minute).UTC(),
+			Valid: true,
+		}
+		err = db.UpdateModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+	},
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+			},
+		}}, {{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		// The activity was recorded too recently to be updated.
+		c.Check(model.LastActivity.Valid, qt.IsTrue)
+		c.Check(time.Since(model.LastActivity.Time) > 5*time.Minute, qt.IsTrue)
+	},
 }, {
 	name: "DeleteMachine",
 	deltas: [][]jujuparams.Delta{
@@ -1082,6 +1186,218 @@ func TestWatcherIgnoreDeltasForModelsFromIncorrectController(t *testing.T) {
 	c.Check(m2, qt.DeepEquals, m1)
 }
 
+// watchMigratedModel runs a watcher on controller-1 that sees model-1
+// successfully migrate away and then be removed. If record is true the
+// migration is recorded as being to controller-2 before the watcher
+// starts. The number of times controller-2 was asked for the model is
+// returned.
+func watchMigratedModel(c *qt.C, w *jimm.Watcher, record bool) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nextC := make(chan []jujuparams.Delta)
+	var modelInfoCalls int
+	w.Pubsub = &testPublisher{}
+	w.Database = db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	w.Dialer = jimmtest.DialerMap{
+		"controller-1": &jimmtest.Dialer{
+			API: &jimmtest.API{
+				AllModelWatcherNext_: func(_ context.Context, _ string) ([]jujuparams.Delta, error) {
+					select {
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					case d, ok := <-nextC:
+						if ok {
+							return d, nil
+						}
+						cancel()
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+				},
+				AllModelWatcherStop_: func(context.Context, string) error {
+					return nil
+				},
+				WatchAllModels_: func(ctx context.Context) (string, error) {
+					return "1234", nil
+				},
+			},
+		},
+		"controller-2": &jimmtest.Dialer{
+			API: &jimmtest.API{
+				ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+					modelInfoCalls++
+					if mi.UUID != "00000002-0000-0000-0000-000000000001" {
+						return errors.E(errors.CodeNotFound)
+					}
+					return nil
+				},
+			},
+		},
+	}
+	env := jimmtest.ParseEnvironment(c, testWatcherIgnoreDeltasForModelsFromIncorrectControllerEnv)
+	err := w.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDB(c, w.Database)
+
+	if record {
+		target := dbmodel.Controller{Name: "controller-2"}
+		err = w.Database.GetController(ctx, &target)
+		c.Assert(err, qt.IsNil)
+		m := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err = w.Database.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		m.MigrationControllerID = sql.NullInt32{Int32: int32(target.ID), Valid: true}
+		err = w.Database.UpdateModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		err = w.Database.AddMigration(ctx, &dbmodel.Migration{
+			MigrationID:      "migration-1",
+			ModelUUID:        m.UUID.String,
+			ModelName:        m.Name,
+			SourceController: "controller-1",
+			TargetController: "controller-2",
+			Requester:        "alice@canonical.com",
+			Phase:            dbmodel.MigrationPhaseInitiated,
+			StartedAt:        time.Now().UTC(),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = w.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := jimm.WatchController(w, ctx, &ctl)
+		checkIfContextCanceled(c, ctx, err)
+	}()
+
+	nextC <- []jujuparams.Delta{{
+		Entity: &jujuparams.ModelUpdate{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			Name:      "model-1",
+			Owner:     "alice@canonical.com",
+			Life:      life.Value(state.Alive.String()),
+			Status: jujuparams.StatusInfo{
+				Current: "busy",
+				Message: "migrating: importing",
+			},
+		},
+	}}
+	nextC <- []jujuparams.Delta{{
+		Entity: &jujuparams.ModelUpdate{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			Name:      "model-1",
+			Owner:     "alice@canonical.com",
+			Life:      life.Value(state.Alive.String()),
+			Status: jujuparams.StatusInfo{
+				Current: "available",
+				Message: "migrating: successful",
+			},
+		},
+	}}
+	nextC <- []jujuparams.Delta{{
+		Removed: true,
+		Entity: &jujuparams.ModelUpdate{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			Name:      "model-1",
+			Owner:     "alice@canonical.com",
+			Life:      life.Value(state.Alive.String()),
+		},
+	}}
+	close(nextC)
+	wg.Wait()
+	return modelInfoCalls
+}
+
+func TestWatcherDetectsMigratedModel(t *testing.T) {
+	c := qt.New(t)
+
+	var auditLog testAuditLoggerBackend
+	w := &jimm.Watcher{
+		AuditLog: &auditLog,
+	}
+	calls := watchMigratedModel(c, w, true)
+
+	// The recorded target is checked when the migration succeeds, the
+	// removal of the model is then ignored.
+	c.Check(calls, qt.Equals, 1)
+	m := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000002-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err := w.Database.GetModel(context.Background(), &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Controller.Name, qt.Equals, "controller-2")
+	c.Check(m.MigrationControllerID.Valid, qt.IsFalse)
+
+	migrations, err := w.Database.ListMigrations(context.Background(), db.MigrationFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].SourceController, qt.Equals, "controller-1")
+	c.Check(migrations[0].TargetController, qt.Equals, "controller-2")
+	c.Check(migrations[0].Phase, qt.Equals, dbmodel.MigrationPhaseCompleted)
+
+	// The audit log entry is written to the configured AuditLog.
+	c.Assert(auditLog.entries, qt.HasLen, 1)
+	c.Check(auditLog.entries[0].FacadeMethod, qt.Equals, "UpdateMigratedModel")
+	c.Check(auditLog.entries[0].Model, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(auditLog.entries[0].IdentityTag, qt.Equals, "user-alice@canonical.com")
+	c.Check([]byte(auditLog.entries[0].Params), qt.JSONEquals, map[string]string{
+		"model-tag":         "model-00000002-0000-0000-0000-000000000001",
+		"target-controller": "controller-2",
+	})
+}
+
+func TestWatcherDetectsUnrecordedMigration(t *testing.T) {
+	c := qt.New(t)
+
+	w := &jimm.Watcher{}
+	calls := watchMigratedModel(c, w, false)
+
+	// Other controllers are only searched for the model once it has
+	// been removed from its controller.
+	c.Check(calls, qt.Equals, 1)
+	m := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000002-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err := w.Database.GetModel(context.Background(), &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Controller.Name, qt.Equals, "controller-2")
+
+	migrations, err := w.Database.ListMigrations(context.Background(), db.MigrationFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].SourceController, qt.Equals, "controller-1")
+	c.Check(migrations[0].TargetController, qt.Equals, "controller-2")
+	c.Check(migrations[0].Phase, qt.Equals, dbmodel.MigrationPhaseCompleted)
+
+	var entries []dbmodel.AuditLogEntry
+	err = w.Database.ForEachAuditLogEntry(context.Background(), db.AuditLogFilter{Method: "UpdateMigratedModel"}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+	c.Check(entries[0].Model, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(entries[0].IdentityTag, qt.Equals, "")
+}
+
 func checkIfContextCanceled(c *qt.C, ctx context.Context, err error) {
 	errorToCheck := err
 	if ctx.Err() != nil {
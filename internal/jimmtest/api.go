@@ -119,6 +119,7 @@ type API struct {
 	base.APICaller
 
 	AddCloud_                          func(context.Context, names.CloudTag, jujuparams.Cloud, bool) error
+	AllModelSummaries_                 func(context.Context) ([]jujuparams.ModelSummary, error)
 	AllModelWatcherNext_               func(context.Context, string) ([]jujuparams.Delta, error)
 	AllModelWatcherStop_               func(context.Context, string) error
 	ChangeModelCredential_             func(context.Context, names.ModelTag, names.CloudCredentialTag) error
@@ -176,6 +177,13 @@ func (a *API) AddCloud(ctx context.Context, tag names.CloudTag, cld jujuparams.C
 	return a.AddCloud_(ctx, tag, cld, force)
 }
 
+func (a *API) AllModelSummaries(ctx context.Context) ([]jujuparams.ModelSummary, error) {
+	if a.AllModelSummaries_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return a.AllModelSummaries_(ctx)
+}
+
 func (a *API) AllModelWatcherNext(ctx context.Context, id string) ([]jujuparams.Delta, error) {
 	if a.AllModelWatcherNext_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
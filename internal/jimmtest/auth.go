@@ -202,8 +202,14 @@ func newSessionToken(c SimpleTester, username string, signatureSecret string) st
 // to define how login will take place. In this case we login using a session token
 // that the JIMM server should verify with the same test secret.
 func NewUserSessionLogin(c SimpleTester, username string) api.LoginProvider {
-	b64Token := newSessionToken(c, username, JWTTestSecret)
-	return api.NewSessionTokenLoginProvider(b64Token, nil, nil)
+	return api.NewSessionTokenLoginProvider(NewUserSessionToken(c, username), nil, nil)
+}
+
+// NewUserSessionToken returns a session token for the given user that the
+// JIMM server should verify with the same test secret. This can be used to
+// authenticate HTTP requests.
+func NewUserSessionToken(c SimpleTester, username string) string {
+	return newSessionToken(c, username, JWTTestSecret)
 }
 
 func convertUsernameToEmail(username string) string {
@@ -4,6 +4,7 @@ package jimmtest
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
@@ -21,6 +22,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -37,13 +39,19 @@ type JIMM struct {
 	AddGroup_                          func(ctx context.Context, user *openfga.User, name string) (*dbmodel.GroupEntry, error)
 	AddHostedCloud_                    func(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddServiceAccount_                 func(ctx context.Context, u *openfga.User, clientId string) error
+	AddTemporaryRelations_             func(ctx context.Context, user *openfga.User, expiresAt time.Time, tuples ...openfga.Tuple) error
+	ApplyAccessPolicy_                 func(ctx context.Context, user *openfga.User, policy apiparams.AccessPolicy, apply bool) (*apiparams.AccessPolicyPlan, error)
+	ApproveAccessRequest_              func(ctx context.Context, user *openfga.User, id uint, comment string, expiresAt time.Time) (*dbmodel.AccessRequest, error)
 	Authenticate_                      func(ctx context.Context, req *jujuparams.LoginRequest) (*openfga.User, error)
 	AuthorizationClient_               func() *openfga.OFGAClient
 	CheckPermission_                   func(ctx context.Context, user *openfga.User, cachedPerms map[string]string, desiredPerms map[string]interface{}) (map[string]string, error)
+	ClearRelationExpiries_             func(ctx context.Context, tuples ...openfga.Tuple) error
 	CopyServiceAccountCredential_      func(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
 	DB_                                func() *db.Database
+	DenyAccessRequest_                 func(ctx context.Context, user *openfga.User, id uint, comment string) (*dbmodel.AccessRequest, error)
 	DestroyOffer_                      func(ctx context.Context, user *openfga.User, offerURL string, force bool) error
 	EarliestControllerVersion_         func(ctx context.Context) (version.Number, error)
+	ExplainRelation_                   func(ctx context.Context, user *openfga.User, tuple openfga.Tuple) (*jimm.RelationExplanation, error)
 	FindApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	FindAuditEvents_                   func(ctx context.Context, user *openfga.User, filter db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error)
 	ForEachCloud_                      func(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
@@ -56,6 +64,7 @@ type JIMM struct {
 	GetCloudCredentialAttributes_      func(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
 	GetControllerConfig_               func(ctx context.Context, u *dbmodel.Identity) (*dbmodel.ControllerConfig, error)
 	GetCredentialStore_                func() jimmcreds.CredentialStore
+	GetIdentity_                       func(ctx context.Context, user *openfga.User, identityName string) (*jimm.IdentityDetails, error)
 	GetJimmControllerAccess_           func(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
 	GetUserCloudAccess_                func(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error)
 	GetUserControllerAccess_           func(ctx context.Context, user *openfga.User, controller names.ControllerTag) (string, error)
@@ -65,20 +74,31 @@ type JIMM struct {
 	GrantModelAccess_                  func(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
 	GrantOfferAccess_                  func(ctx context.Context, u *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error
 	GrantServiceAccountAccess_         func(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, entities []string) error
+	IdentityAccessReport_              func(ctx context.Context, user *openfga.User, identity *ofganames.Tag) ([]jimm.AccessGrant, error)
 	InitiateMigration_                 func(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
 	InitiateInternalMigration_         func(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetController string) (jujuparams.InitiateMigrationResult, error)
+	ListAccessRequests_                func(ctx context.Context, user *openfga.User, status string, mine bool) ([]dbmodel.AccessRequest, error)
 	ListApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
+	ListAuditLogArchives_              func(ctx context.Context, user *openfga.User) ([]dbmodel.AuditLogArchive, error)
 	ListControllers_                   func(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
 	ListGroups_                        func(ctx context.Context, user *openfga.User) ([]dbmodel.GroupEntry, error)
+	ListIdentities_                    func(ctx context.Context, user *openfga.User, filter db.IdentityFilter) ([]dbmodel.Identity, error)
+	ListQuotas_                        func(ctx context.Context, user *openfga.User) ([]jimm.QuotaStatus, error)
+	OffboardIdentity_                  func(ctx context.Context, user *openfga.User, identityName, newOwnerName string) (*apiparams.OffboardSummary, error)
 	Offer_                             func(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
 	OAuthAuthenticationService_        func() jimm.OAuthAuthenticator
 	ParseTag_                          func(ctx context.Context, key string) (*ofganames.Tag, error)
 	PubSubHub_                         func() *pubsub.Hub
 	PurgeLogs_                         func(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	QuotaSubjectTag_                   func(ctx context.Context, q *dbmodel.Quota) (string, error)
+	RequestAccess_                     func(ctx context.Context, user *openfga.User, resource, access, reason string) (*dbmodel.AccessRequest, error)
+	ResourceAccessReport_              func(ctx context.Context, user *openfga.User, resource *ofganames.Tag) ([]jimm.AccessGrant, error)
+	RestoreAuditLogArchive_            func(ctx context.Context, user *openfga.User, id uint) (int64, error)
 	RemoveCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController_         func(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
 	RemoveController_                  func(ctx context.Context, user *openfga.User, controllerName string, force bool) error
 	RemoveGroup_                       func(ctx context.Context, user *openfga.User, name string) error
+	RemoveQuota_                       func(ctx context.Context, user *openfga.User, subject string, cloud names.CloudTag, region string) error
 	RenameGroup_                       func(ctx context.Context, user *openfga.User, oldName, newName string) error
 	ResourceTag_                       func() names.ControllerTag
 	RevokeAuditLogAccess_              func(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
@@ -86,14 +106,28 @@ type JIMM struct {
 	RevokeCloudCredential_             func(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error
 	RevokeModelAccess_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
 	RevokeOfferAccess_                 func(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
+	SetControllerCapacity_             func(ctx context.Context, user *openfga.User, controllerName string, maxModels, maxUnits, maxMachines sql.NullInt64) error
 	SetControllerConfig_               func(ctx context.Context, u *openfga.User, args jujuparams.ControllerConfigSet) error
 	SetControllerDeprecated_           func(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerRegionPriority_       func(ctx context.Context, user *openfga.User, controllerName, cloudName, regionName string, priority uint) error
+	DrainController_                   func(ctx context.Context, user *openfga.User, controllerName string, batchSize int) (*dbmodel.ControllerDrain, error)
+	ControllerDrainStatus_             func(ctx context.Context, user *openfga.User, controllerName string) (*dbmodel.ControllerDrain, error)
+	ListMigrations_                    func(ctx context.Context, user *openfga.User, filter db.MigrationFilter) ([]dbmodel.Migration, error)
+	MigrationStatus_                   func(ctx context.Context, user *openfga.User, migrationID string, modelTag names.ModelTag) (*dbmodel.Migration, error)
+	Reconcile_                         func(ctx context.Context, user *openfga.User, controllerName string, fix bool) ([]jimm.ReconcileReport, error)
+	ListControllerRegionPriorities_    func(ctx context.Context, user *openfga.User, controllerName string) ([]dbmodel.CloudRegionControllerPriority, error)
+	SetIdentityDisabled_               func(ctx context.Context, user *openfga.User, identityName string, disabled bool) error
+	SetQuota_                          func(ctx context.Context, user *openfga.User, q *dbmodel.Quota) error
 	SetIdentityModelDefaults_          func(ctx context.Context, user *dbmodel.Identity, configs map[string]interface{}) error
+	StaleReport_                       func(ctx context.Context, user *openfga.User, days int) (*jimm.StaleReport, error)
 	ToJAASTag_                         func(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TrackSession_                      func(identityName string, terminate func()) (untrack func())
+	TransferModelOwnership_            func(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwnerName string, credTag names.CloudCredentialTag) (*apiparams.ModelOwnershipTransfer, error)
 	UpdateApplicationOffer_            func(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error
 	UpdateCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential_             func(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
 	UserLogin_                         func(ctx context.Context, identityName string) (*openfga.User, error)
+	VerifyAuditLog_                    func(ctx context.Context, user *openfga.User) (*jimm.AuditLogVerification, error)
 }
 
 func (j *JIMM) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
@@ -133,6 +167,33 @@ func (j *JIMM) AddServiceAccount(ctx context.Context, u *openfga.User, clientId
 	}
 	return j.AddServiceAccount_(ctx, u, clientId)
 }
+func (j *JIMM) AddTemporaryRelations(ctx context.Context, user *openfga.User, expiresAt time.Time, tuples ...openfga.Tuple) error {
+	if j.AddTemporaryRelations_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddTemporaryRelations_(ctx, user, expiresAt, tuples...)
+}
+
+func (j *JIMM) ApplyAccessPolicy(ctx context.Context, user *openfga.User, policy apiparams.AccessPolicy, apply bool) (*apiparams.AccessPolicyPlan, error) {
+	if j.ApplyAccessPolicy_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ApplyAccessPolicy_(ctx, user, policy, apply)
+}
+
+func (j *JIMM) ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string, expiresAt time.Time) (*dbmodel.AccessRequest, error) {
+	if j.ApproveAccessRequest_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ApproveAccessRequest_(ctx, user, id, comment, expiresAt)
+}
+
+func (j *JIMM) ClearRelationExpiries(ctx context.Context, tuples ...openfga.Tuple) error {
+	if j.ClearRelationExpiries_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.ClearRelationExpiries_(ctx, tuples...)
+}
 
 func (j *JIMM) CopyServiceAccountCredential(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error) {
 	if j.CopyServiceAccountCredential_ == nil {
@@ -166,6 +227,13 @@ func (j *JIMM) DB() *db.Database {
 	}
 	return j.DB_()
 }
+func (j *JIMM) DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) (*dbmodel.AccessRequest, error) {
+	if j.DenyAccessRequest_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.DenyAccessRequest_(ctx, user, id, comment)
+}
+
 func (j *JIMM) DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error {
 	if j.DestroyOffer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -179,6 +247,13 @@ func (j *JIMM) EarliestControllerVersion(ctx context.Context) (version.Number, e
 	}
 	return j.EarliestControllerVersion_(ctx)
 }
+func (j *JIMM) ExplainRelation(ctx context.Context, user *openfga.User, tuple openfga.Tuple) (*jimm.RelationExplanation, error) {
+	if j.ExplainRelation_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ExplainRelation_(ctx, user, tuple)
+}
+
 func (j *JIMM) FindApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if j.FindApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -253,6 +328,13 @@ func (j *JIMM) GetCredentialStore() jimmcreds.CredentialStore {
 	}
 	return j.GetCredentialStore_()
 }
+func (j *JIMM) GetIdentity(ctx context.Context, user *openfga.User, identityName string) (*jimm.IdentityDetails, error) {
+	if j.GetIdentity_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetIdentity_(ctx, user, identityName)
+}
+
 func (j *JIMM) GetJimmControllerAccess(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error) {
 	if j.GetJimmControllerAccess_ == nil {
 		return "", errors.E(errors.CodeNotImplemented)
@@ -309,6 +391,13 @@ func (j *JIMM) GrantServiceAccountAccess(ctx context.Context, u *openfga.User, s
 	return j.GrantServiceAccountAccess_(ctx, u, svcAccTag, entities)
 }
 
+func (j *JIMM) IdentityAccessReport(ctx context.Context, user *openfga.User, identity *ofganames.Tag) ([]jimm.AccessGrant, error) {
+	if j.IdentityAccessReport_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.IdentityAccessReport_(ctx, user, identity)
+}
+
 func (j *JIMM) InitiateMigration(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error) {
 	if j.InitiateMigration_ == nil {
 		return jujuparams.InitiateMigrationResult{}, errors.E(errors.CodeNotImplemented)
@@ -321,6 +410,13 @@ func (j *JIMM) InitiateInternalMigration(ctx context.Context, user *openfga.User
 	}
 	return j.InitiateInternalMigration_(ctx, user, modelTag, targetController)
 }
+func (j *JIMM) ListAccessRequests(ctx context.Context, user *openfga.User, status string, mine bool) ([]dbmodel.AccessRequest, error) {
+	if j.ListAccessRequests_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAccessRequests_(ctx, user, status, mine)
+}
+
 func (j *JIMM) ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if j.ListApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -340,6 +436,27 @@ func (j *JIMM) ListGroups(ctx context.Context, user *openfga.User) ([]dbmodel.Gr
 	return j.ListGroups_(ctx, user)
 }
 
+func (j *JIMM) ListIdentities(ctx context.Context, user *openfga.User, filter db.IdentityFilter) ([]dbmodel.Identity, error) {
+	if j.ListIdentities_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListIdentities_(ctx, user, filter)
+}
+
+func (j *JIMM) ListQuotas(ctx context.Context, user *openfga.User) ([]jimm.QuotaStatus, error) {
+	if j.ListQuotas_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListQuotas_(ctx, user)
+}
+
+func (j *JIMM) OffboardIdentity(ctx context.Context, user *openfga.User, identityName, newOwnerName string) (*apiparams.OffboardSummary, error) {
+	if j.OffboardIdentity_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.OffboardIdentity_(ctx, user, identityName, newOwnerName)
+}
+
 func (j *JIMM) Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error {
 	if j.Offer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -371,6 +488,41 @@ func (j *JIMM) PurgeLogs(ctx context.Context, user *openfga.User, before time.Ti
 	return j.PurgeLogs_(ctx, user, before)
 }
 
+func (j *JIMM) ListAuditLogArchives(ctx context.Context, user *openfga.User) ([]dbmodel.AuditLogArchive, error) {
+	if j.ListAuditLogArchives_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAuditLogArchives_(ctx, user)
+}
+
+func (j *JIMM) RequestAccess(ctx context.Context, user *openfga.User, resource, access, reason string) (*dbmodel.AccessRequest, error) {
+	if j.RequestAccess_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RequestAccess_(ctx, user, resource, access, reason)
+}
+
+func (j *JIMM) ResourceAccessReport(ctx context.Context, user *openfga.User, resource *ofganames.Tag) ([]jimm.AccessGrant, error) {
+	if j.ResourceAccessReport_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ResourceAccessReport_(ctx, user, resource)
+}
+
+func (j *JIMM) RestoreAuditLogArchive(ctx context.Context, user *openfga.User, id uint) (int64, error) {
+	if j.RestoreAuditLogArchive_ == nil {
+		return 0, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RestoreAuditLogArchive_(ctx, user, id)
+}
+
+func (j *JIMM) QuotaSubjectTag(ctx context.Context, q *dbmodel.Quota) (string, error) {
+	if j.QuotaSubjectTag_ == nil {
+		return "", errors.E(errors.CodeNotImplemented)
+	}
+	return j.QuotaSubjectTag_(ctx, q)
+}
+
 func (j *JIMM) RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error {
 	if j.RemoveCloud_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -395,6 +547,12 @@ func (j *JIMM) RemoveGroup(ctx context.Context, user *openfga.User, name string)
 	}
 	return j.RemoveGroup_(ctx, user, name)
 }
+func (j *JIMM) RemoveQuota(ctx context.Context, user *openfga.User, subject string, cloud names.CloudTag, region string) error {
+	if j.RemoveQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveQuota_(ctx, user, subject, cloud, region)
+}
 func (j *JIMM) RenameGroup(ctx context.Context, user *openfga.User, oldName, newName string) error {
 	if j.RenameGroup_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -437,6 +595,13 @@ func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerU
 	}
 	return j.RevokeOfferAccess_(ctx, user, offerURL, ut, access)
 }
+func (j *JIMM) SetControllerCapacity(ctx context.Context, user *openfga.User, controllerName string, maxModels, maxUnits, maxMachines sql.NullInt64) error {
+	if j.SetControllerCapacity_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerCapacity_(ctx, user, controllerName, maxModels, maxUnits, maxMachines)
+}
+
 func (j *JIMM) SetControllerConfig(ctx context.Context, u *openfga.User, args jujuparams.ControllerConfigSet) error {
 	if j.SetControllerConfig_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -450,12 +615,82 @@ func (j *JIMM) SetControllerDeprecated(ctx context.Context, user *openfga.User,
 	return j.SetControllerDeprecated_(ctx, user, controllerName, deprecated)
 }
 
+func (j *JIMM) SetControllerRegionPriority(ctx context.Context, user *openfga.User, controllerName, cloudName, regionName string, priority uint) error {
+	if j.SetControllerRegionPriority_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerRegionPriority_(ctx, user, controllerName, cloudName, regionName, priority)
+}
+
+func (j *JIMM) ListControllerRegionPriorities(ctx context.Context, user *openfga.User, controllerName string) ([]dbmodel.CloudRegionControllerPriority, error) {
+	if j.ListControllerRegionPriorities_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListControllerRegionPriorities_(ctx, user, controllerName)
+}
+
+func (j *JIMM) DrainController(ctx context.Context, user *openfga.User, controllerName string, batchSize int) (*dbmodel.ControllerDrain, error) {
+	if j.DrainController_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.DrainController_(ctx, user, controllerName, batchSize)
+}
+
+func (j *JIMM) ControllerDrainStatus(ctx context.Context, user *openfga.User, controllerName string) (*dbmodel.ControllerDrain, error) {
+	if j.ControllerDrainStatus_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ControllerDrainStatus_(ctx, user, controllerName)
+}
+
+func (j *JIMM) ListMigrations(ctx context.Context, user *openfga.User, filter db.MigrationFilter) ([]dbmodel.Migration, error) {
+	if j.ListMigrations_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListMigrations_(ctx, user, filter)
+}
+
+func (j *JIMM) MigrationStatus(ctx context.Context, user *openfga.User, migrationID string, modelTag names.ModelTag) (*dbmodel.Migration, error) {
+	if j.MigrationStatus_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.MigrationStatus_(ctx, user, migrationID, modelTag)
+}
+
+func (j *JIMM) Reconcile(ctx context.Context, user *openfga.User, controllerName string, fix bool) ([]jimm.ReconcileReport, error) {
+	if j.Reconcile_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.Reconcile_(ctx, user, controllerName, fix)
+}
+
+func (j *JIMM) SetIdentityDisabled(ctx context.Context, user *openfga.User, identityName string, disabled bool) error {
+	if j.SetIdentityDisabled_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetIdentityDisabled_(ctx, user, identityName, disabled)
+}
+
+func (j *JIMM) SetQuota(ctx context.Context, user *openfga.User, q *dbmodel.Quota) error {
+	if j.SetQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetQuota_(ctx, user, q)
+}
+
 func (j *JIMM) SetIdentityModelDefaults(ctx context.Context, user *dbmodel.Identity, configs map[string]interface{}) error {
 	if j.SetIdentityModelDefaults_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.SetIdentityModelDefaults_(ctx, user, configs)
 }
+func (j *JIMM) StaleReport(ctx context.Context, user *openfga.User, days int) (*jimm.StaleReport, error) {
+	if j.StaleReport_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.StaleReport_(ctx, user, days)
+}
+
 func (j *JIMM) ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error) {
 	if j.ToJAASTag_ == nil {
 		return "", errors.E(errors.CodeNotImplemented)
@@ -463,6 +698,20 @@ func (j *JIMM) ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs b
 	return j.ToJAASTag_(ctx, tag, resolveUUIDs)
 }
 
+func (j *JIMM) TrackSession(identityName string, terminate func()) (untrack func()) {
+	if j.TrackSession_ == nil {
+		return func() {}
+	}
+	return j.TrackSession_(identityName, terminate)
+}
+
+func (j *JIMM) TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwnerName string, credTag names.CloudCredentialTag) (*apiparams.ModelOwnershipTransfer, error) {
+	if j.TransferModelOwnership_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.TransferModelOwnership_(ctx, user, mt, newOwnerName, credTag)
+}
+
 func (j *JIMM) UpdateApplicationOffer(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error {
 	if j.UpdateApplicationOffer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -482,6 +731,13 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, u *openfga.User, args
 	return j.UpdateCloudCredential_(ctx, u, args)
 }
 
+func (j *JIMM) VerifyAuditLog(ctx context.Context, user *openfga.User) (*jimm.AuditLogVerification, error) {
+	if j.VerifyAuditLog_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.VerifyAuditLog_(ctx, user)
+}
+
 func (j *JIMM) UserLogin(ctx context.Context, identityName string) (*openfga.User, error) {
 	if j.UserLogin_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -26,6 +26,7 @@ type ModelManager struct {
 	ForEachUserModel_       func(ctx context.Context, u *openfga.User, f func(*dbmodel.Model, jujuparams.UserAccessPermission) error) error
 	FullModelStatus_        func(ctx context.Context, user *openfga.User, modelTag names.ModelTag, patterns []string) (*jujuparams.FullStatus, error)
 	ImportModel_            func(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error
+	ImportModels_           func(ctx context.Context, user *openfga.User, controllerName, ownerPattern, namePattern string, ownerMap map[string]string) ([]jimm.ImportModelResult, error)
 	IdentityModelDefaults_  func(ctx context.Context, user *dbmodel.Identity) (map[string]interface{}, error)
 	ModelDefaultsForCloud_  func(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error)
 	ModelInfo_              func(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelInfo, error)
@@ -100,6 +101,13 @@ func (j *ModelManager) ImportModel(ctx context.Context, user *openfga.User, cont
 	return j.ImportModel_(ctx, user, controllerName, modelTag, newOwner)
 }
 
+func (j *ModelManager) ImportModels(ctx context.Context, user *openfga.User, controllerName, ownerPattern, namePattern string, ownerMap map[string]string) ([]jimm.ImportModelResult, error) {
+	if j.ImportModels_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ImportModels_(ctx, user, controllerName, ownerPattern, namePattern, ownerMap)
+}
+
 func (j *ModelManager) ModelDefaultsForCloud(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error) {
 	if j.ModelDefaultsForCloud_ == nil {
 		return jujuparams.ModelDefaultsResult{}, errors.E(errors.CodeNotImplemented)
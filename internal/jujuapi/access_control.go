@@ -97,7 +97,9 @@ func (r *controllerRoot) ListGroups(ctx context.Context) (apiparams.ListGroupRes
 }
 
 // AddRelation creates a tuple between two objects [if applicable]
-// within OpenFGA.
+// within OpenFGA. If the request has an expiry time the tuples are
+// removed automatically at that time, otherwise any pending expiry of
+// the tuples is removed.
 func (r *controllerRoot) AddRelation(ctx context.Context, req apiparams.AddRelationRequest) error {
 	const op = errors.Op("jujuapi.AddRelation")
 
@@ -108,11 +110,21 @@ func (r *controllerRoot) AddRelation(ctx context.Context, req apiparams.AddRelat
 	if err != nil {
 		return errors.E(err)
 	}
+	if req.ExpiresAt != nil {
+		if err := r.jimm.AddTemporaryRelations(ctx, r.user, *req.ExpiresAt, keys...); err != nil {
+			zapctx.Error(ctx, "failed to add temporary tuple(s)", zap.NamedError("add-relation-error", err))
+			return errors.E(op, err)
+		}
+		return nil
+	}
 	err = r.jimm.AuthorizationClient().AddRelation(ctx, keys...)
 	if err != nil {
 		zapctx.Error(ctx, "failed to add tuple(s)", zap.NamedError("add-relation-error", err))
 		return errors.E(op, errors.CodeOpenFGARequestFailed, err)
 	}
+	if err := r.jimm.ClearRelationExpiries(ctx, keys...); err != nil {
+		return errors.E(op, err)
+	}
 	return nil
 }
 
@@ -133,6 +145,9 @@ func (r *controllerRoot) RemoveRelation(ctx context.Context, req apiparams.Remov
 		zapctx.Error(ctx, "failed to delete tuple(s)", zap.NamedError("remove-relation-error", err))
 		return errors.E(op, err)
 	}
+	if err := r.jimm.ClearRelationExpiries(ctx, keys...); err != nil {
+		return errors.E(op, err)
+	}
 	return nil
 }
 
@@ -166,6 +181,38 @@ func (r *controllerRoot) CheckRelation(ctx context.Context, req apiparams.CheckR
 	return checkResp, nil
 }
 
+// ExplainRelation checks a relation and returns the chains of relations
+// through which it is allowed.
+func (r *controllerRoot) ExplainRelation(ctx context.Context, req apiparams.ExplainRelationRequest) (apiparams.ExplainRelationResponse, error) {
+	const op = errors.Op("jujuapi.ExplainRelation")
+
+	parsedTuple, err := r.parseTuple(ctx, req.Tuple)
+	if err != nil {
+		return apiparams.ExplainRelationResponse{}, errors.E(op, errors.CodeFailedToParseTupleKey, err)
+	}
+	explanation, err := r.jimm.ExplainRelation(ctx, r.user, *parsedTuple)
+	if err != nil {
+		return apiparams.ExplainRelationResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ExplainRelationResponse{
+		Allowed: explanation.Allowed,
+	}
+	for _, g := range explanation.Grants {
+		path := apiparams.RelationPath{
+			Identity: r.jaasTag(ctx, g.Identity),
+			Source:   g.Source(),
+		}
+		for _, step := range g.Steps() {
+			path.Steps = append(path.Steps, apiparams.RelationPathStep{
+				Relation: string(step.Relation),
+				Target:   r.jaasTag(ctx, step.Target),
+			})
+		}
+		resp.Paths = append(resp.Paths, path)
+	}
+	return resp, nil
+}
+
 // parseTuples translate the api request struct containing tuples to a slice of openfga tuple keys.
 // This method utilises the parseTuple method which does all the heavy lifting.
 func (r *controllerRoot) parseTuples(ctx context.Context, tuples []apiparams.RelationshipTuple) ([]openfga.Tuple, error) {
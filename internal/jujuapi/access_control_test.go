@@ -491,6 +491,71 @@ func (s *accessControlSuite) TestAddRelation(c *gc.C) {
 	}
 }
 
+func (s *accessControlSuite) TestAddTemporaryRelation(c *gc.C) {
+	ctx := context.Background()
+
+	user, _, _, model, _, _, _, client, closeClient := createTestControllerEnvironment(ctx, c, s)
+	defer closeClient()
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	err := client.AddRelation(&apiparams.AddRelationRequest{
+		Tuples: []apiparams.RelationshipTuple{{
+			Object:       "user-" + user.Name,
+			Relation:     "administrator",
+			TargetObject: "model-" + model.UUID.String,
+		}},
+		ExpiresAt: &expiresAt,
+	})
+	c.Assert(err, gc.IsNil)
+
+	changes, err := s.COFGAClient.ReadChanges(ctx, "model", 99, "")
+	c.Assert(err, gc.IsNil)
+	key := changes.GetChanges()[len(changes.GetChanges())-1].GetTupleKey()
+	c.Assert(*key.User, gc.Equals, "user:"+user.Name)
+	c.Assert(*key.Relation, gc.Equals, "administrator")
+	c.Assert(*key.Object, gc.Equals, "model:"+model.UUID.String)
+
+	var expiries []dbmodel.RelationExpiry
+	err = s.JIMM.Database.ForEachRelationExpiry(ctx, time.Time{}, func(re *dbmodel.RelationExpiry) error {
+		expiries = append(expiries, *re)
+		return nil
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(expiries, gc.HasLen, 1)
+	c.Check(expiries[0].Object, gc.Equals, "user:"+user.Name)
+	c.Check(expiries[0].Relation, gc.Equals, "administrator")
+	c.Check(expiries[0].Target, gc.Equals, "model:"+model.UUID.String)
+	c.Check(expiries[0].ExpiresAt.Equal(expiresAt), gc.Equals, true)
+
+	past := time.Now().Add(-time.Hour)
+	err = client.AddRelation(&apiparams.AddRelationRequest{
+		Tuples: []apiparams.RelationshipTuple{{
+			Object:       "user-" + user.Name,
+			Relation:     "reader",
+			TargetObject: "model-" + model.UUID.String,
+		}},
+		ExpiresAt: &past,
+	})
+	c.Assert(err, gc.ErrorMatches, `expiry time must be in the future.*`)
+
+	// Removing the relation removes its expiry.
+	err = client.RemoveRelation(&apiparams.RemoveRelationRequest{
+		Tuples: []apiparams.RelationshipTuple{{
+			Object:       "user-" + user.Name,
+			Relation:     "administrator",
+			TargetObject: "model-" + model.UUID.String,
+		}},
+	})
+	c.Assert(err, gc.IsNil)
+	expiries = nil
+	err = s.JIMM.Database.ForEachRelationExpiry(ctx, time.Time{}, func(re *dbmodel.RelationExpiry) error {
+		expiries = append(expiries, *re)
+		return nil
+	})
+	c.Assert(err, gc.IsNil)
+	c.Check(expiries, gc.HasLen, 0)
+}
+
 // TestRemoveRelation currently verifies the following test cases,
 // similar to the TestAddRelation but instead we add the relations and then
 // remove them.
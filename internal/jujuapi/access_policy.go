@@ -0,0 +1,26 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// access_policy contains the RPC command for applying a declarative
+// access policy via the JIMM facade.
+
+// ApplyAccessPolicy returns the changes required to bring the groups and
+// relations in JIMM in line with the requested policy, making them if
+// requested.
+func (r *controllerRoot) ApplyAccessPolicy(ctx context.Context, req apiparams.ApplyAccessPolicyRequest) (apiparams.AccessPolicyPlan, error) {
+	const op = errors.Op("jujuapi.ApplyAccessPolicy")
+
+	plan, err := r.jimm.ApplyAccessPolicy(ctx, r.user, req.Policy, req.Apply)
+	if err != nil {
+		return apiparams.AccessPolicyPlan{}, errors.E(op, err)
+	}
+	return *plan, nil
+}
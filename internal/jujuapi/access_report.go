@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// access_report contains the RPC command for reporting the effective
+// access to resources via the JIMM facade.
+
+// AccessReport returns the effective access to the requested resource,
+// or of the requested identity, expanding group membership and inherited
+// relations.
+func (r *controllerRoot) AccessReport(ctx context.Context, req apiparams.AccessReportRequest) (apiparams.AccessReportResponse, error) {
+	const op = errors.Op("jujuapi.AccessReport")
+
+	if (req.Resource == "") == (req.Identity == "") {
+		return apiparams.AccessReportResponse{}, errors.E(op, errors.CodeBadRequest, "either a resource or an identity must be specified")
+	}
+	var grants []jimm.AccessGrant
+	if req.Resource != "" {
+		resource, err := r.jimm.ParseTag(ctx, req.Resource)
+		if err != nil {
+			return apiparams.AccessReportResponse{}, errors.E(op, err)
+		}
+		grants, err = r.jimm.ResourceAccessReport(ctx, r.user, resource)
+		if err != nil {
+			return apiparams.AccessReportResponse{}, errors.E(op, err)
+		}
+	} else {
+		identity, err := r.jimm.ParseTag(ctx, req.Identity)
+		if err != nil {
+			return apiparams.AccessReportResponse{}, errors.E(op, err)
+		}
+		grants, err = r.jimm.IdentityAccessReport(ctx, r.user, identity)
+		if err != nil {
+			return apiparams.AccessReportResponse{}, errors.E(op, err)
+		}
+	}
+
+	resp := apiparams.AccessReportResponse{
+		Entries: make([]apiparams.AccessReportEntry, len(grants)),
+	}
+	for i, g := range grants {
+		entry := apiparams.AccessReportEntry{
+			Identity: r.jaasTag(ctx, g.Identity),
+			Resource: r.jaasTag(ctx, g.Resource),
+			Relation: string(g.Relation),
+			Source:   g.Source(),
+		}
+		for _, t := range g.Via {
+			entry.Via = append(entry.Via, r.jaasTag(ctx, t))
+		}
+		resp.Entries[i] = entry
+	}
+	return resp, nil
+}
+
+// jaasTag returns the human readable JAAS tag for the given OpenFGA tag,
+// falling back to the OpenFGA representation if the tag cannot be
+// resolved.
+func (r *controllerRoot) jaasTag(ctx context.Context, tag *openfga.Tag) string {
+	s, err := r.jimm.ToJAASTag(ctx, tag, true)
+	if err != nil {
+		return tag.String()
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// access_request contains the RPC commands for requesting access to
+// resources and reviewing those requests via the JIMM facade.
+
+// RequestAccess files a request for access to a model, cloud or
+// application offer on behalf of the authenticated user.
+func (r *controllerRoot) RequestAccess(ctx context.Context, req apiparams.RequestAccessRequest) (apiparams.AccessRequest, error) {
+	const op = errors.Op("jujuapi.RequestAccess")
+
+	if req.Resource == "" {
+		return apiparams.AccessRequest{}, errors.E(op, errors.CodeBadRequest, "resource not specified")
+	}
+	ar, err := r.jimm.RequestAccess(ctx, r.user, req.Resource, req.Access, req.Reason)
+	if err != nil {
+		zapctx.Error(ctx, "failed to request access", zaputil.Error(err))
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	return ar.ToAPIAccessRequest(), nil
+}
+
+// ListAccessRequests lists either the access requests the authenticated
+// user may review, or those the user has made.
+func (r *controllerRoot) ListAccessRequests(ctx context.Context, req apiparams.ListAccessRequestsRequest) (apiparams.ListAccessRequestsResponse, error) {
+	const op = errors.Op("jujuapi.ListAccessRequests")
+
+	requests, err := r.jimm.ListAccessRequests(ctx, r.user, req.Status, req.Mine)
+	if err != nil {
+		return apiparams.ListAccessRequestsResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListAccessRequestsResponse{
+		Requests: make([]apiparams.AccessRequest, len(requests)),
+	}
+	for i, ar := range requests {
+		resp.Requests[i] = ar.ToAPIAccessRequest()
+	}
+	return resp, nil
+}
+
+// ApproveAccessRequest approves an access request, granting the
+// requested access.
+func (r *controllerRoot) ApproveAccessRequest(ctx context.Context, req apiparams.ReviewAccessRequestRequest) (apiparams.AccessRequest, error) {
+	const op = errors.Op("jujuapi.ApproveAccessRequest")
+
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	ar, err := r.jimm.ApproveAccessRequest(ctx, r.user, req.ID, req.Comment, expiresAt)
+	if err != nil {
+		zapctx.Error(ctx, "failed to approve access request", zaputil.Error(err))
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	return ar.ToAPIAccessRequest(), nil
+}
+
+// DenyAccessRequest denies an access request.
+func (r *controllerRoot) DenyAccessRequest(ctx context.Context, req apiparams.ReviewAccessRequestRequest) (apiparams.AccessRequest, error) {
+	const op = errors.Op("jujuapi.DenyAccessRequest")
+
+	if req.ExpiresAt != nil {
+		return apiparams.AccessRequest{}, errors.E(op, errors.CodeBadRequest, "expiry time cannot be set when denying a request")
+	}
+	ar, err := r.jimm.DenyAccessRequest(ctx, r.user, req.ID, req.Comment)
+	if err != nil {
+		zapctx.Error(ctx, "failed to deny access request", zaputil.Error(err))
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	return ar.ToAPIAccessRequest(), nil
+}
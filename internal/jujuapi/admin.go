@@ -97,9 +97,7 @@ func (r *controllerRoot) LoginWithSessionCookie(ctx context.Context) (jujuparams
 		return jujuparams.LoginResult{}, errors.E(op, err, errors.CodeUnauthorized)
 	}
 
-	r.mu.Lock()
-	r.user = user
-	r.mu.Unlock()
+	r.setUser(user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
@@ -129,11 +127,7 @@ func (r *controllerRoot) LoginWithSessionToken(ctx context.Context, req params.L
 		return jujuparams.LoginResult{}, errors.E(op, err, errors.CodeUnauthorized)
 	}
 
-	// TODO(ale8k): This isn't needed I don't think as controller roots are unique
-	// per WS, but if anyone knows different please let me know.
-	r.mu.Lock()
-	r.user = user
-	r.mu.Unlock()
+	r.setUser(user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
@@ -160,9 +154,7 @@ func (r *controllerRoot) LoginWithClientCredentials(ctx context.Context, req par
 		return jujuparams.LoginResult{}, errors.E(err, errors.CodeUnauthorized)
 	}
 
-	r.mu.Lock()
-	r.user = user
-	r.mu.Unlock()
+	r.setUser(user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
@@ -0,0 +1,245 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// auditExportFlushInterval is the number of events written between
+// flushes of the response.
+const auditExportFlushInterval = 100
+
+// auditExportCSVHeader holds the column names of a CSV audit log export.
+var auditExportCSVHeader = []string{
+	"time",
+	"conversation-id",
+	"message-id",
+	"facade-name",
+	"facade-method",
+	"facade-version",
+	"object-id",
+	"user-tag",
+	"model",
+	"is-response",
+	"params",
+	"errors",
+}
+
+// AuditExportHandler creates an http.Handler that streams the audit
+// events matching the filters in the request query to the client. The
+// query parameters have the same names as the fields of
+// apiparams.FindAuditEventsRequest, except for "offset" and
+// "continuation-token" which are not supported. Unlike FindAuditEvents
+// the number of events is not limited unless a "limit" is specified. The
+// "format" parameter selects either "ndjson" (the default) or "csv"
+// output. Clients authenticate using their session token as a bearer
+// token.
+func AuditExportHandler(ctx context.Context, jimm *jimm.JIMM) http.Handler {
+	return &auditExportHandler{jimm: jimm}
+}
+
+type auditExportHandler struct {
+	jimm *jimm.JIMM
+}
+
+// ServeHTTP implements http.Handler.
+func (h *auditExportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if req.Method != http.MethodGet {
+		writeAuditExportError(ctx, w, http.StatusMethodNotAllowed, errors.E(errors.CodeBadRequest, "method not allowed"))
+		return
+	}
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		writeAuditExportError(ctx, w, http.StatusUnauthorized, errors.E(errors.CodeUnauthorized, "missing session token"))
+		return
+	}
+	user, err := h.jimm.LoginWithSessionToken(ctx, token)
+	if err != nil {
+		writeAuditExportError(ctx, w, http.StatusUnauthorized, errors.E(err, errors.CodeUnauthorized))
+		return
+	}
+
+	filter, err := auditExportFilter(req.URL.Query())
+	if err != nil {
+		writeAuditExportError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	aw := &auditExportWriter{ResponseWriter: w}
+	var write func(*dbmodel.AuditLogEntry) error
+	var flush func() error
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "ndjson":
+		bw := bufio.NewWriter(aw)
+		enc := json.NewEncoder(bw)
+		write = func(ale *dbmodel.AuditLogEntry) error {
+			return enc.Encode(ale.ToAPIAuditEvent())
+		}
+		flush = bw.Flush
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	case "csv":
+		cw := csv.NewWriter(aw)
+		write = func(ale *dbmodel.AuditLogEntry) error {
+			return cw.Write(auditExportCSVRecord(ale))
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		if err := cw.Write(auditExportCSVHeader); err != nil {
+			writeAuditExportError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+	default:
+		writeAuditExportError(ctx, w, http.StatusBadRequest, errors.E(errors.CodeBadRequest, `invalid "format", expected "ndjson" or "csv"`))
+		return
+	}
+
+	var n int
+	err = h.jimm.ForEachAuditEvent(ctx, user, filter, func(ale *dbmodel.AuditLogEntry) error {
+		if err := write(ale); err != nil {
+			return err
+		}
+		n++
+		if n%auditExportFlushInterval == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if !aw.written {
+			// Nothing has been sent to the client yet, so an error
+			// response can still be sent.
+			status := http.StatusInternalServerError
+			switch errors.ErrorCode(err) {
+			case errors.CodeUnauthorized:
+				status = http.StatusForbidden
+			case errors.CodeBadRequest:
+				status = http.StatusBadRequest
+			}
+			writeAuditExportError(ctx, w, status, err)
+			return
+		}
+		// Abort the response so that the client does not mistake
+		// the partial export for a complete one.
+		zapctx.Error(ctx, "audit log export failed", zap.Error(err))
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// An auditExportWriter is an http.ResponseWriter that records whether
+// any of the response body has been written.
+type auditExportWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// Write implements io.Writer.
+func (w *auditExportWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
+
+// auditExportFilter returns the audit log filter specified by the given
+// query parameters.
+func auditExportFilter(q url.Values) (db.AuditLogFilter, error) {
+	req := apiparams.FindAuditEventsRequest{
+		After:          q.Get("after"),
+		Before:         q.Get("before"),
+		UserTag:        q.Get("user-tag"),
+		Model:          q.Get("model"),
+		Method:         q.Get("method"),
+		FacadeName:     q.Get("facade-name"),
+		ConversationId: q.Get("conversation-id"),
+		ObjectId:       q.Get("object-id"),
+		ParamsContain:  q.Get("params-contain"),
+	}
+	var err error
+	if v := q.Get("has-errors"); v != "" {
+		if req.HasErrors, err = strconv.ParseBool(v); err != nil {
+			return db.AuditLogFilter{}, errors.E(err, errors.CodeBadRequest, `invalid "has-errors" filter`)
+		}
+	}
+	if v := q.Get("sortTime"); v != "" {
+		if req.SortTime, err = strconv.ParseBool(v); err != nil {
+			return db.AuditLogFilter{}, errors.E(err, errors.CodeBadRequest, `invalid "sortTime" parameter`)
+		}
+	}
+	if v := q.Get("restored"); v != "" {
+		if req.Restored, err = strconv.ParseBool(v); err != nil {
+			return db.AuditLogFilter{}, errors.E(err, errors.CodeBadRequest, `invalid "restored" parameter`)
+		}
+	}
+	var limit int
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return db.AuditLogFilter{}, errors.E(errors.CodeBadRequest, `invalid "limit" parameter`)
+		}
+	}
+	filter, err := auditParamsToFilter(req)
+	if err != nil {
+		return db.AuditLogFilter{}, err
+	}
+	filter.Limit = limit
+	return filter, nil
+}
+
+// auditExportCSVRecord returns the CSV record for the given entry, with
+// fields in the order of auditExportCSVHeader.
+func auditExportCSVRecord(ale *dbmodel.AuditLogEntry) []string {
+	return []string{
+		ale.Time.UTC().Format(time.RFC3339Nano),
+		ale.ConversationId,
+		strconv.FormatUint(ale.MessageId, 10),
+		ale.FacadeName,
+		ale.FacadeMethod,
+		strconv.Itoa(ale.FacadeVersion),
+		ale.ObjectId,
+		ale.IdentityTag,
+		ale.Model,
+		strconv.FormatBool(ale.IsResponse),
+		string(ale.Params),
+		string(ale.Errors),
+	}
+}
+
+// writeAuditExportError writes the given error to the client as a JSON
+// encoded juju API error with the given status.
+func writeAuditExportError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	zapctx.Debug(ctx, "audit log export error", zap.Error(err))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(mapError(err)); err != nil {
+		zapctx.Error(ctx, "cannot write error response", zap.Error(err))
+	}
+}
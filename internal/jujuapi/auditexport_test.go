@@ -0,0 +1,113 @@
+// Copyright 2024 Canonical.
+
+package jujuapi_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type auditExportSuite struct {
+	websocketSuite
+}
+
+var _ = gc.Suite(&auditExportSuite{})
+
+// addAuditEvents adds audit events by making ListControllers calls as
+// alice.
+func (s *auditExportSuite) addAuditEvents(c *gc.C, n int) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+	for i := 0; i < n; i++ {
+		_, err := client.ListControllers()
+		c.Assert(err, gc.Equals, nil)
+	}
+}
+
+func (s *auditExportSuite) TestExportNDJSON(c *gc.C) {
+	s.addAuditEvents(c, 3)
+
+	r, err := api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, jimmtest.NewUserSessionToken(c, "alice"), &apiparams.FindAuditEventsRequest{
+		Method: "ListControllers",
+	}, api.AuditExportNDJSON)
+	c.Assert(err, gc.Equals, nil)
+	defer r.Close()
+
+	dec := json.NewDecoder(r)
+	var events []apiparams.AuditEvent
+	for {
+		var ev apiparams.AuditEvent
+		err := dec.Decode(&ev)
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, gc.Equals, nil)
+		events = append(events, ev)
+	}
+	c.Assert(events, gc.HasLen, 6)
+	for i, ev := range events {
+		c.Check(ev.FacadeMethod, gc.Equals, "ListControllers")
+		c.Check(ev.IsResponse, gc.Equals, i%2 == 1)
+		c.Check(ev.UserTag, gc.Equals, "user-alice@canonical.com")
+		if i > 0 {
+			c.Check(ev.Time.Before(events[i-1].Time), gc.Equals, false)
+		}
+	}
+}
+
+func (s *auditExportSuite) TestExportCSV(c *gc.C) {
+	s.addAuditEvents(c, 2)
+
+	r, err := api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, jimmtest.NewUserSessionToken(c, "alice"), &apiparams.FindAuditEventsRequest{
+		Method:   "ListControllers",
+		SortTime: true,
+		Limit:    3,
+	}, api.AuditExportCSV)
+	c.Assert(err, gc.Equals, nil)
+	defer r.Close()
+
+	records, err := csv.NewReader(r).ReadAll()
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(records, gc.HasLen, 4)
+	c.Check(strings.Join(records[0], ","), gc.Equals, "time,conversation-id,message-id,facade-name,facade-method,facade-version,object-id,user-tag,model,is-response,params,errors")
+	c.Check(records[1][4], gc.Equals, "ListControllers")
+	c.Check(records[1][9], gc.Equals, "true")
+	c.Check(records[2][9], gc.Equals, "false")
+	c.Check(records[3][9], gc.Equals, "true")
+}
+
+func (s *auditExportSuite) TestExportUnauthorized(c *gc.C) {
+	_, err := api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, jimmtest.NewUserSessionToken(c, "bob"), &apiparams.FindAuditEventsRequest{}, api.AuditExportNDJSON)
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	c.Check(jujuparams.ErrCode(err), gc.Equals, jujuparams.CodeUnauthorized)
+
+	_, err = api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, "not-a-token", &apiparams.FindAuditEventsRequest{}, api.AuditExportNDJSON)
+	c.Check(jujuparams.ErrCode(err), gc.Equals, jujuparams.CodeUnauthorized)
+
+	resp, err := s.HTTP.Client().Get(s.HTTP.URL + api.AuditExportPath)
+	c.Assert(err, gc.Equals, nil)
+	resp.Body.Close()
+	c.Check(resp.StatusCode, gc.Equals, http.StatusUnauthorized)
+}
+
+func (s *auditExportSuite) TestExportBadRequest(c *gc.C) {
+	_, err := api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, jimmtest.NewUserSessionToken(c, "alice"), &apiparams.FindAuditEventsRequest{}, "yaml")
+	c.Check(err, gc.ErrorMatches, `invalid "format", expected "ndjson" or "csv" \(bad request\)`)
+
+	_, err = api.ExportAuditEvents(context.Background(), s.HTTP.Client(), s.HTTP.URL, jimmtest.NewUserSessionToken(c, "alice"), &apiparams.FindAuditEventsRequest{
+		After: "yesterday",
+	}, api.AuditExportNDJSON)
+	c.Check(err, gc.ErrorMatches, `invalid "after" filter \(bad request\)`)
+}
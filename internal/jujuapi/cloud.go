@@ -426,7 +426,9 @@ func (r *controllerRoot) modifyCloudAccess(ctx context.Context, change jujuparam
 	var modifyf func(context.Context, *openfga.User, names.CloudTag, names.UserTag, string) error
 	switch change.Action {
 	case jujuparams.GrantCloudAccess:
-		modifyf = r.jimm.GrantCloudAccess
+		modifyf = func(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error {
+			return r.jimm.GrantCloudAccess(ctx, user, ct, ut, access)
+		}
 	case jujuparams.RevokeCloudAccess:
 		modifyf = r.jimm.RevokeCloudAccess
 	default:
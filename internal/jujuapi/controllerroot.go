@@ -4,6 +4,7 @@ package jujuapi
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/rogpeppe/fastuuid"
 	"golang.org/x/oauth2"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
@@ -24,6 +26,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -36,11 +39,17 @@ type JIMM interface {
 	AddHostedCloud(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddGroup(ctx context.Context, user *openfga.User, name string) (*dbmodel.GroupEntry, error)
 	AddServiceAccount(ctx context.Context, u *openfga.User, clientId string) error
+	AddTemporaryRelations(ctx context.Context, user *openfga.User, expiresAt time.Time, tuples ...openfga.Tuple) error
+	ApplyAccessPolicy(ctx context.Context, user *openfga.User, policy apiparams.AccessPolicy, apply bool) (*apiparams.AccessPolicyPlan, error)
+	ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string, expiresAt time.Time) (*dbmodel.AccessRequest, error)
 	AuthorizationClient() *openfga.OFGAClient
+	ClearRelationExpiries(ctx context.Context, tuples ...openfga.Tuple) error
 	CopyServiceAccountCredential(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
 	DB() *db.Database
+	DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) (*dbmodel.AccessRequest, error)
 	DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error
 	EarliestControllerVersion(ctx context.Context) (version.Number, error)
+	ExplainRelation(ctx context.Context, user *openfga.User, tuple openfga.Tuple) (*jimm.RelationExplanation, error)
 	FindApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	FindAuditEvents(ctx context.Context, user *openfga.User, filter db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error)
 	ForEachCloud(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
@@ -53,6 +62,7 @@ type JIMM interface {
 	GetCloudCredentialAttributes(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
 	GetControllerConfig(ctx context.Context, u *dbmodel.Identity) (*dbmodel.ControllerConfig, error)
 	GetCredentialStore() credentials.CredentialStore
+	GetIdentity(ctx context.Context, user *openfga.User, identityName string) (*jimm.IdentityDetails, error)
 	GetJimmControllerAccess(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
 	GetUserCloudAccess(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error)
 	GetUserControllerAccess(ctx context.Context, user *openfga.User, controller names.ControllerTag) (string, error)
@@ -62,19 +72,30 @@ type JIMM interface {
 	GrantModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
 	GrantOfferAccess(ctx context.Context, u *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error
 	GrantServiceAccountAccess(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, tags []string) error
+	IdentityAccessReport(ctx context.Context, user *openfga.User, identity *ofganames.Tag) ([]jimm.AccessGrant, error)
 	InitiateInternalMigration(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetController string) (jujuparams.InitiateMigrationResult, error)
 	InitiateMigration(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
+	ListAccessRequests(ctx context.Context, user *openfga.User, status string, mine bool) ([]dbmodel.AccessRequest, error)
 	ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
+	ListAuditLogArchives(ctx context.Context, user *openfga.User) ([]dbmodel.AuditLogArchive, error)
+	ListIdentities(ctx context.Context, user *openfga.User, filter db.IdentityFilter) ([]dbmodel.Identity, error)
 	ListGroups(ctx context.Context, user *openfga.User) ([]dbmodel.GroupEntry, error)
+	ListQuotas(ctx context.Context, user *openfga.User) ([]jimm.QuotaStatus, error)
+	OffboardIdentity(ctx context.Context, user *openfga.User, identityName, newOwnerName string) (*apiparams.OffboardSummary, error)
 	Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
 	ParseTag(ctx context.Context, key string) (*ofganames.Tag, error)
 	PubSubHub() *pubsub.Hub
 	PurgeLogs(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	QuotaSubjectTag(ctx context.Context, q *dbmodel.Quota) (string, error)
+	RequestAccess(ctx context.Context, user *openfga.User, resource, access, reason string) (*dbmodel.AccessRequest, error)
+	RestoreAuditLogArchive(ctx context.Context, user *openfga.User, id uint) (int64, error)
 	RenameGroup(ctx context.Context, user *openfga.User, oldName, newName string) error
 	RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
 	RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error
 	RemoveGroup(ctx context.Context, user *openfga.User, name string) error
+	RemoveQuota(ctx context.Context, user *openfga.User, subject string, cloud names.CloudTag, region string) error
+	ResourceAccessReport(ctx context.Context, user *openfga.User, resource *ofganames.Tag) ([]jimm.AccessGrant, error)
 	ResourceTag() names.ControllerTag
 	RevokeAuditLogAccess(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	RevokeCloudAccess(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
@@ -82,11 +103,25 @@ type JIMM interface {
 	RevokeModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
 	RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
 	SetControllerConfig(ctx context.Context, u *openfga.User, args jujuparams.ControllerConfigSet) error
+	SetControllerCapacity(ctx context.Context, user *openfga.User, controllerName string, maxModels, maxUnits, maxMachines sql.NullInt64) error
 	SetControllerDeprecated(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerRegionPriority(ctx context.Context, user *openfga.User, controllerName, cloudName, regionName string, priority uint) error
+	SetIdentityDisabled(ctx context.Context, user *openfga.User, identityName string, disabled bool) error
+	ListControllerRegionPriorities(ctx context.Context, user *openfga.User, controllerName string) ([]dbmodel.CloudRegionControllerPriority, error)
+	DrainController(ctx context.Context, user *openfga.User, controllerName string, batchSize int) (*dbmodel.ControllerDrain, error)
+	ControllerDrainStatus(ctx context.Context, user *openfga.User, controllerName string) (*dbmodel.ControllerDrain, error)
+	ListMigrations(ctx context.Context, user *openfga.User, filter db.MigrationFilter) ([]dbmodel.Migration, error)
+	MigrationStatus(ctx context.Context, user *openfga.User, migrationID string, modelTag names.ModelTag) (*dbmodel.Migration, error)
+	Reconcile(ctx context.Context, user *openfga.User, controllerName string, fix bool) ([]jimm.ReconcileReport, error)
+	SetQuota(ctx context.Context, user *openfga.User, q *dbmodel.Quota) error
+	StaleReport(ctx context.Context, user *openfga.User, days int) (*jimm.StaleReport, error)
 	ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TrackSession(identityName string, terminate func()) (untrack func())
+	TransferModelOwnership(ctx context.Context, user *openfga.User, mt names.ModelTag, newOwnerName string, credTag names.CloudCredentialTag) (*apiparams.ModelOwnershipTransfer, error)
 	UpdateApplicationOffer(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error
 	UpdateCloud(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
+	VerifyAuditLog(ctx context.Context, user *openfga.User) (*jimm.AuditLogVerification, error)
 	UserLogin(ctx context.Context, identityName string) (*openfga.User, error)
 }
 
@@ -116,6 +151,14 @@ type controllerRoot struct {
 
 	// identityId is the id of the identity attempting to login via a session cookie.
 	identityId string
+
+	// terminate, if set, ends the connection. It is called when the
+	// logged in identity is disabled.
+	terminate func()
+
+	// untrackSession removes the session of the logged in identity
+	// from those tracked by JIMM.
+	untrackSession func()
 }
 
 func newControllerRoot(j JIMM, p Params, identityId string) *controllerRoot {
@@ -186,6 +229,33 @@ func (r *controllerRoot) setPingF(f func()) {
 	r.pingF = f
 }
 
+// setUser sets the authenticated user of the connection and records
+// the session so that it can be terminated if the identity is disabled.
+func (r *controllerRoot) setUser(user *openfga.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.user = user
+	if r.untrackSession != nil {
+		r.untrackSession()
+		r.untrackSession = nil
+	}
+	if r.terminate != nil {
+		r.untrackSession = r.jimm.TrackSession(user.Name, r.terminate)
+	}
+}
+
+// endSession stops tracking the session of the logged in identity.
+func (r *controllerRoot) endSession() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.untrackSession != nil {
+		r.untrackSession()
+		r.untrackSession = nil
+	}
+}
+
 // cleanup releases all resources used by the controllerRoot.
 func (r *controllerRoot) cleanup() {
 	r.watchers.stop()
@@ -207,8 +277,8 @@ func (r *controllerRoot) setupUUIDGenerator() error {
 	return nil
 }
 
-func (r *controllerRoot) newAuditLogger() jimm.DbAuditLogger {
-	return jimm.NewDbAuditLogger(r.jimm, r.getUser)
+func (r *controllerRoot) newAuditLogger(redactor *auditredact.Redactor) jimm.DbAuditLogger {
+	return jimm.NewDbAuditLogger(r.jimm, redactor, r.getUser)
 }
 
 // getUser implements jujuapi.root interface to return the currently logged in user.
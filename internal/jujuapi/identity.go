@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// identity contains the RPC commands for inspecting the identities known
+// to JIMM via the JIMM facade.
+
+// ListIdentities lists the identities known to JIMM, ordered by name.
+func (r *controllerRoot) ListIdentities(ctx context.Context, req apiparams.ListIdentitiesRequest) (apiparams.ListIdentitiesResponse, error) {
+	const op = errors.Op("jujuapi.ListIdentities")
+
+	filter := db.IdentityFilter{
+		Match:    req.Match,
+		Disabled: req.Disabled,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	}
+	if filter.Limit < 1 {
+		filter.Limit = limitDefault
+	}
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	identities, err := r.jimm.ListIdentities(ctx, r.user, filter)
+	if err != nil {
+		return apiparams.ListIdentitiesResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListIdentitiesResponse{
+		Identities: make([]apiparams.Identity, len(identities)),
+	}
+	for i, identity := range identities {
+		resp.Identities[i] = identity.ToAPIIdentity()
+	}
+	return resp, nil
+}
+
+// GetIdentity returns the details of an identity, including the cloud
+// credentials and models it owns and the groups it is a member of.
+func (r *controllerRoot) GetIdentity(ctx context.Context, req apiparams.GetIdentityRequest) (apiparams.IdentityDetails, error) {
+	const op = errors.Op("jujuapi.GetIdentity")
+
+	ut, err := parseUserTag(req.Tag)
+	if err != nil {
+		return apiparams.IdentityDetails{}, errors.E(op, err)
+	}
+	details, err := r.jimm.GetIdentity(ctx, r.user, ut.Id())
+	if err != nil {
+		return apiparams.IdentityDetails{}, errors.E(op, err)
+	}
+
+	resp := apiparams.IdentityDetails{
+		Identity: details.Identity.ToAPIIdentity(),
+	}
+	for _, cred := range details.CloudCredentials {
+		resp.CloudCredentials = append(resp.CloudCredentials, cred.Path())
+	}
+	for _, m := range details.Models {
+		resp.Models = append(resp.Models, m.ToAPIIdentityModel())
+	}
+	for _, g := range details.Groups {
+		resp.Groups = append(resp.Groups, g.Name)
+	}
+	return resp, nil
+}
+
+// OffboardIdentity transfers the models and cloud credentials of an
+// identity to a new owner, removes the identity's relations and disables
+// it.
+func (r *controllerRoot) OffboardIdentity(ctx context.Context, req apiparams.OffboardIdentityRequest) (apiparams.OffboardSummary, error) {
+	const op = errors.Op("jujuapi.OffboardIdentity")
+
+	ut, err := parseUserTag(req.Tag)
+	if err != nil {
+		return apiparams.OffboardSummary{}, errors.E(op, err)
+	}
+	newOwner, err := parseUserTag(req.NewOwnerTag)
+	if err != nil {
+		return apiparams.OffboardSummary{}, errors.E(op, err)
+	}
+	summary, err := r.jimm.OffboardIdentity(ctx, r.user, ut.Id(), newOwner.Id())
+	if err != nil {
+		return apiparams.OffboardSummary{}, errors.E(op, err)
+	}
+	return *summary, nil
+}
@@ -4,6 +4,8 @@ package jujuapi
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -30,12 +32,22 @@ func init() {
 		findAuditEventsMethod := rpc.Method(r.FindAuditEvents)
 		grantAuditLogAccessMethod := rpc.Method(r.GrantAuditLogAccess)
 		importModelMethod := rpc.Method(r.ImportModel)
+		importModelsMethod := rpc.Method(r.ImportModels)
 		listControllersMethod := rpc.Method(r.ListControllers)
 		removeControllerMethod := rpc.Method(r.RemoveController)
 		revokeAuditLogAccessMethod := rpc.Method(r.RevokeAuditLogAccess)
 		setControllerDeprecatedMethod := rpc.Method(r.SetControllerDeprecated)
+		setControllerRegionPriorityMethod := rpc.Method(r.SetControllerRegionPriority)
+		setControllerCapacityMethod := rpc.Method(r.SetControllerCapacity)
+		listControllerRegionPrioritiesMethod := rpc.Method(r.ListControllerRegionPriorities)
+		drainControllerMethod := rpc.Method(r.DrainController)
+		controllerDrainStatusMethod := rpc.Method(r.ControllerDrainStatus)
+		listMigrationsMethod := rpc.Method(r.ListMigrations)
+		migrationStatusMethod := rpc.Method(r.MigrationStatus)
+		reconcileMethod := rpc.Method(r.Reconcile)
 		fullModelStatusMethod := rpc.Method(r.FullModelStatus)
 		updateMigratedModelMethod := rpc.Method(r.UpdateMigratedModel)
+		transferModelOwnershipMethod := rpc.Method(r.TransferModelOwnership)
 		addCloudToControllerMethod := rpc.Method(r.AddCloudToController)
 		removeCloudFromControllerMethod := rpc.Method(r.RemoveCloudFromController)
 		addGroupMethod := rpc.Method(r.AddGroup)
@@ -45,15 +57,32 @@ func init() {
 		addRelationMethod := rpc.Method(r.AddRelation)
 		removeRelationMethod := rpc.Method(r.RemoveRelation)
 		checkRelationMethod := rpc.Method(r.CheckRelation)
+		explainRelationMethod := rpc.Method(r.ExplainRelation)
 		listRelationshipTuplesMethod := rpc.Method(r.ListRelationshipTuples)
 		crossModelQueryMethod := rpc.Method(r.CrossModelQuery)
 		purgeLogsMethod := rpc.Method(r.PurgeLogs)
+		verifyAuditLogMethod := rpc.Method(r.VerifyAuditLog)
+		listAuditLogArchivesMethod := rpc.Method(r.ListAuditLogArchives)
+		restoreAuditLogArchiveMethod := rpc.Method(r.RestoreAuditLogArchive)
 		migrateModel := rpc.Method(r.MigrateModel)
 		addServiceAccountMethod := rpc.Method(r.AddServiceAccount)
 		copyServiceAccountCredentialMethod := rpc.Method(r.CopyServiceAccountCredential)
 		updateServiceAccountCredentials := rpc.Method(r.UpdateServiceAccountCredentials)
 		listServiceAccountCredentials := rpc.Method(r.ListServiceAccountCredentials)
 		grantServiceAccountAccess := rpc.Method(r.GrantServiceAccountAccess)
+		setQuotaMethod := rpc.Method(r.SetQuota)
+		removeQuotaMethod := rpc.Method(r.RemoveQuota)
+		listQuotasMethod := rpc.Method(r.ListQuotas)
+		requestAccessMethod := rpc.Method(r.RequestAccess)
+		listAccessRequestsMethod := rpc.Method(r.ListAccessRequests)
+		approveAccessRequestMethod := rpc.Method(r.ApproveAccessRequest)
+		denyAccessRequestMethod := rpc.Method(r.DenyAccessRequest)
+		accessReportMethod := rpc.Method(r.AccessReport)
+		staleReportMethod := rpc.Method(r.StaleReport)
+		applyAccessPolicyMethod := rpc.Method(r.ApplyAccessPolicy)
+		listIdentitiesMethod := rpc.Method(r.ListIdentities)
+		getIdentityMethod := rpc.Method(r.GetIdentity)
+		offboardIdentityMethod := rpc.Method(r.OffboardIdentity)
 
 		// JIMM Generic RPC
 		r.AddMethod("JIMM", 4, "AddController", addControllerMethod)
@@ -62,14 +91,27 @@ func init() {
 		r.AddMethod("JIMM", 4, "FullModelStatus", fullModelStatusMethod)
 		r.AddMethod("JIMM", 4, "GrantAuditLogAccess", grantAuditLogAccessMethod)
 		r.AddMethod("JIMM", 4, "ImportModel", importModelMethod)
+		r.AddMethod("JIMM", 4, "ImportModels", importModelsMethod)
 		r.AddMethod("JIMM", 4, "ListControllers", listControllersMethod)
 		r.AddMethod("JIMM", 4, "RemoveController", removeControllerMethod)
 		r.AddMethod("JIMM", 4, "RevokeAuditLogAccess", revokeAuditLogAccessMethod)
 		r.AddMethod("JIMM", 4, "SetControllerDeprecated", setControllerDeprecatedMethod)
+		r.AddMethod("JIMM", 4, "SetControllerRegionPriority", setControllerRegionPriorityMethod)
+		r.AddMethod("JIMM", 4, "SetControllerCapacity", setControllerCapacityMethod)
+		r.AddMethod("JIMM", 4, "ListControllerRegionPriorities", listControllerRegionPrioritiesMethod)
+		r.AddMethod("JIMM", 4, "DrainController", drainControllerMethod)
+		r.AddMethod("JIMM", 4, "ControllerDrainStatus", controllerDrainStatusMethod)
+		r.AddMethod("JIMM", 4, "ListMigrations", listMigrationsMethod)
+		r.AddMethod("JIMM", 4, "MigrationStatus", migrationStatusMethod)
+		r.AddMethod("JIMM", 4, "Reconcile", reconcileMethod)
 		r.AddMethod("JIMM", 4, "UpdateMigratedModel", updateMigratedModelMethod)
+		r.AddMethod("JIMM", 4, "TransferModelOwnership", transferModelOwnershipMethod)
 		r.AddMethod("JIMM", 4, "AddCloudToController", addCloudToControllerMethod)
 		r.AddMethod("JIMM", 4, "RemoveCloudFromController", removeCloudFromControllerMethod)
 		r.AddMethod("JIMM", 4, "PurgeLogs", purgeLogsMethod)
+		r.AddMethod("JIMM", 4, "VerifyAuditLog", verifyAuditLogMethod)
+		r.AddMethod("JIMM", 4, "ListAuditLogArchives", listAuditLogArchivesMethod)
+		r.AddMethod("JIMM", 4, "RestoreAuditLogArchive", restoreAuditLogArchiveMethod)
 		r.AddMethod("JIMM", 4, "MigrateModel", migrateModel)
 		// JIMM ReBAC RPC
 		r.AddMethod("JIMM", 4, "AddGroup", addGroupMethod)
@@ -79,6 +121,7 @@ func init() {
 		r.AddMethod("JIMM", 4, "AddRelation", addRelationMethod)
 		r.AddMethod("JIMM", 4, "RemoveRelation", removeRelationMethod)
 		r.AddMethod("JIMM", 4, "CheckRelation", checkRelationMethod)
+		r.AddMethod("JIMM", 4, "ExplainRelation", explainRelationMethod)
 		r.AddMethod("JIMM", 4, "ListRelationshipTuples", listRelationshipTuplesMethod)
 		// JIMM Cross-model queries
 		r.AddMethod("JIMM", 4, "CrossModelQuery", crossModelQueryMethod)
@@ -88,6 +131,27 @@ func init() {
 		r.AddMethod("JIMM", 4, "UpdateServiceAccountCredentials", updateServiceAccountCredentials)
 		r.AddMethod("JIMM", 4, "ListServiceAccountCredentials", listServiceAccountCredentials)
 		r.AddMethod("JIMM", 4, "GrantServiceAccountAccess", grantServiceAccountAccess)
+		// JIMM Quotas
+		r.AddMethod("JIMM", 4, "SetQuota", setQuotaMethod)
+		r.AddMethod("JIMM", 4, "RemoveQuota", removeQuotaMethod)
+		r.AddMethod("JIMM", 4, "ListQuotas", listQuotasMethod)
+		// JIMM Access Requests
+		r.AddMethod("JIMM", 4, "RequestAccess", requestAccessMethod)
+		r.AddMethod("JIMM", 4, "ListAccessRequests", listAccessRequestsMethod)
+		r.AddMethod("JIMM", 4, "ApproveAccessRequest", approveAccessRequestMethod)
+		r.AddMethod("JIMM", 4, "DenyAccessRequest", denyAccessRequestMethod)
+
+		// JIMM Access Reports
+		r.AddMethod("JIMM", 4, "AccessReport", accessReportMethod)
+		r.AddMethod("JIMM", 4, "StaleReport", staleReportMethod)
+
+		// JIMM Access Policies
+		r.AddMethod("JIMM", 4, "ApplyAccessPolicy", applyAccessPolicyMethod)
+
+		// JIMM Identities
+		r.AddMethod("JIMM", 4, "ListIdentities", listIdentitiesMethod)
+		r.AddMethod("JIMM", 4, "GetIdentity", getIdentityMethod)
+		r.AddMethod("JIMM", 4, "OffboardIdentity", offboardIdentityMethod)
 
 		return []int{4}
 	}
@@ -196,6 +260,9 @@ func (r *controllerRoot) AddController(ctx context.Context, req apiparams.AddCon
 		AdminPassword:     req.Password,
 		TLSHostname:       req.TLSHostname,
 		Addresses:         dbmodel.HostPorts{jujuparams.FromProviderHostPorts(nphps)},
+		MaxModels:         toNullInt64(req.MaxModels),
+		MaxUnits:          toNullInt64(req.MaxUnits),
+		MaxMachines:       toNullInt64(req.MaxMachines),
 	}
 	if err := r.jimm.AddController(ctx, r.user, &ctl); err != nil {
 		zapctx.Error(ctx, "failed to add controller", zaputil.Error(err))
@@ -275,6 +342,142 @@ func (r *controllerRoot) SetControllerDeprecated(ctx context.Context, req apipar
 	return ctl.ToAPIControllerInfo(), nil
 }
 
+// SetControllerCapacity sets the capacity caps of a controller.
+func (r *controllerRoot) SetControllerCapacity(ctx context.Context, req apiparams.SetControllerCapacityRequest) (apiparams.ControllerInfo, error) {
+	const op = errors.Op("jujuapi.SetControllerCapacity")
+
+	if err := r.jimm.SetControllerCapacity(ctx, r.user, req.Name, toNullInt64(req.MaxModels), toNullInt64(req.MaxUnits), toNullInt64(req.MaxMachines)); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	ctl := dbmodel.Controller{
+		Name: req.Name,
+	}
+	if err := r.jimm.DB().GetController(ctx, &ctl); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	return ctl.ToAPIControllerInfo(), nil
+}
+
+// SetControllerRegionPriority sets the priority with which a controller
+// is chosen when placing new models in a cloud region.
+func (r *controllerRoot) SetControllerRegionPriority(ctx context.Context, req apiparams.SetControllerRegionPriorityRequest) error {
+	const op = errors.Op("jujuapi.SetControllerRegionPriority")
+
+	if err := r.jimm.SetControllerRegionPriority(ctx, r.user, req.Name, req.Cloud, req.Region, req.Priority); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListControllerRegionPriorities returns the cloud region priorities of
+// the requested controller, or all controllers if none is specified.
+func (r *controllerRoot) ListControllerRegionPriorities(ctx context.Context, req apiparams.ListControllerRegionPrioritiesRequest) (apiparams.ListControllerRegionPrioritiesResponse, error) {
+	const op = errors.Op("jujuapi.ListControllerRegionPriorities")
+
+	crps, err := r.jimm.ListControllerRegionPriorities(ctx, r.user, req.Name)
+	if err != nil {
+		return apiparams.ListControllerRegionPrioritiesResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListControllerRegionPrioritiesResponse{
+		Priorities: make([]apiparams.ControllerRegionPriority, len(crps)),
+	}
+	for i, crp := range crps {
+		resp.Priorities[i] = apiparams.ControllerRegionPriority{
+			Controller: crp.Controller.Name,
+			Cloud:      crp.CloudRegion.CloudName,
+			Region:     crp.CloudRegion.Name,
+			Priority:   crp.Priority,
+		}
+	}
+	return resp, nil
+}
+
+// DrainController starts, or resumes, migrating all models off a
+// controller.
+func (r *controllerRoot) DrainController(ctx context.Context, req apiparams.DrainControllerRequest) (apiparams.ControllerDrainStatus, error) {
+	const op = errors.Op("jujuapi.DrainController")
+
+	cd, err := r.jimm.DrainController(ctx, r.user, req.Name, req.BatchSize)
+	if err != nil {
+		zapctx.Error(ctx, "failed to drain controller", zaputil.Error(err))
+		return apiparams.ControllerDrainStatus{}, errors.E(op, err)
+	}
+	return cd.ToAPIControllerDrainStatus(), nil
+}
+
+// ControllerDrainStatus returns the progress of a controller drain.
+func (r *controllerRoot) ControllerDrainStatus(ctx context.Context, req apiparams.ControllerDrainStatusRequest) (apiparams.ControllerDrainStatus, error) {
+	const op = errors.Op("jujuapi.ControllerDrainStatus")
+
+	cd, err := r.jimm.ControllerDrainStatus(ctx, r.user, req.Name)
+	if err != nil {
+		return apiparams.ControllerDrainStatus{}, errors.E(op, err)
+	}
+	return cd.ToAPIControllerDrainStatus(), nil
+}
+
+// ListMigrations lists the model migrations known to JIMM, most recent
+// first.
+func (r *controllerRoot) ListMigrations(ctx context.Context, req apiparams.ListMigrationsRequest) (apiparams.ListMigrationsResponse, error) {
+	const op = errors.Op("jujuapi.ListMigrations")
+
+	filter := db.MigrationFilter{
+		Active: req.Active,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}
+	if req.ModelTag != "" {
+		mt, err := names.ParseModelTag(req.ModelTag)
+		if err != nil {
+			return apiparams.ListMigrationsResponse{}, errors.E(op, err, errors.CodeBadRequest)
+		}
+		filter.ModelUUID = mt.Id()
+	}
+	if filter.Limit < 1 {
+		filter.Limit = limitDefault
+	}
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	migrations, err := r.jimm.ListMigrations(ctx, r.user, filter)
+	if err != nil {
+		return apiparams.ListMigrationsResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListMigrationsResponse{
+		Migrations: make([]apiparams.Migration, len(migrations)),
+	}
+	for i, m := range migrations {
+		resp.Migrations[i] = m.ToAPIMigration()
+	}
+	return resp, nil
+}
+
+// MigrationStatus returns the status of a model migration.
+func (r *controllerRoot) MigrationStatus(ctx context.Context, req apiparams.MigrationStatusRequest) (apiparams.Migration, error) {
+	const op = errors.Op("jujuapi.MigrationStatus")
+
+	var mt names.ModelTag
+	switch {
+	case req.MigrationID != "":
+	case req.ModelTag != "":
+		var err error
+		mt, err = names.ParseModelTag(req.ModelTag)
+		if err != nil {
+			return apiparams.Migration{}, errors.E(op, err, errors.CodeBadRequest)
+		}
+	default:
+		return apiparams.Migration{}, errors.E(op, errors.CodeBadRequest, "migration id or model tag must be specified")
+	}
+	m, err := r.jimm.MigrationStatus(ctx, r.user, req.MigrationID, mt)
+	if err != nil {
+		return apiparams.Migration{}, errors.E(op, err)
+	}
+	return m.ToAPIMigration(), nil
+}
+
 // maxLimit is the maximum number of audit-log entries that will be
 // returned from the audit log, no matter how many are requested.
 const maxLimit = 1000
@@ -286,6 +489,12 @@ func auditParamsToFilter(req apiparams.FindAuditEventsRequest) (db.AuditLogFilte
 	filter.Method = req.Method
 	filter.Model = req.Model
 	filter.SortTime = req.SortTime
+	filter.SortKeyset = true
+	filter.FacadeName = req.FacadeName
+	filter.ConversationId = req.ConversationId
+	filter.ObjectId = req.ObjectId
+	filter.HasErrors = req.HasErrors
+	filter.Restored = req.Restored
 
 	if req.After != "" {
 		filter.Start, err = time.Parse(time.RFC3339, req.After)
@@ -306,6 +515,13 @@ func auditParamsToFilter(req apiparams.FindAuditEventsRequest) (db.AuditLogFilte
 		}
 		filter.IdentityTag = tag.String()
 	}
+	if req.ParamsContain != "" {
+		var params map[string]any
+		if err := json.Unmarshal([]byte(req.ParamsContain), &params); err != nil || params == nil {
+			return filter, errors.E(errors.CodeBadRequest, `invalid "params-contain" filter, expected a JSON object`)
+		}
+		filter.ParamsContain = req.ParamsContain
+	}
 
 	limit := int(req.Limit)
 	if limit < 1 {
@@ -320,9 +536,42 @@ func auditParamsToFilter(req apiparams.FindAuditEventsRequest) (db.AuditLogFilte
 		offset = 0
 	}
 	filter.Offset = offset
+	if req.ContinuationToken != "" {
+		if filter.Offset != 0 {
+			return filter, errors.E(errors.CodeBadRequest, `"offset" cannot be used with "continuation-token"`)
+		}
+		filter.After, err = decodeAuditContinuationToken(req.ContinuationToken)
+		if err != nil {
+			return filter, errors.E(err, errors.CodeBadRequest, `invalid "continuation-token"`)
+		}
+	}
 	return filter, nil
 }
 
+// encodeAuditContinuationToken encodes the position of the given entry
+// as an opaque continuation token.
+func encodeAuditContinuationToken(ale *dbmodel.AuditLogEntry) string {
+	buf, _ := json.Marshal(db.AuditLogPosition{Time: ale.Time, ID: ale.ID})
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// decodeAuditContinuationToken decodes a token created by
+// encodeAuditContinuationToken.
+func decodeAuditContinuationToken(token string) (*db.AuditLogPosition, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var pos db.AuditLogPosition
+	if err := json.Unmarshal(buf, &pos); err != nil {
+		return nil, err
+	}
+	if pos.ID == 0 {
+		return nil, errors.E("missing entry ID")
+	}
+	return &pos, nil
+}
+
 // FindAuditEvents finds the audit-log entries that match the given filter.
 func (r *controllerRoot) FindAuditEvents(ctx context.Context, req apiparams.FindAuditEventsRequest) (apiparams.AuditEvents, error) {
 	const op = errors.Op("jujuapi.FindAuditEvents")
@@ -339,8 +588,15 @@ func (r *controllerRoot) FindAuditEvents(ctx context.Context, req apiparams.Find
 	for i, ent := range entries {
 		events[i] = ent.ToAPIAuditEvent()
 	}
+	var token string
+	if len(entries) > 0 && len(entries) == filter.Limit {
+		// There may be more events, let the client continue from the
+		// last one returned.
+		token = encodeAuditContinuationToken(&entries[len(entries)-1])
+	}
 	return apiparams.AuditEvents{
-		Events: events,
+		Events:            events,
+		ContinuationToken: token,
 	}, nil
 }
 
@@ -434,6 +690,91 @@ func (r *controllerRoot) ImportModel(ctx context.Context, req apiparams.ImportMo
 	return nil
 }
 
+// ImportModels imports all models running on a controller that are not
+// yet known to JIMM.
+func (r *controllerRoot) ImportModels(ctx context.Context, req apiparams.ImportModelsRequest) (apiparams.ImportModelsResponse, error) {
+	const op = errors.Op("jujuapi.ImportModels")
+
+	results, err := r.jimm.ImportModels(ctx, r.user, req.Controller, req.OwnerPattern, req.NamePattern, req.OwnerMap)
+	if err != nil {
+		return apiparams.ImportModelsResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ImportModelsResponse{
+		Results: make([]apiparams.ImportModelResult, len(results)),
+	}
+	for i, result := range results {
+		resp.Results[i] = apiparams.ImportModelResult{
+			ModelTag: result.ModelTag.String(),
+			Name:     result.Name,
+			Owner:    result.Owner,
+		}
+		if result.Err != nil {
+			resp.Results[i].Error = result.Err.Error()
+		}
+	}
+	return resp, nil
+}
+
+// TransferModelOwnership transfers the ownership of a model to another
+// identity.
+func (r *controllerRoot) TransferModelOwnership(ctx context.Context, req apiparams.TransferModelOwnershipRequest) (apiparams.ModelOwnershipTransfer, error) {
+	const op = errors.Op("jujuapi.TransferModelOwnership")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ModelOwnershipTransfer{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	newOwner, err := parseUserTag(req.NewOwnerTag)
+	if err != nil {
+		return apiparams.ModelOwnershipTransfer{}, errors.E(op, err)
+	}
+	var credTag names.CloudCredentialTag
+	if req.CloudCredentialTag != "" {
+		credTag, err = names.ParseCloudCredentialTag(req.CloudCredentialTag)
+		if err != nil {
+			return apiparams.ModelOwnershipTransfer{}, errors.E(op, err, errors.CodeBadRequest)
+		}
+	}
+	transfer, err := r.jimm.TransferModelOwnership(ctx, r.user, mt, newOwner.Id(), credTag)
+	if err != nil {
+		return apiparams.ModelOwnershipTransfer{}, errors.E(op, err)
+	}
+	return *transfer, nil
+}
+
+// Reconcile compares the state JIMM holds for controllers with the state
+// reported by the controllers, optionally fixing any discrepancies.
+func (r *controllerRoot) Reconcile(ctx context.Context, req apiparams.ReconcileRequest) (apiparams.ReconcileResponse, error) {
+	const op = errors.Op("jujuapi.Reconcile")
+
+	reports, err := r.jimm.Reconcile(ctx, r.user, req.Controller, req.Fix)
+	if err != nil {
+		return apiparams.ReconcileResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ReconcileResponse{
+		Reports: make([]apiparams.ReconcileReport, len(reports)),
+	}
+	for i, report := range reports {
+		resp.Reports[i].Controller = report.Controller
+		if report.Err != nil {
+			resp.Reports[i].Error = report.Err.Error()
+		}
+		for _, d := range report.Discrepancies {
+			discrepancy := apiparams.Discrepancy{
+				Kind:        d.Kind,
+				Tag:         d.Tag.String(),
+				Description: d.Description,
+				Fixed:       d.Fixed,
+			}
+			if d.FixErr != nil {
+				discrepancy.FixError = d.FixErr.Error()
+			}
+			resp.Reports[i].Discrepancies = append(resp.Reports[i].Discrepancies, discrepancy)
+		}
+	}
+	return resp, nil
+}
+
 // RemoveCloudFromController removes the specified cloud from a specific controller.
 func (r *controllerRoot) RemoveCloudFromController(ctx context.Context, req apiparams.RemoveCloudFromControllerRequest) error {
 	const op = errors.Op("jujuapi.RemoveCloudFromController")
@@ -485,6 +826,55 @@ func (r *controllerRoot) PurgeLogs(ctx context.Context, req apiparams.PurgeLogsR
 	}, nil
 }
 
+// VerifyAuditLog verifies the audit log hash chain and reports the first
+// broken link found.
+func (r *controllerRoot) VerifyAuditLog(ctx context.Context) (apiparams.VerifyAuditLogResponse, error) {
+	const op = errors.Op("jujuapi.VerifyAuditLog")
+
+	v, err := r.jimm.VerifyAuditLog(ctx, r.user)
+	if err != nil {
+		return apiparams.VerifyAuditLogResponse{}, errors.E(op, err)
+	}
+	return apiparams.VerifyAuditLogResponse{
+		Valid:         v.Valid(),
+		Verified:      v.Verified,
+		Unhashed:      v.Unhashed,
+		BrokenEntryID: v.BrokenEntryID,
+		Reason:        v.Reason,
+	}, nil
+}
+
+// ListAuditLogArchives returns the segments of the audit log that have
+// been archived.
+func (r *controllerRoot) ListAuditLogArchives(ctx context.Context) (apiparams.ListAuditLogArchivesResponse, error) {
+	const op = errors.Op("jujuapi.ListAuditLogArchives")
+
+	archives, err := r.jimm.ListAuditLogArchives(ctx, r.user)
+	if err != nil {
+		return apiparams.ListAuditLogArchivesResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListAuditLogArchivesResponse{
+		Archives: make([]apiparams.AuditLogArchive, len(archives)),
+	}
+	for i, a := range archives {
+		resp.Archives[i] = a.ToAPIAuditLogArchive()
+	}
+	return resp, nil
+}
+
+// RestoreAuditLogArchive restores an archived segment of the audit log
+// so that its entries can be found using FindAuditEvents with the
+// restored filter.
+func (r *controllerRoot) RestoreAuditLogArchive(ctx context.Context, req apiparams.RestoreAuditLogArchiveRequest) (apiparams.RestoreAuditLogArchiveResponse, error) {
+	const op = errors.Op("jujuapi.RestoreAuditLogArchive")
+
+	n, err := r.jimm.RestoreAuditLogArchive(ctx, r.user, req.ID)
+	if err != nil {
+		return apiparams.RestoreAuditLogArchiveResponse{}, errors.E(op, err)
+	}
+	return apiparams.RestoreAuditLogArchiveResponse{Restored: n}, nil
+}
+
 // MigrateModel is a JIMM specific method for migrating models between two controllers that
 // are already attached to JIMM. See InitiateMigration in controller.go to migrate a model
 // in a controller attached to JIMM to one not managed by JIMM.
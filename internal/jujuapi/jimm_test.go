@@ -455,6 +455,38 @@ func (s *jimmSuite) TestAuditLogFilterByMethod(c *gc.C) {
 	c.Assert(len(evs.Events), gc.Equals, 0)
 }
 
+func (s *jimmSuite) TestFindAuditEventsContinuationToken(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ListControllers()
+		c.Assert(err, gc.Equals, nil)
+	}
+
+	all, err := client.FindAuditEvents(&apiparams.FindAuditEventsRequest{Method: "ListControllers"})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(all.Events, gc.HasLen, 6)
+	c.Check(all.ContinuationToken, gc.Equals, "")
+
+	var paged []apiparams.AuditEvent
+	req := apiparams.FindAuditEventsRequest{Method: "ListControllers", Limit: 4}
+	for {
+		evs, err := client.FindAuditEvents(&req)
+		c.Assert(err, gc.Equals, nil)
+		paged = append(paged, evs.Events...)
+		if evs.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = evs.ContinuationToken
+	}
+	c.Check(paged, jc.DeepEquals, all.Events)
+
+	_, err = client.FindAuditEvents(&apiparams.FindAuditEventsRequest{ContinuationToken: "not-a-token"})
+	c.Check(err, gc.ErrorMatches, `invalid "continuation-token".*`)
+}
+
 // TestAuditLogAPIParamsConversion tests the conversion of API params to a AuditLogFilter struct.
 // Note that this test doesn't require a running Juju/JIMM controller so it doesn't use gc + the jimmSuite.
 func TestAuditLogAPIParamsConversion(t *testing.T) {
@@ -463,7 +495,7 @@ func TestAuditLogAPIParamsConversion(t *testing.T) {
 		about   string
 		request apiparams.FindAuditEventsRequest
 		result  db.AuditLogFilter
-		err     error
+		err     string
 	}{
 		{
 			about: "Test basic conversion",
@@ -486,6 +518,7 @@ func TestAuditLogAPIParamsConversion(t *testing.T) {
 				Offset:      10,
 				Limit:       10,
 				SortTime:    false,
+				SortKeyset:  true,
 			},
 		}, {
 			about: "Test limit lower bound",
@@ -493,7 +526,8 @@ func TestAuditLogAPIParamsConversion(t *testing.T) {
 				Limit: 0,
 			},
 			result: db.AuditLogFilter{
-				Limit: jujuapi.AuditLogDefaultLimit,
+				Limit:      jujuapi.AuditLogDefaultLimit,
+				SortKeyset: true,
 			},
 		}, {
 			about: "Test limit upper bound",
@@ -501,14 +535,65 @@ func TestAuditLogAPIParamsConversion(t *testing.T) {
 				Limit: jujuapi.AuditLogUpperLimit + 1,
 			},
 			result: db.AuditLogFilter{
-				Limit: jujuapi.AuditLogUpperLimit,
+				Limit:      jujuapi.AuditLogUpperLimit,
+				SortKeyset: true,
+			},
+		}, {
+			about: "Test facade, conversation, object, errors and params filters",
+			request: apiparams.FindAuditEventsRequest{
+				FacadeName:     "ModelManager",
+				ConversationId: "0123456789abcdef",
+				ObjectId:       "model-1",
+				HasErrors:      true,
+				ParamsContain:  `{"name": "model-1"}`,
+			},
+			result: db.AuditLogFilter{
+				FacadeName:     "ModelManager",
+				ConversationId: "0123456789abcdef",
+				ObjectId:       "model-1",
+				HasErrors:      true,
+				ParamsContain:  `{"name": "model-1"}`,
+				Limit:          jujuapi.AuditLogDefaultLimit,
+				SortKeyset:     true,
+			},
+		}, {
+			about: "Test invalid params filter",
+			request: apiparams.FindAuditEventsRequest{
+				ParamsContain: `["model-1"]`,
+			},
+			err: `invalid "params-contain" filter, expected a JSON object`,
+		}, {
+			about: "Test continuation token",
+			request: apiparams.FindAuditEventsRequest{
+				ContinuationToken: "eyJ0aW1lIjoiMjAyMy0wOC0xNFQwMDowMDowMFoiLCJpZCI6NDJ9",
+			},
+			result: db.AuditLogFilter{
+				Limit:      jujuapi.AuditLogDefaultLimit,
+				SortKeyset: true,
+				After: &db.AuditLogPosition{
+					Time: time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC),
+					ID:   42,
+				},
+			},
+		}, {
+			about: "Test continuation token with offset",
+			request: apiparams.FindAuditEventsRequest{
+				ContinuationToken: "eyJ0aW1lIjoiMjAyMy0wOC0xNFQwMDowMDowMFoiLCJpZCI6NDJ9",
+				Offset:            10,
+			},
+			err: `"offset" cannot be used with "continuation-token"`,
+		}, {
+			about: "Test invalid continuation token",
+			request: apiparams.FindAuditEventsRequest{
+				ContinuationToken: "eyJ0aW1lIjoiMjAyMy0wOC0xNFQwMDowMDowMFoifQ",
 			},
+			err: `invalid "continuation-token"`,
 		},
 	}
 	for _, test := range testCases {
 		c.Log(test.about)
 		res, err := jujuapi.AuditParamsToFilter(test.request)
-		if test.err == nil {
+		if test.err == "" {
 			c.Assert(err, qt.IsNil)
 			c.Assert(res, qt.DeepEquals, test.result)
 		} else {
@@ -0,0 +1,108 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// quota contains the RPC commands for managing model quotas via the JIMM facade.
+
+// SetQuota sets the limits of a quota for a user or group on a cloud.
+func (r *controllerRoot) SetQuota(ctx context.Context, req apiparams.SetQuotaRequest) error {
+	const op = errors.Op("jujuapi.SetQuota")
+
+	ct, err := names.ParseCloudTag(req.CloudTag)
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, err)
+	}
+	q := dbmodel.Quota{
+		Subject:     req.Subject,
+		CloudName:   ct.Id(),
+		Region:      req.Region,
+		MaxModels:   toNullInt64(req.Limits.MaxModels),
+		MaxMachines: toNullInt64(req.Limits.MaxMachines),
+		MaxCores:    toNullInt64(req.Limits.MaxCores),
+		MaxUnits:    toNullInt64(req.Limits.MaxUnits),
+	}
+	if err := r.jimm.SetQuota(ctx, r.user, &q); err != nil {
+		zapctx.Error(ctx, "failed to set quota", zaputil.Error(err))
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveQuota removes a quota.
+func (r *controllerRoot) RemoveQuota(ctx context.Context, req apiparams.RemoveQuotaRequest) error {
+	const op = errors.Op("jujuapi.RemoveQuota")
+
+	ct, err := names.ParseCloudTag(req.CloudTag)
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, err)
+	}
+	if err := r.jimm.RemoveQuota(ctx, r.user, req.Subject, ct, req.Region); err != nil {
+		zapctx.Error(ctx, "failed to remove quota", zaputil.Error(err))
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListQuotas lists all quotas along with their current usage.
+func (r *controllerRoot) ListQuotas(ctx context.Context) (apiparams.ListQuotasResponse, error) {
+	const op = errors.Op("jujuapi.ListQuotas")
+
+	quotas, err := r.jimm.ListQuotas(ctx, r.user)
+	if err != nil {
+		return apiparams.ListQuotasResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListQuotasResponse{
+		Quotas: make([]apiparams.Quota, len(quotas)),
+	}
+	for i, q := range quotas {
+		subject, err := r.jimm.QuotaSubjectTag(ctx, &q.Quota)
+		if err != nil {
+			return apiparams.ListQuotasResponse{}, errors.E(op, err)
+		}
+		resp.Quotas[i] = apiparams.Quota{
+			Subject: subject,
+			Cloud:   q.Quota.CloudName,
+			Region:  q.Quota.Region,
+			Limits: apiparams.QuotaLimits{
+				MaxModels:   fromNullInt64(q.Quota.MaxModels),
+				MaxMachines: fromNullInt64(q.Quota.MaxMachines),
+				MaxCores:    fromNullInt64(q.Quota.MaxCores),
+				MaxUnits:    fromNullInt64(q.Quota.MaxUnits),
+			},
+			Usage: apiparams.QuotaUsage{
+				Models:   q.Usage.Models,
+				Machines: q.Usage.Machines,
+				Cores:    q.Usage.Cores,
+				Units:    q.Usage.Units,
+			},
+		}
+	}
+	return resp, nil
+}
+
+func toNullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func fromNullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical.
+
+package jujuapi_test
+
+import (
+	"github.com/juju/names/v5"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type quotaSuite struct {
+	websocketSuite
+}
+
+var _ = gc.Suite(&quotaSuite{})
+
+func (s *quotaSuite) TestSetListRemoveQuota(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	maxModels := int64(1)
+	err := client.SetQuota(&apiparams.SetQuotaRequest{
+		Subject:  names.NewUserTag("bob@canonical.com").String(),
+		CloudTag: names.NewCloudTag(jimmtest.TestCloudName).String(),
+		Limits: apiparams.QuotaLimits{
+			MaxModels: &maxModels,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	quotas, err := client.ListQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(quotas, jc.DeepEquals, []apiparams.Quota{{
+		Subject: "user-bob@canonical.com",
+		Cloud:   jimmtest.TestCloudName,
+		Limits: apiparams.QuotaLimits{
+			MaxModels: &maxModels,
+		},
+		Usage: apiparams.QuotaUsage{
+			Models: 1,
+		},
+	}})
+
+	err = client.RemoveQuota(&apiparams.RemoveQuotaRequest{
+		Subject:  names.NewUserTag("bob@canonical.com").String(),
+		CloudTag: names.NewCloudTag(jimmtest.TestCloudName).String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	quotas, err = client.ListQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(quotas, gc.HasLen, 0)
+
+	err = client.RemoveQuota(&apiparams.RemoveQuotaRequest{
+		Subject:  names.NewUserTag("bob@canonical.com").String(),
+		CloudTag: names.NewCloudTag(jimmtest.TestCloudName).String(),
+	})
+	c.Check(err, gc.ErrorMatches, `quota not found \(not found\)`)
+}
+
+func (s *quotaSuite) TestSetQuotaInvalidCloudTag(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	err := client.SetQuota(&apiparams.SetQuotaRequest{
+		Subject:  names.NewUserTag("bob@canonical.com").String(),
+		CloudTag: "not-a-cloud-tag",
+	})
+	c.Check(err, gc.ErrorMatches, `"not-a-cloud-tag" is not a valid tag \(bad request\)`)
+}
+
+func (s *quotaSuite) TestQuotasUnauthorized(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	err := client.SetQuota(&apiparams.SetQuotaRequest{
+		Subject:  names.NewUserTag("bob@canonical.com").String(),
+		CloudTag: names.NewCloudTag(jimmtest.TestCloudName).String(),
+	})
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = client.ListQuotas()
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// stale_report contains the RPC command for reporting inactive identities
+// and stale models via the JIMM facade.
+
+// StaleReport returns the identities that have not logged in, and the
+// models that have had no activity, for the requested number of days
+// along with their resource footprint.
+func (r *controllerRoot) StaleReport(ctx context.Context, req apiparams.StaleReportRequest) (apiparams.StaleReportResponse, error) {
+	const op = errors.Op("jujuapi.StaleReport")
+
+	report, err := r.jimm.StaleReport(ctx, r.user, req.Days)
+	if err != nil {
+		return apiparams.StaleReportResponse{}, errors.E(op, err)
+	}
+
+	resp := apiparams.StaleReportResponse{
+		Before:     report.Before,
+		Identities: make([]apiparams.InactiveIdentity, len(report.Identities)),
+		Models:     make([]apiparams.StaleModel, len(report.Models)),
+	}
+	for i, identity := range report.Identities {
+		resp.Identities[i] = apiparams.InactiveIdentity{
+			Identity:  identity.Identity.ToAPIIdentity(),
+			Footprint: modelsFootprint(identity.Models...),
+		}
+	}
+	for i, m := range report.Models {
+		resp.Models[i] = apiparams.StaleModel{
+			IdentityModel: m.ToAPIIdentityModel(),
+			Owner:         m.OwnerIdentityName,
+			Footprint:     modelsFootprint(m),
+		}
+		if m.LastActivity.Valid {
+			t := m.LastActivity.Time
+			resp.Models[i].LastActivity = &t
+		}
+	}
+	return resp, nil
+}
+
+// modelsFootprint returns the total resources used by the given models.
+func modelsFootprint(models ...dbmodel.Model) apiparams.ResourceFootprint {
+	footprint := apiparams.ResourceFootprint{
+		Models: len(models),
+	}
+	for _, m := range models {
+		footprint.Machines += m.Machines
+		footprint.Cores += m.Cores
+		footprint.Units += m.Units
+	}
+	return footprint
+}
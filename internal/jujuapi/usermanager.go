@@ -7,6 +7,7 @@ import (
 
 	jujuparams "github.com/juju/juju/rpc/params"
 
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 )
@@ -14,8 +15,8 @@ import (
 func init() {
 	facadeInit["UserManager"] = func(r *controllerRoot) []int {
 		addUserMethod := rpc.Method(r.AddUser)
-		disableUserMethod := rpc.Method(r.EnableUser)
-		enableUserMethod := rpc.Method(r.DisableUser)
+		disableUserMethod := rpc.Method(r.DisableUser)
+		enableUserMethod := rpc.Method(r.EnableUser)
 		removeUserMethod := rpc.Method(r.RemoveUser)
 		setPasswordMethod := rpc.Method(r.SetPassword)
 		userInfoMethod := rpc.Method(r.UserInfo)
@@ -46,13 +47,36 @@ func (r *controllerRoot) RemoveUser(jujuparams.Entities) (jujuparams.ErrorResult
 }
 
 // EnableUser implements the UserManager facade's EnableUser method.
-func (r *controllerRoot) EnableUser(jujuparams.Entities) (jujuparams.ErrorResults, error) {
-	return jujuparams.ErrorResults{}, errors.E(errors.CodeUnauthorized, "unauthorized")
+func (r *controllerRoot) EnableUser(ctx context.Context, args jujuparams.Entities) (jujuparams.ErrorResults, error) {
+	return r.setUsersDisabled(ctx, args, false)
 }
 
 // DisableUser implements the UserManager facade's DisableUser method.
-func (r *controllerRoot) DisableUser(jujuparams.Entities) (jujuparams.ErrorResults, error) {
-	return jujuparams.ErrorResults{}, errors.E(errors.CodeUnauthorized, "unauthorized")
+// Disabled users may not log in, and their open sessions are terminated.
+func (r *controllerRoot) DisableUser(ctx context.Context, args jujuparams.Entities) (jujuparams.ErrorResults, error) {
+	return r.setUsersDisabled(ctx, args, true)
+}
+
+func (r *controllerRoot) setUsersDisabled(ctx context.Context, args jujuparams.Entities, disabled bool) (jujuparams.ErrorResults, error) {
+	const op = errors.Op("jujuapi.SetUsersDisabled")
+
+	if !r.user.JimmAdmin {
+		return jujuparams.ErrorResults{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	res := jujuparams.ErrorResults{
+		Results: make([]jujuparams.ErrorResult, len(args.Entities)),
+	}
+	for i, ent := range args.Entities {
+		user, err := parseUserTag(ent.Tag)
+		if err != nil {
+			res.Results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
+			continue
+		}
+		if err := r.jimm.SetIdentityDisabled(ctx, r.user, user.Id(), disabled); err != nil {
+			res.Results[i].Error = mapError(errors.E(op, err))
+		}
+	}
+	return res, nil
 }
 
 // ModelUserInfo returns information on all users in the model.
@@ -66,7 +90,7 @@ func (r *controllerRoot) UserInfo(ctx context.Context, req jujuparams.UserInfoRe
 		Results: make([]jujuparams.UserInfoResult, len(req.Entities)),
 	}
 	for i, ent := range req.Entities {
-		ui, err := r.userInfo(ent.Tag)
+		ui, err := r.userInfo(ctx, ent.Tag)
 		if err != nil {
 			res.Results[i].Error = mapError(err)
 			continue
@@ -76,17 +100,27 @@ func (r *controllerRoot) UserInfo(ctx context.Context, req jujuparams.UserInfoRe
 	return res, nil
 }
 
-func (r *controllerRoot) userInfo(entity string) (*jujuparams.UserInfo, error) {
+func (r *controllerRoot) userInfo(ctx context.Context, entity string) (*jujuparams.UserInfo, error) {
 	const op = errors.Op("jujuapi.UserInfo")
 
 	user, err := parseUserTag(entity)
 	if err != nil {
 		return nil, errors.E(op, err, errors.CodeBadRequest)
 	}
-	if r.user.Name != user.Id() {
+	if r.user.Name == user.Id() {
+		ui := r.user.ToJujuUserInfo()
+		return &ui, nil
+	}
+	// JIMM administrators may see the information of any identity,
+	// including whether it has been disabled.
+	if !r.user.JimmAdmin {
 		return nil, errors.E(op, errors.CodeUnauthorized)
 	}
-	ui := r.user.ToJujuUserInfo()
+	identity := dbmodel.Identity{Name: user.Id()}
+	if err := r.jimm.DB().FetchIdentity(ctx, &identity); err != nil {
+		return nil, errors.E(op, err)
+	}
+	ui := identity.ToJujuUserInfo()
 	return &ui, nil
 }
 
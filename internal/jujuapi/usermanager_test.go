@@ -35,22 +35,62 @@ func (s *userManagerSuite) TestRemoveUser(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
 }
 
-func (s *userManagerSuite) TestEnableUser(c *gc.C) {
+func (s *userManagerSuite) TestDisableAndEnableUser(c *gc.C) {
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+
 	conn := s.open(c, nil, "alice")
 	defer conn.Close()
 
 	client := usermanager.NewClient(conn)
-	err := client.EnableUser("bob")
-	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	err := client.DisableUser("bob@canonical.com")
+	c.Assert(err, gc.Equals, nil)
+
+	// bob's open session is terminated.
+	select {
+	case <-bobConn.Broken():
+	case <-time.After(time.Minute):
+		c.Fatalf("session not terminated")
+	}
+
+	users, err := client.UserInfo([]string{"bob@canonical.com"}, usermanager.AllUsers)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(users, gc.HasLen, 1)
+	c.Check(users[0].Username, gc.Equals, "bob@canonical.com")
+	c.Check(users[0].Disabled, gc.Equals, true)
+
+	_, err = s.openNoAssert(c, loginDetails{username: "bob"})
+	c.Assert(err, gc.ErrorMatches, `.*identity disabled.*`)
+
+	err = client.EnableUser("bob@canonical.com")
+	c.Assert(err, gc.Equals, nil)
+
+	bobConn = s.open(c, nil, "bob")
+	defer bobConn.Close()
+	users, err = client.UserInfo([]string{"bob@canonical.com"}, usermanager.AllUsers)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(users, gc.HasLen, 1)
+	c.Check(users[0].Disabled, gc.Equals, false)
 }
 
-func (s *userManagerSuite) TestDisableUser(c *gc.C) {
+func (s *userManagerSuite) TestDisableUserUnauthorized(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+
+	client := usermanager.NewClient(conn)
+	err := client.DisableUser("alice@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+	err = client.EnableUser("alice@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+}
+
+func (s *userManagerSuite) TestDisableUserInvalidUsername(c *gc.C) {
 	conn := s.open(c, nil, "alice")
 	defer conn.Close()
 
 	client := usermanager.NewClient(conn)
 	err := client.DisableUser("bob")
-	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	c.Assert(err, gc.ErrorMatches, `.*unsupported local user.*`)
 }
 
 func (s *userManagerSuite) TestUserInfoAllUsers(c *gc.C) {
@@ -82,12 +122,12 @@ func (s *userManagerSuite) TestUserInfoSpecifiedUser(c *gc.C) {
 }
 
 func (s *userManagerSuite) TestUserInfoSpecifiedUsers(c *gc.C) {
-	conn := s.open(c, nil, "alice")
+	conn := s.open(c, nil, "bob")
 	defer conn.Close()
 
 	client := usermanager.NewClient(conn)
-	users, err := client.UserInfo([]string{"alice@canonical.com", "bob@canonical.com"}, usermanager.AllUsers)
-	c.Assert(err, gc.ErrorMatches, "bob@canonical.com: unauthorized access")
+	users, err := client.UserInfo([]string{"bob@canonical.com", "alice@canonical.com"}, usermanager.AllUsers)
+	c.Assert(err, gc.ErrorMatches, "alice@canonical.com: unauthorized access")
 	c.Assert(users, gc.HasLen, 0)
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
 	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
+	"github.com/canonical/jimm/v3/internal/utils"
 )
 
 const (
@@ -51,10 +52,14 @@ func (s *apiServer) GetAuthenticationService() jimm.OAuthAuthenticator {
 
 // ServeWS implements jimmhttp.WSServer.
 func (s *apiServer) ServeWS(ctx context.Context, conn *websocket.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	identityId := auth.SessionIdentityFromContext(ctx)
 	controllerRoot := newControllerRoot(s.jimm, s.params, identityId)
+	controllerRoot.terminate = cancel
+	defer controllerRoot.endSession()
 	s.cleanup = controllerRoot.cleanup
-	Dblogger := controllerRoot.newAuditLogger()
+	Dblogger := controllerRoot.newAuditLogger(s.jimm.AuditRedactor)
 	serveRoot(ctx, controllerRoot, Dblogger, conn)
 }
 
@@ -68,6 +73,9 @@ func (s *apiServer) Kill() {
 // serveRoot serves an RPC root object on a websocket connection.
 func serveRoot(ctx context.Context, root root, logger jimm.DbAuditLogger, wsConn *websocket.Conn) {
 	ctx = zapctx.WithFields(ctx, zap.Bool("websocket", true))
+	// Authorization decisions recorded while handling requests are
+	// associated with the connection's conversation.
+	ctx = utils.ContextWithConversation(ctx, logger.ConversationID(), 0)
 
 	// Note that although NewConn accepts a `RecorderFactory` input, the call to conn.ServeRoot
 	// also accepts a `RecorderFactory` and will override anything set during the call to NewConn.
@@ -88,6 +96,16 @@ func serveRoot(ctx context.Context, root root, logger jimm.DbAuditLogger, wsConn
 	})
 	defer t.Stop()
 	root.setPingF(func() { t.Reset(pingTimeout) })
+	go func() {
+		// The connection is closed when the context is cancelled,
+		// for example because the identity has been disabled.
+		select {
+		case <-ctx.Done():
+			zapctx.Info(ctx, "context cancelled, closing connection")
+			conn.Close()
+		case <-conn.Dead():
+		}
+	}()
 	conn.Start(ctx)
 	<-conn.Dead()
 }
@@ -151,8 +169,10 @@ func (s modelProxyServer) ServeWS(ctx context.Context, clientConn *websocket.Con
 		TokenGen:                &jwtGenerator,
 		ConnectController:       connectionFunc,
 		AuditLog:                auditLogger,
+		AuditRedactor:           s.jimm.AuditRedactor,
 		LoginService:            s.jimm,
 		AuthenticatedIdentityID: auth.SessionIdentityFromContext(ctx),
+		TrackSession:            s.jimm.TrackSession,
 	}
 	if err := jimmRPC.ProxySockets(ctx, proxyHelpers); err != nil {
 		zapctx.Error(ctx, "failed to start jimm model proxy", zap.Error(err))
@@ -25,6 +25,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/wellknownapi"
+	jimmapi "github.com/canonical/jimm/v3/pkg/api"
 )
 
 type websocketSuite struct {
@@ -52,6 +53,7 @@ func (s *websocketSuite) SetUpTest(c *gc.C) {
 	mux := http.NewServeMux()
 	mux.Handle("/api", jujuapi.APIHandler(ctx, s.JIMM, s.Params))
 	mux.Handle("/model/", jujuapi.ModelHandler(ctx, s.JIMM, s.Params))
+	mux.Handle(jimmapi.AuditExportPath, jujuapi.AuditExportHandler(ctx, s.JIMM))
 	jwks := wellknownapi.NewWellKnownHandler(s.JIMM.CredentialStore)
 	mux.HandleFunc("/.well-known/jwks.json", jwks.JWKS)
 
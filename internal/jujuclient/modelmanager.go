@@ -185,6 +185,32 @@ func (c Connection) RevokeModelAccess(ctx context.Context, modelTag names.ModelT
 	return nil
 }
 
+// AllModelSummaries retrieves the ModelSummary of every model on the
+// controller. AllModelSummaries uses the ListModelSummaries procedure on
+// the ModelManager facade.
+func (c Connection) AllModelSummaries(ctx context.Context) ([]jujuparams.ModelSummary, error) {
+	const op = errors.Op("jujuclient.AllModelSummaries")
+	args := jujuparams.ModelSummariesRequest{
+		UserTag: c.userTag,
+		All:     true,
+	}
+	var resp jujuparams.ModelSummaryResults
+	err := c.Call(ctx, "ModelManager", 9, "", "ListModelSummaries", &args, &resp)
+	if err != nil {
+		return nil, errors.E(op, jujuerrors.Cause(err))
+	}
+	summaries := make([]jujuparams.ModelSummary, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Error != nil {
+			return nil, errors.E(op, r.Error)
+		}
+		if r.Result != nil {
+			summaries = append(summaries, *r.Result)
+		}
+	}
+	return summaries, nil
+}
+
 // ControllerModelSummary retrieves the ModelSummary for the controller
 // model. ControllerModelSummary uses the ListModelSummaries procedure on
 // the ModelManager facade.
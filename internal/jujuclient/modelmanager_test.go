@@ -123,6 +123,33 @@ func (s *modelmanagerSuite) TestModelInfoError(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `permission denied`)
 }
 
+func (s *modelmanagerSuite) TestAllModelSummaries(c *gc.C) {
+	ctx := context.Background()
+
+	var info jujuparams.ModelInfo
+	err := s.API.CreateModel(ctx, &jujuparams.ModelCreateArgs{
+		Name:     "test-model",
+		OwnerTag: names.NewUserTag("test-user@canonical.com").String(),
+	}, &info)
+	c.Assert(err, gc.Equals, nil)
+
+	summaries, err := s.API.AllModelSummaries(ctx)
+	c.Assert(err, gc.Equals, nil)
+	var found, foundController bool
+	for _, ms := range summaries {
+		if ms.UUID == info.UUID {
+			found = true
+			c.Check(ms.Name, gc.Equals, "test-model")
+			c.Check(ms.OwnerTag, gc.Equals, names.NewUserTag("test-user@canonical.com").String())
+		}
+		if ms.IsController {
+			foundController = true
+		}
+	}
+	c.Check(found, gc.Equals, true)
+	c.Check(foundController, gc.Equals, true)
+}
+
 func (s *modelmanagerSuite) TestGrantRevokeModel(c *gc.C) {
 	ctx := context.Background()
 
@@ -146,6 +146,18 @@ func (o *OFGAClient) RemoveRelation(ctx context.Context, tuples ...Tuple) (err e
 	return o.cofgaClient.RemoveRelation(ctx, tuples...)
 }
 
+// AddRemoveRelations adds and removes the given relations (tuples) in a
+// single write, either all of the changes are made or none are.
+func (o *OFGAClient) AddRemoveRelations(ctx context.Context, addTuples, removeTuples []Tuple) (err error) {
+	op := errors.Op("openfga.AddRemoveRelations")
+
+	durationObserver := servermon.DurationObserver(servermon.OpenFGACallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
+
+	return o.cofgaClient.AddRemoveRelations(ctx, addTuples, removeTuples)
+}
+
 // ListObjects returns all object IDs of <objType> that a user has the relation <relation> to.
 func (o *OFGAClient) ListObjects(ctx context.Context, user *Tag, relation Relation, objType Kind, contextualTuples []Tuple) (_ []Tag, err error) {
 	op := errors.Op("openfga.ListObjects")
@@ -175,6 +187,7 @@ func (o *OFGAClient) ReadRelatedObjects(ctx context.Context, tuple Tuple, pageSi
 // CheckRelation verifies that a user (or object) is allowed to access the target object by the specified relation.
 //
 // It will return a bool of simply true or false, denoting authorisation, and an error.
+// If the context was created by WithRelationTrace the check is recorded in the trace.
 func (o *OFGAClient) CheckRelation(ctx context.Context, tuple Tuple, trace bool) (_ bool, err error) {
 	op := errors.Op("openfga.CheckRelation")
 
@@ -182,10 +195,17 @@ func (o *OFGAClient) CheckRelation(ctx context.Context, tuple Tuple, trace bool)
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.OpenFGACallErrorCount, &err, string(op))
 
+	var allowed bool
 	if trace {
-		return o.cofgaClient.CheckRelationWithTracing(ctx, tuple)
+		allowed, err = o.cofgaClient.CheckRelationWithTracing(ctx, tuple)
+	} else {
+		allowed, err = o.cofgaClient.CheckRelation(ctx, tuple)
+	}
+	if err != nil {
+		return false, err
 	}
-	return o.cofgaClient.CheckRelation(ctx, tuple)
+	recordRelationCheck(ctx, tuple, allowed)
+	return allowed, nil
 }
 
 // removeTuples iteratively reads through all the tuples with the parameters as supplied by tuple and deletes them.
@@ -143,6 +143,50 @@ func (s *openFGATestSuite) TestCheckRelationSucceeds(c *gc.C) {
 	c.Assert(allowed, gc.Equals, true)
 }
 
+func (s *openFGATestSuite) TestCheckRelationRecordsTrace(c *gc.C) {
+	ctx := context.Background()
+
+	controllerUUID, _ := uuid.NewRandom()
+	controller := names.NewControllerTag(controllerUUID.String())
+	user := ofganames.ConvertTag(names.NewUserTag("eve"))
+
+	err := s.ofgaClient.AddRelation(ctx, openfga.Tuple{
+		Object:   user,
+		Relation: "administrator",
+		Target:   ofganames.ConvertTag(controller),
+	})
+	c.Assert(err, gc.IsNil)
+
+	tctx, checks := openfga.WithRelationTrace(ctx)
+	for _, relation := range []openfga.Relation{"administrator", "audit_log_viewer"} {
+		_, err := s.ofgaClient.CheckRelation(tctx, openfga.Tuple{
+			Object:   user,
+			Relation: relation,
+			Target:   ofganames.ConvertTag(controller),
+		}, false)
+		c.Assert(err, gc.IsNil)
+	}
+	// Checks made without the trace context are not recorded.
+	_, err = s.ofgaClient.CheckRelation(ctx, openfga.Tuple{
+		Object:   user,
+		Relation: "administrator",
+		Target:   ofganames.ConvertTag(controller),
+	}, false)
+	c.Assert(err, gc.IsNil)
+
+	c.Check(checks(), gc.DeepEquals, []openfga.RelationCheck{{
+		Object:   "user:eve",
+		Relation: "administrator",
+		Target:   "controller:" + controller.Id(),
+		Allowed:  true,
+	}, {
+		Object:   "user:eve",
+		Relation: "audit_log_viewer",
+		Target:   "controller:" + controller.Id(),
+		Allowed:  true,
+	}})
+}
+
 func (s *openFGATestSuite) TestRemoveTuplesSucceeds(c *gc.C) {
 	groupUUID := uuid.NewString()
 
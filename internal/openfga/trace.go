@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical.
+
+package openfga
+
+import (
+	"context"
+	"sync"
+)
+
+// A RelationCheck records a single relation check made against OpenFGA
+// and its outcome.
+type RelationCheck struct {
+	// Object is the object, usually the user, the check was made for.
+	Object string `json:"object"`
+
+	// Relation is the relation checked.
+	Relation string `json:"relation"`
+
+	// Target is the object the relation was checked against.
+	Target string `json:"target"`
+
+	// Allowed is whether the object has the relation to the target.
+	Allowed bool `json:"allowed"`
+}
+
+type relationTraceKey struct{}
+
+type relationTrace struct {
+	mu     sync.Mutex
+	checks []RelationCheck
+}
+
+// WithRelationTrace returns a context that records every relation check
+// made with it, or any context derived from it, and a function that
+// returns the checks recorded so far in the order they were made.
+func WithRelationTrace(ctx context.Context) (context.Context, func() []RelationCheck) {
+	t := new(relationTrace)
+	return context.WithValue(ctx, relationTraceKey{}, t), func() []RelationCheck {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return append([]RelationCheck(nil), t.checks...)
+	}
+}
+
+// recordRelationCheck adds the given check to the relation trace in the
+// given context, if there is one.
+func recordRelationCheck(ctx context.Context, tuple Tuple, allowed bool) {
+	t, ok := ctx.Value(relationTraceKey{}).(*relationTrace)
+	if !ok {
+		return
+	}
+	rc := RelationCheck{
+		Relation: tuple.Relation.String(),
+		Allowed:  allowed,
+	}
+	if tuple.Object != nil {
+		rc.Object = tuple.Object.String()
+	}
+	if tuple.Target != nil {
+		rc.Target = tuple.Target.String()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checks = append(t.checks, rc)
+}
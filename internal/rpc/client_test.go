@@ -17,6 +17,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/juju/names/v5"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
@@ -423,6 +424,67 @@ func TestProxySocketsAuditLogs(t *testing.T) {
 
 }
 
+func TestProxySocketsAuditLogsRedacted(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	redactor, err := auditredact.New([]auditredact.Rule{{
+		Facade: "TestType",
+		Method: "TestReq",
+		Paths:  []string{"$.Secret"},
+	}})
+	c.Assert(err, qt.IsNil)
+
+	srvController := newServer(echo)
+	auditLogs := make([]*dbmodel.AuditLogEntry, 0)
+
+	errChan := make(chan error)
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		defer connClient.Close()
+		testTokenGen := testTokenGenerator{}
+		f := func(context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL)
+			c.Check(err, qt.IsNil)
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:      connController,
+				ModelName: "TestModelName",
+			}, nil
+		}
+		auditLogger := func(ale *dbmodel.AuditLogEntry) { auditLogs = append(auditLogs, ale) }
+		proxyHelpers := rpc.ProxyHelpers{
+			ConnClient:        connClient,
+			TokenGen:          &testTokenGen,
+			ConnectController: f,
+			AuditLog:          auditLogger,
+			AuditRedactor:     redactor,
+			LoginService:      &mockLoginService{},
+		}
+		err := rpc.ProxySockets(ctx, proxyHelpers)
+		c.Check(err, qt.ErrorMatches, `error reading from (client|controller).*`)
+		errChan <- err
+		return err
+	})
+
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	p := json.RawMessage(`{"Key":"TestVal","Secret":"TestSecret"}`)
+	msg := rpc.Message{RequestID: 1, Type: "TestType", Request: "TestReq", Params: p}
+	err = ws.WriteJSON(&msg)
+	c.Assert(err, qt.IsNil)
+	resp := rpc.Message{}
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	ws.Close()
+	<-errChan // Ensure go routines are cleaned up
+	c.Assert(auditLogs, qt.HasLen, 2)
+	c.Check(string(auditLogs[0].Params), qt.Equals, `{"Key":"TestVal","Secret":"redacted"}`)
+}
+
 type server struct {
 	*httptest.Server
 
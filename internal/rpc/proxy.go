@@ -15,6 +15,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 
+	"github.com/canonical/jimm/v3/internal/auditredact"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
@@ -75,8 +76,13 @@ type ProxyHelpers struct {
 	TokenGen                TokenGenerator
 	ConnectController       func(context.Context) (WebsocketConnectionWithMetadata, error)
 	AuditLog                func(*dbmodel.AuditLogEntry)
+	AuditRedactor           *auditredact.Redactor
 	LoginService            LoginService
 	AuthenticatedIdentityID string
+	// TrackSession, if set, is called when the client logs in with the
+	// name of the identity and a function that terminates the proxied
+	// session. The returned function is called when the session ends.
+	TrackSession func(identityName string, terminate func()) (untrack func())
 }
 
 // ProxySockets will proxy requests from a client connection through to a controller
@@ -96,6 +102,15 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 		zapctx.Error(ctx, "Missing login service function")
 		return errors.E(op, "Missing login service function")
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var sessions sessionTracker
+	if helpers.TrackSession != nil {
+		sessions.track = func(identityName string) {
+			sessions.add(helpers.TrackSession(identityName, cancel))
+		}
+		defer sessions.untrackAll()
+	}
 	errChan := make(chan error, 2)
 	msgInFlight := inflightMsgs{messages: make(map[uint64]*message)}
 	client := writeLockConn{conn: helpers.ConnClient}
@@ -107,9 +122,11 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 			msgs:                    &msgInFlight,
 			tokenGen:                helpers.TokenGen,
 			auditLog:                helpers.AuditLog,
+			auditRedactor:           helpers.AuditRedactor,
 			conversationId:          utils.NewConversationID(),
 			loginService:            helpers.LoginService,
 			authenticatedIdentityID: helpers.AuthenticatedIdentityID,
+			trackSession:            sessions.track,
 		},
 		errChan:              errChan,
 		createControllerConn: helpers.ConnectController,
@@ -141,6 +158,30 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 	return err
 }
 
+// sessionTracker records the sessions tracked for the identities that
+// log in on a proxied connection.
+type sessionTracker struct {
+	track func(identityName string)
+
+	mu        sync.Mutex
+	untrackFs []func()
+}
+
+func (t *sessionTracker) add(untrack func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.untrackFs = append(t.untrackFs, untrack)
+}
+
+func (t *sessionTracker) untrackAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, untrack := range t.untrackFs {
+		untrack()
+	}
+	t.untrackFs = nil
+}
+
 // writeLockConn provides a websocket connection that is safe for concurrent writes.
 type writeLockConn struct {
 	mu   sync.Mutex
@@ -245,11 +286,13 @@ type modelProxy struct {
 	dst                     *writeLockConn
 	msgs                    *inflightMsgs
 	auditLog                func(*dbmodel.AuditLogEntry)
+	auditRedactor           *auditredact.Redactor
 	tokenGen                TokenGenerator
 	loginService            LoginService
 	modelName               string
 	conversationId          string
 	authenticatedIdentityID string
+	trackSession            func(identityName string)
 
 	deviceOAuthResponse *oauth2.DeviceAuthResponse
 }
@@ -312,6 +355,7 @@ func (p *modelProxy) auditLogMessage(msg *message, isResponse bool) error {
 		}
 		ale.Params = jsonBody
 	}
+	p.auditRedactor.Redact(&ale)
 	p.auditLog(&ale)
 	return nil
 }
@@ -412,6 +456,7 @@ func (p *clientProxy) makeControllerConnection(ctx context.Context) error {
 			dst:            p.src,
 			msgs:           p.msgs,
 			auditLog:       p.auditLog,
+			auditRedactor:  p.auditRedactor,
 			tokenGen:       p.tokenGen,
 			modelName:      p.modelName,
 			conversationId: p.conversationId,
@@ -450,7 +495,10 @@ func (p *controllerProxy) start(ctx context.Context) error {
 		}
 		if permissionsRequired != nil {
 			zapctx.Error(ctx, "Access Required error")
-			if err := p.redoLogin(ctx, permissionsRequired); err != nil {
+			// Authorization decisions made for the new login are
+			// associated with the request that required them.
+			rctx := utils.ContextWithConversation(ctx, p.conversationId, msg.RequestID)
+			if err := p.redoLogin(rctx, permissionsRequired); err != nil {
 				zapctx.Error(ctx, "Failed to redo login", zap.Error(err))
 				p.handleError(msg, err)
 				continue
@@ -627,6 +675,9 @@ func (p *clientProxy) handleAdminFacade(ctx context.Context, msg *message) (clie
 		if err != nil {
 			return errorFnc(err)
 		}
+		if p.trackSession != nil {
+			p.trackSession(user.Name)
+		}
 		m := *msg
 		m.Type = "Admin"
 		m.Request = "Login"
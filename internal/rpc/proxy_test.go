@@ -293,6 +293,79 @@ func TestProxySocketsAdminFacade(t *testing.T) {
 	}
 }
 
+func TestProxySocketsTerminateSession(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	clientWebsocket := newMockWebsocketConnection(10)
+	controllerWebsocket := newMockWebsocketConnection(10)
+
+	var mu sync.Mutex
+	var trackedIdentity string
+	var terminate func()
+	untracked := make(chan struct{})
+	helpers := rpc.ProxyHelpers{
+		ConnClient: clientWebsocket,
+		TokenGen:   &mockTokenGenerator{},
+		ConnectController: func(ctx context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:           controllerWebsocket,
+				ModelName:      "test model",
+				ControllerUUID: uuid.NewString(),
+			}, nil
+		},
+		AuditLog: func(*dbmodel.AuditLogEntry) {},
+		LoginService: &mockLoginService{
+			email: "alice@wonderland.io",
+		},
+		TrackSession: func(identityName string, terminateF func()) func() {
+			mu.Lock()
+			defer mu.Unlock()
+			trackedIdentity = identityName
+			terminate = terminateF
+			return func() { close(untracked) }
+		},
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- rpc.ProxySockets(ctx, helpers)
+	}()
+
+	data, err := json.Marshal(message{
+		RequestID: 1,
+		Type:      "Admin",
+		Version:   4,
+		Request:   "LoginWithSessionToken",
+		Params:    []byte(`{"client-id": "test session token"}`),
+	})
+	c.Assert(err, qt.IsNil)
+	clientWebsocket.read <- data
+	select {
+	case <-controllerWebsocket.write:
+	case <-time.After(2 * time.Second):
+		c.Fatal("timed out waiting for login")
+	}
+
+	mu.Lock()
+	c.Check(trackedIdentity, qt.Equals, "alice@wonderland.io")
+	terminateF := terminate
+	mu.Unlock()
+	c.Assert(terminateF, qt.IsNotNil)
+	terminateF()
+
+	select {
+	case err := <-errChan:
+		c.Check(err, qt.ErrorMatches, "Context cancelled")
+	case <-time.After(2 * time.Second):
+		c.Fatal("timed out waiting for the session to end")
+	}
+	select {
+	case <-untracked:
+	case <-time.After(2 * time.Second):
+		c.Fatal("session not untracked")
+	}
+}
+
 type mockLoginService struct {
 	err          error
 	email        string
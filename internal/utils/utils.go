@@ -21,3 +21,25 @@ func NewConversationID() string {
 	}
 	return hex.EncodeToString(buf)
 }
+
+type conversationKey struct{}
+
+type conversation struct {
+	id        string
+	messageID uint64
+}
+
+// ContextWithConversation returns a context holding the given audit log
+// conversation and message IDs, so that audit log entries created while
+// handling a request can be associated with the request.
+func ContextWithConversation(ctx context.Context, conversationID string, messageID uint64) context.Context {
+	return context.WithValue(ctx, conversationKey{}, conversation{id: conversationID, messageID: messageID})
+}
+
+// ConversationFromContext returns the conversation and message IDs held
+// in the given context by ContextWithConversation. Empty values are
+// returned if the context holds no conversation.
+func ConversationFromContext(ctx context.Context) (conversationID string, messageID uint64) {
+	c, _ := ctx.Value(conversationKey{}).(conversation)
+	return c.id, c.messageID
+}
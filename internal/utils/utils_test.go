@@ -2,6 +2,7 @@
 package utils_test
 
 import (
+	"context"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -14,3 +15,16 @@ func TestNewConversationID(t *testing.T) {
 	res := utils.NewConversationID()
 	c.Assert(res, qt.HasLen, 16)
 }
+
+func TestConversationFromContext(t *testing.T) {
+	c := qt.New(t)
+
+	id, msgID := utils.ConversationFromContext(context.Background())
+	c.Check(id, qt.Equals, "")
+	c.Check(msgID, qt.Equals, uint64(0))
+
+	ctx := utils.ContextWithConversation(context.Background(), "0123456789abcdef", 3)
+	id, msgID = utils.ConversationFromContext(ctx)
+	c.Check(id, qt.Equals, "0123456789abcdef")
+	c.Check(msgID, qt.Equals, uint64(3))
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Canonical.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// AuditExportPath is the path of the JIMM HTTP endpoint that streams
+// audit events.
+const AuditExportPath = "/audit/export"
+
+// Audit export formats.
+const (
+	AuditExportNDJSON = "ndjson"
+	AuditExportCSV    = "csv"
+)
+
+// ExportAuditEvents streams the audit events matching the given request
+// from the JIMM server at baseURL, authenticating with the given session
+// token. The events are written in the given format, which is either
+// AuditExportNDJSON or AuditExportCSV. The Offset and ContinuationToken
+// fields of the request are not supported and, unlike FindAuditEvents,
+// all matching events are returned if Limit is zero. The caller must
+// close the returned reader.
+func ExportAuditEvents(ctx context.Context, client *http.Client, baseURL, sessionToken string, req *params.FindAuditEventsRequest, format string) (io.ReadCloser, error) {
+	q := make(url.Values)
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("after", req.After)
+	set("before", req.Before)
+	set("user-tag", req.UserTag)
+	set("model", req.Model)
+	set("method", req.Method)
+	set("facade-name", req.FacadeName)
+	set("conversation-id", req.ConversationId)
+	set("object-id", req.ObjectId)
+	set("params-contain", req.ParamsContain)
+	if req.HasErrors {
+		q.Set("has-errors", "true")
+	}
+	if req.SortTime {
+		q.Set("sortTime", "true")
+	}
+	if req.Restored {
+		q.Set("restored", "true")
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	set("format", format)
+
+	u := strings.TrimSuffix(baseURL, "/") + AuditExportPath + "?" + q.Encode()
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Authorization", "Bearer "+sessionToken)
+	resp, err := client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var apiErr jujuparams.Error
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return nil, fmt.Errorf("cannot export audit events: %s", resp.Status)
+		}
+		return nil, &apiErr
+	}
+	return resp.Body, nil
+}
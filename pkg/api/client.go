@@ -95,6 +95,64 @@ func (c *Client) SetControllerDeprecated(req *params.SetControllerDeprecatedRequ
 	return info, err
 }
 
+// SetControllerCapacity sets the capacity caps of a controller.
+func (c *Client) SetControllerCapacity(req *params.SetControllerCapacityRequest) (params.ControllerInfo, error) {
+	var info params.ControllerInfo
+	err := c.caller.APICall("JIMM", 4, "", "SetControllerCapacity", req, &info)
+	return info, err
+}
+
+// SetControllerRegionPriority sets the priority of a controller for a
+// cloud region.
+func (c *Client) SetControllerRegionPriority(req *params.SetControllerRegionPriorityRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetControllerRegionPriority", req, nil)
+}
+
+// ListControllerRegionPriorities lists the cloud region priorities of
+// controllers.
+func (c *Client) ListControllerRegionPriorities(req *params.ListControllerRegionPrioritiesRequest) ([]params.ControllerRegionPriority, error) {
+	var resp params.ListControllerRegionPrioritiesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListControllerRegionPriorities", req, &resp)
+	return resp.Priorities, err
+}
+
+// DrainController starts, or resumes, migrating all models off a
+// controller.
+func (c *Client) DrainController(req *params.DrainControllerRequest) (params.ControllerDrainStatus, error) {
+	var status params.ControllerDrainStatus
+	err := c.caller.APICall("JIMM", 4, "", "DrainController", req, &status)
+	return status, err
+}
+
+// ControllerDrainStatus returns the progress of a controller drain.
+func (c *Client) ControllerDrainStatus(req *params.ControllerDrainStatusRequest) (params.ControllerDrainStatus, error) {
+	var status params.ControllerDrainStatus
+	err := c.caller.APICall("JIMM", 4, "", "ControllerDrainStatus", req, &status)
+	return status, err
+}
+
+// ListMigrations lists the model migrations known to JIMM.
+func (c *Client) ListMigrations(req *params.ListMigrationsRequest) (params.ListMigrationsResponse, error) {
+	var resp params.ListMigrationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListMigrations", req, &resp)
+	return resp, err
+}
+
+// MigrationStatus returns the status of a model migration.
+func (c *Client) MigrationStatus(req *params.MigrationStatusRequest) (params.Migration, error) {
+	var migration params.Migration
+	err := c.caller.APICall("JIMM", 4, "", "MigrationStatus", req, &migration)
+	return migration, err
+}
+
+// Reconcile compares the state JIMM holds for controllers with the state
+// reported by the controllers.
+func (c *Client) Reconcile(req *params.ReconcileRequest) (params.ReconcileResponse, error) {
+	var resp params.ReconcileResponse
+	err := c.caller.APICall("JIMM", 4, "", "Reconcile", req, &resp)
+	return resp, err
+}
+
 // FullModelStatus returns the full status of the juju model.
 func (c *Client) FullModelStatus(req *params.FullModelStatusRequest) (jujuparams.FullStatus, error) {
 	var status jujuparams.FullStatus
@@ -107,6 +165,14 @@ func (c *Client) ImportModel(req *params.ImportModelRequest) error {
 	return c.caller.APICall("JIMM", 4, "", "ImportModel", req, nil)
 }
 
+// ImportModels imports all models running on a controller that are not
+// yet known to JIMM.
+func (c *Client) ImportModels(req *params.ImportModelsRequest) (params.ImportModelsResponse, error) {
+	var resp params.ImportModelsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ImportModels", req, &resp)
+	return resp, err
+}
+
 // UpdateMigratedModel updates which controller a model is running on
 // following an external migration operation.
 func (c *Client) UpdateMigratedModel(req *params.UpdateMigratedModelRequest) error {
@@ -163,6 +229,14 @@ func (c *Client) CheckRelation(req *params.CheckRelationRequest) (params.CheckRe
 	return checkResp, err
 }
 
+// ExplainRelation checks a relation in the same way as CheckRelation and
+// returns the chains of relations through which it is allowed.
+func (c *Client) ExplainRelation(req *params.ExplainRelationRequest) (params.ExplainRelationResponse, error) {
+	var resp params.ExplainRelationResponse
+	err := c.caller.APICall("JIMM", 4, "", "ExplainRelation", req, &resp)
+	return resp, err
+}
+
 // ListRelationshipTuples returns a list of tuples matching the specified criteria.
 func (c *Client) ListRelationshipTuples(req *params.ListRelationshipTuplesRequest) (*params.ListRelationshipTuplesResponse, error) {
 	var response params.ListRelationshipTuplesResponse
@@ -186,6 +260,27 @@ func (c *Client) PurgeLogs(req *params.PurgeLogsRequest) (*params.PurgeLogsRespo
 	return &response, err
 }
 
+// ListAuditLogArchives lists the archived segments of the audit log.
+func (c *Client) ListAuditLogArchives() (*params.ListAuditLogArchivesResponse, error) {
+	var response params.ListAuditLogArchivesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAuditLogArchives", nil, &response)
+	return &response, err
+}
+
+// RestoreAuditLogArchive restores an archived segment of the audit log.
+func (c *Client) RestoreAuditLogArchive(req *params.RestoreAuditLogArchiveRequest) (*params.RestoreAuditLogArchiveResponse, error) {
+	var response params.RestoreAuditLogArchiveResponse
+	err := c.caller.APICall("JIMM", 4, "", "RestoreAuditLogArchive", req, &response)
+	return &response, err
+}
+
+// VerifyAuditLog verifies the audit log hash chain.
+func (c *Client) VerifyAuditLog() (*params.VerifyAuditLogResponse, error) {
+	var response params.VerifyAuditLogResponse
+	err := c.caller.APICall("JIMM", 4, "", "VerifyAuditLog", nil, &response)
+	return &response, err
+}
+
 // MigrateModel migrates a model between two controllers that are attached to JIMM.
 func (c *Client) MigrateModel(req *params.MigrateModelRequest) (*jujuparams.InitiateMigrationResults, error) {
 	var response jujuparams.InitiateMigrationResults
@@ -223,3 +318,105 @@ func (c *Client) UpdateServiceAccountCredentials(req *params.UpdateServiceAccoun
 func (c *Client) GrantServiceAccountAccess(req *params.GrantServiceAccountAccess) error {
 	return c.caller.APICall("JIMM", 4, "", "GrantServiceAccountAccess", req, nil)
 }
+
+// SetQuota sets the limits of a quota for a user or group on a cloud.
+func (c *Client) SetQuota(req *params.SetQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetQuota", req, nil)
+}
+
+// RemoveQuota removes a quota.
+func (c *Client) RemoveQuota(req *params.RemoveQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveQuota", req, nil)
+}
+
+// ListQuotas lists all quotas known to JIMM along with their usage.
+func (c *Client) ListQuotas() ([]params.Quota, error) {
+	var resp params.ListQuotasResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListQuotas", nil, &resp)
+	return resp.Quotas, err
+}
+
+// RequestAccess files a request for access to a model, cloud or
+// application offer.
+func (c *Client) RequestAccess(req *params.RequestAccessRequest) (params.AccessRequest, error) {
+	var resp params.AccessRequest
+	err := c.caller.APICall("JIMM", 4, "", "RequestAccess", req, &resp)
+	return resp, err
+}
+
+// ListAccessRequests lists either the access requests the user may
+// review or those the user has made.
+func (c *Client) ListAccessRequests(req *params.ListAccessRequestsRequest) ([]params.AccessRequest, error) {
+	var resp params.ListAccessRequestsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAccessRequests", req, &resp)
+	return resp.Requests, err
+}
+
+// ApproveAccessRequest approves an access request.
+func (c *Client) ApproveAccessRequest(req *params.ReviewAccessRequestRequest) (params.AccessRequest, error) {
+	var resp params.AccessRequest
+	err := c.caller.APICall("JIMM", 4, "", "ApproveAccessRequest", req, &resp)
+	return resp, err
+}
+
+// DenyAccessRequest denies an access request.
+func (c *Client) DenyAccessRequest(req *params.ReviewAccessRequestRequest) (params.AccessRequest, error) {
+	var resp params.AccessRequest
+	err := c.caller.APICall("JIMM", 4, "", "DenyAccessRequest", req, &resp)
+	return resp, err
+}
+
+// AccessReport returns the effective access to a resource, or of an
+// identity.
+func (c *Client) AccessReport(req *params.AccessReportRequest) (*params.AccessReportResponse, error) {
+	var resp params.AccessReportResponse
+	err := c.caller.APICall("JIMM", 4, "", "AccessReport", req, &resp)
+	return &resp, err
+}
+
+// ApplyAccessPolicy returns the changes required to bring JIMM in line
+// with an access policy, making them if requested.
+func (c *Client) ApplyAccessPolicy(req *params.ApplyAccessPolicyRequest) (*params.AccessPolicyPlan, error) {
+	var resp params.AccessPolicyPlan
+	err := c.caller.APICall("JIMM", 4, "", "ApplyAccessPolicy", req, &resp)
+	return &resp, err
+}
+
+// ListIdentities lists the identities known to JIMM.
+func (c *Client) ListIdentities(req *params.ListIdentitiesRequest) (*params.ListIdentitiesResponse, error) {
+	var resp params.ListIdentitiesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListIdentities", req, &resp)
+	return &resp, err
+}
+
+// GetIdentity returns the details of an identity.
+func (c *Client) GetIdentity(req *params.GetIdentityRequest) (*params.IdentityDetails, error) {
+	var resp params.IdentityDetails
+	err := c.caller.APICall("JIMM", 4, "", "GetIdentity", req, &resp)
+	return &resp, err
+}
+
+// OffboardIdentity transfers the models and cloud credentials of an
+// identity to a new owner, removes the identity's relations and disables
+// it.
+func (c *Client) OffboardIdentity(req *params.OffboardIdentityRequest) (*params.OffboardSummary, error) {
+	var resp params.OffboardSummary
+	err := c.caller.APICall("JIMM", 4, "", "OffboardIdentity", req, &resp)
+	return &resp, err
+}
+
+// TransferModelOwnership transfers the ownership of a model to another
+// identity.
+func (c *Client) TransferModelOwnership(req *params.TransferModelOwnershipRequest) (*params.ModelOwnershipTransfer, error) {
+	var resp params.ModelOwnershipTransfer
+	err := c.caller.APICall("JIMM", 4, "", "TransferModelOwnership", req, &resp)
+	return &resp, err
+}
+
+// StaleReport returns the identities that have not logged in, and the
+// models that have had no activity, for the requested number of days.
+func (c *Client) StaleReport(req *params.StaleReportRequest) (*params.StaleReportResponse, error) {
+	var resp params.StaleReportResponse
+	err := c.caller.APICall("JIMM", 4, "", "StaleReport", req, &resp)
+	return &resp, err
+}
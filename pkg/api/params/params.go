@@ -63,6 +63,20 @@ type AddControllerRequest struct {
 	// Password contains the password that JIMM should use to connect to
 	// the controller.
 	Password string `json:"password"`
+
+	// MaxModels is the maximum number of models JIMM will place on the
+	// controller. If this is not specified there is no limit.
+	MaxModels *int64 `json:"max-models,omitempty"`
+
+	// MaxUnits is the maximum number of units that may be running on the
+	// controller for JIMM to place new models on it. If this is not
+	// specified there is no limit.
+	MaxUnits *int64 `json:"max-units,omitempty"`
+
+	// MaxMachines is the maximum number of machines that may be running
+	// on the controller for JIMM to place new models on it. If this is
+	// not specified there is no limit.
+	MaxMachines *int64 `json:"max-machines,omitempty"`
 }
 
 // AuditLogAccessRequest is the request used to modify a user's access
@@ -137,6 +151,10 @@ type AuditEvent struct {
 // An AuditEvents contains events from the audit log.
 type AuditEvents struct {
 	Events []AuditEvent `json:"events"`
+
+	// ContinuationToken, if set, can be used in a subsequent request to
+	// retrieve the events following these ones.
+	ContinuationToken string `json:"continuation-token,omitempty" yaml:"continuation-token,omitempty"`
 }
 
 // A ControllerInfo describes a controller on a JIMM system.
@@ -205,15 +223,48 @@ type FindAuditEventsRequest struct {
 	// called a specific facade method.
 	Method string `json:"method,omitempty"`
 
+	// FacadeName is used to filter the event log to only contain events
+	// that called a specific facade.
+	FacadeName string `json:"facade-name,omitempty"`
+
+	// ConversationId is used to filter the event log to only contain
+	// events from a single conversation.
+	ConversationId string `json:"conversation-id,omitempty"`
+
+	// ObjectId is used to filter the event log to only contain events
+	// that acted on a specific object.
+	ObjectId string `json:"object-id,omitempty"`
+
+	// HasErrors is used to filter the event log to only contain responses
+	// that contain at least one error.
+	HasErrors bool `json:"has-errors,omitempty"`
+
+	// ParamsContain is used to filter the event log to only contain
+	// events whose parameters contain the given JSON object. For example
+	// {"name":"model-1"} matches all requests with a "name" parameter of
+	// "model-1".
+	ParamsContain string `json:"params-contain,omitempty"`
+
 	// Offset is the number of items to offset the set of returned results.
+	// Offset cannot be used with ContinuationToken.
 	Offset int `json:"offset,omitempty"`
 
 	// Limit is the maximum number of audit events to return.
 	Limit int `json:"limit,omitempty"`
 
 	// SortTime will sort by most recent (time descending) when true.
-	// When false no explicit ordering will be applied.
+	// When false the events are sorted by time ascending.
 	SortTime bool `json:"sortTime,omitempty"`
+
+	// Restored will find events in audit log archives that have been
+	// restored, rather than in the current audit log, when true.
+	Restored bool `json:"restored,omitempty"`
+
+	// ContinuationToken is the token returned in a previous AuditEvents
+	// response. If this is specified the events following the last event
+	// of that response are returned. All other fields must be the same
+	// as in the previous request.
+	ContinuationToken string `json:"continuation-token,omitempty"`
 }
 
 // A ListControllersResponse is the response that is sent in a
@@ -240,6 +291,284 @@ type SetControllerDeprecatedRequest struct {
 	Deprecated bool `json:"deprecated"`
 }
 
+// A SetControllerCapacityRequest is the request that is sent in a
+// SetControllerCapacity method.
+type SetControllerCapacityRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// MaxModels is the maximum number of models JIMM will place on the
+	// controller. If this is not specified there is no limit.
+	MaxModels *int64 `json:"max-models,omitempty"`
+
+	// MaxUnits is the maximum number of units that may be running on the
+	// controller for JIMM to place new models on it. If this is not
+	// specified there is no limit.
+	MaxUnits *int64 `json:"max-units,omitempty"`
+
+	// MaxMachines is the maximum number of machines that may be running
+	// on the controller for JIMM to place new models on it. If this is
+	// not specified there is no limit.
+	MaxMachines *int64 `json:"max-machines,omitempty"`
+}
+
+// A SetControllerRegionPriorityRequest is the request that is sent in a
+// SetControllerRegionPriority method.
+type SetControllerRegionPriorityRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Cloud is the name of the cloud containing the region.
+	Cloud string `json:"cloud"`
+
+	// Region is the name of the cloud region.
+	Region string `json:"region"`
+
+	// Priority is the priority with which the controller is chosen when
+	// placing new models in the cloud region. Higher priority controllers
+	// are preferred.
+	Priority uint `json:"priority"`
+}
+
+// A ListControllerRegionPrioritiesRequest is the request that is sent in
+// a ListControllerRegionPriorities method.
+type ListControllerRegionPrioritiesRequest struct {
+	// Name is the name of the controller. If this is empty the
+	// priorities for all controllers are returned.
+	Name string `json:"name,omitempty"`
+}
+
+// A ControllerRegionPriority is the priority with which a controller is
+// chosen when placing new models in a cloud region.
+type ControllerRegionPriority struct {
+	Controller string `json:"controller" yaml:"controller"`
+	Cloud      string `json:"cloud" yaml:"cloud"`
+	Region     string `json:"region" yaml:"region"`
+	Priority   uint   `json:"priority" yaml:"priority"`
+}
+
+// A ListControllerRegionPrioritiesResponse is the response that is sent
+// from a ListControllerRegionPriorities method.
+type ListControllerRegionPrioritiesResponse struct {
+	Priorities []ControllerRegionPriority `json:"priorities" yaml:"priorities"`
+}
+
+// A DrainControllerRequest is the request that is sent in a
+// DrainController method.
+type DrainControllerRequest struct {
+	// Name is the name of the controller to drain.
+	Name string `json:"name"`
+
+	// BatchSize is the maximum number of models that are migrated
+	// concurrently. If this is zero a default batch size is used.
+	BatchSize int `json:"batch-size,omitempty"`
+}
+
+// A ControllerDrainStatusRequest is the request that is sent in a
+// ControllerDrainStatus method.
+type ControllerDrainStatusRequest struct {
+	// Name is the name of the drained controller.
+	Name string `json:"name"`
+}
+
+// ControllerDrainStatus holds the progress of a controller drain.
+type ControllerDrainStatus struct {
+	// Controller is the name of the controller being drained.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Status is the status of the drain, one of "running", "completed"
+	// or "failed".
+	Status string `json:"status" yaml:"status"`
+
+	// BatchSize is the maximum number of models that are migrated
+	// concurrently.
+	BatchSize int `json:"batch-size" yaml:"batch-size"`
+
+	// Started is the time the drain was first started.
+	Started time.Time `json:"started" yaml:"started"`
+
+	// Updated is the time the drain last progressed.
+	Updated time.Time `json:"updated" yaml:"updated"`
+
+	// Models contains the migration status of each model on the
+	// controller.
+	Models []ControllerDrainModel `json:"models" yaml:"models"`
+}
+
+// ControllerDrainModel holds the migration status of a model that is
+// part of a controller drain.
+type ControllerDrainModel struct {
+	UUID             string `json:"uuid" yaml:"uuid"`
+	Name             string `json:"name" yaml:"name"`
+	TargetController string `json:"target-controller,omitempty" yaml:"target-controller,omitempty"`
+	Status           string `json:"status" yaml:"status"`
+	Error            string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// An ImportModelsRequest holds a request to import all models running on
+// the specified controller that are not yet known to JIMM.
+type ImportModelsRequest struct {
+	// Controller holds the name of the controller that is running the
+	// models.
+	Controller string `json:"controller"`
+
+	// OwnerPattern is a glob pattern, if specified only models whose
+	// owner matches the pattern are imported.
+	OwnerPattern string `json:"owner-pattern,omitempty"`
+
+	// NamePattern is a glob pattern, if specified only models whose
+	// name matches the pattern are imported.
+	NamePattern string `json:"name-pattern,omitempty"`
+
+	// OwnerMap maps the names of model owners on the controller to the
+	// owners of the imported models. Owners not in the map are kept.
+	OwnerMap map[string]string `json:"owner-map,omitempty"`
+}
+
+// ImportModelsResponse holds the results of an ImportModels method.
+type ImportModelsResponse struct {
+	Results []ImportModelResult `json:"results" yaml:"results"`
+}
+
+// ImportModelResult holds the result of importing a single model.
+type ImportModelResult struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the owner of the imported model.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Error contains the reason the model could not be imported, if
+	// the import failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// A ListMigrationsRequest is the request that is sent in a
+// ListMigrations method.
+type ListMigrationsRequest struct {
+	// ModelTag is used to only list the migrations of the specified
+	// model.
+	ModelTag string `json:"model-tag,omitempty"`
+
+	// Active is used to only list migrations that are in progress.
+	Active bool `json:"active,omitempty"`
+
+	// Offset is the number of items to offset the set of returned results.
+	Offset int `json:"offset,omitempty"`
+
+	// Limit is the maximum number of migrations to return.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ListMigrationsResponse holds the migrations returned by a
+// ListMigrations method, most recent first.
+type ListMigrationsResponse struct {
+	Migrations []Migration `json:"migrations" yaml:"migrations"`
+}
+
+// A MigrationStatusRequest is the request that is sent in a
+// MigrationStatus method. Exactly one of MigrationID and ModelTag should
+// be specified. If ModelTag is specified the status of the most recent
+// migration of the model is returned.
+type MigrationStatusRequest struct {
+	// MigrationID is the ID of the migration.
+	MigrationID string `json:"migration-id,omitempty"`
+
+	// ModelTag is the tag of the migrated model.
+	ModelTag string `json:"model-tag,omitempty"`
+}
+
+// Migration holds the status of a model migration.
+type Migration struct {
+	// MigrationID is the ID of the migration assigned by the source
+	// controller.
+	MigrationID string `json:"migration-id" yaml:"migration-id"`
+
+	// ModelUUID is the UUID of the migrated model.
+	ModelUUID string `json:"model-uuid" yaml:"model-uuid"`
+
+	// ModelName is the name of the migrated model.
+	ModelName string `json:"model-name" yaml:"model-name"`
+
+	// SourceController is the name of the controller the model is
+	// migrated from.
+	SourceController string `json:"source-controller" yaml:"source-controller"`
+
+	// TargetController is the name of the controller the model is
+	// migrated to.
+	TargetController string `json:"target-controller" yaml:"target-controller"`
+
+	// Requester is the name of the user that requested the migration.
+	Requester string `json:"requester" yaml:"requester"`
+
+	// Phase is the phase of the migration, one of "initiated",
+	// "running", "completed" or "aborted".
+	Phase string `json:"phase" yaml:"phase"`
+
+	// Message is the most recent status message reported for the
+	// migration.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// StartedAt is the time the migration was initiated.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+
+	// EndedAt is the time the migration finished, if it has.
+	EndedAt *time.Time `json:"ended-at,omitempty" yaml:"ended-at,omitempty"`
+}
+
+// A ReconcileRequest is the request that is sent in a Reconcile method.
+type ReconcileRequest struct {
+	// Controller is the name of the controller to reconcile. If it is
+	// empty all controllers are reconciled.
+	Controller string `json:"controller,omitempty"`
+
+	// Fix requests that any discrepancies found are fixed.
+	Fix bool `json:"fix,omitempty"`
+}
+
+// A ReconcileResponse is the response from a Reconcile method.
+type ReconcileResponse struct {
+	Reports []ReconcileReport `json:"reports" yaml:"reports"`
+}
+
+// A ReconcileReport holds the discrepancies found on a single
+// controller.
+type ReconcileReport struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Error contains the reason the controller could not be
+	// reconciled, if it could not.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Discrepancies are the discrepancies found on the controller.
+	Discrepancies []Discrepancy `json:"discrepancies,omitempty" yaml:"discrepancies,omitempty"`
+}
+
+// A Discrepancy is a difference between the state of a controller held
+// by JIMM and the state reported by the controller.
+type Discrepancy struct {
+	// Kind is the kind of discrepancy, for example
+	// "model-not-on-controller".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Tag is the tag of the entity the discrepancy applies to.
+	Tag string `json:"tag" yaml:"tag"`
+
+	// Description describes the discrepancy.
+	Description string `json:"description" yaml:"description"`
+
+	// Fixed is true if the discrepancy has been fixed.
+	Fixed bool `json:"fixed" yaml:"fixed"`
+
+	// FixError contains the reason the discrepancy could not be fixed,
+	// if it could not.
+	FixError string `json:"fix-error,omitempty" yaml:"fix-error,omitempty"`
+}
+
 // FullModelStatusRequest is the request that is sent in a FullModelStatus method.
 type FullModelStatusRequest struct {
 	ModelTag string
@@ -327,6 +656,9 @@ type RelationshipTuple struct {
 // AddRelationRequest holds the tuples to be added to OpenFGA in an AddRelation request.
 type AddRelationRequest struct {
 	Tuples []RelationshipTuple `yaml:"tuples" json:"tuples"`
+	// ExpiresAt, if set, is the time at which the tuples are
+	// automatically removed.
+	ExpiresAt *time.Time `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
 }
 
 // RemoveRelationRequest holds the request information to remove tuples.
@@ -346,6 +678,42 @@ type CheckRelationResponse struct {
 	Allowed bool `json:"allowed" yaml:"allowed"`
 }
 
+// ExplainRelationRequest holds a tuple containing the object, target
+// object and relation whose check should be explained.
+type ExplainRelationRequest struct {
+	Tuple RelationshipTuple `json:"tuple"`
+}
+
+// RelationPathStep is a single step in a chain of relations.
+type RelationPathStep struct {
+	// Relation is the relation the previous entity in the chain has to
+	// the target.
+	Relation string `json:"relation" yaml:"relation"`
+	// Target is the entity the relation is to.
+	Target string `json:"target" yaml:"target"`
+}
+
+// RelationPath is a chain of relations through which a relation check
+// is allowed.
+type RelationPath struct {
+	// Identity is the identity the chain starts from, this is either
+	// the object of the check or the everyone user.
+	Identity string `json:"identity" yaml:"identity"`
+	// Source is "direct", "group" or "inherited".
+	Source string `json:"source" yaml:"source"`
+	// Steps holds the chain of relations from the identity to the
+	// target object. The relation of the final step may be stronger
+	// than the relation checked.
+	Steps []RelationPathStep `json:"steps" yaml:"steps"`
+}
+
+// ExplainRelationResponse holds the result of a relation check and the
+// chains of relations through which it is allowed.
+type ExplainRelationResponse struct {
+	Allowed bool           `json:"allowed" yaml:"allowed"`
+	Paths   []RelationPath `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
 // ListRelationshipTuplesRequests holds the request information to list tuples.
 type ListRelationshipTuplesRequest struct {
 	Tuple             RelationshipTuple `json:"tuple,omitempty"`
@@ -390,6 +758,84 @@ type PurgeLogsResponse struct {
 	DeletedCount int64 `json:"deleted-count" yaml:"deleted-count"`
 }
 
+// VerifyAuditLogResponse is the response returned by the VerifyAuditLog
+// method.
+type VerifyAuditLogResponse struct {
+	// Valid is true if the audit log hash chain is intact.
+	Valid bool `json:"valid" yaml:"valid"`
+
+	// Verified is the number of entries whose hash was verified.
+	Verified int64 `json:"verified" yaml:"verified"`
+
+	// Unhashed is the number of entries added before hash chaining was
+	// introduced, these entries cannot be verified.
+	Unhashed int64 `json:"unhashed" yaml:"unhashed"`
+
+	// BrokenEntryID is the ID of the first entry at which the hash chain
+	// is broken.
+	BrokenEntryID uint `json:"broken-entry-id,omitempty" yaml:"broken-entry-id,omitempty"`
+
+	// Reason describes why the hash chain is broken.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// An AuditLogArchive describes a segment of the audit log that has been
+// archived before being purged.
+type AuditLogArchive struct {
+	// ID is the ID of the archive.
+	ID uint `json:"id" yaml:"id"`
+
+	// CreatedAt is the time the archive was created.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+
+	// Key is the key of the segment in the archive store.
+	Key string `json:"key" yaml:"key"`
+
+	// FirstEntryID and LastEntryID are the lowest and highest IDs of
+	// the archived entries.
+	FirstEntryID uint `json:"first-entry-id" yaml:"first-entry-id"`
+	LastEntryID  uint `json:"last-entry-id" yaml:"last-entry-id"`
+
+	// StartTime and EndTime are the earliest and latest times of the
+	// archived entries.
+	StartTime time.Time `json:"start-time" yaml:"start-time"`
+	EndTime   time.Time `json:"end-time" yaml:"end-time"`
+
+	// EntryCount is the number of archived entries.
+	EntryCount int64 `json:"entry-count" yaml:"entry-count"`
+
+	// Size is the size, in bytes, of the compressed segment.
+	Size int64 `json:"size" yaml:"size"`
+
+	// SHA256 is the hex encoded SHA-256 checksum of the compressed
+	// segment.
+	SHA256 string `json:"sha256" yaml:"sha256"`
+
+	// RestoredAt is the time the segment was last restored, if it has
+	// been restored.
+	RestoredAt *time.Time `json:"restored-at,omitempty" yaml:"restored-at,omitempty"`
+}
+
+// ListAuditLogArchivesResponse is the response returned by the
+// ListAuditLogArchives method.
+type ListAuditLogArchivesResponse struct {
+	Archives []AuditLogArchive `json:"archives" yaml:"archives"`
+}
+
+// RestoreAuditLogArchiveRequest is the request sent to the
+// RestoreAuditLogArchive method.
+type RestoreAuditLogArchiveRequest struct {
+	// ID is the ID of the archive to restore.
+	ID uint `json:"id"`
+}
+
+// RestoreAuditLogArchiveResponse is the response returned by the
+// RestoreAuditLogArchive method.
+type RestoreAuditLogArchiveResponse struct {
+	// Restored is the number of entries restored.
+	Restored int64 `json:"restored" yaml:"restored"`
+}
+
 // MigrateModelInfo represents a single migration where a source model
 // target controller must be specified with both the source model and
 // target controller residing within JIMM.
@@ -492,3 +938,358 @@ type WhoamiResponse struct {
 	DisplayName string `json:"display-name" yaml:"display-name"`
 	Email       string `json:"email" yaml:"email"`
 }
+
+// Quota related request parameters
+
+// QuotaLimits holds the limits of a quota. A nil limit means the resource
+// is not limited.
+type QuotaLimits struct {
+	// MaxModels is the maximum number of models.
+	MaxModels *int64 `json:"max-models,omitempty" yaml:"max-models,omitempty"`
+	// MaxMachines is the maximum number of machines across all models.
+	MaxMachines *int64 `json:"max-machines,omitempty" yaml:"max-machines,omitempty"`
+	// MaxCores is the maximum number of cores across all models.
+	MaxCores *int64 `json:"max-cores,omitempty" yaml:"max-cores,omitempty"`
+	// MaxUnits is the maximum number of units across all models.
+	MaxUnits *int64 `json:"max-units,omitempty" yaml:"max-units,omitempty"`
+}
+
+// QuotaUsage holds the resources counted against a quota.
+type QuotaUsage struct {
+	Models   int64 `json:"models" yaml:"models"`
+	Machines int64 `json:"machines" yaml:"machines"`
+	Cores    int64 `json:"cores" yaml:"cores"`
+	Units    int64 `json:"units" yaml:"units"`
+}
+
+// SetQuotaRequest holds a request to set a quota.
+type SetQuotaRequest struct {
+	// Subject is the tag of the user or group the quota applies to.
+	Subject string `json:"subject"`
+	// CloudTag is the tag of the cloud the quota applies to.
+	CloudTag string `json:"cloud-tag"`
+	// Region is the cloud region the quota applies to. If empty the
+	// quota applies to all regions of the cloud.
+	Region string `json:"region,omitempty"`
+	// Limits holds the limits of the quota.
+	Limits QuotaLimits `json:"limits"`
+}
+
+// RemoveQuotaRequest holds a request to remove a quota.
+type RemoveQuotaRequest struct {
+	// Subject is the tag of the user or group the quota applies to.
+	Subject string `json:"subject"`
+	// CloudTag is the tag of the cloud the quota applies to.
+	CloudTag string `json:"cloud-tag"`
+	// Region is the cloud region the quota applies to.
+	Region string `json:"region,omitempty"`
+}
+
+// Quota holds the details of a quota and its current usage.
+type Quota struct {
+	Subject string      `json:"subject" yaml:"subject"`
+	Cloud   string      `json:"cloud" yaml:"cloud"`
+	Region  string      `json:"region,omitempty" yaml:"region,omitempty"`
+	Limits  QuotaLimits `json:"limits" yaml:"limits"`
+	Usage   QuotaUsage  `json:"usage" yaml:"usage"`
+}
+
+// ListQuotasResponse holds the response to a ListQuotas request.
+type ListQuotasResponse struct {
+	Quotas []Quota `json:"quotas" yaml:"quotas"`
+}
+
+// Access request related request parameters
+
+// RequestAccessRequest holds a request for access to a resource.
+type RequestAccessRequest struct {
+	// Resource is the tag of the model, cloud or application offer
+	// access is requested for, for example "model-alice@canonical.com/prod".
+	Resource string `json:"resource"`
+	// Access is the access level requested, using the same names as
+	// "juju grant", for example "read", "write" or "admin".
+	Access string `json:"access"`
+	// Reason is an optional justification for the request.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListAccessRequestsRequest holds a request to list access requests.
+type ListAccessRequestsRequest struct {
+	// Status, if set, limits the requests to those with the given
+	// status, one of "pending", "approved" or "denied".
+	Status string `json:"status,omitempty"`
+	// Mine lists the requests made by the authenticated user rather
+	// than those the user may review.
+	Mine bool `json:"mine,omitempty"`
+}
+
+// ReviewAccessRequestRequest holds a request to approve or deny an
+// access request.
+type ReviewAccessRequestRequest struct {
+	// ID is the ID of the access request.
+	ID uint `json:"id"`
+	// Comment is an optional comment for the requester.
+	Comment string `json:"comment,omitempty"`
+	// ExpiresAt, if set when approving a request, is the time at which
+	// the granted access is revoked.
+	ExpiresAt *time.Time `json:"expires-at,omitempty"`
+}
+
+// AccessRequest holds the details of an access request.
+type AccessRequest struct {
+	ID         uint       `json:"id" yaml:"id"`
+	Requester  string     `json:"requester" yaml:"requester"`
+	Resource   string     `json:"resource" yaml:"resource"`
+	Relation   string     `json:"relation" yaml:"relation"`
+	Reason     string     `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Status     string     `json:"status" yaml:"status"`
+	ReviewedBy string     `json:"reviewed-by,omitempty" yaml:"reviewed-by,omitempty"`
+	Comment    string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+	ExpiresAt  *time.Time `json:"expires-at,omitempty" yaml:"expires-at,omitempty"`
+	Created    time.Time  `json:"created" yaml:"created"`
+	Updated    time.Time  `json:"updated" yaml:"updated"`
+}
+
+// ListAccessRequestsResponse holds the response to a ListAccessRequests
+// request.
+type ListAccessRequestsResponse struct {
+	Requests []AccessRequest `json:"requests" yaml:"requests"`
+}
+
+// Access report related request parameters
+
+// AccessReportRequest holds a request for an effective access report.
+// Exactly one of Resource and Identity must be specified.
+type AccessReportRequest struct {
+	// Resource is the tag of the controller, cloud, model, application
+	// offer or service account to report the access to.
+	Resource string `json:"resource,omitempty"`
+	// Identity is the tag of the user to report the access of.
+	Identity string `json:"identity,omitempty"`
+}
+
+// AccessReportEntry describes one way in which an identity has a relation
+// to a resource.
+type AccessReportEntry struct {
+	Identity string `json:"identity" yaml:"identity"`
+	Resource string `json:"resource" yaml:"resource"`
+	Relation string `json:"relation" yaml:"relation"`
+	// Source is "direct", "group" or "inherited".
+	Source string `json:"source" yaml:"source"`
+	// Via holds the groups and parent resources through which the
+	// relation is granted, ordered from the identity to the resource.
+	Via []string `json:"via,omitempty" yaml:"via,omitempty"`
+}
+
+// AccessReportResponse holds an effective access report.
+type AccessReportResponse struct {
+	Entries []AccessReportEntry `json:"entries" yaml:"entries"`
+}
+
+// Access policy related request parameters
+
+// AccessPolicy declares the groups, group memberships and relations that
+// should exist in JIMM. Tags use the same syntax as RelationshipTuple.
+type AccessPolicy struct {
+	// Groups holds the names of groups that should exist.
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Memberships maps group names to the tags of their members, for
+	// example "user-alice@canonical.com" or "group-ops#member". Groups
+	// with memberships are created if necessary.
+	Memberships map[string][]string `json:"memberships,omitempty" yaml:"memberships,omitempty"`
+	// Relations holds the relations that should exist.
+	Relations []RelationshipTuple `json:"relations,omitempty" yaml:"relations,omitempty"`
+}
+
+// ApplyAccessPolicyRequest holds a request to plan, and optionally apply,
+// the changes required to bring JIMM in line with an access policy.
+type ApplyAccessPolicyRequest struct {
+	Policy AccessPolicy `json:"policy"`
+	// Apply makes the planned changes, otherwise only the plan is
+	// returned.
+	Apply bool `json:"apply,omitempty"`
+}
+
+// AccessPolicyPlan holds the changes required to bring JIMM in line with
+// an access policy.
+type AccessPolicyPlan struct {
+	// CreateGroups holds the names of the groups to be created.
+	CreateGroups []string `json:"create-groups,omitempty" yaml:"create-groups,omitempty"`
+	// Add holds the relations to be added.
+	Add []RelationshipTuple `json:"add,omitempty" yaml:"add,omitempty"`
+	// Remove holds the relations to be removed.
+	Remove []RelationshipTuple `json:"remove,omitempty" yaml:"remove,omitempty"`
+	// Applied is true if the changes have been made.
+	Applied bool `json:"applied" yaml:"applied"`
+}
+
+// Identity management related request parameters
+
+// ListIdentitiesRequest holds a request to list identities.
+type ListIdentitiesRequest struct {
+	// Match, if set, limits the identities to those whose name or
+	// display name contains the given string, ignoring case.
+	Match string `json:"match,omitempty"`
+	// Disabled limits the identities to those that are disabled.
+	Disabled bool `json:"disabled,omitempty"`
+	// Offset is the number of items to offset the set of returned results.
+	Offset int `json:"offset,omitempty"`
+	// Limit is the maximum number of identities to return.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Identity holds the details of an identity.
+type Identity struct {
+	Name        string     `json:"name" yaml:"name"`
+	DisplayName string     `json:"display-name" yaml:"display-name"`
+	Disabled    bool       `json:"disabled" yaml:"disabled"`
+	Created     time.Time  `json:"created" yaml:"created"`
+	LastLogin   *time.Time `json:"last-login,omitempty" yaml:"last-login,omitempty"`
+}
+
+// ListIdentitiesResponse holds the response to a ListIdentities request.
+type ListIdentitiesResponse struct {
+	Identities []Identity `json:"identities" yaml:"identities"`
+}
+
+// GetIdentityRequest holds a request for the details of an identity.
+type GetIdentityRequest struct {
+	// Tag is the tag of the identity, for example
+	// "user-alice@canonical.com".
+	Tag string `json:"tag"`
+}
+
+// IdentityModel holds the details of a model owned by an identity.
+type IdentityModel struct {
+	Name       string `json:"name" yaml:"name"`
+	UUID       string `json:"uuid" yaml:"uuid"`
+	Controller string `json:"controller" yaml:"controller"`
+	Cloud      string `json:"cloud" yaml:"cloud"`
+	Region     string `json:"region" yaml:"region"`
+}
+
+// IdentityDetails holds the details of an identity along with the
+// resources it owns and the groups it is a member of.
+type IdentityDetails struct {
+	Identity `yaml:",inline"`
+	// CloudCredentials holds the IDs of the cloud credentials owned by
+	// the identity, for example "aws/alice@canonical.com/default".
+	CloudCredentials []string `json:"cloud-credentials,omitempty" yaml:"cloud-credentials,omitempty"`
+	// Models holds the models owned by the identity.
+	Models []IdentityModel `json:"models,omitempty" yaml:"models,omitempty"`
+	// Groups holds the names of the groups the identity is a member of,
+	// including through membership of other groups.
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// OffboardIdentityRequest holds a request to offboard an identity.
+type OffboardIdentityRequest struct {
+	// Tag is the tag of the identity to offboard.
+	Tag string `json:"tag"`
+	// NewOwnerTag is the tag of the identity that will take ownership
+	// of the models and cloud credentials of the offboarded identity.
+	NewOwnerTag string `json:"new-owner-tag"`
+}
+
+// OffboardSummary holds a summary of the changes made when offboarding an
+// identity.
+type OffboardSummary struct {
+	// Identity is the name of the offboarded identity.
+	Identity string `json:"identity" yaml:"identity"`
+	// NewOwner is the name of the identity that took ownership of the
+	// offboarded identity's models and cloud credentials.
+	NewOwner string `json:"new-owner" yaml:"new-owner"`
+	// Models holds the models transferred to the new owner.
+	Models []IdentityModel `json:"models,omitempty" yaml:"models,omitempty"`
+	// CloudCredentials holds the IDs of the cloud credentials
+	// transferred to the new owner, as owned by the new owner.
+	CloudCredentials []string `json:"cloud-credentials,omitempty" yaml:"cloud-credentials,omitempty"`
+	// RemovedRelations holds the relations of the offboarded identity
+	// that were removed.
+	RemovedRelations []RelationshipTuple `json:"removed-relations,omitempty" yaml:"removed-relations,omitempty"`
+	// Disabled is true if the identity was disabled.
+	Disabled bool `json:"disabled" yaml:"disabled"`
+}
+
+// TransferModelOwnershipRequest holds a request to transfer the ownership
+// of a model to another identity.
+type TransferModelOwnershipRequest struct {
+	// ModelTag is the tag of the model to transfer.
+	ModelTag string `json:"model-tag"`
+	// NewOwnerTag is the tag of the identity that will take ownership
+	// of the model.
+	NewOwnerTag string `json:"new-owner-tag"`
+	// CloudCredentialTag is the tag of the new owner's cloud credential
+	// the model will use. If this is not specified one of the new
+	// owner's credentials for the model's cloud will be used.
+	CloudCredentialTag string `json:"cloud-credential-tag,omitempty"`
+}
+
+// ModelOwnershipTransfer holds a summary of the changes made when
+// transferring the ownership of a model.
+type ModelOwnershipTransfer struct {
+	// Model is the transferred model.
+	Model IdentityModel `json:"model" yaml:"model"`
+	// PreviousOwner is the name of the identity that previously owned
+	// the model.
+	PreviousOwner string `json:"previous-owner" yaml:"previous-owner"`
+	// NewOwner is the name of the identity that now owns the model.
+	NewOwner string `json:"new-owner" yaml:"new-owner"`
+	// PreviousCloudCredential is the ID of the cloud credential the
+	// model previously used.
+	PreviousCloudCredential string `json:"previous-cloud-credential" yaml:"previous-cloud-credential"`
+	// CloudCredential is the ID of the cloud credential the model now
+	// uses.
+	CloudCredential string `json:"cloud-credential" yaml:"cloud-credential"`
+}
+
+// StaleReportRequest holds a request for a report of inactive identities
+// and stale models.
+type StaleReportRequest struct {
+	// Days is the number of days without a login, or model activity,
+	// after which an identity, or model, is reported.
+	Days int `json:"days"`
+}
+
+// ResourceFootprint holds the resources used by a set of models.
+type ResourceFootprint struct {
+	Models   int   `json:"models" yaml:"models"`
+	Machines int64 `json:"machines" yaml:"machines"`
+	Cores    int64 `json:"cores" yaml:"cores"`
+	Units    int64 `json:"units" yaml:"units"`
+}
+
+// InactiveIdentity describes an identity that has not logged in to JIMM
+// recently.
+type InactiveIdentity struct {
+	Identity `yaml:",inline"`
+	// Footprint holds the resources used by the models the identity
+	// owns.
+	Footprint ResourceFootprint `json:"footprint" yaml:"footprint"`
+}
+
+// StaleModel describes a model that has had no recent activity.
+type StaleModel struct {
+	IdentityModel `yaml:",inline"`
+	// Owner is the name of the identity that owns the model.
+	Owner string `json:"owner" yaml:"owner"`
+	// LastActivity is the time activity was last seen in the model, it
+	// is not set if no activity has been seen.
+	LastActivity *time.Time `json:"last-activity,omitempty" yaml:"last-activity,omitempty"`
+	// Footprint holds the resources used by the model.
+	Footprint ResourceFootprint `json:"footprint" yaml:"footprint"`
+}
+
+// StaleReportResponse holds a report of inactive identities and stale
+// models.
+type StaleReportResponse struct {
+	// Before is the time before which identities must have last logged
+	// in, and models last been active, to be reported.
+	Before time.Time `json:"before" yaml:"before"`
+	// Identities holds the identities that have not logged in since
+	// Before, ordered by name.
+	Identities []InactiveIdentity `json:"identities" yaml:"identities"`
+	// Models holds the models that have had no activity since Before,
+	// ordered from the least recently active.
+	Models []StaleModel `json:"models" yaml:"models"`
+}